@@ -17,6 +17,16 @@ limitations under the License.
 package v1beta1
 
 const (
+	// DeployDeferredCondition is set to true while deployments are deferred because the target is outside of all its
+	// maintenance windows.
+	DeployDeferredCondition string = "DeployDeferred"
+)
+
+const (
+	// OutsideMaintenanceWindowReason represents the fact that a deployment
+	// was deferred until the next maintenance window opens.
+	OutsideMaintenanceWindowReason string = "OutsideMaintenanceWindow"
+
 	// DiffFailedReason represents the fact that the
 	// kluctl diff command failed.
 	DiffFailedReason string = "DiffFailed"
@@ -186,6 +186,20 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	AbortOnError bool `json:"abortOnError,omitempty"`
 
+	// OverrideMaintenanceWindow instructs kluctl to deploy and prune even if the current time is outside of all
+	// maintenance windows configured in the target.
+	// Equivalent to using '--override-maintenance-window' when calling kluctl.
+	// +kubebuilder:default:=false
+	// +optional
+	OverrideMaintenanceWindow bool `json:"overrideMaintenanceWindow,omitempty"`
+
+	// AllowBreakingCRDChanges instructs kluctl to apply potentially breaking CRD changes even if custom resources of
+	// the CRD exist.
+	// Equivalent to using '--allow-breaking-crd-changes' when calling kluctl.
+	// +kubebuilder:default:=false
+	// +optional
+	AllowBreakingCRDChanges bool `json:"allowBreakingCRDChanges,omitempty"`
+
 	// IncludeTags instructs kluctl to only include deployments with given tags.
 	// Equivalent to using '--include-tag' when calling kluctl.
 	// +optional
@@ -0,0 +1,87 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KluctlDeployResultSpec is a compact summary of a single command result. The full command result is stored in the
+// result store and can be looked up via CommandResultId.
+type KluctlDeployResultSpec struct {
+	// CommandResultId is the id of the full command result.
+	CommandResultId string `json:"commandResultId"`
+
+	// Command is the command that was executed, e.g. deploy, diff or prune.
+	Command string `json:"command"`
+
+	// Initiator is either CommandLine or KluctlDeployment.
+	Initiator string `json:"initiator"`
+
+	StartTime metav1.Time `json:"startTime"`
+	EndTime   metav1.Time `json:"endTime"`
+
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// +optional
+	ProjectRepoKey string `json:"projectRepoKey,omitempty"`
+	// +optional
+	ProjectSubDir string `json:"projectSubDir,omitempty"`
+
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+	// +optional
+	Discriminator string `json:"discriminator,omitempty"`
+
+	// GitCommit is the commit of the project that was deployed, if known.
+	// +optional
+	GitCommit string `json:"gitCommit,omitempty"`
+
+	// KluctlDeployment references the KluctlDeployment that executed the command, if any.
+	// +optional
+	KluctlDeployment *KluctlDeployResultDeploymentRef `json:"kluctlDeployment,omitempty"`
+
+	NewObjects     int `json:"newObjects"`
+	ChangedObjects int `json:"changedObjects"`
+	OrphanObjects  int `json:"orphanObjects"`
+	DeletedObjects int `json:"deletedObjects"`
+	AppliedObjects int `json:"appliedObjects"`
+
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+}
+
+type KluctlDeployResultDeploymentRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Command",type="string",JSONPath=".spec.command",description=""
+//+kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetName",description=""
+//+kubebuilder:printcolumn:name="DryRun",type="boolean",JSONPath=".spec.dryRun",description=""
+//+kubebuilder:printcolumn:name="Changed",type="integer",JSONPath=".spec.changedObjects",description=""
+//+kubebuilder:printcolumn:name="Errors",type="integer",JSONPath=".spec.errors",description=""
+//+kubebuilder:printcolumn:name="Warnings",type="integer",JSONPath=".spec.warnings",description=""
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// KluctlDeployResult is a compact, in-cluster notification about a finished Kluctl command. It is optionally written
+// after each command so that in-cluster automation can react to deployments.
+type KluctlDeployResult struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KluctlDeployResultSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KluctlDeployResultList contains a list of KluctlDeployResult
+type KluctlDeployResultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KluctlDeployResult `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KluctlDeployResult{}, &KluctlDeployResultList{})
+}
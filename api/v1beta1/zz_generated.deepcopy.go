@@ -64,6 +64,101 @@ func (in *HelmCredentials) DeepCopy() *HelmCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeployResult) DeepCopyInto(out *KluctlDeployResult) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeployResult.
+func (in *KluctlDeployResult) DeepCopy() *KluctlDeployResult {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeployResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KluctlDeployResult) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeployResultDeploymentRef) DeepCopyInto(out *KluctlDeployResultDeploymentRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeployResultDeploymentRef.
+func (in *KluctlDeployResultDeploymentRef) DeepCopy() *KluctlDeployResultDeploymentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeployResultDeploymentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeployResultList) DeepCopyInto(out *KluctlDeployResultList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KluctlDeployResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeployResultList.
+func (in *KluctlDeployResultList) DeepCopy() *KluctlDeployResultList {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeployResultList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KluctlDeployResultList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeployResultSpec) DeepCopyInto(out *KluctlDeployResultSpec) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.KluctlDeployment != nil {
+		in, out := &in.KluctlDeployment, &out.KluctlDeployment
+		*out = new(KluctlDeployResultDeploymentRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeployResultSpec.
+func (in *KluctlDeployResultSpec) DeepCopy() *KluctlDeployResultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeployResultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KluctlDeployment) DeepCopyInto(out *KluctlDeployment) {
 	*out = *in
@@ -0,0 +1,6 @@
+package args
+
+type DeployStatusFlags struct {
+	StatusFileDir    string   `group:"misc" help:"Write a deploy status summary (<target>.json) and a status badge (<target>.svg) into the given directory after the deployment has finished. The summary contains the git commit, the time and the result of the deployment."`
+	StatusFileUpload []string `group:"misc" help:"Upload the deploy status summary and badge to the given location after the deployment has finished. The location must be in the form s3://bucket/prefix or gs://bucket/prefix. Can be specified multiple times."`
+}
@@ -3,6 +3,7 @@ package args
 import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"k8s.io/apimachinery/pkg/labels"
 	"path/filepath"
 )
 
@@ -35,3 +36,18 @@ func (args *InclusionFlags) ParseInclusionFromArgs() (*utils.Inclusion, error) {
 	}
 	return inclusion, nil
 }
+
+type SelectorFlags struct {
+	Selector string `group:"misc" short:"l" help:"Label selector (e.g. app=foo) to restrict the operation to rendered and remote objects with matching labels. Supports the same syntax as kubectl's --selector."`
+}
+
+func (args *SelectorFlags) ParseSelectorFromArgs() (labels.Selector, error) {
+	if args.Selector == "" {
+		return nil, nil
+	}
+	s, err := labels.Parse(args.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --selector: %w", err)
+	}
+	return s, nil
+}
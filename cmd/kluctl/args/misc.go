@@ -26,8 +26,36 @@ type ReplaceOnErrorFlags struct {
 	ForceReplaceOnError bool `group:"misc" help:"Same as --replace-on-error, but also try to delete and re-create objects. See documentation for more details."`
 }
 
+type AllowBreakingCRDChangesFlags struct {
+	AllowBreakingCrdChanges bool `group:"misc" help:"Allow applying CRD changes that are potentially breaking (e.g. storage version or scope changes, removed versions or fields) while custom resources of the CRD exist."`
+}
+
 type HookFlags struct {
-	ReadinessTimeout time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is meant per-object. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
+	ReadinessTimeout time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is shared by all objects that are waited for together (e.g. at a barrier). Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
+}
+
+type MaxDeletesFlags struct {
+	MaxDeletes   int  `group:"misc" help:"Abort if more than the given number of objects would be deleted. Overrides 'maxDeletes' from the target. A negative value means that the target configuration is used." default:"-1"`
+	IgnoreLimits bool `group:"misc" help:"Ignore all limits configured via 'maxDeletes' and 'maxChanges' in the target or via --max-deletes and --max-changes."`
+}
+
+type MaxChangesFlags struct {
+	MaxChanges int `group:"misc" help:"Abort if more than the given number of objects would be created or changed. Overrides 'maxChanges' from the target. A negative value means that the target configuration is used." default:"-1"`
+}
+
+func optionalLimit(v int) *int {
+	if v < 0 {
+		return nil
+	}
+	return &v
+}
+
+func (f MaxDeletesFlags) GetMaxDeletes() *int {
+	return optionalLimit(f.MaxDeletes)
+}
+
+func (f MaxChangesFlags) GetMaxChanges() *int {
+	return optionalLimit(f.MaxChanges)
 }
 
 type IgnoreFlags struct {
@@ -41,8 +69,12 @@ type AbortOnErrorFlags struct {
 	AbortOnError bool `group:"misc" help:"Abort deploying when an error occurs instead of trying the remaining deployments"`
 }
 
+type MaintenanceWindowFlags struct {
+	OverrideMaintenanceWindow bool `group:"misc" help:"Allow changes to the target even if the current time is outside of all maintenance windows configured in the target."`
+}
+
 type OutputFormatFlags struct {
-	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text' or 'yaml'. Can be specified multiple times. The actual format for yaml is currently not documented and subject to change."`
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'summary' or 'yaml'. Can be specified multiple times. The yaml format follows the published command result schema, see https://kluctl.io/docs/kluctl/results/ for details."`
 	NoObfuscate  bool     `group:"misc" help:"Disable obfuscation of sensitive/secret data"`
 	ShortOutput  bool     `group:"misc" help:"When using the 'text' output format (which is the default), only names of changes objects are shown instead of showing all changes."`
 }
@@ -51,6 +83,11 @@ type OutputFlags struct {
 	Output []string `group:"misc" short:"o" help:"Specify output target file. Can be specified multiple times"`
 }
 
+type CollectArtifactsFlags struct {
+	CollectArtifacts string `group:"misc" help:"Collect the output artifacts (see 'artifacts' in deployment items) of all deployment items into the given directory."`
+}
+
 type RenderOutputDirFlags struct {
 	RenderOutputDir string `group:"misc" help:"Specifies the target directory to render the project into. If omitted, a temporary directory is used."`
+	KeepRenderTmp   string `group:"misc" help:"Preserves the intermediate rendering stages of all deployment items in the given directory. For each deployment item, the directories 'post-jinja2' and 'pre-kustomize' and the file 'post-kustomize.yaml' are written. Useful to debug templates."`
 }
@@ -1,7 +1,9 @@
 package args
 
 import (
+	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"os"
 	"path/filepath"
@@ -36,6 +38,7 @@ type ProjectFlags struct {
 type ArgsFlags struct {
 	Arg          []string `group:"project" short:"a" help:"Passes a template argument in the form of name=value. Nested args can be set with the '-a my.nested.arg=value' syntax. Values are interpreted as yaml values, meaning that 'true' and 'false' will lead to boolean values and numbers will be treated as numbers. Use quotes if you want these to be treated as strings. If the value starts with @, it is treated as a file, meaning that the contents of the file will be loaded and treated as yaml."`
 	ArgsFromFile []string `group:"project" help:"Loads a yaml file and makes it available as arguments, meaning that they will be available thought the global 'args' variable."`
+	ItemArg      []string `group:"project" help:"Overrides a variable for a single deployment item in the form of path/to/item:name=value. The path must be relative to the root deployment project. The variable is only visible while rendering this item and takes precedence over the item's vars. The same value syntax as for --arg applies, e.g. 'my-item:args.replicas=3' overrides an arg for this item only."`
 }
 
 func (a *ArgsFlags) LoadArgs() (*uo.UnstructuredObject, error) {
@@ -62,6 +65,25 @@ func (a *ArgsFlags) LoadArgs() (*uo.UnstructuredObject, error) {
 	return args, nil
 }
 
+func (a *ArgsFlags) LoadItemArgs() (map[string]*uo.UnstructuredObject, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	itemArgs, err := kluctl_project.ParseItemArgs(a.ItemArg)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]*uo.UnstructuredObject{}
+	for itemPath, args := range itemArgs {
+		ret[itemPath], err = kluctl_project.ConvertArgsToVars(args, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 type TargetFlagsBase struct {
 	Target             string `group:"project" short:"t" help:"Target name to run command for. Target must exist in .kluctl.yaml."`
 	TargetNameOverride string `group:"project" short:"T" help:"Overrides the target name. If -t is used at the same time, then the target will be looked up based on -t <name> and then renamed to the value of -T. If no target is specified via -t, then the no-name target is renamed to the value of -T."`
@@ -74,6 +96,23 @@ type TargetFlags struct {
 
 type KubeconfigFlags struct {
 	Kubeconfig ExistingFileType `group:"project" help:"Overrides the kubeconfig to use."`
+
+	KubeCaFile                ExistingFileType `group:"project" help:"Overrides the CA bundle used to verify the Kubernetes API server certificate. Takes precedence over the kubeconfig and the tls config of the target."`
+	KubeInsecureSkipTlsVerify bool             `group:"project" help:"Disables verification of the Kubernetes API server certificate. This is insecure and should only be used for lab/test clusters. Takes precedence over the kubeconfig and the tls config of the target."`
+}
+
+func (f *KubeconfigFlags) LoadTLSOverride() (*types.TLSConfig, error) {
+	ret := &types.TLSConfig{
+		InsecureSkipTLSVerify: f.KubeInsecureSkipTlsVerify,
+	}
+	if f.KubeCaFile != "" {
+		b, err := os.ReadFile(f.KubeCaFile.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		ret.CAData = string(b)
+	}
+	return ret, nil
 }
 
 type CommandResultReadOnlyFlags struct {
@@ -85,6 +124,7 @@ type CommandResultWriteFlags struct {
 	ForceWriteCommandResult  bool `group:"results" help:"Force writing of command results, even if the command is run in dry-run mode."`
 	KeepCommandResultsCount  int  `group:"results" help:"Configure how many old command results to keep." default:"5"`
 	KeepValidateResultsCount int  `group:"results" help:"Configure how many old validate results to keep." default:"2"`
+	WriteKluctlDeployResult  bool `group:"results" help:"Additionally write a compact KluctlDeployResult object into the command result namespace after each command. Requires the KluctlDeployResult CRD to be installed, which is part of the controller installation."`
 }
 
 type CommandResultFlags struct {
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	helm_auth "github.com/kluctl/kluctl/v2/pkg/helm/auth"
+	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"os"
+	"path/filepath"
+)
+
+type checkUpdatesCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.OfflineKubernetesFlags
+
+	JsonFile string `group:"misc" help:"Write the update report as JSON into the given file, e.g. for further processing in automation."`
+}
+
+func (cmd *checkUpdatesCmd) Help() string {
+	return `This recursively searches for 'helm-chart.yaml' files and renders the target to determine all git and oci
+includes of the project. The Helm repositories, git repositories and OCI registries are then queried for newer
+versions and a report with all available updates is printed.
+
+Helm Charts are checked with respect to 'updateConstraints' and 'skipUpdate' of the corresponding helm-chart.yaml.
+Git includes are only checked if they are pinned to a tag and OCI includes are only checked if they are pinned to a
+tag without digest. OCI includes for which the tags can't be listed are skipped with a warning. Only tags that are
+valid semantic versions are considered and pre-releases are ignored unless the current version is a pre-release itself.
+
+This command does not modify the project. Use 'helm-update --upgrade' to upgrade Helm Charts.`
+}
+
+func (cmd *checkUpdatesCmd) Run(ctx context.Context) error {
+	projectDir, err := cmd.ProjectDir.GetProjectDir()
+	if err != nil {
+		return err
+	}
+
+	if !yaml.Exists(filepath.Join(projectDir, ".kluctl.yaml")) && !yaml.Exists(filepath.Join(projectDir, ".kluctl-library.yaml")) {
+		return fmt.Errorf("check-updates can only be used on the root of a Kluctl project that must have a .kluctl.yaml or .kluctl-library.yaml file")
+	}
+
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:        cmd.ProjectFlags,
+		kubeconfigFlags:     cmd.KubeconfigFlags,
+		targetFlags:         cmd.TargetFlags,
+		argsFlags:           cmd.ArgsFlags,
+		helmCredentials:     cmd.HelmCredentials,
+		registryCredentials: cmd.RegistryCredentials,
+		offlineKubernetes:   cmd.OfflineKubernetes,
+		kubernetesVersion:   cmd.KubernetesVersion,
+	}
+
+	var updates []commands.AvailableUpdate
+	err = withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		cmd2 := commands.NewCheckIncludeUpdatesCommand(cmdCtx.targetCtx)
+		x, err := cmd2.Run(cmdCtx.ctx)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, x...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	x, err := cmd.checkHelmCharts(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+	updates = append(updates, x...)
+	commands.SortUpdates(updates)
+
+	if cmd.JsonFile != "" {
+		b, err := json.MarshalIndent(commands.CheckUpdatesResult{Updates: updates}, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(cmd.JsonFile, b, 0o600)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(updates) == 0 {
+		status.Info(ctx, "No updates available")
+		return nil
+	}
+
+	var table utils.PrettyTable
+	table.AddRow("Location", "Kind", "Source", "Current", "Latest", "Update")
+	for _, u := range updates {
+		table.AddRow(u.Location, string(u.Kind), u.Source, u.CurrentVersion, u.LatestVersion, string(u.UpdateType))
+	}
+	status.Flush(ctx)
+	_, err = getStdout(ctx).WriteString(table.Render([]int{-1, -1, -1, -1, -1, -1}))
+	return err
+}
+
+func (cmd *checkUpdatesCmd) checkHelmCharts(ctx context.Context, projectDir string) ([]commands.AvailableUpdate, error) {
+	ociAuthProvider := auth_provider.NewDefaultAuthProviders("KLUCTL_REGISTRY")
+	helmAuthProvider := helm_auth.NewDefaultAuthProviders("KLUCTL_HELM")
+	if x, err := cmd.HelmCredentials.BuildAuthProvider(ctx); err != nil {
+		return nil, err
+	} else {
+		helmAuthProvider.RegisterAuthProvider(x, false)
+	}
+	if x, err := cmd.RegistryCredentials.BuildAuthProvider(ctx); err != nil {
+		return nil, err
+	} else {
+		ociAuthProvider.RegisterAuthProvider(x, false)
+	}
+
+	baseChartsDir := filepath.Join(projectDir, ".helm-charts")
+
+	releases, charts, err := loadHelmReleases(ctx, projectDir, baseChartsDir, helmAuthProvider, ociAuthProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	g := utils.NewGoHelper(ctx, 8)
+	for _, chart := range charts {
+		chart := chart
+		g.RunE(func() error {
+			s := status.Startf(ctx, "%s: Querying versions", chart.GetChartName())
+			defer s.Failed()
+			err := chart.QueryVersions(ctx)
+			if err != nil {
+				s.FailedWithMessagef("%s: %s", chart.GetChartName(), err.Error())
+				return err
+			}
+			s.Success()
+			return nil
+		})
+	}
+	g.Wait()
+	if g.ErrorOrNil() != nil {
+		return nil, g.ErrorOrNil()
+	}
+
+	var ret []commands.AvailableUpdate
+	for _, hr := range releases {
+		relDir, err := filepath.Rel(projectDir, filepath.Dir(hr.ConfigFile))
+		if err != nil {
+			return nil, err
+		}
+
+		if hr.Config.SkipUpdate {
+			status.Tracef(ctx, "%s: Skipping Chart %s as skipUpdate is set", relDir, hr.Chart.GetChartName())
+			continue
+		}
+
+		latestVersion, err := hr.Chart.GetLatestVersion(hr.Config.UpdateConstraints)
+		if err != nil {
+			return nil, err
+		}
+		if u := commands.BuildHelmChartUpdate(relDir, hr.Chart.GetChartName(), hr.Config.ChartVersion, latestVersion); u != nil {
+			ret = append(ret, *u)
+		}
+	}
+	return ret, nil
+}
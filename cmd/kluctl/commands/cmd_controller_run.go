@@ -7,9 +7,9 @@ import (
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/controllers"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/sourceoverride"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/metrics"
-	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -186,6 +186,7 @@ func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 		EventRecorder:         eventRecorder,
 		MetricsRecorder:       metricsRecorder,
 		SshPool:               sshPool,
+		ClusterPool:           &k8s.ClusterPool{},
 	}
 
 	r.ResultStore, err = buildResultStoreRW(ctx, restConfig, mgr.GetRESTMapper(), &cmd.CommandResultFlags, true)
@@ -238,7 +239,7 @@ func (cmd *controllerRunCmd) loadConfig(kubeconfig string, context string) (conf
 
 		defer func() {
 			if configErr != nil {
-				log.Error(err, "unable to load in-cluster config")
+				setupLog.Error(err, "unable to load in-cluster config")
 			}
 		}()
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
 	"github.com/kluctl/kluctl/v2/pkg/prompts"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"time"
 )
 
 type deleteCmd struct {
@@ -16,6 +17,7 @@ type deleteCmd struct {
 	args.ArgsFlags
 	args.ImageFlags
 	args.InclusionFlags
+	args.SelectorFlags
 	args.HelmCredentials
 	args.RegistryCredentials
 	args.YesFlags
@@ -23,10 +25,13 @@ type deleteCmd struct {
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 	args.CommandResultFlags
+	args.MaintenanceWindowFlags
 
 	Discriminator string `group:"misc" help:"Override the discriminator used to find objects for deletion."`
 
 	NoWait bool `group:"misc" help:"Don't wait for deletion of objects to finish.'"`
+
+	NamespaceCleanupTimeout time.Duration `group:"misc" help:"Wait up to the given duration for deleted namespaces to finish terminating. Namespaces that are still terminating afterwards are reported as errors, together with the objects stuck in Terminating and the finalizers responsible. Disabled by default."`
 }
 
 func (cmd *deleteCmd) Help() string {
@@ -45,6 +50,7 @@ func (cmd *deleteCmd) Run(ctx context.Context) error {
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
+		selectorFlags:        cmd.SelectorFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		registryCredentials:  cmd.RegistryCredentials,
 		dryRunArgs:           &cmd.DryRunFlags,
@@ -52,13 +58,19 @@ func (cmd *deleteCmd) Run(ctx context.Context) error {
 		commandResultFlags:   &cmd.CommandResultFlags,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		err := checkMaintenanceWindow(cmdCtx, cmd.OverrideMaintenanceWindow, cmd.DryRun)
+		if err != nil {
+			return err
+		}
+
 		cmd2 := commands.NewDeleteCommand(cmd.Discriminator, cmdCtx.targetCtx, nil, !cmd.NoWait)
+		cmd2.NamespaceCleanupTimeout = cmd.NamespaceCleanupTimeout
 
 		result := cmd2.Run(cmdCtx.targetCtx.SharedContext.Ctx, cmdCtx.targetCtx.SharedContext.K, func(refs []k8s2.ObjectRef) error {
 			return confirmDeletion(ctx, refs, cmd.DryRun, cmd.Yes)
 		})
 
-		err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+		err = outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
 		if err != nil {
 			return err
 		}
@@ -17,23 +17,39 @@ type deployCmd struct {
 	args.ArgsFlags
 	args.ImageFlags
 	args.InclusionFlags
+	args.SelectorFlags
 	args.HelmCredentials
 	args.RegistryCredentials
 	args.YesFlags
 	args.DryRunFlags
 	args.ForceApplyFlags
 	args.ReplaceOnErrorFlags
+	args.AllowBreakingCRDChangesFlags
 	args.AbortOnErrorFlags
 	args.HookFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
+	args.CollectArtifactsFlags
 	args.CommandResultFlags
+	args.DeployStatusFlags
+	args.MaxDeletesFlags
+	args.MaxChangesFlags
+	args.MaintenanceWindowFlags
 
 	DeployExtraFlags
 
-	Discriminator string `group:"misc" help:"Override the target discriminator."`
+	Discriminator    string `group:"misc" help:"Override the target discriminator."`
+	RetryFailedItems int    `group:"misc" help:"Retry deployment items that encountered errors up to the given number of times. Retries happen after all other deployment items have been applied, while still respecting the order and barriers of the deployment items." default:"0"`
+
+	AllowOlderCommit bool `group:"misc" help:"Allow deploying a git commit that is not a descendant of the commit deployed last time (as recorded in the command results), or a dirty working tree on top of a deployment from a clean working tree. Without this flag, such deployments are refused to protect against accidental rollbacks from stale checkouts."`
+
+	ProgressByNamespace bool `group:"misc" help:"Show a single aggregated progress with error/warning counts per namespace instead of one progress line per deployment item and print a per-namespace summary at the end. Useful for targets that span a large number of namespaces."`
+
+	SupportBundle string `group:"misc" help:"If the deployment fails, write a support bundle to the given path. The bundle is a tar.gz archive with the rendered manifests, live state and events of all failed objects, the kluctl logs and version information. Secrets are obfuscated and all known secret values are redacted. The bundle is also written if loading or rendering the project fails."`
 
 	internal bool
+
+	supportBundleRecorder *status.RecordingStatusHandler
 }
 
 type DeployExtraFlags struct {
@@ -49,6 +65,11 @@ It will also output a list of prunable objects (without actually deleting them).
 }
 
 func (cmd *deployCmd) Run(ctx context.Context) error {
+	_, err := parseDeployStatusUploadTargets(cmd.DeployStatusFlags)
+	if err != nil {
+		return err
+	}
+
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
 		kubeconfigFlags:      cmd.KubeconfigFlags,
@@ -56,6 +77,7 @@ func (cmd *deployCmd) Run(ctx context.Context) error {
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
+		selectorFlags:        cmd.SelectorFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		registryCredentials:  cmd.RegistryCredentials,
 		dryRunArgs:           &cmd.DryRunFlags,
@@ -64,15 +86,52 @@ func (cmd *deployCmd) Run(ctx context.Context) error {
 		internalDeploy:       cmd.internal,
 		discriminator:        cmd.Discriminator,
 	}
-	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
-		return cmd.runCmdDeploy(cmdCtx)
+
+	if cmd.SupportBundle != "" {
+		// record all messages, so that they can be included in the support bundle
+		cmd.supportBundleRecorder = status.NewRecordingStatusHandler(status.FromContext(ctx))
+		ctx = status.NewContext(ctx, cmd.supportBundleRecorder)
+	}
+
+	// remember how far the command got, as the support bundle is also written when loading or rendering fails
+	var sbState supportBundleState
+	err = withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		sbState.cmdCtx = cmdCtx
+		var err error
+		sbState.result, err = cmd.runCmdDeploy(cmdCtx)
+		return err
 	})
+	if err != nil && sbState.result == nil && !cmd.DryRun {
+		// the deploy status is only written when a command result is available, so we write a failed status here
+		writeFailedDeployStatus(ctx, cmd.DeployStatusFlags, cmd.TargetFlags, cmd.Discriminator, sbState.cmdCtx, err)
+	}
+	if err != nil && cmd.SupportBundle != "" {
+		writeSupportBundle(ctx, cmd.SupportBundle, cmd.supportBundleRecorder, cmd.TargetFlags, &sbState, err)
+	}
+	return err
 }
 
-func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
+func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) (*result.CommandResult, error) {
 	status.Trace(cmdCtx.ctx, "enter runCmdDeploy")
 	defer status.Trace(cmdCtx.ctx, "leave runCmdDeploy")
 
+	applyDeployDefaults(cmdCtx.ctx, cmdCtx.targetCtx.Target.DeployDefaults, &cmd.ForceApply, &cmd.ReplaceOnError, &cmd.AbortOnError, &cmd.NoWait, &cmd.ReadinessTimeout)
+
+	err := checkMaintenanceWindow(cmdCtx, cmd.OverrideMaintenanceWindow, cmd.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkDeployedCommit(cmdCtx, cmd.AllowOlderCommit, cmd.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	err = collectArtifacts(cmdCtx, cmd.CollectArtifactsFlags)
+	if err != nil {
+		return nil, err
+	}
+
 	cmd2 := commands.NewDeployCommand(cmdCtx.targetCtx)
 	cmd2.ForceApply = cmd.ForceApply
 	cmd2.ReplaceOnError = cmd.ReplaceOnError
@@ -82,6 +141,12 @@ func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
 	cmd2.NoWait = cmd.NoWait
 	cmd2.Prune = cmd.Prune
 	cmd2.WaitPrune = !cmd.NoWait
+	cmd2.MaxDeletes = cmd.GetMaxDeletes()
+	cmd2.MaxChanges = cmd.GetMaxChanges()
+	cmd2.IgnoreLimits = cmd.IgnoreLimits
+	cmd2.RetryFailedItems = cmd.RetryFailedItems
+	cmd2.ProgressByNamespace = cmd.ProgressByNamespace
+	cmd2.AllowBreakingCRDChanges = cmd.AllowBreakingCrdChanges
 
 	cb := func(diffResult *result.CommandResult) error {
 		return cmd.diffResultCb(cmdCtx, diffResult)
@@ -91,14 +156,20 @@ func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
 	}
 
 	result := cmd2.Run(cb)
-	err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+	err = outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
 	if err != nil {
-		return err
+		return result, err
+	}
+	if !cmd.DryRun {
+		err = writeDeployStatus(cmdCtx, cmd.DeployStatusFlags, result)
+		if err != nil {
+			return result, err
+		}
 	}
 	if len(result.Errors) != 0 {
-		return fmt.Errorf("command failed")
+		return result, fmt.Errorf("command failed")
 	}
-	return nil
+	return result, nil
 }
 
 func (cmd *deployCmd) diffResultCb(ctx *commandCtx, diffResult *result.CommandResult) error {
@@ -13,16 +13,19 @@ type diffCmd struct {
 	args.TargetFlags
 	args.ArgsFlags
 	args.InclusionFlags
+	args.SelectorFlags
 	args.ImageFlags
 	args.HelmCredentials
 	args.RegistryCredentials
 	args.ForceApplyFlags
 	args.ReplaceOnErrorFlags
+	args.AllowBreakingCRDChangesFlags
 	args.IgnoreFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 
 	Discriminator string `group:"misc" help:"Override the target discriminator."`
+	Short         bool   `group:"misc" help:"Only print a summary with one line per changed object and the totals per deployment item. This is the same as using the 'summary' output format instead of 'text'."`
 }
 
 func (cmd *diffCmd) Help() string {
@@ -40,12 +43,19 @@ func (cmd *diffCmd) Run(ctx context.Context) error {
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
+		selectorFlags:        cmd.SelectorFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		registryCredentials:  cmd.RegistryCredentials,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
 		discriminator:        cmd.Discriminator,
 	}
+	if cmd.Short {
+		cmd.OutputFormat = replaceTextOutputFormat(cmd.OutputFormat, "summary")
+	}
+
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		applyDeployDefaults(cmdCtx.ctx, cmdCtx.targetCtx.Target.DeployDefaults, &cmd.ForceApply, &cmd.ReplaceOnError, nil, nil, nil)
+
 		cmd2 := commands.NewDiffCommand(cmdCtx.targetCtx)
 		cmd2.ForceApply = cmd.ForceApply
 		cmd2.ReplaceOnError = cmd.ReplaceOnError
@@ -54,6 +64,7 @@ func (cmd *diffCmd) Run(ctx context.Context) error {
 		cmd2.IgnoreLabels = cmd.IgnoreLabels
 		cmd2.IgnoreAnnotations = cmd.IgnoreAnnotations
 		cmd2.IgnoreKluctlMetadata = cmd.IgnoreKluctlMetadata
+		cmd2.AllowBreakingCRDChanges = cmd.AllowBreakingCrdChanges
 		result := cmd2.Run()
 		err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, false)
 		if err != nil {
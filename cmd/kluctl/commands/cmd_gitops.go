@@ -496,6 +496,12 @@ func (g *gitopsCmdHelper) buildOverridePatch(ctx context.Context, kdIn *v1beta1.
 	handleFlag("prune", func(f *flag.Flag) {
 		kd.Spec.Prune = utils.ParseBoolOrFalse(f.Value.String())
 	})
+	handleFlag("override-maintenance-window", func(f *flag.Flag) {
+		kd.Spec.OverrideMaintenanceWindow = utils.ParseBoolOrFalse(f.Value.String())
+	})
+	handleFlag("allow-breaking-crd-changes", func(f *flag.Flag) {
+		kd.Spec.AllowBreakingCRDChanges = utils.ParseBoolOrFalse(f.Value.String())
+	})
 
 	if g.overridableArgs.Target != "" {
 		kd.Spec.Target = &g.overridableArgs.Target
@@ -547,6 +553,10 @@ func (g *gitopsCmdHelper) buildOverridePatch(ctx context.Context, kdIn *v1beta1.
 }
 
 func (g *gitopsCmdHelper) overrideDeploymentArgs(kd *v1beta1.KluctlDeployment) error {
+	if len(g.overridableArgs.ArgsFlags.ItemArg) != 0 {
+		return fmt.Errorf("--item-arg is not supported for GitOps commands")
+	}
+
 	overrideArgs, err := g.overridableArgs.ArgsFlags.LoadArgs()
 	if err != nil {
 		return err
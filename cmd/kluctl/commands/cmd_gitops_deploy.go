@@ -14,7 +14,9 @@ type gitopsDeployCmd struct {
 	args.GitOpsArgs
 	args.OutputFormatFlags
 	args.GitOpsLogArgs
-	args.GitOpsOverridableArgs `groupOverride:"override"`
+	args.GitOpsOverridableArgs        `groupOverride:"override"`
+	args.MaintenanceWindowFlags       `groupOverride:"override"`
+	args.AllowBreakingCRDChangesFlags `groupOverride:"override"`
 
 	DeployExtraFlags `groupOverride:"override"`
 }
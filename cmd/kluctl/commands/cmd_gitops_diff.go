@@ -15,7 +15,8 @@ type gitopsDiffCmd struct {
 	args.GitOpsArgs
 	args.OutputFormatFlags
 	args.GitOpsLogArgs
-	args.GitOpsOverridableArgs `groupOverride:"override"`
+	args.GitOpsOverridableArgs        `groupOverride:"override"`
+	args.AllowBreakingCRDChangesFlags `groupOverride:"override"`
 }
 
 func (cmd *gitopsDiffCmd) Help() string {
@@ -15,6 +15,7 @@ type gitopsPruneCmd struct {
 	args.GitOpsLogArgs
 	args.OutputFormatFlags
 	args.GitOpsOverridableArgs
+	args.MaintenanceWindowFlags `groupOverride:"override"`
 }
 
 func (cmd *gitopsPruneCmd) Help() string {
@@ -13,6 +13,8 @@ type gitopsReconcileCmd struct {
 	args.GitOpsArgs
 	args.GitOpsLogArgs
 	args.GitOpsOverridableArgs
+	args.MaintenanceWindowFlags       `groupOverride:"override"`
+	args.AllowBreakingCRDChangesFlags `groupOverride:"override"`
 
 	DeployExtraFlags `groupOverride:"override"`
 }
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"sync"
+)
+
+type hookLogsCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+
+	Last   bool `group:"misc" help:"Only show the logs of the most recent instance of each hook."`
+	Follow bool `group:"misc" short:"f" help:"Follow the logs of hook containers that are still running."`
+}
+
+func (cmd *hookLogsCmd) Help() string {
+	return `This renders the target to determine all hooks that are implemented as Jobs or Pods and then searches the
+target cluster for the pods created by these hooks. The logs of all containers of these pods are printed, with each
+line being prefixed with the pod and container name. If a container got restarted, the logs of the previous instance
+are printed as well.
+
+Hooks that use metadata.generateName create a new instance on every deployment. Pass --last to only show the logs of
+the most recent instance of each hook. Hooks that were already deleted due to their hook-delete-policy can not be
+shown.`
+}
+
+func (cmd *hookLogsCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:        cmd.ProjectFlags,
+		kubeconfigFlags:     cmd.KubeconfigFlags,
+		targetFlags:         cmd.TargetFlags,
+		argsFlags:           cmd.ArgsFlags,
+		inclusionFlags:      cmd.InclusionFlags,
+		helmCredentials:     cmd.HelmCredentials,
+		registryCredentials: cmd.RegistryCredentials,
+	}
+
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		cmd2, err := commands.NewHookLogsCommand(cmdCtx.targetCtx, cmd.Last)
+		if err != nil {
+			return err
+		}
+		return cmd.doHookLogs(cmdCtx, cmd2)
+	})
+}
+
+func (cmd *hookLogsCmd) doHookLogs(cmdCtx *commandCtx, cmd2 *commands.HookLogsCommand) error {
+	ctx := cmdCtx.ctx
+
+	pods, err := cmd2.FindHookPods(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		status.Info(ctx, "No pods of hooks found")
+		return nil
+	}
+
+	var sources []commands.HookLogSource
+	for _, hp := range pods {
+		sources = append(sources, commands.BuildLogSources(&hp)...)
+	}
+
+	var mutex sync.Mutex
+	printLogs := func(s commands.HookLogSource) {
+		rc, err := cmd2.StreamLogs(ctx, s, cmd.Follow)
+		if err != nil {
+			// e.g. containers that did not start yet
+			status.Warningf(ctx, "Failed to retrieve logs of %s: %s", s.String(), err.Error())
+			return
+		}
+		defer rc.Close()
+
+		sc := bufio.NewScanner(rc)
+		for sc.Scan() {
+			mutex.Lock()
+			_, _ = getStdout(ctx).WriteString(fmt.Sprintf("[%s] %s\n", s.String(), sc.Text()))
+			mutex.Unlock()
+		}
+	}
+
+	if !cmd.Follow {
+		for _, s := range sources {
+			printLogs(s)
+		}
+		return nil
+	}
+
+	// when following, logs of all containers are streamed in parallel, as running containers might never finish
+	gh := utils.NewGoHelper(ctx, 0)
+	for _, s := range sources {
+		s := s
+		gh.RunE(func() error {
+			printLogs(s)
+			return nil
+		})
+	}
+	gh.Wait()
+	return nil
+}
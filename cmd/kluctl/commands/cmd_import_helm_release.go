@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"path/filepath"
+)
+
+type importHelmReleaseCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.YesFlags
+	args.DryRunFlags
+	args.OutputFlags
+
+	Release            string `group:"misc" required:"true" help:"The name of the Helm release to import."`
+	Namespace          string `group:"misc" short:"n" required:"true" help:"The namespace of the Helm release to import."`
+	Repo               string `group:"misc" required:"true" help:"The Helm repository (or OCI url) to pull the chart from. Helm does not store the repository inside the release, so it must be specified."`
+	OutputDir          string `group:"misc" help:"The directory (relative to the project directory) to write the deployment item to. Defaults to the name of the release."`
+	RemoveHelmMetadata bool   `group:"misc" help:"Remove the Helm release secrets and the Helm ownership annotations from all objects of the release."`
+}
+
+func (cmd *importHelmReleaseCmd) Help() string {
+	return `This command reads the currently deployed revision of an existing Helm release and generates a
+deployment item (helm-chart.yaml, helm-values.yaml and kustomization.yaml) which renders the same
+chart version with the same values. All live objects of the release are then labeled with the
+discriminator of the target, so that kluctl can take over management of the release.
+
+The generated deployment item must be added to the deployments list of the corresponding
+deployment.yaml manually.
+
+If '--remove-helm-metadata' is specified, the Helm release secrets and the Helm ownership
+annotations are removed, meaning that Helm will not know about the release anymore. The
+'app.kubernetes.io/managed-by: Helm' label is kept, as charts usually render it and the next
+deployment would re-add it anyway.
+
+When '--dry-run' is specified, no files are written and all changes to the cluster are only
+performed as server-side dry-runs.`
+}
+
+func (cmd *importHelmReleaseCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:        cmd.ProjectFlags,
+		kubeconfigFlags:     cmd.KubeconfigFlags,
+		targetFlags:         cmd.TargetFlags,
+		argsFlags:           cmd.ArgsFlags,
+		helmCredentials:     cmd.HelmCredentials,
+		registryCredentials: cmd.RegistryCredentials,
+		dryRunArgs:          &cmd.DryRunFlags,
+	}
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		projectDir := cmdCtx.targetCtx.KluctlProject.LoadArgs.ProjectDir
+
+		outputDir := cmd.OutputDir
+		if outputDir == "" {
+			outputDir = cmd.Release
+		}
+		if filepath.IsAbs(outputDir) {
+			return fmt.Errorf("--output-dir must be relative to the project directory")
+		}
+		outputDir = filepath.Join(projectDir, outputDir)
+		err := utils.CheckInDir(projectDir, outputDir)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Yes && !cmd.DryRun {
+			if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to import the Helm release %s/%s on the context/cluster %s?", cmd.Namespace, cmd.Release, cmdCtx.targetCtx.ClusterContext)) {
+				return fmt.Errorf("aborted")
+			}
+		}
+
+		cmd2 := commands.NewImportHelmReleaseCommand(cmdCtx.targetCtx)
+		cmd2.ReleaseName = cmd.Release
+		cmd2.Namespace = cmd.Namespace
+		cmd2.Repo = cmd.Repo
+		cmd2.OutputDir = outputDir
+		cmd2.RemoveHelmMetadata = cmd.RemoveHelmMetadata
+		cmd2.DryRun = cmd.DryRun
+
+		result, err := cmd2.Run()
+		if result != nil {
+			err2 := outputYamlResult(ctx, cmd.Output, result, false)
+			if err2 != nil {
+				return err2
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+)
+
+type ownershipCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.SelectorFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.OutputFlags
+	args.RenderOutputDirFlags
+
+	Ref   []string `group:"misc" help:"Only report the given object, in the form group/Kind/namespace/name or group/Kind/name for cluster scoped objects. Use 'core' or an empty group for core objects. Can be specified multiple times. If omitted, all objects of the target are reported."`
+	Depth int      `group:"misc" help:"Truncate reported fields to the given depth, e.g. 2 reports fields like '.spec.replicas'. Use 0 to report all fields." default:"2"`
+}
+
+func (cmd *ownershipCmd) Help() string {
+	return `This command will render the target, retrieve all objects from the cluster and print a report about
+which field managers own which fields, based on the managedFields of the objects. This helps to decide
+where force-apply or ignore rules for conflict resolution are needed.`
+}
+
+func (cmd *ownershipCmd) Run(ctx context.Context) error {
+	var refs []k8s.ObjectRef
+	for _, s := range cmd.Ref {
+		r, err := k8s.ParseObjectRef(s)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, r)
+	}
+
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		kubeconfigFlags:      cmd.KubeconfigFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		selectorFlags:        cmd.SelectorFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		cmd2 := commands.NewOwnershipCommand(cmdCtx.targetCtx)
+		cmd2.Refs = refs
+		cmd2.Depth = cmd.Depth
+
+		result, err := cmd2.Run()
+		if err != nil {
+			return err
+		}
+		return outputYamlResult(ctx, cmd.Output, result, false)
+	})
+}
@@ -22,6 +22,8 @@ type pokeImagesCmd struct {
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 	args.CommandResultFlags
+	args.MaintenanceWindowFlags
+	args.AllowBreakingCRDChangesFlags
 }
 
 func (cmd *pokeImagesCmd) Help() string {
@@ -45,6 +47,11 @@ func (cmd *pokeImagesCmd) Run(ctx context.Context) error {
 		commandResultFlags:   &cmd.CommandResultFlags,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		err := checkMaintenanceWindow(cmdCtx, cmd.OverrideMaintenanceWindow, cmd.DryRun)
+		if err != nil {
+			return err
+		}
+
 		if !cmd.Yes && !cmd.DryRun {
 			if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to poke images to the context/cluster %s?", cmdCtx.targetCtx.ClusterContext)) {
 				return fmt.Errorf("aborted")
@@ -52,9 +59,10 @@ func (cmd *pokeImagesCmd) Run(ctx context.Context) error {
 		}
 
 		cmd2 := commands.NewPokeImagesCommand(cmdCtx.targetCtx)
+		cmd2.AllowBreakingCRDChanges = cmd.AllowBreakingCrdChanges
 
 		result := cmd2.Run()
-		err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+		err = outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
 		if err != nil {
 			return err
 		}
@@ -22,6 +22,8 @@ type pruneCmd struct {
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 	args.CommandResultFlags
+	args.MaxDeletesFlags
+	args.MaintenanceWindowFlags
 
 	Discriminator string `group:"misc" help:"Override the target discriminator."`
 }
@@ -55,11 +57,18 @@ func (cmd *pruneCmd) Run(ctx context.Context) error {
 }
 
 func (cmd *pruneCmd) runCmdPrune(cmdCtx *commandCtx) error {
+	err := checkMaintenanceWindow(cmdCtx, cmd.OverrideMaintenanceWindow, cmd.DryRun)
+	if err != nil {
+		return err
+	}
+
 	cmd2 := commands.NewPruneCommand(cmdCtx.targetCtx.Target.Discriminator, cmdCtx.targetCtx, true)
+	cmd2.MaxDeletes = cmd.GetMaxDeletes()
+	cmd2.IgnoreLimits = cmd.IgnoreLimits
 	result := cmd2.Run(func(refs []k8s2.ObjectRef) error {
 		return confirmDeletion(cmdCtx.ctx, refs, cmd.DryRun, cmd.Yes)
 	})
-	err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+	err = outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
 	if err != nil {
 		return err
 	}
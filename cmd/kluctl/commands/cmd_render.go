@@ -6,6 +6,7 @@ import (
 	"github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
+	yaml3 "gopkg.in/yaml.v3"
 	"io/ioutil"
 	"os"
 )
@@ -20,9 +21,11 @@ type renderCmd struct {
 	args.HelmCredentials
 	args.RegistryCredentials
 	args.RenderOutputDirFlags
+	args.CollectArtifactsFlags
 	args.OfflineKubernetesFlags
 
-	PrintAll bool `group:"misc" help:"Write all rendered manifests to stdout"`
+	PrintAll           bool `group:"misc" help:"Write all rendered manifests to stdout"`
+	PreserveYamlFormat bool `group:"misc" help:"Preserve key order, comments, anchors and merge keys of the source manifests when writing rendered manifests. Useful when the rendered manifests are committed back to Git."`
 }
 
 func (cmd *renderCmd) Help() string {
@@ -53,9 +56,29 @@ func (cmd *renderCmd) Run(ctx context.Context) error {
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
 		offlineKubernetes:    cmd.OfflineKubernetes,
 		kubernetesVersion:    cmd.KubernetesVersion,
+		preserveYamlFormat:   cmd.PreserveYamlFormat,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
-		if cmd.PrintAll {
+		err := collectArtifacts(cmdCtx, cmd.CollectArtifactsFlags)
+		if err != nil {
+			return err
+		}
+
+		if cmd.PrintAll && cmd.PreserveYamlFormat {
+			var all []*yaml3.Node
+			for _, d := range cmdCtx.targetCtx.DeploymentCollection.Deployments {
+				nodes, err := d.BuildRenderedYamlNodes()
+				if err != nil {
+					return err
+				}
+				all = append(all, nodes...)
+			}
+			if isTmp {
+				defer os.RemoveAll(cmd.RenderOutputDir)
+			}
+			status.Flush(cmdCtx.ctx)
+			return yaml.WriteYamlNodesStream(getStdout(ctx), all)
+		} else if cmd.PrintAll {
 			var all []any
 			for _, d := range cmdCtx.targetCtx.DeploymentCollection.Deployments {
 				for _, o := range d.Objects {
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+)
+
+type takeOwnershipCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.YesFlags
+	args.DryRunFlags
+	args.OutputFlags
+	args.RenderOutputDirFlags
+
+	Ref   []string `group:"misc" required:"true" help:"The object to take ownership of, in the form group/Kind/namespace/name or group/Kind/name for cluster scoped objects. Use 'core' or an empty group for core objects. Can be specified multiple times."`
+	Field []string `group:"misc" help:"Only take ownership of fields matching the given JSON path, e.g. 'spec.replicas'. Can be specified multiple times. If omitted, ownership of all fields is taken."`
+}
+
+func (cmd *takeOwnershipCmd) Help() string {
+	return `This command will fully render the target and then perform a forced server-side apply of the
+selected objects, so that kluctl becomes the owner of all conflicting fields. This is a targeted
+alternative to '--force-apply', which would force-apply all objects of the target.
+
+A report is printed which lists the fields that were taken over and the field managers they were
+taken from.`
+}
+
+func (cmd *takeOwnershipCmd) Run(ctx context.Context) error {
+	var refs []k8s.ObjectRef
+	for _, s := range cmd.Ref {
+		r, err := k8s.ParseObjectRef(s)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, r)
+	}
+
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		kubeconfigFlags:      cmd.KubeconfigFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		dryRunArgs:           &cmd.DryRunFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		if !cmd.Yes && !cmd.DryRun {
+			if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to take ownership of %d objects on the context/cluster %s?", len(refs), cmdCtx.targetCtx.ClusterContext)) {
+				return fmt.Errorf("aborted")
+			}
+		}
+
+		cmd2 := commands.NewTakeOwnershipCommand(cmdCtx.targetCtx)
+		cmd2.Refs = refs
+		cmd2.Fields = cmd.Field
+
+		result, err := cmd2.Run()
+		if result != nil {
+			err2 := outputYamlResult(ctx, cmd.Output, result, false)
+			if err2 != nil {
+				return err2
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"time"
+)
+
+type watchCmd struct {
+	args.ProjectFlags
+	args.KubeconfigFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+	args.SelectorFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.RenderOutputDirFlags
+
+	Interval     time.Duration `group:"misc" help:"Interval between status updates" default:"5s"`
+	EventsSince  time.Duration `group:"misc" help:"Also show events that happened in the given duration before watching started" default:"5m"`
+	WatchTimeout time.Duration `group:"misc" help:"Stop watching after the given duration. Watching is not limited by default." default:"0"`
+}
+
+func (cmd *watchCmd) Help() string {
+	return `This continuously retrieves all objects of the target from the cluster and prints their readiness whenever it
+changes. Events emitted for these objects are printed as well. This is useful to monitor a target right after a deploy.
+
+Watching continues until kluctl is interrupted or, if specified, until the duration passed via --watch-timeout
+has passed. The project wide --timeout (which defaults to 10m) is not applied to this command.`
+}
+
+func (cmd *watchCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		kubeconfigFlags:      cmd.KubeconfigFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		selectorFlags:        cmd.SelectorFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	// the project timeout would stop watching after 10 minutes by default, so --watch-timeout is used instead
+	ptArgs.projectFlags.Timeout = 0
+
+	if cmd.WatchTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.WatchTimeout)
+		defer cancel()
+	}
+
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		cmd2 := commands.NewWatchCommand("", cmdCtx.targetCtx, time.Now().Add(-cmd.EventsSince))
+		return cmd.doWatch(cmdCtx, cmd2)
+	})
+}
+
+func (cmd *watchCmd) doWatch(ctx *commandCtx, cmd2 *commands.WatchCommand) error {
+	for {
+		r, err := cmd2.Poll(ctx.ctx)
+		if err != nil {
+			if ctx.ctx.Err() != nil {
+				// --watch-timeout reached or interrupted
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now().Format(time.RFC3339)
+		for _, s := range r.ChangedObjects {
+			line := fmt.Sprintf("%s %-8s %s", now, s.State, s.Ref.String())
+			if s.Message != "" {
+				line += ": " + s.Message
+			}
+			_, _ = getStdout(ctx.ctx).WriteString(line + "\n")
+		}
+		for _, e := range r.Events {
+			line := fmt.Sprintf("%s %-8s %s: %s: %s", e.Time.Format(time.RFC3339), e.Type, e.Ref.String(), e.Reason, e.Message)
+			if e.Count > 1 {
+				line += fmt.Sprintf(" (x%d)", e.Count)
+			}
+			_, _ = getStdout(ctx.ctx).WriteString(line + "\n")
+		}
+
+		select {
+		case <-ctx.ctx.Done():
+			return nil
+		case <-time.After(cmd.Interval):
+		}
+	}
+}
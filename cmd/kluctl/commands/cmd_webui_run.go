@@ -18,6 +18,8 @@ type webuiRunCmd struct {
 	Port       int    `group:"misc" help:"Port to bind to." default:"8080"`
 	PathPrefix string `group:"misc" help:"Specify the prefix of the path to serve the webui on. This is required when using a reverse proxy, ingress or gateway that serves the webui on another path than /." default:"/"`
 
+	MetricsBindAddress string `group:"misc" help:"The address the metrics endpoint binds to. Metrics are served on a separate port so that they are not exposed together with the webui. Pass '0' to disable the metrics endpoint." default:"0"`
+
 	Kubeconfig  args.ExistingFileType `group:"misc" help:"Overrides the kubeconfig to use."`
 	Context     []string              `group:"misc" help:"List of kubernetes contexts to use."`
 	AllContexts bool                  `group:"misc" help:"Use all Kubernetes contexts found in the kubeconfig."`
@@ -159,5 +161,5 @@ func (cmd *webuiRunCmd) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.Run(cmd.Host, cmd.Port, isTerminal)
+	return server.Run(cmd.Host, cmd.Port, isTerminal, cmd.MetricsBindAddress)
 }
@@ -249,6 +249,21 @@ func copyViperValuesToCobraCmd(cmd *cobra.Command) error {
 	return nil
 }
 
+func buildFlagEnvName(flagName string) string {
+	envName := strings.ReplaceAll(flagName, "-", "_")
+	envName = strings.ToUpper(envName)
+	return fmt.Sprintf("KLUCTL_%s", envName)
+}
+
+// isFlagSetViaEnvOrConfig returns true if the flag's value was provided via the kluctl config or a KLUCTL_XXX
+// environment variable. copyViperValuesToCobraFlags does not mark such flags as changed.
+func isFlagSetViaEnvOrConfig(flag *pflag.Flag) bool {
+	if a := flag.Annotations["skipenv"]; len(a) != 0 && a[0] == "true" {
+		return false
+	}
+	return viper.IsSet(flag.Name) || len(envutils.ParseEnvConfigList(buildFlagEnvName(flag.Name))) != 0
+}
+
 func copyViperValuesToCobraFlags(flags *pflag.FlagSet) error {
 	var errs *multierror.Error
 	flags.VisitAll(func(flag *pflag.Flag) {
@@ -285,11 +300,7 @@ func copyViperValuesToCobraFlags(flags *pflag.FlagSet) error {
 			}
 		}
 
-		envName := strings.ReplaceAll(flag.Name, "-", "_")
-		envName = strings.ToUpper(envName)
-		envName = fmt.Sprintf("KLUCTL_%s", envName)
-
-		for _, v := range envutils.ParseEnvConfigList(envName) {
+		for _, v := range envutils.ParseEnvConfigList(buildFlagEnvName(flag.Name)) {
 			a = append(a, v)
 		}
 
@@ -8,11 +8,14 @@ import (
 	"github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/telemetry"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"io"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -24,8 +27,12 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 	var deletedObjects []k8s.ObjectRef
 	var orphanObjects []k8s.ObjectRef
 	var appliedHookObjects []k8s.ObjectRef
+	dryRunObjects := map[k8s.ObjectRef]bool{}
 
 	for _, o := range cr.Objects {
+		if o.DryRun {
+			dryRunObjects[o.Ref] = true
+		}
 		if o.New {
 			newObjects = append(newObjects, o.Ref)
 		}
@@ -43,13 +50,15 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 		}
 	}
 
+	prettyChangeImpact(buf, cr.ChangeImpact)
+
 	if len(newObjects) != 0 {
 		buf.WriteString("\nNew objects:\n")
-		prettyObjectRefs(buf, newObjects)
+		prettyObjectRefsWithSuffix(buf, newObjects, dryRunObjects, " (dry-run, would be created)")
 	}
 	if len(changedObjects) != 0 {
 		buf.WriteString("\nChanged objects:\n")
-		prettyObjectRefs(buf, changedObjects)
+		prettyObjectRefsWithSuffix(buf, changedObjects, dryRunObjects, " (dry-run, would change)")
 
 		if !short {
 			buf.WriteString("\n")
@@ -88,13 +97,26 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 		buf.WriteString("\nErrors:\n")
 		prettyErrors(buf, cr.Errors)
 	}
+	if len(cr.ErrorHints) != 0 {
+		buf.WriteString("\nHints:\n")
+		prettyErrorHints(buf, cr.ErrorHints)
+	}
 
 	return buf.String()
 }
 
 func prettyObjectRefs(buf io.StringWriter, refs []k8s.ObjectRef) {
+	prettyObjectRefsWithSuffix(buf, refs, nil, "")
+}
+
+// prettyObjectRefsWithSuffix appends the given suffix to all refs that are marked
+func prettyObjectRefsWithSuffix(buf io.StringWriter, refs []k8s.ObjectRef, marked map[k8s.ObjectRef]bool, suffix string) {
 	for _, ref := range refs {
-		_, _ = buf.WriteString(fmt.Sprintf("  %s\n", ref.String()))
+		s := ""
+		if marked[ref] {
+			s = suffix
+		}
+		_, _ = buf.WriteString(fmt.Sprintf("  %s%s\n", ref.String(), s))
 	}
 }
 
@@ -108,6 +130,38 @@ func prettyErrors(buf io.StringWriter, errors []result.DeploymentError) {
 	}
 }
 
+func prettyErrorHints(buf io.StringWriter, hints []result.ErrorHint) {
+	for _, h := range hints {
+		_, _ = buf.WriteString(fmt.Sprintf("  %s: %s\n", h.Category, h.Hint))
+		if len(h.Flags) != 0 {
+			_, _ = buf.WriteString(fmt.Sprintf("    Relevant flags: %s\n", strings.Join(h.Flags, ", ")))
+		}
+		for _, ref := range h.Refs {
+			_, _ = buf.WriteString(fmt.Sprintf("    %s\n", ref.String()))
+		}
+	}
+}
+
+func prettyChangeImpact(buf io.StringWriter, ci *result.ChangeImpact) {
+	if ci == nil || ci.Score == 0 {
+		return
+	}
+	_, _ = buf.WriteString(fmt.Sprintf("\nChange impact: %s (score %d)\n", strings.ToUpper(string(ci.Level)), ci.Score))
+	var t utils.PrettyTable
+	t.AddRow("Category", "Count")
+	add := func(name string, cnt int) {
+		if cnt != 0 {
+			t.AddRow(name, fmt.Sprint(cnt))
+		}
+	}
+	add("Workload restarts", ci.WorkloadRestarts)
+	add("Secret changes", ci.SecretChanges)
+	add("RBAC changes", ci.RbacChanges)
+	add("CRD changes", ci.CrdChanges)
+	add("Deletions", ci.Deletions)
+	_, _ = buf.WriteString(t.Render([]int{-1, -1}))
+}
+
 func prettyChanges(buf io.StringWriter, ref k8s.ObjectRef, changes []result.Change) {
 	_, _ = buf.WriteString(fmt.Sprintf("Diff for object %s\n", ref.String()))
 
@@ -129,10 +183,119 @@ func formatCommandResultYaml(cr *result.CommandResult) (string, error) {
 	return b, nil
 }
 
+func getResultObjectItemDir(o result.ResultObject) string {
+	for _, x := range []*uo.UnstructuredObject{o.Rendered, o.Applied, o.Remote} {
+		if x == nil {
+			continue
+		}
+		if itemDir := x.GetK8sAnnotation("kluctl.io/deployment-item-dir"); itemDir != nil {
+			return *itemDir
+		}
+	}
+	return "<none>"
+}
+
+type itemSummary struct {
+	new, changed, deleted, orphan, fields int
+}
+
+// formatCommandResultSummary prints one line per new/changed/deleted/orphan object and the totals per deployment item.
+// This is meant for quick reviews, e.g. in PR comments, where full diffs are too long.
+func formatCommandResultSummary(cr *result.CommandResult) string {
+	buf := bytes.NewBuffer(nil)
+
+	objects := append([]result.ResultObject(nil), cr.Objects...)
+	sort.SliceStable(objects, func(i, j int) bool {
+		a, b := getResultObjectItemDir(objects[i]), getResultObjectItemDir(objects[j])
+		if a != b {
+			return a < b
+		}
+		return objects[i].Ref.Less(objects[j].Ref)
+	})
+
+	var t utils.PrettyTable
+	t.AddRow("Item", "Object", "Change", "Fields")
+	items := map[string]*itemSummary{}
+	var itemNames []string
+	for _, o := range objects {
+		var change string
+		switch {
+		case o.New:
+			change = "new"
+		case o.Deleted:
+			change = "deleted"
+		case len(o.Changes) != 0:
+			change = "changed"
+		case o.Orphan:
+			change = "orphan"
+		default:
+			continue
+		}
+
+		itemDir := getResultObjectItemDir(o)
+		s, ok := items[itemDir]
+		if !ok {
+			s = &itemSummary{}
+			items[itemDir] = s
+			itemNames = append(itemNames, itemDir)
+		}
+
+		fields := ""
+		switch change {
+		case "new":
+			s.new++
+		case "deleted":
+			s.deleted++
+		case "changed":
+			s.changed++
+			s.fields += len(o.Changes)
+			fields = fmt.Sprintf("%d", len(o.Changes))
+		case "orphan":
+			s.orphan++
+		}
+		t.AddRow(itemDir, o.Ref.String(), change, fields)
+	}
+
+	prettyChangeImpact(buf, cr.ChangeImpact)
+
+	if len(itemNames) == 0 {
+		buf.WriteString("\nNo changes.\n")
+	} else {
+		buf.WriteString("\nChanges:\n")
+		buf.WriteString(t.Render([]int{-1, -1, -1, -1}))
+
+		var t2 utils.PrettyTable
+		t2.AddRow("Item", "New", "Changed", "Changed fields", "Deleted", "Orphan")
+		for _, n := range itemNames {
+			s := items[n]
+			t2.AddRow(n, fmt.Sprint(s.new), fmt.Sprint(s.changed), fmt.Sprint(s.fields), fmt.Sprint(s.deleted), fmt.Sprint(s.orphan))
+		}
+		buf.WriteString("\nTotals per item:\n")
+		buf.WriteString(t2.Render([]int{-1, -1, -1, -1, -1, -1}))
+	}
+
+	if len(cr.Warnings) != 0 {
+		buf.WriteString("\nWarnings:\n")
+		prettyErrors(buf, cr.Warnings)
+	}
+	if len(cr.Errors) != 0 {
+		buf.WriteString("\nErrors:\n")
+		prettyErrors(buf, cr.Errors)
+	}
+	if len(cr.ErrorHints) != 0 {
+		buf.WriteString("\nHints:\n")
+		prettyErrorHints(buf, cr.ErrorHints)
+	}
+
+	return buf.String()
+}
+
 func formatCommandResult(cr *result.CommandResult, format string, short bool) (string, error) {
 	switch format {
 	case "text":
 		return formatCommandResultText(cr, short), nil
+	case "summary":
+		return formatCommandResultSummary(cr), nil
 	case "yaml":
 		return formatCommandResultYaml(cr)
 	default:
@@ -197,6 +360,21 @@ func formatValidateResult(vr *result.ValidateResult, format string) (string, err
 	}
 }
 
+// replaceTextOutputFormat replaces all 'text' output formats (including the implicit default) with the given format.
+func replaceTextOutputFormat(output []string, format string) []string {
+	if len(output) == 0 {
+		return []string{format}
+	}
+	ret := make([]string, 0, len(output))
+	for _, o := range output {
+		if o == "text" || strings.HasPrefix(o, "text=") {
+			o = format + strings.TrimPrefix(o, "text")
+		}
+		ret = append(ret, o)
+	}
+	return ret
+}
+
 func outputHelper(ctx context.Context, output []string, cb func(format string) (string, error)) error {
 	if len(output) == 0 {
 		output = []string{"text"}
@@ -267,6 +445,8 @@ func outputCommandResult(ctx *commandCtx, flags args.OutputFormatFlags, cr *resu
 }
 
 func outputCommandResult2(ctx context.Context, flags args.OutputFormatFlags, cr *result.CommandResult) error {
+	telemetry.FromContext(ctx).AddCommandResult(cr)
+
 	status.Flush(ctx)
 	err := outputHelper(ctx, flags.OutputFormat, func(format string) (string, error) {
 		return formatCommandResult(cr, format, flags.ShortOutput)
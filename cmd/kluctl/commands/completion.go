@@ -5,6 +5,7 @@ import (
 	"github.com/kluctl/kluctl/lib/status"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/spf13/cobra"
@@ -92,7 +93,10 @@ func buildTargetCompletionFunc(ctx context.Context, projectArgs *args.ProjectFla
 }
 
 func buildAutocompleteProjectTargetCommandArgs(cmdStruct interface{}) projectTargetCommandArgs {
-	ptArgs := projectTargetCommandArgs{}
+	ptArgs := projectTargetCommandArgs{
+		// completion handles all targets in parallel, so clients and discovery are shared between targets
+		clusterPool: &k8s.ClusterPool{},
+	}
 
 	cmdV := reflect.ValueOf(cmdStruct).Elem()
 	if cmdV.FieldByName("ProjectFlags").IsValid() {
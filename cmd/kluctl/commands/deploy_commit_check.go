@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	git2 "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kluctl/kluctl/lib/git"
+	gittypes "github.com/kluctl/kluctl/lib/git/types"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"strings"
+)
+
+// findLastDeployResult returns the summary of the most recent non-dry-run deployment of the current target which
+// recorded a git commit, or nil if there is none.
+func findLastDeployResult(cmdCtx *commandCtx, projectKey gittypes.ProjectKey) (*result.CommandResultSummary, error) {
+	targetCtx := cmdCtx.targetCtx
+
+	clusterId, err := targetCtx.SharedContext.K.GetClusterId()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := cmdCtx.resultStore.ListCommandResultSummaries(results.ListResultSummariesOptions{
+		ProjectFilter: &projectKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// summaries are sorted with the newest being the first
+	for _, s := range summaries {
+		if s.Command.Command != "deploy" || s.Command.DryRun || s.GitInfo.Commit == "" {
+			continue
+		}
+		if s.ProjectKey != projectKey || s.TargetKey.TargetName != targetCtx.Target.Name ||
+			s.TargetKey.Discriminator != targetCtx.Target.Discriminator || s.TargetKey.ClusterId != clusterId {
+			continue
+		}
+		return &s, nil
+	}
+	return nil, nil
+}
+
+// checkDeployedCommit compares the local git state with the commit of the last deployment of the current target, as
+// recorded in the result store. Deploying a commit that is not a descendant of the last deployed commit (e.g. because
+// of a stale checkout) or deploying a dirty working tree on top of a clean deployment is refused unless
+// allowOlderCommit is set.
+func checkDeployedCommit(cmdCtx *commandCtx, allowOlderCommit bool, dryRun bool) error {
+	if cmdCtx.resultStore == nil || cmdCtx.targetCtx.SharedContext.K == nil {
+		return nil
+	}
+	ctx := cmdCtx.ctx
+	repoRoot := cmdCtx.targetCtx.KluctlProject.LoadArgs.RepoRoot
+
+	gitInfo, projectKey, err := git.BuildGitInfo(ctx, repoRoot, cmdCtx.targetCtx.KluctlProject.LoadArgs.ProjectDir)
+	if err != nil || gitInfo.Commit == "" {
+		// not a git repository, so there is nothing to compare
+		return nil
+	}
+
+	last, err := findLastDeployResult(cmdCtx, projectKey)
+	if err != nil {
+		status.Warningf(ctx, "Failed to determine the last deployed commit: %s", err.Error())
+		return nil
+	}
+	if last == nil {
+		return nil
+	}
+
+	return checkGitInfoAgainstLastDeploy(ctx, repoRoot, gitInfo, last, allowOlderCommit, dryRun)
+}
+
+// checkGitInfoAgainstLastDeploy performs the actual comparison for checkDeployedCommit.
+func checkGitInfoAgainstLastDeploy(ctx context.Context, repoRoot string, gitInfo gittypes.GitInfo, last *result.CommandResultSummary, allowOlderCommit bool, dryRun bool) error {
+	var problems []string
+	if last.GitInfo.Commit != gitInfo.Commit {
+		fastForward, err := isAncestorCommit(repoRoot, last.GitInfo.Commit, gitInfo.Commit)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("the last deployed commit %s is not known locally (%s), which usually means that your checkout is outdated. Try to fetch/pull first", last.GitInfo.Commit, err.Error()))
+		} else if !fastForward {
+			problems = append(problems, fmt.Sprintf("the current commit %s is not a descendant of the last deployed commit %s, meaning that changes deployed before would be rolled back", gitInfo.Commit, last.GitInfo.Commit))
+		}
+	}
+	if gitInfo.Dirty && !last.GitInfo.Dirty {
+		problems = append(problems, "the working tree contains uncommitted changes, while the last deployment was done from a clean working tree")
+	} else if gitInfo.Dirty {
+		status.Warning(ctx, "Deploying a working tree with uncommitted changes")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("WARNING: The last deployment of this target happened at %s from commit %s, but %s.",
+		last.Command.StartTime.String(), last.GitInfo.Commit, strings.Join(problems, " and "))
+	status.Warning(ctx, msg)
+
+	if allowOlderCommit || dryRun {
+		return nil
+	}
+	return fmt.Errorf("refusing to deploy, as this might roll back changes deployed before. Pass --allow-older-commit if this is intended")
+}
+
+// isAncestorCommit returns true if ancestor is the same as or an ancestor of commit.
+func isAncestorCommit(repoRoot string, ancestor string, commit string) (bool, error) {
+	g, err := git2.PlainOpen(repoRoot)
+	if err != nil {
+		return false, err
+	}
+	ancestorCommit, err := g.CommitObject(plumbing.NewHash(ancestor))
+	if err != nil {
+		return false, err
+	}
+	c, err := g.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return false, err
+	}
+	return ancestorCommit.IsAncestor(c)
+}
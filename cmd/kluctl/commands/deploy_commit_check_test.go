@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	git2 "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gittypes "github.com/kluctl/kluctl/lib/git/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// prepareCommitCheckRepo creates a repository with the history c1 <- c2 on master and c1 <- c3 on a side branch
+func prepareCommitCheckRepo(t *testing.T) (string, string, string, string) {
+	dir := t.TempDir()
+	r, err := git2.PlainInit(dir, false)
+	assert.NoError(t, err)
+	w, err := r.Worktree()
+	assert.NoError(t, err)
+
+	commit := func(name string) string {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600)
+		assert.NoError(t, err)
+		_, err = w.Add(name)
+		assert.NoError(t, err)
+		h, err := w.Commit(name, &git2.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "no@mail.com", When: time.Now()},
+		})
+		assert.NoError(t, err)
+		return h.String()
+	}
+
+	c1 := commit("c1")
+	c2 := commit("c2")
+
+	err = w.Checkout(&git2.CheckoutOptions{
+		Hash:   plumbing.NewHash(c1),
+		Branch: plumbing.NewBranchReferenceName("side"),
+		Create: true,
+	})
+	assert.NoError(t, err)
+	c3 := commit("c3")
+
+	return dir, c1, c2, c3
+}
+
+func TestIsAncestorCommit(t *testing.T) {
+	dir, c1, c2, c3 := prepareCommitCheckRepo(t)
+
+	ok, err := isAncestorCommit(dir, c1, c2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = isAncestorCommit(dir, c2, c2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = isAncestorCommit(dir, c2, c1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = isAncestorCommit(dir, c2, c3)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = isAncestorCommit(dir, "0123456789012345678901234567890123456789", c2)
+	assert.Error(t, err)
+}
+
+func TestCheckGitInfoAgainstLastDeploy(t *testing.T) {
+	dir, c1, c2, c3 := prepareCommitCheckRepo(t)
+	ctx := context.Background()
+
+	buildLast := func(commit string, dirty bool) *result.CommandResultSummary {
+		return &result.CommandResultSummary{
+			GitInfo: gittypes.GitInfo{Commit: commit, Dirty: dirty},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		current gittypes.GitInfo
+		last    *result.CommandResultSummary
+		wantErr bool
+	}{
+		{name: "same commit", current: gittypes.GitInfo{Commit: c2}, last: buildLast(c2, false)},
+		{name: "fast-forward", current: gittypes.GitInfo{Commit: c2}, last: buildLast(c1, false)},
+		{name: "rollback", current: gittypes.GitInfo{Commit: c1}, last: buildLast(c2, false), wantErr: true},
+		{name: "diverged", current: gittypes.GitInfo{Commit: c3}, last: buildLast(c2, false), wantErr: true},
+		{name: "unknown commit", current: gittypes.GitInfo{Commit: c2}, last: buildLast("0123456789012345678901234567890123456789", false), wantErr: true},
+		{name: "dirty over clean", current: gittypes.GitInfo{Commit: c2, Dirty: true}, last: buildLast(c2, false), wantErr: true},
+		{name: "dirty over dirty", current: gittypes.GitInfo{Commit: c2, Dirty: true}, last: buildLast(c2, true)},
+		{name: "clean over dirty", current: gittypes.GitInfo{Commit: c2}, last: buildLast(c2, true)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkGitInfoAgainstLastDeploy(ctx, dir, tc.current, tc.last, false, false)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "--allow-older-commit")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// --allow-older-commit and dry-runs only warn
+			assert.NoError(t, checkGitInfoAgainstLastDeploy(ctx, dir, tc.current, tc.last, true, false))
+			assert.NoError(t, checkGitInfoAgainstLastDeploy(ctx, dir, tc.current, tc.last, false, true))
+		})
+	}
+}
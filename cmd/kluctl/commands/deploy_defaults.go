@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"time"
+)
+
+// applyDeployDefaults sets all given flag values to the defaults configured via deployDefaults in the target or
+// .kluctl.yaml, unless the corresponding flag was explicitly passed, either on the command line, via environment
+// variables or via the kluctl config. Flags not supported by the command are passed as nil.
+func applyDeployDefaults(ctx context.Context, d *types.DeployDefaults, forceApply *bool, replaceOnError *bool, abortOnError *bool, noWait *bool, readinessTimeout *time.Duration) {
+	if d == nil {
+		return
+	}
+
+	cobraCmd := getCobraCommand(ctx)
+	isChanged := func(name string) bool {
+		if cobraCmd == nil {
+			return false
+		}
+		f := cobraCmd.Flag(name)
+		return f != nil && (f.Changed || isFlagSetViaEnvOrConfig(f))
+	}
+	setBool := func(name string, dst *bool, v *bool) {
+		if dst != nil && v != nil && !isChanged(name) {
+			*dst = *v
+		}
+	}
+
+	setBool("force-apply", forceApply, d.ForceApply)
+	setBool("replace-on-error", replaceOnError, d.ReplaceOnError)
+	setBool("abort-on-error", abortOnError, d.AbortOnError)
+	setBool("no-wait", noWait, d.NoWait)
+	if readinessTimeout != nil && d.WaitTimeout != nil && !isChanged("readiness-timeout") {
+		*readinessTimeout = d.WaitTimeout.Duration
+	}
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/deploystatus"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func parseDeployStatusUploadTargets(flags args.DeployStatusFlags) ([]*deploystatus.UploadTarget, error) {
+	var ret []*deploystatus.UploadTarget
+	for _, u := range flags.StatusFileUpload {
+		t, err := deploystatus.ParseUploadTarget(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --status-file-upload: %w", err)
+		}
+		ret = append(ret, t)
+	}
+	return ret, nil
+}
+
+func writeDeployStatus(cmdCtx *commandCtx, flags args.DeployStatusFlags, cr *result.CommandResult) error {
+	if flags.StatusFileDir == "" && len(flags.StatusFileUpload) == 0 {
+		return nil
+	}
+	return writeDeployStatusFiles(cmdCtx.ctx, flags, cmdCtx.targetCtx, deploystatus.BuildDeployStatus(cr))
+}
+
+// writeFailedDeployStatus writes the deploy status for a command that failed before a command result was available,
+// e.g. because loading or rendering the project failed. Failing to write the status is only reported, as the original
+// error is more important.
+func writeFailedDeployStatus(ctx context.Context, flags args.DeployStatusFlags, targetFlags args.TargetFlags, discriminator string, cmdCtx *commandCtx, commandErr error) {
+	if flags.StatusFileDir == "" && len(flags.StatusFileUpload) == 0 {
+		return
+	}
+
+	targetName := targetFlags.Target
+	var targetCtx *target_context.TargetContext
+	if cmdCtx != nil {
+		targetCtx = cmdCtx.targetCtx
+		targetName = targetCtx.Target.Name
+		discriminator = targetCtx.Target.Discriminator
+	}
+
+	var errorCount int
+	var renderErrs deployment.RenderErrors
+	if errors.As(commandErr, &renderErrs) {
+		errorCount = len(renderErrs)
+	}
+
+	s := deploystatus.BuildFailedDeployStatus(targetName, discriminator, "deploy", errorCount)
+	err := writeDeployStatusFiles(ctx, flags, targetCtx, s)
+	if err != nil {
+		status.Errorf(ctx, "Failed to write deploy status: %s", err.Error())
+	}
+}
+
+func writeDeployStatusFiles(ctx context.Context, flags args.DeployStatusFlags, targetCtx *target_context.TargetContext, ds *deploystatus.DeployStatus) error {
+	uploadTargets, err := parseDeployStatusUploadTargets(flags)
+	if err != nil {
+		return err
+	}
+
+	s := status.Start(ctx, "Writing deploy status")
+	defer s.Failed()
+
+	files, err := ds.BuildFiles()
+	if err != nil {
+		return err
+	}
+
+	if flags.StatusFileDir != "" {
+		err = deploystatus.WriteFiles(flags.StatusFileDir, files)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(uploadTargets) != 0 {
+		var c client.Client
+		var awsConfig *types.AwsConfig
+		if targetCtx != nil {
+			if targetCtx.SharedContext.K != nil {
+				c, err = targetCtx.SharedContext.K.ToClient()
+				if err != nil {
+					return err
+				}
+			}
+			awsConfig = targetCtx.Target.Aws
+		}
+		for _, t := range uploadTargets {
+			err = t.Upload(ctx, c, awsConfig, files)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.Success()
+	return nil
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"time"
+)
+
+// checkMaintenanceWindow refuses to continue when the current time is outside of all maintenance windows configured in
+// the target. Dry-runs are always allowed, as they don't change anything on the cluster.
+func checkMaintenanceWindow(cmdCtx *commandCtx, override bool, dryRun bool) error {
+	open, err := kluctl_project.IsInMaintenanceWindow(cmdCtx.targetCtx.Target.MaintenanceWindows, time.Now())
+	if err != nil {
+		return err
+	}
+	if open || dryRun {
+		return nil
+	}
+	if override {
+		status.Warning(cmdCtx.ctx, "Target is outside of its maintenance windows, continuing due to --override-maintenance-window")
+		return nil
+	}
+	return fmt.Errorf("target is outside of its maintenance windows, only dry-runs are allowed. Pass --override-maintenance-window to continue anyway")
+}
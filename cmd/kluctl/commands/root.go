@@ -17,12 +17,15 @@ package commands
 
 import (
 	"context"
+	goflag "flag"
 	"fmt"
 	go_container_logs "github.com/google/go-containerregistry/pkg/logs"
 	"github.com/google/gops/agent"
 	status2 "github.com/kluctl/kluctl/lib/status"
 	"github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/telemetry"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
 	flag "github.com/spf13/pflag"
 	"io"
 	"log"
@@ -31,6 +34,7 @@ import (
 	"path/filepath"
 	"runtime/pprof"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -52,31 +56,46 @@ type GlobalFlags struct {
 	NoUpdateCheck bool `group:"global" help:"Disable update check on startup"`
 	NoColor       bool `group:"global" help:"Disable colored output"`
 
+	Quiet     bool   `group:"global" help:"Only print warnings, errors and prompts."`
+	Verbosity int    `group:"global" help:"Log verbosity. 1 enables debug messages (same as --debug), higher values additionally raise the log verbosity of the Kubernetes client libraries."`
+	LogFormat string `group:"global" help:"Log format, either 'text' or 'json'. The json format writes one JSON record per message to stderr, including the target, deployment item and object ref the message relates to." default:"text"`
+
 	CpuProfile    string `group:"global" help:"Enable CPU profiling and write the result to the given path"`
 	GopsAgent     bool   `group:"global" help:"Start gops agent in the background"`
 	GopsAgentAddr string `group:"global" help:"Specify the address:port to use for the gops agent" default:"127.0.0.1:0"`
 
 	UseSystemPython bool `group:"global" help:"Use the system Python instead of the embedded Python."`
+
+	ReadinessPlugin []string `group:"global" help:"Use an external executable to determine readiness of all objects of a kind, in the form Kind.group=/path/to/executable. The executable receives the live object as JSON on stdin and must print a JSON object with 'status' (ready, pending or error) and an optional 'message' to stdout. Can be specified multiple times."`
+
+	Telemetry         bool   `group:"global" help:"Enable recording of anonymized command performance data (durations, object counts, error categories and cluster version). Records are appended to telemetry/records.jsonl inside the kluctl cache directory. Telemetry is disabled by default."`
+	TelemetryEndpoint string `group:"global" help:"Additionally upload each telemetry record as JSON via HTTP POST to the given endpoint. Requires --telemetry."`
 }
 
 type cli struct {
 	GlobalFlags
 
-	Delete      deleteCmd      `cmd:"" help:"Delete a target (or parts of it) from the corresponding cluster"`
-	Deploy      deployCmd      `cmd:"" help:"Deploys a target to the corresponding cluster"`
-	Diff        diffCmd        `cmd:"" help:"Perform a diff between the locally rendered target and the already deployed target"`
-	HelmPull    helmPullCmd    `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and pre-pulls the specified Helm charts"`
-	HelmUpdate  helmUpdateCmd  `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and checks for new available versions"`
-	ListImages  listImagesCmd  `cmd:"" help:"Renders the target and outputs all images used via 'images.get_image(...)"`
-	ListTargets listTargetsCmd `cmd:"" help:"Outputs a yaml list with all targets"`
-	PokeImages  pokeImagesCmd  `cmd:"" help:"Replace all images in target"`
-	Prune       pruneCmd       `cmd:"" help:"Searches the target cluster for prunable objects and deletes them"`
-	Render      renderCmd      `cmd:"" help:"Renders all resources and configuration files"`
-	Validate    validateCmd    `cmd:"" help:"Validates the already deployed deployment"`
-	Controller  controllerCmd  `cmd:"" help:"Kluctl controller sub-commands"`
-	Gitops      gitopsCmd      `cmd:"" help:"GitOps sub-commands"`
-	Webui       webuiCmd       `cmd:"" help:"Kluctl Webui sub-commands"`
-	Oci         ociCmd         `cmd:"" help:"Oci sub-commands"`
+	CheckUpdates      checkUpdatesCmd      `cmd:"" help:"Checks Helm Charts and git/oci includes for newer versions"`
+	Delete            deleteCmd            `cmd:"" help:"Delete a target (or parts of it) from the corresponding cluster"`
+	Deploy            deployCmd            `cmd:"" help:"Deploys a target to the corresponding cluster"`
+	Diff              diffCmd              `cmd:"" help:"Perform a diff between the locally rendered target and the already deployed target"`
+	HelmPull          helmPullCmd          `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and pre-pulls the specified Helm charts"`
+	HelmUpdate        helmUpdateCmd        `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and checks for new available versions"`
+	HookLogs          hookLogsCmd          `cmd:"" help:"Prints the logs of the Job and Pod hooks of a target"`
+	ImportHelmRelease importHelmReleaseCmd `cmd:"" help:"Imports an existing Helm release into the kluctl project and takes over its objects"`
+	ListImages        listImagesCmd        `cmd:"" help:"Renders the target and outputs all images used via 'images.get_image(...)"`
+	ListTargets       listTargetsCmd       `cmd:"" help:"Outputs a yaml list with all targets"`
+	Ownership         ownershipCmd         `cmd:"" help:"Reports which field managers own which fields of the objects of a target"`
+	PokeImages        pokeImagesCmd        `cmd:"" help:"Replace all images in target"`
+	Prune             pruneCmd             `cmd:"" help:"Searches the target cluster for prunable objects and deletes them"`
+	Render            renderCmd            `cmd:"" help:"Renders all resources and configuration files"`
+	TakeOwnership     takeOwnershipCmd     `cmd:"" help:"Takes over field ownership of selected objects by performing a forced server-side apply"`
+	Validate          validateCmd          `cmd:"" help:"Validates the already deployed deployment"`
+	Watch             watchCmd             `cmd:"" help:"Continuously shows the readiness and events of all objects of a target"`
+	Controller        controllerCmd        `cmd:"" help:"Kluctl controller sub-commands"`
+	Gitops            gitopsCmd            `cmd:"" help:"GitOps sub-commands"`
+	Webui             webuiCmd             `cmd:"" help:"Kluctl Webui sub-commands"`
+	Oci               ociCmd               `cmd:"" help:"Oci sub-commands"`
 
 	Version versionCmd `cmd:"" help:"Print kluctl version"`
 }
@@ -101,22 +120,49 @@ var origStderr = os.Stderr
 // we must determine isTerminal before we override os.Stderr
 var isTerminal = isatty.IsTerminal(os.Stderr.Fd())
 
-func initStatusHandlerAndPrompts(ctx context.Context, debug bool, noColor bool) context.Context {
+func initStatusHandlerAndPrompts(ctx context.Context, flags *GlobalFlags) (context.Context, error) {
+	trace := flags.Debug || flags.Verbosity >= 1
+	var minLevel status2.Level = status2.LevelTrace
+	if flags.Quiet {
+		minLevel = status2.LevelWarning
+	}
+
 	var sh status2.StatusHandler
 	var pp prompts.PromptProvider
-	if !debug && isTerminal {
-		sh = status2.NewMultiLineStatusHandler(ctx, origStderr, isTerminal && !noColor, false)
-		pp = &prompts.StatusAndStdinPromptProvider{}
-	} else {
-		sh = status2.NewSimpleStatusHandler(func(level status2.Level, message string) {
-			_, _ = fmt.Fprintf(origStderr, "%s\n", message)
-		}, debug)
+	switch flags.LogFormat {
+	case "json":
+		sh = status2.NewJsonStatusHandler(origStderr, minLevel, trace)
 		pp = &prompts.SimplePromptProvider{Out: origStderr}
+	case "text":
+		if !flags.Debug && !flags.Quiet && isTerminal {
+			sh = status2.NewMultiLineStatusHandler(ctx, origStderr, isTerminal && !flags.NoColor, trace)
+			pp = &prompts.StatusAndStdinPromptProvider{}
+		} else {
+			sh = status2.NewSimpleStatusHandler(func(level status2.Level, message string) {
+				if status2.IsLevelEnabled(level, minLevel) {
+					_, _ = fmt.Fprintf(origStderr, "%s\n", message)
+				}
+			}, trace)
+			pp = &prompts.SimplePromptProvider{Out: origStderr}
+		}
+	default:
+		return ctx, fmt.Errorf("invalid --log-format '%s', must be 'text' or 'json'", flags.LogFormat)
 	}
 	ctx = status2.NewContext(ctx, sh)
 	ctx = prompts.NewContext(ctx, pp)
 
-	return ctx
+	return ctx, nil
+}
+
+// setupKlogVerbosity raises the verbosity of klog, which is used by the Kubernetes client libraries, for verbosity
+// levels above 1. klog output is redirected to the status handler, so it ends up in the same log stream.
+func setupKlogVerbosity(verbosity int) error {
+	if verbosity < 2 {
+		return nil
+	}
+	fs := goflag.NewFlagSet("klog", goflag.ContinueOnError)
+	klog.InitFlags(fs)
+	return fs.Set("v", strconv.Itoa(verbosity))
 }
 
 func redirectLogsAndStderr(ctx context.Context) {
@@ -164,6 +210,44 @@ func setupGops(flags *GlobalFlags) error {
 
 var cpuProfileFile *os.File
 
+var telemetryRecorder *telemetry.Recorder
+
+func setupReadinessPlugins(ctx context.Context, flags *GlobalFlags) (context.Context, error) {
+	var plugins []validation.ReadinessPlugin
+	for _, s := range flags.ReadinessPlugin {
+		p, err := validation.ParseReadinessPlugin(s)
+		if err != nil {
+			return ctx, err
+		}
+		plugins = append(plugins, p)
+	}
+	return validation.WithReadinessPlugins(ctx, plugins), nil
+}
+
+func setupTelemetry(ctx context.Context, flags *GlobalFlags, cmd *cobra.Command) context.Context {
+	if !flags.Telemetry {
+		return ctx
+	}
+	if len(os.Args) >= 2 && (os.Args[1] == "completion" || os.Args[1] == "__complete") {
+		return ctx
+	}
+
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	telemetryRecorder = telemetry.NewRecorder(filepath.Join(utils.GetCacheDir(ctx), "telemetry"), flags.TelemetryEndpoint, command)
+	return telemetry.NewContext(ctx, telemetryRecorder)
+}
+
+func finishTelemetry(ctx context.Context, cmdErr error) {
+	if telemetryRecorder == nil {
+		return
+	}
+	_, err := telemetryRecorder.Finish(ctx, cmdErr)
+	if err != nil {
+		status2.Warning(ctx, err.Error())
+	}
+	telemetryRecorder = nil
+}
+
 func setupProfiling(cpuProfile string) error {
 	var err error
 	if cpuProfile != "" {
@@ -276,9 +360,24 @@ func Main() {
 			return ctx, err
 		}
 
-		ctx = initStatusHandlerAndPrompts(ctxIn, flags.Debug, flags.NoColor)
+		ctx, err = initStatusHandlerAndPrompts(ctxIn, flags)
+		if err != nil {
+			return ctx, err
+		}
 		didSetupStatusHandler = true
 
+		err = setupKlogVerbosity(flags.Verbosity)
+		if err != nil {
+			return ctx, err
+		}
+
+		ctx = setupTelemetry(ctx, flags, cmd)
+
+		ctx, err = setupReadinessPlugins(ctx, flags)
+		if err != nil {
+			return ctx, err
+		}
+
 		if cmd.Parent() == nil || (cmd.Name() != "run" && cmd.Parent().Name() != "controller") {
 			redirectLogsAndStderr(ctx)
 		}
@@ -297,6 +396,8 @@ func Main() {
 		cpuProfileFile = nil
 	}
 
+	finishTelemetry(ctx, err)
+
 	if err != nil {
 		if didSetupStatusHandler {
 			status2.Error(ctx, err.Error())
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+)
+
+// supportBundleState remembers how far a command got, so that a support bundle can also be written when loading or
+// rendering the project fails
+type supportBundleState struct {
+	cmdCtx *commandCtx
+	result *result.CommandResult
+}
+
+// writeSupportBundle writes a support bundle for a failed command. Failing to write the bundle is only reported, as
+// the original error is the more important one.
+func writeSupportBundle(ctx context.Context, p string, rsh *status.RecordingStatusHandler, targetFlags args.TargetFlags, state *supportBundleState, commandErr error) {
+	var cmd *commands.SupportBundleCommand
+	if state.cmdCtx != nil {
+		cmd = commands.NewSupportBundleCommand(state.cmdCtx.targetCtx)
+	} else {
+		cmd = commands.NewSupportBundleCommand(nil)
+	}
+	cmd.TargetName = targetFlags.Target
+	if rsh != nil {
+		cmd.Logs = rsh.Lines()
+	}
+
+	err := cmd.Write(ctx, p, state.result, commandErr)
+	if err != nil {
+		status.Errorf(ctx, "Failed to write support bundle to %s: %s", p, err.Error())
+		return
+	}
+	status.Infof(ctx, "Wrote support bundle to %s", p)
+}
@@ -20,14 +20,19 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/prompts"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
 	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/telemetry"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 	"os"
+	"path/filepath"
 	client2 "sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
 )
 
 func withKluctlProjectFromArgs(ctx context.Context, kubeconfigFlags *args.KubeconfigFlags, projectFlags args.ProjectFlags, argsFlags *args.ArgsFlags, helmCredentials *args.HelmCredentials, registryCredentials *args.RegistryCredentials, internalDeploy bool, strictTemplates bool, forCompletion bool, cb func(ctx context.Context, p *kluctl_project.LoadedKluctlProject) error) error {
@@ -56,8 +61,12 @@ func withKluctlProjectFromArgs(ctx context.Context, kubeconfigFlags *args.Kubeco
 		repoRoot = projectDir
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, projectFlags.Timeout)
-	defer cancel()
+	// a timeout of 0 is only used internally by commands that run until interrupted (e.g. watch)
+	if projectFlags.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, projectFlags.Timeout)
+		defer cancel()
+	}
 
 	sshPool := &ssh_pool.SshPool{}
 
@@ -97,16 +106,33 @@ func withKluctlProjectFromArgs(ctx context.Context, kubeconfigFlags *args.Kubeco
 		return err
 	}
 
+	externalItemArgs, err := argsFlags.LoadItemArgs()
+	if err != nil {
+		return err
+	}
+
+	var tlsOverride *types.TLSConfig
+	if kubeconfigFlags != nil {
+		tlsOverride, err = kubeconfigFlags.LoadTLSOverride()
+		if err != nil {
+			return err
+		}
+	}
+
 	loadArgs := kluctl_project.LoadKluctlProjectArgs{
 		RepoRoot:           repoRoot,
 		ProjectDir:         projectDir,
 		ProjectConfig:      projectFlags.ProjectConfig.String(),
 		ExternalArgs:       externalArgs,
+		ExternalItemArgs:   externalItemArgs,
+		Environ:            os.Environ(),
 		GitRP:              gitRp,
 		OciRP:              ociRp,
 		OciAuthProvider:    ociAuth,
 		HelmAuthProvider:   helmAuth,
 		ClientConfigGetter: clientConfigGetter(kubeconfigFlags, forCompletion),
+		TLSOverride:        tlsOverride,
+		UseTargetTLS:       true,
 	}
 
 	p, err := kluctl_project.LoadKluctlProject(ctx, loadArgs, j2)
@@ -124,6 +150,7 @@ type projectTargetCommandArgs struct {
 	argsFlags            args.ArgsFlags
 	imageFlags           args.ImageFlags
 	inclusionFlags       args.InclusionFlags
+	selectorFlags        args.SelectorFlags
 	helmCredentials      args.HelmCredentials
 	registryCredentials  args.RegistryCredentials
 	dryRunArgs           *args.DryRunFlags
@@ -136,6 +163,12 @@ type projectTargetCommandArgs struct {
 	forCompletion     bool
 	offlineKubernetes bool
 	kubernetesVersion string
+
+	preserveYamlFormat bool
+
+	// clusterPool is set when multiple targets are handled in the same process, so that clients and discovery are
+	// shared between targets that use the same cluster
+	clusterPool *k8s.ClusterPool
 }
 
 type commandCtx struct {
@@ -174,6 +207,10 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 	if err != nil {
 		return err
 	}
+	objectSelector, err := args.selectorFlags.ParseSelectorFromArgs()
+	if err != nil {
+		return err
+	}
 
 	renderOutputDir := args.renderOutputDirFlags.RenderOutputDir
 	if renderOutputDir == "" {
@@ -185,6 +222,14 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		renderOutputDir = tmpDir
 	}
 
+	keepRenderTmpDir := args.renderOutputDirFlags.KeepRenderTmp
+	if keepRenderTmpDir != "" {
+		keepRenderTmpDir, err = filepath.Abs(keepRenderTmpDir)
+		if err != nil {
+			return err
+		}
+	}
+
 	targetParams := target_context.TargetContextParams{
 		TargetName:         args.targetFlags.Target,
 		TargetNameOverride: args.targetFlags.TargetNameOverride,
@@ -195,9 +240,16 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		DryRun:             args.dryRunArgs == nil || args.dryRunArgs.DryRun || args.forCompletion,
 		Images:             images,
 		Inclusion:          inclusion,
+		ObjectSelector:     objectSelector,
 		OciAuthProvider:    p.LoadArgs.OciAuthProvider,
 		HelmAuthProvider:   p.LoadArgs.HelmAuthProvider,
 		RenderOutputDir:    renderOutputDir,
+		PreserveYamlFormat: args.preserveYamlFormat,
+		KeepRenderTmpDir:   keepRenderTmpDir,
+	}
+
+	if targetParams.TargetName != "" {
+		ctx = status.WithFields(ctx, "target", targetParams.TargetName)
 	}
 
 	commandResultId := uuid.NewString()
@@ -210,18 +262,34 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 	var k *k8s.K8sCluster
 	var resultStore results.ResultStore
 	if clientConfig != nil {
-		discovery, mapper, err := k8s.CreateDiscoveryAndMapper(ctx, clientConfig)
-		if err != nil {
-			return err
-		}
-
+		var mapper meta.RESTMapper
+		var release func()
 		s := status.Start(ctx, fmt.Sprintf("Initializing k8s client"))
-		k, err = k8s.NewK8sCluster(ctx, clientConfig, discovery, mapper, targetParams.DryRun)
+		if args.clusterPool != nil {
+			k, release, err = args.clusterPool.Get(ctx, clientConfig, targetParams.DryRun)
+		} else {
+			var discovery discovery.CachedDiscoveryInterface
+			discovery, mapper, err = k8s.CreateDiscoveryAndMapper(ctx, clientConfig)
+			if err == nil {
+				k, err = k8s.NewK8sCluster(ctx, clientConfig, discovery, mapper, targetParams.DryRun)
+			}
+		}
 		if err != nil {
 			s.Failed()
 			return err
 		}
+		if release != nil {
+			defer release()
+		}
+		if mapper == nil {
+			mapper, _ = k.ToRESTMapper()
+		}
 		s.Success()
+		defer warnThrottled(ctx, k)
+
+		if k.ServerVersion != nil {
+			telemetry.FromContext(ctx).SetClusterVersion(k.ServerVersion.String())
+		}
 
 		resultStore, err = buildResultStoreRW(ctx, clientConfig, mapper, args.commandResultFlags, false)
 		if err != nil {
@@ -254,6 +322,26 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 	return cb(cmdCtx)
 }
 
+func collectArtifacts(cmdCtx *commandCtx, flags args.CollectArtifactsFlags) error {
+	if flags.CollectArtifacts == "" {
+		return nil
+	}
+	files, err := cmdCtx.targetCtx.DeploymentCollection.CollectArtifacts(flags.CollectArtifacts)
+	if err != nil {
+		return err
+	}
+	status.Infof(cmdCtx.ctx, "Collected %d artifacts into %s", len(files), flags.CollectArtifacts)
+	return nil
+}
+
+func warnThrottled(ctx context.Context, k *k8s.K8sCluster) {
+	ts := k.GetThrottleSummary()
+	if ts.Count == 0 {
+		return
+	}
+	status.Warningf(ctx, "The Kubernetes API server throttled %d requests, which caused a total backoff of %s. Consider reviewing the API Priority and Fairness configuration of the cluster.", ts.Count, ts.TotalBackoff.Round(time.Millisecond))
+}
+
 func clientConfigGetter(kubeconfigFlags *args.KubeconfigFlags, forCompletion bool) func(context *string) (*rest.Config, *api.Config, error) {
 	return func(context *string) (*rest.Config, *api.Config, error) {
 		if forCompletion {
@@ -320,6 +408,7 @@ func buildResultStoreRW(ctx context.Context, restConfig *rest.Config, mapper met
 	if err != nil {
 		return nil, err
 	}
+	resultStore.SetWriteDeployResults(flags.WriteKluctlDeployResult)
 
 	if startCleanup {
 		err = resultStore.StartCleanupOrphans()
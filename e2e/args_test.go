@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
 )
@@ -188,6 +189,60 @@ func TestArgsFromEnvAndCli(t *testing.T) {
 	assertNestedFieldEquals(t, cm, "c2", "data", "c")
 }
 
+func TestArgsFromNamedEnv(t *testing.T) {
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t, test_project.WithUseProcess(true))
+	p.SetEnv("KLUCTL_ARG_A", "a")
+	p.SetEnv("KLUCTL_ARG_B", "b")
+	p.SetEnv("KLUCTL_ARG_C", `{"nested": "c"}`)
+	p.SetEnv("CI_COMMIT", "1234")
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField("target-a", "args", "a")
+		_ = target.SetNestedField("target-d", "args", "d")
+		_ = target.SetNestedField("CI_COMMIT", "argsFromEnv", "commit")
+		_ = target.SetNestedField("CI_NOT_SET", "argsFromEnv", "e")
+	})
+
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]any{
+			map[string]any{"name": "b", "default": "default"},
+			map[string]any{"name": "e", "default": "default"},
+		}, "args")
+		return nil
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"a":      `{{ args.a }}`,
+		"b":      `{{ args.b }}`,
+		"c":      `{{ args.c.nested }}`,
+		"d":      `{{ args.d }}`,
+		"e":      `{{ args.e }}`,
+		"commit": `{{ args.commit }}`,
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	cm := k.MustGetCoreV1(t, "configmaps", p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "a", "data", "a")
+	assertNestedFieldEquals(t, cm, "b", "data", "b")
+	assertNestedFieldEquals(t, cm, "c", "data", "c")
+	assertNestedFieldEquals(t, cm, "target-d", "data", "d")
+	assertNestedFieldEquals(t, cm, "default", "data", "e")
+	assertNestedFieldEquals(t, cm, "1234", "data", "commit")
+
+	// explicit args override args from env
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "-a", "a=cli", "-a", "commit=5678")
+	cm = k.MustGetCoreV1(t, "configmaps", p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "cli", "data", "a")
+	assertNestedFieldEquals(t, cm, "5678", "data", "commit")
+}
+
 func testArgsInDiscriminator(t *testing.T, inDefaultDiscriminator bool) {
 	t.Parallel()
 
@@ -257,3 +312,80 @@ func TestArgsInDefaultDiscriminator(t *testing.T) {
 func TestArgsInTargetDiscriminator(t *testing.T) {
 	testArgsInDiscriminator(t, false)
 }
+
+func TestItemArgs(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	for _, n := range []string{"cm1", "cm2"} {
+		addConfigMapDeployment(p, n, map[string]string{
+			"a": `{{ args.a | default("na") }}`,
+			"v": `{{ my_var | default("na") }}`,
+		}, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "-aa=a", "--item-arg=cm1:args.a=a2", "--item-arg=cm2:my_var=v")
+	cm := k.MustGetCoreV1(t, "configmaps", p.TestSlug(), "cm1")
+	assertNestedFieldEquals(t, cm, "a2", "data", "a")
+	assertNestedFieldEquals(t, cm, "na", "data", "v")
+	cm = k.MustGetCoreV1(t, "configmaps", p.TestSlug(), "cm2")
+	assertNestedFieldEquals(t, cm, "a", "data", "a")
+	assertNestedFieldEquals(t, cm, "v", "data", "v")
+
+	_, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test", "--item-arg=cm3:my_var=v")
+	assert.ErrorContains(t, err, "no deployment item found for item args with path 'cm3'")
+}
+
+func TestTargetExtends(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("base", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(true, "abstract")
+		_ = target.SetNestedField("base", "args", "a")
+		_ = target.SetNestedField("base", "args", "b")
+	})
+	p.UpdateTarget("mixin", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(true, "abstract")
+		_ = target.SetNestedField("mixin", "args", "c")
+	})
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField([]any{"base", "mixin"}, "extends")
+		_ = target.SetNestedField("test", "args", "b")
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"a": `{{ args.a }}`,
+		"b": `{{ args.b }}`,
+		"c": `{{ args.c }}`,
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	cm := k.MustGetCoreV1(t, "configmaps", p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "base", "data", "a")
+	assertNestedFieldEquals(t, cm, "test", "data", "b")
+	assertNestedFieldEquals(t, cm, "mixin", "data", "c")
+
+	// abstract targets can't be deployed
+	_, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "base")
+	assert.Error(t, err)
+}
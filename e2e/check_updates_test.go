@@ -0,0 +1,90 @@
+package e2e
+
+import (
+	"encoding/json"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckUpdates(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	helmRepo := &test_utils.TestHelmRepo{
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+			{ChartName: "test-chart1", Version: "0.2.0"},
+		},
+	}
+	helmRepo.Start(t)
+
+	ociRepo := &test_utils.TestHelmRepo{
+		Oci: true,
+	}
+	ociRepo.Start(t)
+	ociUrl := ociRepo.URL.String() + "/org1/repo1"
+
+	ip1 := prepareIncludeProject(t, "include1", "", nil)
+	ip1.KluctlMust(t, "oci", "push", "--url", ociUrl+":1.0.0")
+	ip1.KluctlMust(t, "oci", "push", "--url", ociUrl+":1.1.0")
+	ip1.KluctlMust(t, "oci", "push", "--url", ociUrl+":2.0.0-rc.1")
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	p.AddDeploymentItem("", uo.FromMap(map[string]interface{}{
+		"oci": map[string]any{
+			"url": ociUrl,
+			"ref": map[string]any{
+				"tag": "1.0.0",
+			},
+		},
+	}))
+	p.AddHelmDeployment("helm1", helmRepo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), nil)
+	p.AddHelmDeployment("helm2", helmRepo.URL.String(), "test-chart1", "0.1.0", "test-helm2", p.TestSlug(), nil)
+	p.AddHelmDeployment("helm3", helmRepo.URL.String(), "test-chart1", "0.2.0", "test-helm3", p.TestSlug(), nil)
+	p.UpdateYaml("helm2/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(true, "helmChart", "skipUpdate")
+		return nil
+	}, "")
+
+	p.KluctlMust(t, "helm-pull")
+
+	jsonFile := filepath.Join(t.TempDir(), "updates.json")
+	stdout, _ := p.KluctlMust(t, "check-updates", "-t", "test", "--json-file", jsonFile)
+	assert.Contains(t, stdout, "test-chart1")
+
+	b, err := os.ReadFile(jsonFile)
+	assert.NoError(t, err)
+	var r commands.CheckUpdatesResult
+	err = json.Unmarshal(b, &r)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []commands.AvailableUpdate{
+		{
+			Kind:           commands.UpdateKindOciInclude,
+			Location:       ".",
+			Source:         ociUrl,
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "1.1.0",
+			UpdateType:     commands.UpdateTypeMinor,
+		},
+		{
+			Kind:           commands.UpdateKindHelmChart,
+			Location:       "helm1",
+			Source:         "test-chart1",
+			CurrentVersion: "0.1.0",
+			LatestVersion:  "0.2.0",
+			UpdateType:     commands.UpdateTypeMinor,
+		},
+	}, r.Updates)
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/e2e/test_resources"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
-	"strings"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"testing"
 	"time"
 )
@@ -58,16 +58,15 @@ func TestDeployCRDUnordered(t *testing.T) {
 	k := createTestCluster(t, "cluster1")
 	p := prepareCRDsTest(t, k, true, false)
 
-	for i := 0; i < 100; i++ {
-		stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test1")
-		if err != nil && strings.Contains(err.Error(), "command failed") && strings.Contains(stdout, `no matches for kind "CronTab" in version`) {
-			// success
-			return
-		}
+	// without a barrier, the CR is often applied before the CRD, in which case it must be deferred until the CRD got
+	// established
+	for i := 0; i < 10; i++ {
+		stdout, _ := p.KluctlMust(t, "deploy", "--yes", "-t", "test1")
+		assert.NotContains(t, stdout, `no matches for kind "CronTab" in version`)
+		assertObjectExists(t, k, schema.GroupVersionResource{Group: "stable.example.com", Version: "v1", Resource: "crontabs"}, p.TestSlug(), "test")
+
 		p.KluctlMust(t, "delete", "--yes", "-t", "test1")
 	}
-
-	t.Errorf("could not cause missing CRD error")
 }
 
 func TestDiffCRDSimulated(t *testing.T) {
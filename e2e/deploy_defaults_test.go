@@ -0,0 +1,72 @@
+package e2e
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func prepareDeployDefaultsTest(t *testing.T, p *test_project.TestProject) {
+	k := defaultCluster1
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+		annotations: map[string]string{
+			"kluctl.io/is-ready":       "false",
+			"kluctl.io/wait-readiness": "true",
+		},
+	})
+}
+
+func TestDeployDefaults(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	prepareDeployDefaultsTest(t, p)
+
+	// the project wide default disables waiting
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(true, "deployDefaults", "noWait")
+		return nil
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+
+	// the target overrides the project wide default
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(false, "deployDefaults", "noWait")
+		_ = target.SetNestedField("2s", "deployDefaults", "waitTimeout")
+	})
+	_, stderr, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, fmt.Sprintf("timed out while waiting for readiness of %s/ConfigMap/cm1", p.TestSlug()))
+
+	// command line flags override all defaults
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--no-wait")
+}
+
+func TestDeployDefaultsFromEnv(t *testing.T) {
+	p := test_project.NewTestProject(t, test_project.WithUseProcess(true))
+	prepareDeployDefaultsTest(t, p)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(false, "deployDefaults", "noWait")
+		_ = target.SetNestedField("2s", "deployDefaults", "waitTimeout")
+	})
+	_, stderr, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, fmt.Sprintf("timed out while waiting for readiness of %s/ConfigMap/cm1", p.TestSlug()))
+
+	// environment variables override the defaults as well
+	p.SetEnv("KLUCTL_NO_WAIT", "true")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	"path/filepath"
 	"testing"
 )
@@ -287,3 +289,172 @@ func TestIncludeLocalFromRoot(t *testing.T) {
 func TestIncludeLocalFromSubdir(t *testing.T) {
 	testLocalIncludes(t, "foo")
 }
+
+func TestDefaultNamespace(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	nsTarget := p.TestSlug() + "-t"
+	nsItem := p.TestSlug() + "-i"
+	nsInclude := p.TestSlug() + "-inc"
+	createNamespace(t, k, nsTarget)
+	createNamespace(t, k, nsItem)
+	createNamespace(t, k, nsInclude)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(nsTarget, "defaultNamespace")
+	})
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name: "cm1",
+	})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name: "cm2",
+	})
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[1].SetNestedField(nsItem, "defaultNamespace")
+		return items
+	})
+	addConfigMapDeployment(p, "sub/cm3", nil, resourceOpts{
+		name: "cm3",
+	})
+	addConfigMapDeployment(p, "sub/cm4", nil, resourceOpts{
+		name:      "cm4",
+		namespace: nsTarget,
+	})
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[2].SetNestedField(nsInclude, "defaultNamespace")
+		return items
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, nsTarget, "cm1")
+	assertConfigMapExists(t, k, nsItem, "cm2")
+	assertConfigMapExists(t, k, nsInclude, "cm3")
+	assertConfigMapExists(t, k, nsTarget, "cm4")
+}
+
+func TestConfigMapGenerator(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	deployment := uo.New()
+	deployment.SetK8sGVKs("apps", "v1", "Deployment")
+	deployment.SetK8sName("app")
+	deployment.SetK8sNamespace(p.TestSlug())
+	_ = deployment.SetNestedField(map[string]any{"app": "app"}, "spec", "selector", "matchLabels")
+	_ = deployment.SetNestedField(map[string]any{"app": "app"}, "spec", "template", "metadata", "labels")
+	_ = deployment.SetNestedField([]any{
+		map[string]any{
+			"name":  "c",
+			"image": "busybox",
+			"envFrom": []any{
+				map[string]any{"configMapRef": map[string]any{"name": "gen-cm"}},
+			},
+		},
+	}, "spec", "template", "spec", "containers")
+
+	p.AddKustomizeDeployment("gen", []test_project.KustomizeResource{
+		{Name: "deployment.yml", Content: deployment},
+	}, nil)
+
+	setLiteral := func(v string) {
+		p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+			_ = items[0].SetNestedField([]any{
+				map[string]any{
+					"name":      "gen-cm",
+					"namespace": p.TestSlug(),
+					"literals":  []any{"a=" + v},
+				},
+			}, "configMapGenerator")
+			return items
+		})
+	}
+
+	getGenerated := func() []*uo.UnstructuredObject {
+		l, err := k.List(v1.SchemeGroupVersion.WithResource("configmaps"), p.TestSlug(), nil)
+		assert.NoError(t, err)
+		var ret []*uo.UnstructuredObject
+		for _, x := range l {
+			if x.GetK8sAnnotation("kluctl.io/generator") != nil {
+				ret = append(ret, x)
+			}
+		}
+		return ret
+	}
+
+	setLiteral("v1")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	l := getGenerated()
+	assert.Len(t, l, 1)
+	cm1 := l[0]
+	assert.Regexp(t, "^gen-cm-.+", cm1.GetK8sName())
+	assertNestedFieldEquals(t, cm1, "v1", "data", "a")
+
+	o := assertObjectExists(t, k, appsv1.SchemeGroupVersion.WithResource("deployments"), p.TestSlug(), "app")
+	assertNestedFieldEquals(t, o, cm1.GetK8sName(), "spec", "template", "spec", "containers", 0, "envFrom", 0, "configMapRef", "name")
+
+	setLiteral("v2")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	l = getGenerated()
+	assert.Len(t, l, 1)
+	cm2 := l[0]
+	assert.NotEqual(t, cm1.GetK8sName(), cm2.GetK8sName())
+	assertNestedFieldEquals(t, cm2, "v2", "data", "a")
+	assertConfigMapNotExists(t, k, p.TestSlug(), cm1.GetK8sName())
+
+	// deleting the old generation counts against maxDeletes, even without --prune
+	setLiteral("v3")
+	stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test", "--max-deletes", "0")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "refusing to delete 1 objects")
+	l = getGenerated()
+	assert.Len(t, l, 1)
+	assert.Equal(t, cm2.GetK8sName(), l[0].GetK8sName())
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--max-deletes", "1")
+	l = getGenerated()
+	assert.Len(t, l, 1)
+	assert.NotEqual(t, cm2.GetK8sName(), l[0].GetK8sName())
+}
+
+func TestRetryFailedItems(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	// the namespace is only created by the last deployment item, so the first deployment item will fail in the first
+	// pass and then recover when being retried
+	lateNamespace := p.TestSlug() + "-late"
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", nil, resourceOpts{
+		name:      "cm",
+		namespace: lateNamespace,
+	})
+	p.AddDeploymentItem(".", uo.FromMap(map[string]interface{}{
+		"barrier": true,
+	}))
+	p.AddKustomizeDeployment("ns", []test_project.KustomizeResource{
+		{Name: "namespace.yml", Content: createCoreV1Object("Namespace", resourceOpts{name: lateNamespace})},
+	}, nil)
+
+	_, stderr := p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--retry-failed-items", "1")
+	assert.Contains(t, stderr, "Deployment item cm recovered after retrying")
+	assertConfigMapExists(t, k, lateNamespace, "cm")
+}
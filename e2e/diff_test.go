@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestDiffShort(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	addConfigMapDeployment(p, "cm1", map[string]string{"a": "1", "b": "1"}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	p.UpdateYaml("cm1/configmap-cm1.yml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("2", "data", "a")
+		_ = o.SetNestedField("2", "data", "b")
+		return nil
+	}, "")
+	addConfigMapDeployment(p, "cm3", nil, resourceOpts{
+		name:      "cm3",
+		namespace: p.TestSlug(),
+	})
+
+	stdout, _ := p.KluctlMust(t, "diff", "-t", "test", "--short")
+	assert.Regexp(t, regexp.MustCompile(fmt.Sprintf(`\| cm1 +\| %s/ConfigMap/cm1 +\| changed +\| 2 +\|`, p.TestSlug())), stdout)
+	assert.Regexp(t, regexp.MustCompile(fmt.Sprintf(`\| cm3 +\| %s/ConfigMap/cm3 +\| new +\| +\|`, p.TestSlug())), stdout)
+	assert.NotContains(t, stdout, "ConfigMap/cm2")
+	assert.Regexp(t, regexp.MustCompile(`\| cm1 +\| 0 +\| 1 +\| 2 +\| 0 +\| 0 +\|`), stdout)
+	assert.Regexp(t, regexp.MustCompile(`\| cm3 +\| 1 +\| 0 +\| 0 +\| 0 +\| 0 +\|`), stdout)
+	assert.NotContains(t, stdout, "Diff for object")
+}
@@ -0,0 +1,114 @@
+package e2e
+
+import (
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDryRunAnnotation(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm1", map[string]string{"k": "v1"}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm2", map[string]string{"k": "v1"}, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+		annotations: map[string]string{
+			"kluctl.io/dry-run":        "true",
+			"kluctl.io/wait-readiness": "true",
+		},
+	})
+
+	r, _ := p.KluctlMustCommandResult(t, "deploy", "--yes", "-t", "test", "-oyaml")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+	assert.Equal(t, 2, r.BuildSummary().NewObjects)
+	for _, o := range r.Objects {
+		assert.Equal(t, o.Ref.Name == "cm2", o.DryRun, o.Ref.String())
+	}
+
+	// switching the annotation off must create the object for real
+	p.UpdateYaml("cm2/configmap-cm2.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sAnnotation("kluctl.io/dry-run", "false")
+		_ = o.SetNestedField("v2", "data", "k")
+		return nil
+	}, "")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	cm2 := assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertNestedFieldEquals(t, cm2, "v2", "data", "k")
+
+	// changes to existing objects are only reported but not applied
+	p.UpdateYaml("cm2/configmap-cm2.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sAnnotation("kluctl.io/dry-run", "true")
+		_ = o.SetNestedField("v3", "data", "k")
+		return nil
+	}, "")
+	r, _ = p.KluctlMustCommandResult(t, "deploy", "--yes", "-t", "test", "-oyaml")
+	cm2 = assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertNestedFieldEquals(t, cm2, "v2", "data", "k")
+	assert.Equal(t, 1, r.BuildSummary().ChangedObjects)
+}
+
+func TestDryRunAnnotationHooks(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	for _, policy := range []string{"before-hook-creation", "hook-succeeded"} {
+		addConfigMapDeployment(p, policy, map[string]string{"k": "v1"}, resourceOpts{
+			name:      policy,
+			namespace: p.TestSlug(),
+			annotations: map[string]string{
+				"kluctl.io/hook":               "post-deploy",
+				"kluctl.io/hook-delete-policy": policy,
+			},
+		})
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "before-hook-creation")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "hook-succeeded")
+
+	// the existing hook must not be deleted for real when it is marked as dry-run
+	p.UpdateYaml("before-hook-creation/configmap-before-hook-creation.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sAnnotation("kluctl.io/dry-run", "true")
+		_ = o.SetNestedField("v2", "data", "k")
+		return nil
+	}, "")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	cm := assertConfigMapExists(t, k, p.TestSlug(), "before-hook-creation")
+	assertNestedFieldEquals(t, cm, "v1", "data", "k")
+
+	// re-create the hook-succeeded hook manually and verify that the dry-run hook does not delete it
+	p.UpdateYaml("hook-succeeded/configmap-hook-succeeded.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sAnnotation("kluctl.io/hook-delete-policy", "before-hook-creation")
+		return nil
+	}, "")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "hook-succeeded")
+	p.UpdateYaml("hook-succeeded/configmap-hook-succeeded.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sAnnotation("kluctl.io/hook-delete-policy", "hook-succeeded")
+		o.SetK8sAnnotation("kluctl.io/dry-run", "true")
+		return nil
+	}, "")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "hook-succeeded")
+}
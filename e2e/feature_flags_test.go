@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]any{
+			map[string]any{"name": "f1"},
+			map[string]any{"name": "f2", "default": true},
+			map[string]any{"name": "f3"},
+		}, "featureFlags")
+		return nil
+	})
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(true, "featureFlags", "f1")
+		_ = target.SetNestedField(false, "featureFlags", "f2")
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"f1": `{{ featureFlags.f1 }}`,
+		"f2": `{{ featureFlags.f2 }}`,
+		"f3": `{{ featureFlags.f3 }}`,
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm-f1", nil, resourceOpts{
+		name:      "cm-f1",
+		namespace: p.TestSlug(),
+		when:      "featureFlags.f1",
+	})
+	addConfigMapDeployment(p, "cm-f3", nil, resourceOpts{
+		name:      "cm-f3",
+		namespace: p.TestSlug(),
+		when:      "featureFlags.f3",
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	cm := assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "True", "data", "f1")
+	assertNestedFieldEquals(t, cm, "False", "data", "f2")
+	assertNestedFieldEquals(t, cm, "False", "data", "f3")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm-f1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm-f3")
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(true, "featureFlags", "typo")
+	})
+	_, stderr, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "target test sets the undeclared feature flag typo")
+}
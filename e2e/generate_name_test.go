@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"strings"
+	"testing"
+)
+
+func TestGenerateName(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	hook := createConfigMapObject(map[string]string{"a": "b"}, resourceOpts{
+		namespace: p.TestSlug(),
+		annotations: map[string]string{
+			"kluctl.io/hook": "post-deploy",
+		},
+	})
+	_ = hook.SetNestedField("hook-", "metadata", "generateName")
+
+	p.AddKustomizeDeployment("hook", []test_project.KustomizeResource{
+		{Name: "hook.yml", Content: hook},
+	}, nil)
+
+	countGenerated := func(prefix string) int {
+		l, err := k.List(v1.SchemeGroupVersion.WithResource("configmaps"), p.TestSlug(), nil)
+		assert.NoError(t, err)
+		cnt := 0
+		for _, x := range l {
+			if strings.HasPrefix(x.GetK8sName(), prefix) {
+				cnt++
+			}
+		}
+		return cnt
+	}
+
+	cr, _ := p.KluctlMustCommandResult(t, "deploy", "--yes", "-t", "test", "-oyaml")
+	assert.Equal(t, 1, countGenerated("hook-"))
+
+	// the result must contain the generated name, together with the rendered object
+	found := false
+	for _, o := range cr.Objects {
+		if !strings.HasPrefix(o.Ref.Name, "hook-") || o.Deleted {
+			continue
+		}
+		found = true
+		assert.True(t, o.Hook)
+		if assert.NotNil(t, o.Rendered) {
+			assertNestedFieldEquals(t, o.Rendered, "hook-", "metadata", "generateName")
+		}
+	}
+	assert.True(t, found)
+
+	// every deployment creates a new instance, while before-hook-creation deletes the previous one
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assert.Equal(t, 1, countGenerated("hook-"))
+
+	// dry-run must neither create nor delete anything
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--dry-run")
+	assert.Equal(t, 1, countGenerated("hook-"))
+}
+
+func TestGenerateNameNonHook(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	cm := createConfigMapObject(map[string]string{"a": "b"}, resourceOpts{namespace: p.TestSlug()})
+	_ = cm.SetNestedField("cm-", "metadata", "generateName")
+
+	p.AddKustomizeDeployment("cm", []test_project.KustomizeResource{
+		{Name: "cm.yml", Content: cm},
+	}, nil)
+
+	stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "metadata.generateName is only supported for hooks")
+
+	l, err := k.List(v1.SchemeGroupVersion.WithResource("configmaps"), p.TestSlug(), nil)
+	assert.NoError(t, err)
+	for _, x := range l {
+		assert.False(t, strings.HasPrefix(x.GetK8sName(), "cm-"))
+	}
+}
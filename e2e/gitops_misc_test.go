@@ -1,14 +1,17 @@
 package e2e
 
 import (
+	"fmt"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 )
@@ -137,3 +140,47 @@ func (suite *GitOpsMiscSuite) TestNoTargetError() {
 		g.Expect(kd.Status.LastPrepareError).To(ContainSubstring("a target must be explicitly selected when targets are defined in the Kluctl project"))
 	})
 }
+
+func (suite *GitOpsMiscSuite) TestMaintenanceWindowDeferral() {
+	g := NewWithT(suite.T())
+
+	p := test_project.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	// a window that opens in 2 hours, so that we're guaranteed to be outside of it
+	start := time.Now().UTC().Add(2 * time.Hour)
+	p.UpdateTarget("target1", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField([]any{
+			map[string]any{
+				"schedule": fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour()),
+				"duration": "1h",
+			},
+		}, "maintenanceWindows")
+	})
+	addConfigMapDeployment(p, "d1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	key := suite.createKluctlDeployment(p, "target1", nil)
+
+	suite.Run("deployment is deferred", func() {
+		kd := suite.waitForReconcile(key)
+		assertConfigMapNotExists(suite.T(), suite.k, p.TestSlug(), "cm1")
+
+		c := apimeta.FindStatusCondition(kd.Status.Conditions, kluctlv1.DeployDeferredCondition)
+		g.Expect(c).ToNot(BeNil())
+		g.Expect(c.Status).To(Equal(metav1.ConditionTrue))
+		g.Expect(c.Reason).To(Equal(kluctlv1.OutsideMaintenanceWindowReason))
+	})
+
+	suite.Run("deployment with overridden maintenance window", func() {
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			kd.Spec.OverrideMaintenanceWindow = true
+		})
+		kd := suite.waitForReconcile(key)
+		assertConfigMapExists(suite.T(), suite.k, p.TestSlug(), "cm1")
+
+		g.Expect(apimeta.FindStatusCondition(kd.Status.Conditions, kluctlv1.DeployDeferredCondition)).To(BeNil())
+	})
+}
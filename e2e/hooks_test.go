@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"fmt"
+	"github.com/go-git/go-git/v5"
 	"github.com/kluctl/kluctl/v2/e2e/test-utils"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/stretchr/testify/assert"
@@ -320,3 +321,149 @@ func TestHooksWait(t *testing.T) {
 	_, err = s.ensureHookExecuted2(t, 5*time.Second, "cm1", "hook1", "hook2", "hook3")
 	assert.NoError(t, err)
 }
+
+func TestHooksInclude(t *testing.T) {
+	t.Parallel()
+
+	s := prepareHookTestProjectBase(t)
+
+	s.p.AddKustomizeDeployment("sub/item", nil, nil)
+	s.addConfigMap("sub/item", resourceOpts{name: "cm1", namespace: s.p.TestSlug()})
+
+	for _, dir := range []string{"pre-hooks", "post-hooks"} {
+		s.p.UpdateKustomizeDeployment(dir, func(o *uo.UnstructuredObject, wt *git.Worktree) error {
+			o.SetNestedField("kustomize.config.k8s.io/v1beta1", "apiVersion")
+			o.SetNestedField("Kustomization", "kind")
+			return nil
+		})
+	}
+	// no hook annotation, so it defaults to pre-deploy
+	s.addConfigMap("pre-hooks", resourceOpts{name: "pre1", namespace: s.p.TestSlug()})
+	s.addHookConfigMap("pre-hooks", resourceOpts{name: "pre2", namespace: s.p.TestSlug()}, false, "pre-deploy-upgrade", "")
+	// no hook annotation, so it defaults to post-deploy
+	s.addConfigMap("post-hooks", resourceOpts{name: "post1", namespace: s.p.TestSlug()})
+
+	s.p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[0].SetNestedField("pre-hooks", "preIncludeHooks")
+		_ = items[0].SetNestedField("post-hooks", "postIncludeHooks")
+		return items
+	})
+
+	s.ensureHookExecuted(t, "pre1", "cm1", "post1")
+	s.ensureHookExecuted(t, "pre1", "pre2", "cm1", "post1")
+}
+
+func TestHookResultMount(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	job := uo.FromStringMust(fmt.Sprintf(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hook
+  namespace: %s
+  annotations:
+    kluctl.io/hook: post-deploy
+    kluctl.io/hook-wait: "false"
+    kluctl.io/hook-result-mount-path: /kluctl
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: c
+        image: busybox
+`, p.TestSlug()))
+	cm := createConfigMapObject(map[string]string{"a": "b"}, resourceOpts{name: "cm1", namespace: p.TestSlug()})
+
+	p.AddKustomizeDeployment("item", []test_project.KustomizeResource{
+		{Name: "cm.yml", Content: cm},
+		{Name: "job.yml", Content: job},
+	}, nil)
+
+	getResult := func() *uo.UnstructuredObject {
+		resultCm := assertConfigMapExists(t, k, p.TestSlug(), "hook-result")
+		s, _, _ := resultCm.GetNestedString("data", "result.yaml")
+		r, err := uo.FromString(s)
+		assert.NoError(t, err)
+		return r
+	}
+	cm1Ref := map[string]any{"version": "v1", "kind": "ConfigMap", "name": "cm1", "namespace": p.TestSlug()}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	r := getResult()
+	assertNestedFieldEquals(t, r, []any{cm1Ref}, "appliedObjects")
+	assertNestedFieldEquals(t, r, []any{cm1Ref}, "newObjects")
+	assertNestedFieldEquals(t, r, []any{cm1Ref}, "changedObjects")
+	assertNestedFieldEquals(t, r, []any{}, "errors")
+
+	o := assertObjectExists(t, k, schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, p.TestSlug(), "hook")
+	assertNestedFieldEquals(t, o, "hook-result", "spec", "template", "spec", "volumes", 0, "configMap", "name")
+	assertNestedFieldEquals(t, o, "/kluctl", "spec", "template", "spec", "containers", 0, "volumeMounts", 0, "mountPath")
+
+	// the result ConfigMap belongs to the same deployment item as the hook
+	resultCm := assertConfigMapExists(t, k, p.TestSlug(), "hook-result")
+	assert.Equal(t, o.GetK8sLabels()["kluctl.io/discriminator"], resultCm.GetK8sLabels()["kluctl.io/discriminator"])
+	assertNestedFieldEquals(t, resultCm, "item", "metadata", "annotations", "kluctl.io/deployment-item-dir")
+
+	// nothing changed, so cm1 is only applied
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	r = getResult()
+	assertNestedFieldEquals(t, r, []any{cm1Ref}, "appliedObjects")
+	assertNestedFieldEquals(t, r, []any{}, "newObjects")
+	assertNestedFieldEquals(t, r, []any{}, "changedObjects")
+}
+
+func TestHookResultMountExistingConfigMap(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	job := uo.FromStringMust(fmt.Sprintf(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hook
+  namespace: %s
+  annotations:
+    kluctl.io/hook: post-deploy
+    kluctl.io/hook-wait: "false"
+    kluctl.io/hook-result-mount-path: /kluctl
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: c
+        image: busybox
+`, p.TestSlug()))
+
+	p.AddKustomizeDeployment("item", []test_project.KustomizeResource{
+		{Name: "job.yml", Content: job},
+	}, nil)
+
+	// a ConfigMap owned by the user must never be overwritten
+	k.MustApply(t, createConfigMapObject(map[string]string{"a": "user"}, resourceOpts{name: "hook-result", namespace: p.TestSlug()}))
+
+	stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "refusing to overwrite it")
+
+	cm := assertConfigMapExists(t, k, p.TestSlug(), "hook-result")
+	assertNestedFieldEquals(t, cm, "user", "data", "a")
+	assertObjectNotExists(t, k, schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, p.TestSlug(), "hook")
+}
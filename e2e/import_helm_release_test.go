@@ -0,0 +1,116 @@
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	"path/filepath"
+	"testing"
+)
+
+// buildHelmReleaseSecret builds the release secret the same way Helm's secrets storage driver does
+func buildHelmReleaseSecret(t *testing.T, rls *release.Release) *uo.UnstructuredObject {
+	b, err := json.Marshal(rls)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(b)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return createSecretObject(map[string]string{
+		"release": base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, resourceOpts{
+		name:      "sh.helm.release.v1." + rls.Name + ".v1",
+		namespace: rls.Namespace,
+		labels: map[string]string{
+			"owner":   "helm",
+			"name":    rls.Name,
+			"status":  release.StatusDeployed.String(),
+			"version": "1",
+		},
+	})
+}
+
+func TestImportHelmRelease(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	cm := createConfigMapObject(map[string]string{"k1": "v1"}, resourceOpts{
+		name: "cm1",
+		labels: map[string]string{
+			"app.kubernetes.io/managed-by": "Helm",
+		},
+		annotations: map[string]string{
+			"meta.helm.sh/release-name":      "rel",
+			"meta.helm.sh/release-namespace": p.TestSlug(),
+		},
+	})
+	manifest, err := yaml.WriteYamlString(cm)
+	assert.NoError(t, err)
+
+	rls := &release.Release{
+		Name:      "rel",
+		Namespace: p.TestSlug(),
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "test-chart", Version: "0.1.0"},
+		},
+		Config:   map[string]any{"replicas": float64(2)},
+		Manifest: manifest,
+	}
+
+	cm.SetK8sNamespace(p.TestSlug())
+	k.MustApply(t, cm)
+	k.MustApply(t, buildHelmReleaseSecret(t, rls))
+
+	args := []string{"import-helm-release", "--yes", "-t", "test", "--release", "rel", "-n", p.TestSlug(), "--repo", "https://charts.example.com", "--remove-helm-metadata"}
+
+	// dry-run must neither write the deployment item nor modify the cluster
+	p.KluctlMust(t, append(args, "--dry-run")...)
+	assert.NoFileExists(t, filepath.Join(p.LocalProjectDir(), "rel", "helm-chart.yaml"))
+	o := assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assert.NotContains(t, o.GetK8sLabels(), "kluctl.io/discriminator")
+	assertNestedFieldEquals(t, o, "rel", "metadata", "annotations", "meta.helm.sh/release-name")
+	assertSecretExists(t, k, p.TestSlug(), "sh.helm.release.v1.rel.v1")
+
+	p.KluctlMust(t, args...)
+
+	var chartConfig types.HelmChartConfig
+	err = yaml.ReadYamlFile(filepath.Join(p.LocalProjectDir(), "rel", "helm-chart.yaml"), &chartConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://charts.example.com", chartConfig.Repo)
+	assert.Equal(t, "test-chart", chartConfig.ChartName)
+	assert.Equal(t, "0.1.0", chartConfig.ChartVersion)
+	assert.Equal(t, "rel", chartConfig.ReleaseName)
+
+	values, err := uo.FromFile(filepath.Join(p.LocalProjectDir(), "rel", "helm-values.yaml"))
+	assert.NoError(t, err)
+	replicas, _, _ := values.GetNestedInt("replicas")
+	assert.Equal(t, int64(2), replicas)
+
+	o = assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assert.Equal(t, map[string]string{
+		"kluctl.io/discriminator":      p.Discriminator("test"),
+		"app.kubernetes.io/managed-by": "Helm",
+	}, o.GetK8sLabels())
+	assert.Empty(t, o.GetK8sAnnotations())
+	assertObjectNotExists(t, k, v1.SchemeGroupVersion.WithResource("secrets"), p.TestSlug(), "sh.helm.release.v1.rel.v1")
+}
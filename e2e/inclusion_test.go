@@ -4,6 +4,7 @@ import (
 	"github.com/kluctl/kluctl/v2/e2e/test-utils"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"path/filepath"
 	"reflect"
@@ -230,3 +231,76 @@ func TestInclusionDelete(t *testing.T) {
 	}
 	doAssertExists(nil, a)
 }
+
+func TestInclusionSelector(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {})
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{name: "cm1", namespace: p.TestSlug(), labels: map[string]string{"app": "foo"}})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{name: "cm2", namespace: p.TestSlug(), labels: map[string]string{"app": "bar"}})
+	addConfigMapDeployment(p, "cm3", nil, resourceOpts{name: "cm3", namespace: p.TestSlug()})
+
+	shouldExists := make(map[string]bool)
+	doAssertExists := func(add []string, remove []string) {
+		assertExistsHelper(t, p, k, shouldExists, add, remove)
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "-l", "app=foo")
+	doAssertExists([]string{"cm1"}, nil)
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "-l", "app in (foo,bar)")
+	doAssertExists([]string{"cm2"}, nil)
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "-l", "!app")
+	doAssertExists([]string{"cm3"}, nil)
+
+	p.KluctlMust(t, "delete", "--yes", "-t", "test", "-l", "app=bar")
+	doAssertExists(nil, []string{"cm2"})
+
+	_, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test", "-l", "app==")
+	assert.ErrorContains(t, err, "invalid --selector")
+}
+
+func TestInclusionSelectorPruneChangedLabel(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {})
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{name: "cm1", namespace: p.TestSlug(), labels: map[string]string{"app": "foo"}})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{name: "cm2", namespace: p.TestSlug(), labels: map[string]string{"app": "foo"}})
+
+	shouldExists := make(map[string]bool)
+	doAssertExists := func(add []string, remove []string) {
+		assertExistsHelper(t, p, k, shouldExists, add, remove)
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	doAssertExists([]string{"cm1", "cm2"}, nil)
+
+	// cm1 is still rendered, but its label changed while the live object still has the old label
+	p.UpdateYaml("cm1/configmap-cm1.yml", func(o *uo.UnstructuredObject) error {
+		o.SetK8sLabel("app", "bar")
+		return nil
+	}, "")
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--prune", "-l", "app=foo")
+	doAssertExists(nil, nil)
+	cm1 := assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertNestedFieldEquals(t, cm1, "foo", "metadata", "labels", "app")
+
+	// real orphans matching the selector are still pruned
+	p.DeleteKustomizeDeployment("cm2")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--prune", "-l", "app=foo")
+	doAssertExists(nil, []string{"cm2"})
+}
@@ -0,0 +1,97 @@
+package e2e
+
+import (
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMaxDeletes(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(1, "maxDeletes")
+	})
+
+	for _, n := range []string{"cm1", "cm2", "cm3"} {
+		addConfigMapDeployment(p, n, map[string]string{}, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	p.DeleteKustomizeDeployment("cm2")
+	p.DeleteKustomizeDeployment("cm3")
+
+	stdout, _, err := p.Kluctl(t, "prune", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "refusing to delete 2 objects")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	stdout, _, err = p.Kluctl(t, "deploy", "--yes", "-t", "test", "--prune")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "refusing to delete 2 objects")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	// deploying without pruning is not affected
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	p.KluctlMust(t, "prune", "--yes", "-t", "test", "--max-deletes", "2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm3")
+}
+
+func TestMaxChanges(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(1, "maxChanges")
+	})
+
+	addConfigMapDeployment(p, "cm1", map[string]string{}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+
+	addConfigMapDeployment(p, "cm2", map[string]string{}, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm3", map[string]string{}, resourceOpts{
+		name:      "cm3",
+		namespace: p.TestSlug(),
+	})
+
+	stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "refusing to change 2 objects")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm3")
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--ignore-limits")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+}
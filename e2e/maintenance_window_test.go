@@ -0,0 +1,60 @@
+package e2e
+
+import (
+	"fmt"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	// a window that opens in 2 hours, so that we're guaranteed to be outside of it
+	start := time.Now().UTC().Add(2 * time.Hour)
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField([]any{
+			map[string]any{
+				"schedule": fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour()),
+				"duration": "1h",
+			},
+		}, "maintenanceWindows")
+	})
+
+	addConfigMapDeployment(p, "cm1", map[string]string{}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	_, stderr, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "target is outside of its maintenance windows")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+
+	// dry-runs and diffs are always allowed
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--dry-run")
+	p.KluctlMust(t, "diff", "-t", "test")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--override-maintenance-window")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+
+	// a window that is always open
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField([]any{
+			map[string]any{
+				"schedule": "* * * * *",
+				"duration": "1m",
+			},
+		}, "maintenanceWindows")
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+}
@@ -1,11 +1,15 @@
 package e2e
 
 import (
+	"fmt"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"testing"
 )
 
@@ -125,3 +129,116 @@ func TestPruneForceManaged(t *testing.T) {
 	p.KluctlMust(t, "prune", "--yes", "-t", "test")
 	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
 }
+
+func TestDeleteCollection(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	for _, n := range []string{"cm1", "cm2", "cm3"} {
+		addConfigMapDeployment(p, n, map[string]string{}, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	// cm1 is still part of the deployment, so pruning cm2 and cm3 must not use a deleteCollection request
+	p.DeleteKustomizeDeployment("cm2")
+	p.DeleteKustomizeDeployment("cm3")
+	p.KluctlMust(t, "prune", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm3")
+
+	for _, n := range []string{"cm2", "cm3", "cm4"} {
+		addConfigMapDeployment(p, n, map[string]string{}, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	// all objects of the target are deleted, which allows deleting them with a single request
+	p.KluctlMust(t, "delete", "--yes", "-t", "test")
+	for _, n := range []string{"cm1", "cm2", "cm3", "cm4"} {
+		assertConfigMapNotExists(t, k, p.TestSlug(), n)
+	}
+}
+
+func TestDeleteCollectionUsed(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	username := p.TestSlug()
+	au, err := k.AddUser(envtest.User{Name: username}, nil)
+	assert.NoError(t, err)
+
+	createNamespace(t, k, p.TestSlug())
+
+	// the user is only allowed to use deleteCollection, so deleting single objects would fail
+	rbac := buildSingleNamespaceRbac(username, p.TestSlug(), nil, []schema.GroupResource{{Group: "", Resource: "configmaps"}})
+	rbac = append(rbac, uo.FromStringMust(fmt.Sprintf(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: %s-deletecollection
+  namespace: %s
+rules:
+  - apiGroups: [""]
+    resources: ["configmaps"]
+    verbs: ["deletecollection"]
+`, username, p.TestSlug())), uo.FromStringMust(fmt.Sprintf(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: %s-deletecollection
+  namespace: %s
+subjects:
+  - kind: User
+    name: %s
+roleRef:
+  kind: Role
+  name: %s-deletecollection
+  apiGroup: rbac.authorization.k8s.io
+`, username, p.TestSlug(), username, username)))
+	for _, x := range rbac {
+		k.MustApply(t, x)
+	}
+
+	p.UpdateTarget("test", nil)
+
+	for _, n := range []string{"cm1", "cm2", "cm3"} {
+		addConfigMapDeployment(p, n, map[string]string{}, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	kc, err := au.KubeConfig()
+	assert.NoError(t, err)
+	p.AddExtraArgs("--kubeconfig", getKubeconfigTmpFile(t, kc))
+
+	p.KluctlMust(t, "delete", "--yes", "-t", "test")
+	for _, n := range []string{"cm1", "cm2", "cm3"} {
+		assertConfigMapNotExists(t, k, p.TestSlug(), n)
+	}
+}
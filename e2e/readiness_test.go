@@ -86,6 +86,69 @@ func TestWaitReadinessViaDeployment2(t *testing.T) {
 	})
 }
 
+func TestWaitReadinessViaWaitFor(t *testing.T) {
+	testWaitReadiness(t, func(p *test_project.TestProject) {
+		p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+			items[3].SetNestedField([]map[string]any{
+				{
+					"kind":      "ConfigMap",
+					"namespace": p.TestSlug(),
+					"name":      "cm2",
+				},
+			}, "waitFor")
+			return items
+		})
+	})
+}
+
+func TestWaitForSkippedWithNoWait(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+		annotations: map[string]string{
+			"kluctl.io/is-ready": "false",
+		},
+	})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		items[1].SetNestedField([]map[string]any{
+			{
+				"kind":      "ConfigMap",
+				"namespace": p.TestSlug(),
+				"name":      "cm1",
+			},
+		}, "waitFor")
+		return items
+	})
+
+	stdout, _ := p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--no-wait")
+	assert.Contains(t, stdout, "not waiting for objects referenced via waitFor of cm2 as --no-wait is set")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+}
+
+func TestWaitReadinessViaBarrier(t *testing.T) {
+	testWaitReadiness(t, func(p *test_project.TestProject) {
+		p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+			items[2] = uo.FromMap(map[string]interface{}{
+				"waitReadinessBarrier": true,
+			})
+			return items
+		})
+	})
+}
+
 func TestWaitReadinessViaAnnotation(t *testing.T) {
 	testWaitReadiness(t, func(p *test_project.TestProject) {
 		p.UpdateYaml("cm2/configmap-cm2.yml", func(o *uo.UnstructuredObject) error {
@@ -118,3 +181,41 @@ func TestWaitReadinessViaKustomizationWithHook(t *testing.T) {
 		}, "")
 	})
 }
+
+func TestWaitReadinessBarrierSkipsDeletedHooks(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "hook", nil, resourceOpts{
+		name:      "hook",
+		namespace: p.TestSlug(),
+		annotations: map[string]string{
+			"kluctl.io/hook":               "post-deploy",
+			"kluctl.io/hook-delete-policy": "hook-succeeded",
+		},
+	})
+	p.AddDeploymentItem(".", uo.FromMap(map[string]interface{}{
+		"waitReadinessBarrier": true,
+	}))
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+
+	// the deleted hook must not be waited for, as it would never become ready
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--timeout", (10 * time.Second).String())
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "hook")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+}
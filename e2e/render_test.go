@@ -1,10 +1,14 @@
 package e2e
 
 import (
+	"fmt"
 	"github.com/kluctl/kluctl/lib/yaml"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -59,3 +63,397 @@ func TestRenderNoKubeconfig(t *testing.T) {
 	assert.ErrorContains(t, err, "context \"context1\" does not exist")
 
 }
+
+func TestRenderErrorsAggregated(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	for _, n := range []string{"cm1", "cm2", "cm3"} {
+		addConfigMapDeployment(p, n, nil, resourceOpts{
+			name:      n,
+			namespace: p.TestSlug(),
+		})
+	}
+	p.UpdateFile("cm1/configmap-cm1.yml", func(f string) (string, error) {
+		return f + "\n# {{ missing1.x }}\n", nil
+	}, "")
+	p.UpdateFile("cm3/configmap-cm3.yml", func(f string) (string, error) {
+		return f + "\n# {{ missing3.x }}\n", nil
+	}, "")
+
+	_, _, err := p.Kluctl(t, "render", "-t", "test", "--print-all")
+	assert.ErrorContains(t, err, "failed to render 2 templates")
+	assert.ErrorContains(t, err, "cm1/configmap-cm1.yml:")
+	assert.ErrorContains(t, err, "'missing1' is undefined")
+	assert.ErrorContains(t, err, "cm3/configmap-cm3.yml:")
+	assert.ErrorContains(t, err, "'missing3' is undefined")
+}
+
+func TestRenderCollectArtifacts(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	addConfigMapDeployment(p, "cm", nil, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	p.UpdateFile("cm/docs/README.md", func(f string) (string, error) {
+		return "result: {{ 40 + 2 }}\n", nil
+	}, "")
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[0].SetNestedField([]any{"docs/*.md", ".rendered.yml"}, "artifacts")
+		return items
+	})
+
+	artifactsDir := t.TempDir()
+	p.KluctlMust(t, "render", "-t", "test", "--collect-artifacts", artifactsDir)
+
+	b, err := os.ReadFile(filepath.Join(artifactsDir, "cm/docs/README.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "result: 42", strings.TrimSpace(string(b)))
+	assert.FileExists(t, filepath.Join(artifactsDir, "cm/.rendered.yml"))
+	assert.NoFileExists(t, filepath.Join(artifactsDir, "cm/configmap-cm.yml"))
+
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[0].SetNestedField([]any{"missing/*.md"}, "artifacts")
+		return items
+	})
+	_, _, err = p.Kluctl(t, "render", "-t", "test", "--collect-artifacts", t.TempDir())
+	assert.ErrorContains(t, err, "artifact pattern 'missing/*.md' in cm did not match any files")
+}
+
+func TestRenderOverlay(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("prod", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField("prod", "overlay")
+	})
+	p.UpdateTarget("dev", nil)
+
+	addOverlayDeployment := func(dir string, overlayDir string) {
+		p.AddKustomizeDeployment(dir, []test_utils.KustomizeResource{
+			{Name: "base"},
+		}, nil)
+		p.UpdateYaml(filepath.Join(dir, "base/configmap.yml"), func(o *uo.UnstructuredObject) error {
+			*o = *createConfigMapObject(map[string]string{"env": "base"}, resourceOpts{
+				name:      dir,
+				namespace: p.TestSlug(),
+			})
+			return nil
+		}, "")
+		p.UpdateYaml(filepath.Join(dir, "base/kustomization.yml"), func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField([]any{"configmap.yml"}, "resources")
+			return nil
+		}, "")
+		p.UpdateYaml(filepath.Join(dir, overlayDir, "kustomization.yml"), func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField([]any{"../../base"}, "resources")
+			_ = o.SetNestedField([]any{map[string]any{
+				"patch": fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n  namespace: %s\ndata:\n  env: prod\n", dir, p.TestSlug()),
+			}}, "patches")
+			return nil
+		}, "")
+	}
+
+	addOverlayDeployment("cm1", "overlays/prod")
+	addOverlayDeployment("cm2", "envs/production")
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[1].SetNestedField("envs/production", "overlays", "prod")
+		return items
+	})
+
+	getEnvs := func(target string) map[string]string {
+		stdout, _ := p.KluctlMust(t, "render", "-t", target, "--print-all")
+		y, err := uo.FromStringMulti(stdout)
+		assert.NoError(t, err)
+		ret := map[string]string{}
+		for _, o := range y {
+			v, _, _ := o.GetNestedString("data", "env")
+			ret[o.GetK8sName()] = v
+		}
+		return ret
+	}
+
+	assert.Equal(t, map[string]string{"cm1": "prod", "cm2": "prod"}, getEnvs("prod"))
+	assert.Equal(t, map[string]string{"cm1": "base", "cm2": "base"}, getEnvs("dev"))
+}
+
+func TestRenderPreserveYamlFormat(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	p.AddKustomizeDeployment("cm", []test_utils.KustomizeResource{
+		{Name: "configmap.yml"},
+	}, nil)
+	p.UpdateFile("cm/configmap.yml", func(f string) (string, error) {
+		return fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: %s
+# the data
+data:
+  z: &v "value" # a comment
+  a: *v
+`, p.TestSlug()), nil
+	}, "")
+
+	stdout, _ := p.KluctlMust(t, "render", "-t", "test", "--print-all", "--preserve-yaml-format")
+	assert.Contains(t, stdout, "# the data\ndata:\n  z: &v \"value\" # a comment\n  a: *v\n")
+	assert.True(t, strings.Index(stdout, "name: cm") < strings.Index(stdout, "labels:"))
+
+	y, err := yaml.ReadYamlAllString(stdout)
+	assert.NoError(t, err)
+	assert.Len(t, y, 1)
+	data, _, _ := uo.FromMap(y[0].(map[string]any)).GetNestedStringMapCopy("data")
+	assert.Equal(t, map[string]string{"z": "value", "a": "value"}, data)
+}
+
+func TestRenderOfflineCrdSources(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(p.TestSlug(), "defaultNamespace")
+	})
+
+	buildCrd := func(kind string, scope string) map[string]any {
+		return map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]any{
+				"name": strings.ToLower(kind) + "s.test.kluctl.io",
+			},
+			"spec": map[string]any{
+				"group": "test.kluctl.io",
+				"names": map[string]any{
+					"kind":   kind,
+					"plural": strings.ToLower(kind) + "s",
+				},
+				"scope": scope,
+			},
+		}
+	}
+
+	p.UpdateYaml("crds/namespaced.yaml", func(o *uo.UnstructuredObject) error {
+		*o = *uo.FromMap(buildCrd("NamespacedThing", "Namespaced"))
+		return nil
+	}, "")
+	p.UpdateYaml("crds/export.yaml", func(o *uo.UnstructuredObject) error {
+		*o = *uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items": []any{
+				buildCrd("ClusterThing", "Cluster"),
+			},
+		})
+		return nil
+	}, "")
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]any{
+			map[string]any{"path": "crds"},
+		}, "crdSources")
+		return nil
+	})
+
+	p.AddKustomizeDeployment("things", []test_utils.KustomizeResource{
+		{Name: "namespaced.yaml", Content: uo.FromMap(map[string]any{
+			"apiVersion": "test.kluctl.io/v1",
+			"kind":       "NamespacedThing",
+			"metadata":   map[string]any{"name": "n1"},
+		})},
+		{Name: "cluster.yaml", Content: uo.FromMap(map[string]any{
+			"apiVersion": "test.kluctl.io/v1",
+			"kind":       "ClusterThing",
+			"metadata":   map[string]any{"name": "c1"},
+		})},
+	}, nil)
+
+	stdout, _ := p.KluctlMust(t, "render", "-t", "test", "--print-all", "--offline-kubernetes")
+	y, err := uo.FromStringMulti(stdout)
+	assert.NoError(t, err)
+	assert.Len(t, y, 2)
+
+	for _, o := range y {
+		switch o.GetK8sGVK().Kind {
+		case "NamespacedThing":
+			assert.Equal(t, p.TestSlug(), o.GetK8sNamespace())
+		case "ClusterThing":
+			assert.Equal(t, "", o.GetK8sNamespace())
+		default:
+			t.Errorf("unexpected kind %s", o.GetK8sGVK().Kind)
+		}
+	}
+}
+
+func buildSchemaTestCrd(group string, kind string) map[string]any {
+	return map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name": strings.ToLower(kind) + "s." + group,
+		},
+		"spec": map[string]any{
+			"group": group,
+			"names": map[string]any{
+				"kind":     kind,
+				"listKind": kind + "List",
+				"plural":   strings.ToLower(kind) + "s",
+				"singular": strings.ToLower(kind),
+			},
+			"scope": "Namespaced",
+			"versions": []any{
+				map[string]any{
+					"name":    "v1",
+					"served":  true,
+					"storage": true,
+					"schema": map[string]any{
+						"openAPIV3Schema": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"spec": map[string]any{
+									"type": "object",
+									"properties": map[string]any{
+										"replicas": map[string]any{"type": "integer"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderOfflineCrdSourcesValidation(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(p.TestSlug(), "defaultNamespace")
+	})
+
+	p.UpdateYaml("crds/thing.yaml", func(o *uo.UnstructuredObject) error {
+		*o = *uo.FromMap(buildSchemaTestCrd("test.kluctl.io", "Thing"))
+		return nil
+	}, "")
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]any{
+			map[string]any{"path": "crds"},
+		}, "crdSources")
+		return nil
+	})
+
+	p.AddKustomizeDeployment("things", []test_utils.KustomizeResource{
+		{Name: "t1.yaml", Content: uo.FromMap(map[string]any{
+			"apiVersion": "test.kluctl.io/v1",
+			"kind":       "Thing",
+			"metadata":   map[string]any{"name": "t1"},
+			"spec":       map[string]any{"replicas": 1},
+		})},
+	}, nil)
+
+	p.KluctlMust(t, "render", "-t", "test", "--offline-kubernetes")
+
+	p.UpdateYaml("things/t1.yaml", func(o *uo.UnstructuredObject) error {
+		return o.SetNestedField("one", "spec", "replicas")
+	}, "")
+
+	_, _, err := p.Kluctl(t, "render", "-t", "test", "--offline-kubernetes")
+	assert.ErrorContains(t, err, "Thing/t1 is invalid according to the schema from crdSources")
+	assert.ErrorContains(t, err, "spec.replicas")
+}
+
+func TestRenderCrdSourcesContext(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+	p := test_utils.NewTestProject(t)
+
+	group := p.TestSlug() + ".kluctl.io"
+	k.MustApply(t, uo.FromMap(buildSchemaTestCrd(group, "Thing")))
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(p.TestSlug(), "defaultNamespace")
+	})
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]any{
+			map[string]any{"context": k.Context},
+		}, "crdSources")
+		return nil
+	})
+
+	p.AddKustomizeDeployment("things", []test_utils.KustomizeResource{
+		{Name: "t1.yaml", Content: uo.FromMap(map[string]any{
+			"apiVersion": group + "/v1",
+			"kind":       "Thing",
+			"metadata":   map[string]any{"name": "t1"},
+			"spec":       map[string]any{"replicas": "one"},
+		})},
+	}, nil)
+
+	// the CRD is known to the cluster, so the API server is responsible for validation
+	p.KluctlMust(t, "render", "-t", "test")
+
+	// the exported CRDs were cached and are used for scope detection and validation
+	_, _, err := p.Kluctl(t, "render", "-t", "test", "--offline-kubernetes")
+	assert.ErrorContains(t, err, "Thing/t1 is invalid according to the schema from crdSources")
+
+	p.UpdateYaml("things/t1.yaml", func(o *uo.UnstructuredObject) error {
+		return o.SetNestedField(1, "spec", "replicas")
+	}, "")
+	stdout, _ := p.KluctlMust(t, "render", "-t", "test", "--print-all", "--offline-kubernetes")
+	y, err := uo.FromStringMulti(stdout)
+	assert.NoError(t, err)
+	if assert.Len(t, y, 1) {
+		assert.Equal(t, p.TestSlug(), y[0].GetK8sNamespace())
+	}
+}
+
+func TestRenderKeepRenderTmp(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"a": "{{ target.name }}",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	keepDir := t.TempDir()
+	p.KluctlMust(t, "render", "-t", "test", "--keep-render-tmp", keepDir)
+
+	itemDirs, err := filepath.Glob(filepath.Join(keepDir, "*", "cm"))
+	assert.NoError(t, err)
+	assert.Len(t, itemDirs, 1)
+
+	assert.DirExists(t, filepath.Join(itemDirs[0], "post-jinja2"))
+	assert.FileExists(t, filepath.Join(itemDirs[0], "pre-kustomize", "kustomization.yml"))
+
+	y, err := uo.FromFileMulti(filepath.Join(itemDirs[0], "post-kustomize.yaml"))
+	assert.NoError(t, err)
+	assert.Len(t, y, 1)
+	v, _, _ := y[0].GetNestedString("data", "a")
+	assert.Equal(t, "test", v)
+}
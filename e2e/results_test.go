@@ -8,7 +8,9 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"testing"
+	"time"
 )
 
 func assertSummary(t *testing.T, expected result.CommandResultSummary, actual result.CommandResultSummary) {
@@ -116,3 +118,55 @@ func TestWriteResult(t *testing.T) {
 		DeletedObjects: 1,
 	}, summaries[0])
 }
+
+func TestWriteKluctlDeployResult(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	crd, err := uo.FromFile("../config/crd/bases/gitops.kluctl.io_kluctldeployresults.yaml")
+	assert.NoError(t, err)
+	k.MustApply(t, crd)
+
+	gvr := schema.GroupVersionResource{Group: "gitops.kluctl.io", Version: "v1beta1", Resource: "kluctldeployresults"}
+	assert.Eventually(t, func() bool {
+		_, err := k.List(gvr, "", nil)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+	resultsNs := p.TestSlug() + "-results"
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", nil, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--command-result-namespace", resultsNs, "--write-kluctl-deploy-result")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm")
+
+	l, err := k.List(gvr, resultsNs, nil)
+	assert.NoError(t, err)
+	assert.Len(t, l, 1)
+	assertNestedFieldEquals(t, l[0], "deploy", "spec", "command")
+	assertNestedFieldEquals(t, l[0], "test", "spec", "targetName")
+	assertNestedFieldEquals(t, l[0], int64(1), "spec", "newObjects")
+	assertNestedFieldEquals(t, l[0], int64(0), "spec", "errors")
+
+	// without the flag, no new objects must be written
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--command-result-namespace", resultsNs)
+	l, err = k.List(gvr, resultsNs, nil)
+	assert.NoError(t, err)
+	assert.Len(t, l, 1)
+
+	// old objects are still cleaned up together with their command results
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--command-result-namespace", resultsNs, "--keep-command-results-count", "1")
+	assert.Eventually(t, func() bool {
+		l, err = k.List(gvr, resultsNs, nil)
+		return err == nil && len(l) == 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
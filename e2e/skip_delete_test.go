@@ -73,6 +73,47 @@ func TestSkipDelete(t *testing.T) {
 	assertConfigMapExists(t, k, p.TestSlug(), "cm4")
 }
 
+func TestAlwaysSkipDelete(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm1", map[string]string{}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm2", map[string]string{}, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+	p.UpdateDeploymentItems(".", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[1].SetNestedField(true, "alwaysSkipDelete")
+		return items
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	cm2 := assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assert.Equal(t, "true", cm2.GetK8sAnnotations()["kluctl.io/skip-delete"])
+
+	p.KluctlMust(t, "delete", "--yes", "-t", "test")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	p.DeleteKustomizeDeployment("cm1")
+	p.DeleteKustomizeDeployment("cm2")
+	p.KluctlMust(t, "prune", "--yes", "-t", "test")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+}
+
 func TestForceReplaceSkipDelete(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+func TestTakeOwnership(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+	addConfigMapDeployment(p, "cm1", map[string]string{
+		"k1": "v1",
+		"k2": "v2",
+	}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	o := assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	patch := client.MergeFrom(o.ToUnstructured().DeepCopy())
+	_ = o.SetNestedField("x1", "data", "k1")
+	_ = o.SetNestedField("x2", "data", "k2")
+	err := k.Client.Patch(context.Background(), o.ToUnstructured(), patch, client.FieldOwner("test-field-manager"))
+	assert.NoError(t, err)
+
+	ref := fmt.Sprintf("core/ConfigMap/%s/cm1", p.TestSlug())
+
+	stdout, _ := p.KluctlMust(t, "take-ownership", "--yes", "-t", "test", "--ref", ref, "--field", "data.k1")
+	var r []commands.TakeOwnershipResult
+	err = yaml.ReadYamlString(stdout, &r)
+	assert.NoError(t, err)
+	assert.Len(t, r, 1)
+	assert.Equal(t, []diff.TakenOwnership{
+		{Field: ".data.k1", Manager: "test-field-manager"},
+	}, r[0].Taken)
+
+	o = assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertNestedFieldEquals(t, o, "v1", "data", "k1")
+	assertNestedFieldEquals(t, o, "x2", "data", "k2")
+
+	p.KluctlMust(t, "take-ownership", "--yes", "-t", "test", "--ref", ref)
+	o = assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertNestedFieldEquals(t, o, "v1", "data", "k1")
+	assertNestedFieldEquals(t, o, "v2", "data", "k2")
+
+	_, _, err = p.Kluctl(t, "take-ownership", "--yes", "-t", "test", "--ref", fmt.Sprintf("core/ConfigMap/%s/cm2", p.TestSlug()))
+	assert.ErrorContains(t, err, "is not part of the rendered target")
+}
+
+func TestOwnershipReport(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+	addConfigMapDeployment(p, "cm1", map[string]string{
+		"k1": "v1",
+		"k2": "v2",
+	}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	o := assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	patch := client.MergeFrom(o.ToUnstructured().DeepCopy())
+	_ = o.SetNestedField("x3", "data", "k3")
+	err := k.Client.Patch(context.Background(), o.ToUnstructured(), patch, client.FieldOwner("test-field-manager"))
+	assert.NoError(t, err)
+
+	ref := fmt.Sprintf("core/ConfigMap/%s/cm1", p.TestSlug())
+
+	stdout, _ := p.KluctlMust(t, "ownership", "-t", "test", "--ref", ref, "--depth", "0")
+	var r []commands.OwnershipResult
+	err = yaml.ReadYamlString(stdout, &r)
+	assert.NoError(t, err)
+	assert.Len(t, r, 1)
+	assert.Contains(t, r[0].Fields, diff.FieldOwnership{Field: ".data.k1", Managers: []string{"kluctl"}})
+	assert.Contains(t, r[0].Fields, diff.FieldOwnership{Field: ".data.k3", Managers: []string{"test-field-manager"}})
+}
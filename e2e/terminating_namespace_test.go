@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"context"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestApplyIntoTerminatingNamespace(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	terminatingNs := p.TestSlug() + "-terminating"
+	createNamespace(t, k, p.TestSlug())
+	createNamespace(t, k, terminatingNs)
+
+	// envtest has no namespace controller, so the namespace will stay in the terminating state
+	err := k.DynamicClient.Resource(v1.SchemeGroupVersion.WithResource("namespaces")).Delete(context.Background(), terminatingNs, metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name:      "cm2",
+		namespace: terminatingNs,
+	})
+
+	stdout, _, err := p.Kluctl(t, "deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "namespace "+terminatingNs+" is being deleted, refusing to apply")
+
+	// nothing must have been applied
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+}
+
+func TestDeleteVerifyNamespaceCleanup(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	ns := p.TestSlug() + "-owned"
+
+	p.UpdateTarget("test", nil)
+	p.AddKustomizeDeployment("ns", []test_utils.KustomizeResource{
+		{Name: "namespace.yml", Content: createCoreV1Object("Namespace", resourceOpts{name: ns})},
+	}, nil)
+	p.AddDeploymentItem("", uo.FromMap(map[string]interface{}{
+		"barrier": true,
+	}))
+	addConfigMapDeployment(p, "cm1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: ns,
+	})
+	p.UpdateYaml("cm1/configmap-cm1.yml", func(o *uo.UnstructuredObject) error {
+		return o.SetNestedField([]any{"example.com/stuck"}, "metadata", "finalizers")
+	}, "")
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, ns, "cm1")
+
+	// envtest has no namespace controller, so we have to simulate it by deleting the ConfigMap, which will then be stuck
+	// because of its finalizer
+	err := k.DynamicClient.Resource(v1.SchemeGroupVersion.WithResource("configmaps")).Namespace(ns).Delete(context.Background(), "cm1", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	stdout, _, err := p.Kluctl(t, "delete", "--yes", "-t", "test", "--namespace-cleanup-timeout", "3s")
+	assert.Error(t, err)
+	assert.Contains(t, stdout, "namespace is still terminating after 3s, 1 objects are stuck in Terminating")
+	assert.Contains(t, stdout, "object is stuck in Terminating, blocked by finalizers: example.com/stuck")
+}
@@ -0,0 +1,40 @@
+package status
+
+import (
+	"context"
+)
+
+// Fields are structured key/value pairs that are attached to all messages reported via a context, e.g. the target,
+// deployment item or object ref the messages relate to.
+type Fields map[string]string
+
+// StructuredStatusHandler is implemented by status handlers that can make use of Fields.
+type StructuredStatusHandler interface {
+	StatusHandler
+
+	// WithFields returns a status handler that attaches the given fields to all reported messages
+	WithFields(fields Fields) StatusHandler
+}
+
+type fieldsContextKey struct{}
+
+// WithFields returns a new context that attaches the given key/value pairs to all messages reported via this context.
+// Fields from the parent context are preserved, unless overridden.
+func WithFields(ctx context.Context, keysAndValues ...string) context.Context {
+	fields := Fields{}
+	for k, v := range FieldsFromContext(ctx) {
+		fields[k] = v
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[keysAndValues[i]] = keysAndValues[i+1]
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, fields)
+}
+
+func FieldsFromContext(ctx context.Context) Fields {
+	v := ctx.Value(fieldsContextKey{})
+	if v == nil {
+		return nil
+	}
+	return v.(Fields)
+}
@@ -0,0 +1,121 @@
+package status
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultJsonFields are always present in records written by the JsonStatusHandler, so that log pipelines can rely
+// on them.
+var DefaultJsonFields = []string{"target", "item", "ref"}
+
+// ProgressEvent describes the records written by the JsonStatusHandler. All fields are always present, but might be
+// empty. Fields added via WithFields are written as additional string properties. The published JSON schema of this
+// type is progress-events.json.
+type ProgressEvent struct {
+	Time   string `json:"time"`
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Target string `json:"target"`
+	Item   string `json:"item"`
+	Ref    string `json:"ref"`
+}
+
+// JsonStatusHandler writes one JSON record per message, which allows ingesting the output via log pipelines.
+// Progress updates are not written, only the start messages of progress lines.
+type JsonStatusHandler struct {
+	out      io.Writer
+	mutex    *sync.Mutex
+	minLevel Level
+	trace    bool
+	fields   Fields
+}
+
+type jsonStatusLine struct {
+}
+
+func NewJsonStatusHandler(out io.Writer, minLevel Level, trace bool) *JsonStatusHandler {
+	return &JsonStatusHandler{
+		out:      out,
+		mutex:    &sync.Mutex{},
+		minLevel: minLevel,
+		trace:    trace,
+	}
+}
+
+func (s *JsonStatusHandler) WithFields(fields Fields) StatusHandler {
+	s2 := *s
+	s2.fields = Fields{}
+	for k, v := range s.fields {
+		s2.fields[k] = v
+	}
+	for k, v := range fields {
+		s2.fields[k] = v
+	}
+	return &s2
+}
+
+func (s *JsonStatusHandler) IsTraceEnabled() bool {
+	return s.trace
+}
+
+func (s *JsonStatusHandler) Stop() {
+}
+
+func (s *JsonStatusHandler) Flush() {
+}
+
+func (s *JsonStatusHandler) StartStatus(level Level, total int, message string) StatusLine {
+	if message != "" {
+		s.Message(level, message)
+	}
+	return &jsonStatusLine{}
+}
+
+func (s *JsonStatusHandler) Message(level Level, message string) {
+	if level == LevelTrace && !s.trace {
+		return
+	}
+	if !IsLevelEnabled(level, s.minLevel) {
+		return
+	}
+
+	record := map[string]string{}
+	for _, k := range DefaultJsonFields {
+		record[k] = ""
+	}
+	for k, v := range s.fields {
+		record[k] = v
+	}
+	record["time"] = time.Now().Format(time.RFC3339Nano)
+	record["level"] = LevelName(level)
+	record["msg"] = message
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, _ = s.out.Write(b)
+}
+
+func (s *JsonStatusHandler) MessageFallback(level Level, message string) {
+	s.Message(level, message)
+}
+
+func (sl *jsonStatusLine) SetTotal(total int) {
+}
+
+func (sl *jsonStatusLine) Increment() {
+}
+
+func (sl *jsonStatusLine) Update(message string) {
+}
+
+func (sl *jsonStatusLine) End(result EndResult) {
+}
@@ -0,0 +1,84 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readJsonRecords(t *testing.T, buf *bytes.Buffer) []map[string]string {
+	var ret []map[string]string
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		var m map[string]string
+		err := json.Unmarshal([]byte(l), &m)
+		assert.NoError(t, err)
+		delete(m, "time")
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func TestJsonStatusHandler(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonStatusHandler(buf, LevelTrace, false))
+
+	Info(ctx, "no fields")
+	ctx = WithFields(ctx, "target", "t1")
+	Trace(ctx, "trace is disabled")
+	s := Start(WithFields(ctx, "item", "i1"), "progress")
+	s.Update("updates are not written")
+	s.Success()
+	Warning(WithFields(ctx, "ref", "ns/ConfigMap/cm", "extra", "x"), "warning")
+
+	assert.Equal(t, []map[string]string{
+		{"level": "info", "msg": "no fields", "target": "", "item": "", "ref": ""},
+		{"level": "info", "msg": "progress", "target": "t1", "item": "i1", "ref": ""},
+		{"level": "warning", "msg": "warning", "target": "t1", "item": "", "ref": "ns/ConfigMap/cm", "extra": "x"},
+	}, readJsonRecords(t, buf))
+}
+
+func TestJsonStatusHandlerMinLevel(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonStatusHandler(buf, LevelWarning, true))
+
+	Trace(ctx, "trace")
+	Info(ctx, "info")
+	s := Start(ctx, "progress")
+	s.Success()
+	Warning(ctx, "warning")
+	Error(ctx, "error")
+
+	assert.Equal(t, []map[string]string{
+		{"level": "warning", "msg": "warning", "target": "", "item": "", "ref": ""},
+		{"level": "error", "msg": "error", "target": "", "item": "", "ref": ""},
+	}, readJsonRecords(t, buf))
+}
+
+func TestJsonStatusHandlerProgressEvent(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonStatusHandler(buf, LevelTrace, false))
+	Info(ctx, "msg")
+
+	var m map[string]string
+	err := json.Unmarshal(buf.Bytes(), &m)
+	assert.NoError(t, err)
+
+	// the published ProgressEvent must cover exactly the fields that are always written
+	var expected []string
+	pt := reflect.TypeOf(ProgressEvent{})
+	for i := 0; i < pt.NumField(); i++ {
+		expected = append(expected, pt.Field(i).Tag.Get("json"))
+	}
+	var actual []string
+	for k := range m {
+		actual = append(actual, k)
+	}
+	assert.ElementsMatch(t, expected, actual)
+}
@@ -0,0 +1,130 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordingStatusHandler forwards all messages to another status handler and additionally records them, including
+// trace messages and progress updates. The recorded log can later be retrieved via Lines, e.g. to attach it to a
+// support bundle.
+type RecordingStatusHandler struct {
+	inner  StatusHandler
+	rec    *statusRecorder
+	fields Fields
+}
+
+type statusRecorder struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+type recordingStatusLine struct {
+	s     *RecordingStatusHandler
+	inner StatusLine
+	level Level
+}
+
+func NewRecordingStatusHandler(inner StatusHandler) *RecordingStatusHandler {
+	return &RecordingStatusHandler{
+		inner: inner,
+		rec:   &statusRecorder{},
+	}
+}
+
+// Lines returns all recorded log lines
+func (s *RecordingStatusHandler) Lines() []string {
+	s.rec.mutex.Lock()
+	defer s.rec.mutex.Unlock()
+	ret := make([]string, len(s.rec.lines))
+	copy(ret, s.rec.lines)
+	return ret
+}
+
+func (s *RecordingStatusHandler) WithFields(fields Fields) StatusHandler {
+	s2 := *s
+	if sh, ok := s.inner.(StructuredStatusHandler); ok {
+		s2.inner = sh.WithFields(fields)
+	}
+	s2.fields = Fields{}
+	for k, v := range s.fields {
+		s2.fields[k] = v
+	}
+	for k, v := range fields {
+		s2.fields[k] = v
+	}
+	return &s2
+}
+
+func (s *RecordingStatusHandler) record(level Level, message string) {
+	var keys []string
+	for k := range s.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", time.Now().Format(time.RFC3339Nano), LevelName(level)))
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf(" %s=%s", k, s.fields[k]))
+	}
+	sb.WriteString(" ")
+	sb.WriteString(message)
+
+	s.rec.mutex.Lock()
+	defer s.rec.mutex.Unlock()
+	s.rec.lines = append(s.rec.lines, sb.String())
+}
+
+func (s *RecordingStatusHandler) IsTraceEnabled() bool {
+	return s.inner.IsTraceEnabled()
+}
+
+func (s *RecordingStatusHandler) Stop() {
+	s.inner.Stop()
+}
+
+func (s *RecordingStatusHandler) Flush() {
+	s.inner.Flush()
+}
+
+func (s *RecordingStatusHandler) StartStatus(level Level, total int, message string) StatusLine {
+	if message != "" {
+		s.record(level, message)
+	}
+	return &recordingStatusLine{
+		s:     s,
+		inner: s.inner.StartStatus(level, total, message),
+		level: level,
+	}
+}
+
+func (s *RecordingStatusHandler) Message(level Level, message string) {
+	s.record(level, message)
+	s.inner.Message(level, message)
+}
+
+func (s *RecordingStatusHandler) MessageFallback(level Level, message string) {
+	s.record(level, message)
+	s.inner.MessageFallback(level, message)
+}
+
+func (sl *recordingStatusLine) SetTotal(total int) {
+	sl.inner.SetTotal(total)
+}
+
+func (sl *recordingStatusLine) Increment() {
+	sl.inner.Increment()
+}
+
+func (sl *recordingStatusLine) Update(message string) {
+	sl.s.record(sl.level, message)
+	sl.inner.Update(message)
+}
+
+func (sl *recordingStatusLine) End(result EndResult) {
+	sl.inner.End(result)
+}
@@ -0,0 +1,40 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRecordingStatusHandler(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	rsh := NewRecordingStatusHandler(NewJsonStatusHandler(buf, LevelTrace, false))
+	ctx := NewContext(context.Background(), rsh)
+
+	Info(ctx, "info")
+	Trace(ctx, "trace")
+	s := Start(WithFields(ctx, "target", "t1", "item", "i1"), "progress")
+	s.Update("update")
+	s.Success()
+
+	// the time is stripped, as it is not reproducible
+	var lines []string
+	for _, l := range rsh.Lines() {
+		_, l, _ = strings.Cut(l, " ")
+		lines = append(lines, l)
+	}
+	assert.Equal(t, []string{
+		"info info",
+		"trace trace",
+		"info item=i1 target=t1 progress",
+		"info item=i1 target=t1 update",
+	}, lines)
+
+	// trace messages are recorded, but still not forwarded, fields are forwarded
+	assert.Equal(t, []map[string]string{
+		{"level": "info", "msg": "info", "target": "", "item": "", "ref": ""},
+		{"level": "info", "msg": "progress", "target": "t1", "item": "i1", "ref": ""},
+	}, readJsonRecords(t, buf))
+}
@@ -37,6 +37,39 @@ const (
 	LevelPrompt
 )
 
+// LevelName returns the lower-case name of the level, as used in structured logs
+func LevelName(level Level) string {
+	switch level {
+	case LevelTrace:
+		return "trace"
+	case LevelInfo, LevelProgress:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelPrompt:
+		return "prompt"
+	default:
+		return "unknown"
+	}
+}
+
+// IsLevelEnabled returns true if messages of the given level must be reported when only messages of minLevel and
+// above are requested. Progress messages are treated like info messages and prompts are always reported.
+func IsLevelEnabled(level Level, minLevel Level) bool {
+	severity := func(l Level) Level {
+		if l == LevelProgress {
+			return LevelInfo
+		}
+		return l
+	}
+	if level == LevelPrompt {
+		return true
+	}
+	return severity(level) >= severity(minLevel)
+}
+
 type StatusLine interface {
 	SetTotal(total int)
 	Increment()
@@ -84,6 +117,11 @@ func getContextValue(ctx context.Context) *contextValue {
 
 func FromContext(ctx context.Context) StatusHandler {
 	v := getContextValue(ctx)
+	if fields := FieldsFromContext(ctx); len(fields) != 0 {
+		if sh, ok := v.slh.(StructuredStatusHandler); ok {
+			return sh.WithFields(fields)
+		}
+	}
 	return v.slh
 }
 
@@ -312,7 +350,7 @@ func Errorf(ctx context.Context, status string, args ...any) {
 func Deprecation(ctx context.Context, key string, message string) {
 	cv := getContextValue(ctx)
 	cv.deprecationOnce.Do(key, func() {
-		cv.slh.Message(LevelWarning, message)
+		FromContext(ctx).Message(LevelWarning, message)
 	})
 }
 
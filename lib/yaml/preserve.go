@@ -0,0 +1,341 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	yaml3 "gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// MergeIntoNode returns a yaml.v3 node that represents v while preserving as much as possible from the original
+// node n, which is usually the node of the source file that v was generated from. Sub-trees that did not change are
+// taken over from n as they are, including key order, comments, styles, anchors, aliases and merge keys. Changed
+// scalars keep their comments. Keys that are new in v are appended in sorted order.
+//
+// n itself is never modified.
+func MergeIntoNode(n *yaml3.Node, v any) (*yaml3.Node, error) {
+	nv, err := normalizeValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := nodeMerger{
+		keptAnchors: map[*yaml3.Node]bool{},
+	}
+	if n.Kind == yaml3.DocumentNode && len(n.Content) == 1 {
+		c, err := m.merge(n.Content[0], nv)
+		if err != nil {
+			return nil, err
+		}
+		ret := *n
+		ret.Content = []*yaml3.Node{c}
+		return &ret, nil
+	}
+	return m.merge(n, nv)
+}
+
+// WriteYamlNodesStream writes multiple yaml.v3 nodes as a multi document stream
+func WriteYamlNodesStream(w io.Writer, l []*yaml3.Node) error {
+	enc := yaml3.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, n := range l {
+		err := enc.Encode(n)
+		if err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// ReadYamlNodesStream reads all documents from the given stream as yaml.v3 nodes. The tags of merge keys are cleared,
+// as yaml.v3 would otherwise emit them explicitly ("!!merge <<: *x") when encoding the nodes again.
+func ReadYamlNodesStream(r io.Reader) ([]*yaml3.Node, error) {
+	dec := yaml3.NewDecoder(newUnicodeReader(r))
+	var ret []*yaml3.Node
+	for {
+		var n yaml3.Node
+		err := dec.Decode(&n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		clearMergeTags(&n)
+		ret = append(ret, &n)
+	}
+	return ret, nil
+}
+
+func isMergeKey(n *yaml3.Node) bool {
+	return n.Kind == yaml3.ScalarNode && n.Value == "<<" && (n.Tag == "" || n.Tag == "!!merge")
+}
+
+func clearMergeTags(n *yaml3.Node) {
+	if n.Kind == yaml3.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if isMergeKey(n.Content[i]) {
+				n.Content[i].Tag = ""
+			}
+		}
+	}
+	for _, c := range n.Content {
+		clearMergeTags(c)
+	}
+}
+
+type nodeMerger struct {
+	// all anchored nodes that are part of the output in their original form, meaning that aliases to these are still
+	// valid
+	keptAnchors map[*yaml3.Node]bool
+}
+
+// normalizeValue converts v into the same representation that json.Unmarshal would produce, so that values decoded
+// from yaml nodes can be compared with reflect.DeepEqual
+func normalizeValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var ret any
+	d := json.NewDecoder(bytes.NewReader(b))
+	err = d.Decode(&ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func decodeNode(n *yaml3.Node) (any, bool) {
+	var x any
+	err := n.Decode(&x)
+	if err != nil {
+		return nil, false
+	}
+	x, err = normalizeValue(x)
+	if err != nil {
+		// this happens for non-string keys
+		return nil, false
+	}
+	return x, true
+}
+
+func (m *nodeMerger) isUnchanged(n *yaml3.Node, v any) bool {
+	x, ok := decodeNode(n)
+	if !ok || !reflect.DeepEqual(x, v) {
+		return false
+	}
+	return m.aliasesResolvable(n, map[*yaml3.Node]bool{})
+}
+
+// aliasesResolvable checks that all aliases inside n refer to anchors that are part of the output
+func (m *nodeMerger) aliasesResolvable(n *yaml3.Node, local map[*yaml3.Node]bool) bool {
+	if n.Kind == yaml3.AliasNode {
+		return m.keptAnchors[n.Alias] || local[n.Alias]
+	}
+	if n.Anchor != "" {
+		local[n] = true
+	}
+	for _, c := range n.Content {
+		if !m.aliasesResolvable(c, local) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *nodeMerger) keep(n *yaml3.Node) *yaml3.Node {
+	var walk func(n *yaml3.Node)
+	walk = func(n *yaml3.Node) {
+		if n.Kind == yaml3.AliasNode {
+			return
+		}
+		if n.Anchor != "" {
+			m.keptAnchors[n] = true
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(n)
+	return n
+}
+
+func newNode(v any) (*yaml3.Node, error) {
+	var n yaml3.Node
+	err := n.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func copyComments(to *yaml3.Node, from *yaml3.Node) {
+	to.HeadComment = from.HeadComment
+	to.LineComment = from.LineComment
+	to.FootComment = from.FootComment
+}
+
+func (m *nodeMerger) merge(n *yaml3.Node, v any) (*yaml3.Node, error) {
+	if m.isUnchanged(n, v) {
+		return m.keep(n), nil
+	}
+
+	if n.Kind == yaml3.AliasNode {
+		// the aliased value got changed, so we can't keep the alias
+		ret, err := newNode(v)
+		if err != nil {
+			return nil, err
+		}
+		copyComments(ret, n)
+		return ret, nil
+	}
+
+	switch v2 := v.(type) {
+	case map[string]any:
+		if n.Kind == yaml3.MappingNode {
+			return m.mergeMapping(n, v2)
+		}
+	case []any:
+		if n.Kind == yaml3.SequenceNode {
+			return m.mergeSequence(n, v2)
+		}
+	}
+
+	ret, err := newNode(v)
+	if err != nil {
+		return nil, err
+	}
+	copyComments(ret, n)
+	if n.Kind == ret.Kind && n.Kind == yaml3.ScalarNode && ret.Tag == n.Tag {
+		// keep quoting style and friends, but only if the type did not change
+		ret.Style = n.Style
+	}
+	return ret, nil
+}
+
+func (m *nodeMerger) mergeSequence(n *yaml3.Node, v []any) (*yaml3.Node, error) {
+	ret := *n
+	ret.Anchor = ""
+	ret.Content = nil
+	for i, x := range v {
+		var c *yaml3.Node
+		var err error
+		if i < len(n.Content) {
+			c, err = m.merge(n.Content[i], x)
+		} else {
+			c, err = newNode(x)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret.Content = append(ret.Content, c)
+	}
+	return &ret, nil
+}
+
+func (m *nodeMerger) mergeMapping(n *yaml3.Node, v map[string]any) (*yaml3.Node, error) {
+	ret := *n
+	ret.Anchor = ""
+	ret.Content = nil
+
+	explicit := map[string]bool{}
+	var mergeKey, mergeValue *yaml3.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if isMergeKey(k) {
+			mergeKey, mergeValue = k, n.Content[i+1]
+			continue
+		}
+		explicit[k.Value] = true
+	}
+
+	// check if all keys provided via the merge key are still the same, in which case we can keep the merge key
+	var merged map[string]any
+	keepMerge := false
+	if mergeKey != nil {
+		x, ok := decodeNode(&yaml3.Node{Kind: yaml3.MappingNode, Content: []*yaml3.Node{mergeKey, mergeValue}})
+		merged, _ = x.(map[string]any)
+		keepMerge = ok && merged != nil && m.aliasesResolvable(mergeValue, map[*yaml3.Node]bool{})
+		for k, mv := range merged {
+			if explicit[k] {
+				continue
+			}
+			if fv, ok := v[k]; !ok || !reflect.DeepEqual(fv, mv) {
+				keepMerge = false
+				break
+			}
+		}
+	}
+
+	handled := map[string]bool{}
+	appendNew := func(keys []string) error {
+		sort.Strings(keys)
+		for _, k := range keys {
+			kn, err := newNode(k)
+			if err != nil {
+				return err
+			}
+			vn, err := newNode(v[k])
+			if err != nil {
+				return err
+			}
+			ret.Content = append(ret.Content, kn, vn)
+			handled[k] = true
+		}
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if k == mergeKey {
+			if keepMerge {
+				ret.Content = append(ret.Content, m.keep(mergeKey), m.keep(mergeValue))
+				for mk := range merged {
+					if !explicit[mk] {
+						handled[mk] = true
+					}
+				}
+			} else {
+				// expand the keys that were previously provided by the merge key
+				var keys []string
+				for mk := range merged {
+					if _, ok := v[mk]; ok && !explicit[mk] {
+						keys = append(keys, mk)
+					}
+				}
+				err := appendNew(keys)
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		x, ok := v[k.Value]
+		if !ok {
+			// got removed
+			continue
+		}
+		c, err := m.merge(n.Content[i+1], x)
+		if err != nil {
+			return nil, err
+		}
+		ret.Content = append(ret.Content, k, c)
+		handled[k.Value] = true
+	}
+
+	var keys []string
+	for k := range v {
+		if !handled[k] {
+			keys = append(keys, k)
+		}
+	}
+	err := appendNew(keys)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
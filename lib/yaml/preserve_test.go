@@ -0,0 +1,128 @@
+package yaml
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	yaml3 "gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+func testMergeIntoNode(t *testing.T, src string, v any, expected string) {
+	nodes, err := ReadYamlNodesStream(strings.NewReader(src))
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	n, err := MergeIntoNode(nodes[0], v)
+	assert.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	err = WriteYamlNodesStream(buf, []*yaml3.Node{n})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buf.String())
+
+	// the result must still represent v
+	var x any
+	err = ReadYamlString(buf.String(), &x)
+	assert.NoError(t, err)
+	nv, _ := normalizeValue(v)
+	nx, _ := normalizeValue(x)
+	assert.Equal(t, nv, nx)
+}
+
+func TestMergeIntoNodeUnchanged(t *testing.T) {
+	src := `# head comment
+z: 1 # line comment
+a:
+  q: "quoted"
+  b: [1, 2]
+`
+	testMergeIntoNode(t, src, map[string]any{
+		"z": 1,
+		"a": map[string]any{"q": "quoted", "b": []any{1, 2}},
+	}, src)
+}
+
+func TestMergeIntoNodeChanged(t *testing.T) {
+	src := `# head comment
+z: 1 # line comment
+a:
+  q: "quoted" # keep me
+  b: x
+`
+	testMergeIntoNode(t, src, map[string]any{
+		"z": 2,
+		"a": map[string]any{"q": "changed", "c": "new"},
+		"b": "new",
+	}, `# head comment
+z: 2 # line comment
+a:
+  q: "changed" # keep me
+  c: new
+b: new
+`)
+}
+
+func TestMergeIntoNodeAnchors(t *testing.T) {
+	src := `base: &base
+  a: 1
+  b: 2
+x:
+  <<: *base
+  c: 3
+other: *base
+`
+	// unchanged merge and alias
+	testMergeIntoNode(t, src, map[string]any{
+		"base":  map[string]any{"a": 1, "b": 2},
+		"x":     map[string]any{"a": 1, "b": 2, "c": 3},
+		"other": map[string]any{"a": 1, "b": 2},
+	}, src)
+
+	// changes outside of merged keys keep the merge key
+	testMergeIntoNode(t, src, map[string]any{
+		"base":  map[string]any{"a": 1, "b": 2},
+		"x":     map[string]any{"a": 1, "b": 2, "c": 4, "d": 5},
+		"other": map[string]any{"a": 1, "b": 2},
+	}, `base: &base
+  a: 1
+  b: 2
+x:
+  <<: *base
+  c: 4
+  d: 5
+other: *base
+`)
+
+	// changes to merged keys expand the merge key
+	testMergeIntoNode(t, src, map[string]any{
+		"base":  map[string]any{"a": 1, "b": 2},
+		"x":     map[string]any{"a": 1, "b": 3, "c": 3},
+		"other": map[string]any{"a": 1, "b": 2},
+	}, `base: &base
+  a: 1
+  b: 2
+x:
+  a: 1
+  b: 3
+  c: 3
+other: *base
+`)
+
+	// changes to the anchor itself expand all aliases
+	testMergeIntoNode(t, src, map[string]any{
+		"base":  map[string]any{"a": 1, "b": 3},
+		"x":     map[string]any{"a": 1, "b": 2, "c": 3},
+		"other": map[string]any{"a": 1, "b": 2},
+	}, `base:
+  a: 1
+  b: 3
+x:
+  a: 1
+  b: 2
+  c: 3
+other:
+  a: 1
+  b: 2
+`)
+}
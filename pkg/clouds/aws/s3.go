@@ -0,0 +1,29 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func PutS3Object(ctx context.Context, c client.Client, awsConfig *types.AwsConfig, bucket string, key string, contentType string, data []byte) error {
+	cfg, err := LoadAwsConfigHelper(ctx, c, awsConfig, nil)
+	if err != nil {
+		return err
+	}
+	s3Client := s3.NewFromConfig(cfg)
+
+	_, err = s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &bucket,
+		Key:         &key,
+		ContentType: &contentType,
+		Body:        bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("uploading s3://%s/%s failed: %w", bucket, key, err)
+	}
+	return nil
+}
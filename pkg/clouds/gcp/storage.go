@@ -0,0 +1,28 @@
+package gcp
+
+import (
+	"cloud.google.com/go/storage"
+	"context"
+	"fmt"
+)
+
+func PutStorageObject(ctx context.Context, bucket string, object string, contentType string, data []byte) error {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+	defer client.Close()
+
+	w := client.Bucket(bucket).Object(object).NewWriter(ctx)
+	w.ContentType = contentType
+	_, err = w.Write(data)
+	if err != nil {
+		_ = w.Close()
+		return fmt.Errorf("uploading gs://%s/%s failed: %w", bucket, object, err)
+	}
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("uploading gs://%s/%s failed: %w", bucket, object, err)
+	}
+	return nil
+}
@@ -65,6 +65,8 @@ type preparedProject struct {
 	projectDir string
 
 	soClients []*sourceoverride.ProxyClientController
+
+	releaseClusters []func()
 }
 
 type preparedTarget struct {
@@ -211,6 +213,10 @@ func (pp *preparedProject) cleanup() {
 	if pp.j2 != nil {
 		pp.j2.Close()
 	}
+	for _, release := range pp.releaseClusters {
+		release()
+	}
+	pp.releaseClusters = nil
 }
 
 func (pp *preparedProject) newTarget() *preparedTarget {
@@ -641,15 +647,11 @@ func (pt *preparedTarget) loadTarget(ctx context.Context, p *kluctl_project.Load
 		return nil, err
 	}
 
-	discovery, mapper, err := k8s2.CreateDiscoveryAndMapper(ctx, restConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	k, err := k8s2.NewK8sCluster(ctx, restConfig, discovery, mapper, props.DryRun)
+	k, release, err := pt.pp.r.ClusterPool.Get(ctx, restConfig, props.DryRun)
 	if err != nil {
 		return nil, err
 	}
+	pt.pp.releaseClusters = append(pt.pp.releaseClusters, release)
 
 	targetContext, err := target_context.NewTargetContext(ctx, p, contextName, k, props)
 	if err != nil {
@@ -796,6 +798,7 @@ func (pt *preparedTarget) kluctlDeploy(targetContext *target_context.TargetConte
 	cmd.NoWait = pt.pp.obj.Spec.NoWait
 	cmd.Prune = pt.pp.obj.Spec.Prune
 	cmd.WaitPrune = false
+	cmd.AllowBreakingCRDChanges = pt.pp.obj.Spec.AllowBreakingCRDChanges
 
 	cmdResult := cmd.Run(nil)
 	return cmdResult
@@ -805,6 +808,7 @@ func (pt *preparedTarget) kluctlPokeImages(targetContext *target_context.TargetC
 	timer := prometheus.NewTimer(internal_metrics.NewKluctlDeploymentDuration(pt.pp.obj.ObjectMeta.Namespace, pt.pp.obj.ObjectMeta.Name, pt.pp.obj.Spec.DeployMode))
 	defer timer.ObserveDuration()
 	cmd := commands.NewPokeImagesCommand(targetContext)
+	cmd.AllowBreakingCRDChanges = pt.pp.obj.Spec.AllowBreakingCRDChanges
 
 	cmdResult := cmd.Run()
 	return cmdResult
@@ -828,6 +832,7 @@ func (pt *preparedTarget) kluctlDiff(targetContext *target_context.TargetContext
 	cmd.ReplaceOnError = pt.pp.obj.Spec.ReplaceOnError
 	cmd.ForceReplaceOnError = pt.pp.obj.Spec.ForceReplaceOnError
 	cmd.SkipResourceVersions = resourceVersions
+	cmd.AllowBreakingCRDChanges = pt.pp.obj.Spec.AllowBreakingCRDChanges
 
 	cmdResult := cmd.Run()
 	return cmdResult
@@ -860,15 +865,11 @@ func (pt *preparedTarget) kluctlDelete(ctx context.Context, discriminator string
 		return nil, err
 	}
 
-	discovery, mapper, err := k8s2.CreateDiscoveryAndMapper(ctx, restConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	k, err := k8s2.NewK8sCluster(ctx, restConfig, discovery, mapper, pt.pp.r.DryRun || pt.pp.obj.Spec.DryRun)
+	k, release, err := pt.pp.r.ClusterPool.Get(ctx, restConfig, pt.pp.r.DryRun || pt.pp.obj.Spec.DryRun)
 	if err != nil {
 		return nil, err
 	}
+	pt.pp.releaseClusters = append(pt.pp.releaseClusters, release)
 
 	cmdResult := cmd.Run(ctx, k, func(refs []k8s.ObjectRef) error {
 		pt.printDeletedRefs(ctx, refs)
@@ -904,6 +905,7 @@ func (pt *preparedTarget) exportCommandResultMetricsToProm(summary *result.Comma
 	internal_metrics.NewKluctlNumberOfDeletedObjects(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.DeletedObjects))
 	internal_metrics.NewKluctlNumberOfChangedObjects(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.ChangedObjects))
 	internal_metrics.NewKluctlNumberOfOrphanObjects(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.OrphanObjects))
+	internal_metrics.NewKluctlNumberOfManagedObjects(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.RenderedObjects))
 	internal_metrics.NewKluctlNumberOfWarnings(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Warnings)))
 	internal_metrics.NewKluctlNumberOfErrors(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Errors)))
 }
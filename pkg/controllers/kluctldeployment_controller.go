@@ -10,6 +10,7 @@ import (
 	"github.com/kluctl/kluctl/lib/yaml"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	internal_metrics "github.com/kluctl/kluctl/v2/pkg/controllers/metrics"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
@@ -40,7 +41,8 @@ type KluctlDeploymentReconciler struct {
 	UseSystemPython       bool
 	DryRun                bool
 
-	SshPool *ssh_pool.SshPool
+	SshPool     *ssh_pool.SshPool
+	ClusterPool *k8s2.ClusterPool
 
 	ResultStore results.ResultStore
 
@@ -199,6 +201,7 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 	finalStatus, reason := r.buildFinalStatus(ctx, obj)
 	if reason != kluctlv1.ReconciliationSucceededReason {
 		internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(0.0)
+		internal_metrics.NewKluctlReconciles(obj.Namespace, obj.Name, "failure").Inc()
 		err = fmt.Errorf(finalStatus)
 
 		patchErr = r.patchReadyCondition(ctx, obj, metav1.ConditionFalse, reason, finalStatus)
@@ -210,6 +213,7 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 	}
 
 	internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(1.0)
+	internal_metrics.NewKluctlReconciles(obj.Namespace, obj.Name, "success").Inc()
 	patchErr = r.patchReadyCondition(ctx, obj, metav1.ConditionTrue, reason, finalStatus)
 	if patchErr != nil {
 		return nil, patchErr
@@ -18,6 +18,8 @@ import (
 	"time"
 )
 
+var errMaintenanceWindow = errors.New("target is outside of its maintenance windows, set overrideMaintenanceWindow to deploy anyway")
+
 type getResultPtrCallback func(status *kluctlv1.KluctlDeploymentStatus) **kluctlv1.ManualRequestResult
 
 func (r *KluctlDeploymentReconciler) startHandleManualRequest(ctx context.Context,
@@ -306,6 +308,12 @@ func (r *KluctlDeploymentReconciler) reconcileDeployRequest(ctx context.Context,
 		obj.Spec.DeployMode, kluctlv1.KluctlRequestDeployAnnotation,
 		getResultPtr, false,
 		func(rr *kluctlv1.ManualRequestResult, targetContext *target_context.TargetContext, pt *preparedTarget, reconcileID string, objectsHash string) (any, string, error) {
+			allowed, err := r.allowedByMaintenanceWindow(obj, targetContext)
+			if err != nil {
+				return nil, kluctlv1.DeployFailedReason, err
+			} else if !allowed {
+				return nil, kluctlv1.DeployFailedReason, errMaintenanceWindow
+			}
 			cmdResult, err := pt.kluctlDeployOrPokeImages(obj.Spec.DeployMode, targetContext)
 			if err != nil {
 				return nil, kluctlv1.DeployFailedReason, err
@@ -331,8 +339,14 @@ func (r *KluctlDeploymentReconciler) reconcilePruneRequest(ctx context.Context,
 		"prune", kluctlv1.KluctlRequestPruneAnnotation,
 		getResultPtr, false,
 		func(rr *kluctlv1.ManualRequestResult, targetContext *target_context.TargetContext, pt *preparedTarget, reconcileID string, objectsHash string) (any, string, error) {
+			allowed, err := r.allowedByMaintenanceWindow(obj, targetContext)
+			if err != nil {
+				return nil, kluctlv1.PruneFailedReason, err
+			} else if !allowed {
+				return nil, kluctlv1.PruneFailedReason, errMaintenanceWindow
+			}
 			cmdResult := pt.kluctlPrune(targetContext)
-			err := pt.writeCommandResult(ctx, cmdResult, rr, "prune", reconcileId, objectsHash, true)
+			err = pt.writeCommandResult(ctx, cmdResult, rr, "prune", reconcileId, objectsHash, true)
 			if err != nil {
 				log.Error(err, "Failed to write prune result")
 			}
@@ -428,6 +442,23 @@ func (r *KluctlDeploymentReconciler) reconcileFullRequest2(rr *kluctlv1.ManualRe
 		}
 	}
 
+	deployDeferred := false
+	if needDeploy {
+		allowed, err := r.allowedByMaintenanceWindow(obj, targetContext)
+		if err != nil {
+			return nil, kluctlv1.DeployFailedReason, err
+		}
+		if !allowed {
+			log.Info("deployment is deferred as the target is outside of its maintenance windows")
+			needDeploy = false
+			deployDeferred = true
+		}
+	}
+	err = r.patchDeployDeferredCondition(ctx, obj, deployDeferred)
+	if err != nil {
+		return nil, kluctlv1.DeployFailedReason, err
+	}
+
 	if obj.Spec.Validate {
 		if obj.Status.LastValidateResult == nil || needDeploy {
 			// either never validated before or a deployment requested (which required re-validation)
@@ -447,8 +478,14 @@ func (r *KluctlDeploymentReconciler) reconcileFullRequest2(rr *kluctlv1.ManualRe
 		r.updateResourceVersions(key, nil, nil)
 	}
 
-	obj.Status.LastObjectsHash = objectsHash
-	obj.Status.LastManualObjectsHash = obj.Spec.ManualObjectsHash
+	if deployDeferred {
+		// forces a deployment in the first reconciliation inside a maintenance window, no matter if the deployment was
+		// triggered by source changes, spec changes or the deploy interval
+		obj.Status.LastObjectsHash = ""
+	} else {
+		obj.Status.LastObjectsHash = objectsHash
+		obj.Status.LastManualObjectsHash = obj.Spec.ManualObjectsHash
+	}
 
 	var cmdErrors error
 
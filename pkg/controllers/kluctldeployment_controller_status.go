@@ -33,9 +33,34 @@ func (r *KluctlDeploymentReconciler) patchReadyCondition(ctx context.Context, ob
 	})
 }
 
+// patchDeployDeferredCondition sets the DeployDeferred condition while deployments are deferred due to maintenance
+// windows and removes it otherwise.
+func (r *KluctlDeploymentReconciler) patchDeployDeferredCondition(ctx context.Context, obj *kluctlv1.KluctlDeployment, deferred bool) error {
+	key := client.ObjectKeyFromObject(obj)
+
+	if !deferred && apimeta.FindStatusCondition(obj.GetConditions(), kluctlv1.DeployDeferredCondition) == nil {
+		return nil
+	}
+	return r.patchCondition(ctx, key, func(c *[]metav1.Condition) error {
+		if !deferred {
+			apimeta.RemoveStatusCondition(c, kluctlv1.DeployDeferredCondition)
+			return nil
+		}
+		apimeta.SetStatusCondition(c, metav1.Condition{
+			Type:               kluctlv1.DeployDeferredCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             kluctlv1.OutsideMaintenanceWindowReason,
+			Message:            "Deployment is deferred until the next maintenance window of the target opens",
+			ObservedGeneration: obj.Generation,
+		})
+		return nil
+	})
+}
+
 // patchFail returns the original error + patchErr if required
 func (r *KluctlDeploymentReconciler) patchFail(ctx context.Context, obj *kluctlv1.KluctlDeployment, reason string, err error) error {
 	internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(0.0)
+	internal_metrics.NewKluctlReconciles(obj.Namespace, obj.Name, "failure").Inc()
 	patchErr := r.patchReadyCondition(ctx, obj, metav1.ConditionFalse, reason, err.Error())
 	if patchErr != nil {
 		err = multierror.Append(err, patchErr)
@@ -3,6 +3,8 @@ package controllers
 import (
 	"context"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/meta"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -95,3 +97,13 @@ func (r *KluctlDeploymentReconciler) recordDuration(ctx context.Context, obj *kl
 
 	r.MetricsRecorder.RecordDuration(*objRef, startTime)
 }
+
+// allowedByMaintenanceWindow returns true if the target may be changed right now, which is the case if the current time
+// is inside one of the target's maintenance windows, if the deployment is a dry-run or if the maintenance windows are
+// overridden.
+func (r *KluctlDeploymentReconciler) allowedByMaintenanceWindow(obj *kluctlv1.KluctlDeployment, targetContext *target_context.TargetContext) (bool, error) {
+	if obj.Spec.DryRun || obj.Spec.OverrideMaintenanceWindow {
+		return true, nil
+	}
+	return kluctl_project.IsInMaintenanceWindow(targetContext.Target.MaintenanceWindows, time.Now())
+}
@@ -10,6 +10,7 @@ const (
 	NumberOfChangedObjectsKey = "number_of_changed_objects"
 	NumberOfDeletedObjectsKey = "number_of_deleted_objects"
 	NumberOfErrorsKey         = "number_of_errors"
+	NumberOfManagedObjectsKey = "number_of_managed_objects"
 	NumberOfOrphanObjectsKey  = "number_of_orphan_objects"
 	NumberOfWarningsKey       = "number_of_warnings"
 	PruneDurationKey          = "prune_duration_seconds"
@@ -42,6 +43,12 @@ var (
 		Help:      "How many errors are related to a single project.",
 	}, []string{"namespace", "name", "action"})
 
+	numberOfManagedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      NumberOfManagedObjectsKey,
+		Help:      "How many objects are rendered and managed by a single project.",
+	}, []string{"namespace", "name"})
+
 	numberOfOrphanObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KluctlDeploymentControllerSubsystem,
 		Name:      NumberOfOrphanObjectsKey,
@@ -78,6 +85,7 @@ func init() {
 	metrics.Registry.MustRegister(numberOfChangedObjects)
 	metrics.Registry.MustRegister(numberOfDeletedObjects)
 	metrics.Registry.MustRegister(numberOfErrors)
+	metrics.Registry.MustRegister(numberOfManagedObjects)
 	metrics.Registry.MustRegister(numberOfOrphanObjects)
 	metrics.Registry.MustRegister(numberOfWarnings)
 	metrics.Registry.MustRegister(pruneDuration)
@@ -101,6 +109,10 @@ func NewKluctlNumberOfErrors(namespace string, name string, action string) prome
 	return numberOfErrors.WithLabelValues(namespace, name, action)
 }
 
+func NewKluctlNumberOfManagedObjects(namespace string, name string) prometheus.Gauge {
+	return numberOfManagedObjects.WithLabelValues(namespace, name)
+}
+
 func NewKluctlNumberOfOrphanObjects(namespace string, name string) prometheus.Gauge {
 	return numberOfOrphanObjects.WithLabelValues(namespace, name)
 }
@@ -17,6 +17,7 @@ const (
 	SourceSpecKey         = "source_spec"
 	GitSourceSpecKey      = "git_source_spec"
 	OciSourceSpecKey      = "oci_source_spec"
+	ReconcilesKey         = "reconciles_total"
 )
 
 var (
@@ -65,8 +66,14 @@ var (
 	ociSourceSpec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KluctlDeploymentControllerSubsystem,
 		Name:      OciSourceSpecKey,
-		Help:      "The configured git source spec of a single deployment.",
+		Help:      "The configured oci source spec of a single deployment.",
 	}, []string{"namespace", "name", "url", "path", "ref"})
+
+	reconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      ReconcilesKey,
+		Help:      "How many reconciliations of a single deployment have finished, partitioned by result.",
+	}, []string{"namespace", "name", "result"})
 )
 
 func init() {
@@ -76,10 +83,13 @@ func init() {
 	metrics.Registry.MustRegister(pruneEnabled)
 	metrics.Registry.MustRegister(deleteEnabled)
 	metrics.Registry.MustRegister(sourceSpec)
+	metrics.Registry.MustRegister(gitSourceSpec)
+	metrics.Registry.MustRegister(ociSourceSpec)
+	metrics.Registry.MustRegister(reconciles)
 }
 
 func NewKluctlDeploymentInterval(namespace string, name string) prometheus.Gauge {
-	return dryRunEnabled.WithLabelValues(namespace, name)
+	return deploymentInterval.WithLabelValues(namespace, name)
 }
 
 func NewKluctlDryRunEnabled(namespace string, name string) prometheus.Gauge {
@@ -109,3 +119,7 @@ func NewKluctlGitSourceSpec(namespace string, name string, url string, path stri
 func NewKluctlOciSourceSpec(namespace string, name string, url string, path string, ref string) prometheus.Gauge {
 	return ociSourceSpec.WithLabelValues(namespace, name, url, path, ref)
 }
+
+func NewKluctlReconciles(namespace string, name string, result string) prometheus.Counter {
+	return reconciles.WithLabelValues(namespace, name, result)
+}
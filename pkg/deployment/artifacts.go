@@ -0,0 +1,106 @@
+package deployment
+
+import (
+	"fmt"
+	"github.com/gobwas/glob"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// CollectArtifacts copies the output artifacts of all deployment items into the given directory. Artifacts are
+// matched against the rendered deployment item directory, meaning that they include the results of templating and
+// Helm chart rendering. Each item's artifacts are stored in a sub-directory named after the rendered item directory.
+// It returns the relative paths (inside dir) of all collected artifacts.
+func (c *DeploymentCollection) CollectArtifacts(dir string) ([]string, error) {
+	var ret []string
+	for _, di := range c.Deployments {
+		files, err := di.collectArtifacts(dir)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, files...)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (di *DeploymentItem) collectArtifacts(dir string) ([]string, error) {
+	if di.dir == nil || len(di.Config.Artifacts) == 0 {
+		return nil, nil
+	}
+
+	var globs []glob.Glob
+	for _, a := range di.Config.Artifacts {
+		g, err := glob.Compile(a, '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern '%s' in %s: %w", a, di.RelToProjectItemDir, err)
+		}
+		globs = append(globs, g)
+	}
+	matchedGlobs := make([]bool, len(globs))
+
+	var ret []string
+	err := filepath.WalkDir(di.RenderedDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(di.RenderedDir, p)
+		if err != nil {
+			return err
+		}
+		matched := false
+		for i, g := range globs {
+			if g.Match(filepath.ToSlash(relPath)) {
+				matchedGlobs[i] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil
+		}
+
+		relTarget := filepath.Join(di.RelRenderedDir, relPath)
+		err = copyArtifact(p, filepath.Join(dir, relTarget))
+		if err != nil {
+			return fmt.Errorf("failed to collect artifact %s: %w", relTarget, err)
+		}
+		ret = append(ret, relTarget)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range matchedGlobs {
+		if !m {
+			// fail early so that pipelines don't silently continue without the expected artifacts
+			return nil, fmt.Errorf("artifact pattern '%s' in %s did not match any files", di.Config.Artifacts[i], di.RelToProjectItemDir)
+		}
+	}
+	return ret, nil
+}
+
+func copyArtifact(src string, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
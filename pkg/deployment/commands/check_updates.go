@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"context"
+	"github.com/Masterminds/semver/v3"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type UpdateKind string
+
+const (
+	UpdateKindHelmChart  UpdateKind = "helm-chart"
+	UpdateKindGitInclude UpdateKind = "git-include"
+	UpdateKindOciInclude UpdateKind = "oci-include"
+)
+
+type UpdateType string
+
+const (
+	UpdateTypeMajor UpdateType = "major"
+	UpdateTypeMinor UpdateType = "minor"
+	UpdateTypePatch UpdateType = "patch"
+	UpdateTypeOther UpdateType = "other"
+)
+
+// AvailableUpdate describes a newer version of a Helm chart, git include or oci include
+type AvailableUpdate struct {
+	Kind UpdateKind `json:"kind"`
+	// Location is the directory of the helm-chart.yaml or deployment.yaml declaring the chart/include, relative to the
+	// project directory
+	Location       string     `json:"location"`
+	Source         string     `json:"source"`
+	CurrentVersion string     `json:"currentVersion"`
+	LatestVersion  string     `json:"latestVersion"`
+	UpdateType     UpdateType `json:"updateType"`
+}
+
+type CheckUpdatesResult struct {
+	Updates []AvailableUpdate `json:"updates"`
+}
+
+// IsNewerVersion returns true if latest is newer than current. Non semantic versions are considered newer if they
+// differ.
+func IsNewerVersion(current string, latest string) bool {
+	cv, err1 := semver.NewVersion(current)
+	lv, err2 := semver.NewVersion(latest)
+	if err1 != nil || err2 != nil {
+		return current != latest
+	}
+	return lv.GreaterThan(cv)
+}
+
+// FindLatestVersion returns the latest semantic version found in versions that is newer than current. Pre-releases are
+// only considered if current is a pre-release itself. Versions with a different 'v' prefix style than current are
+// ignored, so that the returned version can be used as a drop-in replacement. An empty string is returned if no newer
+// version exists or if current is not a semantic version.
+func FindLatestVersion(current string, versions []string) string {
+	cv, err := semver.NewVersion(current)
+	if err != nil {
+		return ""
+	}
+	hasV := strings.HasPrefix(current, "v")
+
+	latest := ""
+	for _, x := range versions {
+		if strings.HasPrefix(x, "v") != hasV {
+			continue
+		}
+		v, err := semver.NewVersion(x)
+		if err != nil {
+			continue
+		}
+		if v.Prerelease() != "" && cv.Prerelease() == "" {
+			continue
+		}
+		if !IsNewerVersion(current, x) {
+			continue
+		}
+		if latest == "" || IsNewerVersion(latest, x) {
+			latest = x
+		}
+	}
+	return latest
+}
+
+// GetUpdateType classifies the update from current to latest by the most significant semantic version component that
+// changed
+func GetUpdateType(current string, latest string) UpdateType {
+	cv, err1 := semver.NewVersion(current)
+	lv, err2 := semver.NewVersion(latest)
+	switch {
+	case err1 != nil || err2 != nil:
+		return UpdateTypeOther
+	case cv.Major() != lv.Major():
+		return UpdateTypeMajor
+	case cv.Minor() != lv.Minor():
+		return UpdateTypeMinor
+	case cv.Patch() != lv.Patch():
+		return UpdateTypePatch
+	default:
+		return UpdateTypeOther
+	}
+}
+
+// SortUpdates sorts the given updates by location, kind and source
+func SortUpdates(updates []AvailableUpdate) {
+	sort.SliceStable(updates, func(i, j int) bool {
+		a, b := updates[i], updates[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Source < b.Source
+	})
+}
+
+// CheckIncludeUpdatesCommand checks the git and oci includes of a target for newer tags
+type CheckIncludeUpdatesCommand struct {
+	targetCtx *target_context.TargetContext
+}
+
+func NewCheckIncludeUpdatesCommand(targetCtx *target_context.TargetContext) *CheckIncludeUpdatesCommand {
+	return &CheckIncludeUpdatesCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+// Run checks all includes that are declared in the local project and pinned to a tag. Includes that use branches,
+// commits or digests are skipped, as there is no notion of newer versions for these.
+func (cmd *CheckIncludeUpdatesCommand) Run(ctx context.Context) ([]AvailableUpdate, error) {
+	repoRoot, err := filepath.Abs(cmd.targetCtx.KluctlProject.LoadArgs.RepoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []AvailableUpdate
+	for _, inc := range cmd.targetCtx.DeploymentProject.GetLocalRemoteIncludes() {
+		inc.RelDir, err = filepath.Rel(cmd.targetCtx.KluctlProject.LoadArgs.ProjectDir, filepath.Join(repoRoot, inc.RelDir))
+		if err != nil {
+			return nil, err
+		}
+
+		var u *AvailableUpdate
+		if inc.Git != nil {
+			u, err = cmd.checkGitInclude(ctx, inc)
+		} else {
+			u, err = cmd.checkOciInclude(ctx, inc)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			ret = append(ret, *u)
+		}
+	}
+	return ret, nil
+}
+
+func (cmd *CheckIncludeUpdatesCommand) checkGitInclude(ctx context.Context, inc deployment.RemoteInclude) (*AvailableUpdate, error) {
+	url := inc.Git.Url.String()
+	ref := inc.Git.Ref
+	var current string
+	if ref != nil {
+		current = ref.Tag
+		if ref.Ref != "" {
+			current = strings.TrimPrefix(ref.Ref, "refs/tags/")
+		}
+	}
+	if current == "" {
+		status.Tracef(ctx, "Skipping git include %s as it is not pinned to a tag", url)
+		return nil, nil
+	}
+
+	ge, err := cmd.targetCtx.SharedContext.GitRP.GetEntry(url)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for r := range ge.GetRepoInfo().RemoteRefs {
+		if strings.HasPrefix(r, "refs/tags/") && !strings.HasSuffix(r, "^{}") {
+			tags = append(tags, strings.TrimPrefix(r, "refs/tags/"))
+		}
+	}
+	return buildAvailableUpdate(UpdateKindGitInclude, inc.RelDir, url, current, tags), nil
+}
+
+func (cmd *CheckIncludeUpdatesCommand) checkOciInclude(ctx context.Context, inc deployment.RemoteInclude) (*AvailableUpdate, error) {
+	url := inc.Oci.Url
+	ref := inc.Oci.Ref
+	if ref == nil || ref.Tag == "" || ref.Digest != "" {
+		status.Tracef(ctx, "Skipping oci include %s as it is not pinned to a tag", url)
+		return nil, nil
+	}
+
+	oe, err := cmd.targetCtx.SharedContext.OciRP.GetEntry(url)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := oe.ListTags()
+	if err != nil {
+		// the include itself was already pulled while loading the project, so listing tags might just be forbidden
+		status.Warningf(ctx, "Skipping oci include %s as listing its tags failed: %s", url, err.Error())
+		return nil, nil
+	}
+	return buildAvailableUpdate(UpdateKindOciInclude, inc.RelDir, url, ref.Tag, tags), nil
+}
+
+// BuildHelmChartUpdate returns the update from current to latest, or nil if latest is not newer than current. latest
+// must already be determined with respect to the updateConstraints of the chart.
+func BuildHelmChartUpdate(location string, chartName string, current string, latest string) *AvailableUpdate {
+	if !IsNewerVersion(current, latest) {
+		return nil
+	}
+	return &AvailableUpdate{
+		Kind:           UpdateKindHelmChart,
+		Location:       location,
+		Source:         chartName,
+		CurrentVersion: current,
+		LatestVersion:  latest,
+		UpdateType:     GetUpdateType(current, latest),
+	}
+}
+
+func buildAvailableUpdate(kind UpdateKind, location string, source string, current string, versions []string) *AvailableUpdate {
+	latest := FindLatestVersion(current, versions)
+	if latest == "" {
+		return nil
+	}
+	return &AvailableUpdate{
+		Kind:           kind,
+		Location:       location,
+		Source:         source,
+		CurrentVersion: current,
+		LatestVersion:  latest,
+		UpdateType:     GetUpdateType(current, latest),
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindLatestVersion(t *testing.T) {
+	versions := []string{"1.0.0", "1.1.0", "1.2.0-rc.1", "v2.0.0", "2.0.0-beta.1", "latest", "1.10.1"}
+
+	assert.Equal(t, "1.10.1", FindLatestVersion("1.0.0", versions))
+	assert.Equal(t, "", FindLatestVersion("1.10.1", versions))
+	assert.Equal(t, "", FindLatestVersion("main", versions))
+	assert.Equal(t, "v2.0.0", FindLatestVersion("v1.0.0", versions))
+	assert.Equal(t, "2.0.0-beta.1", FindLatestVersion("2.0.0-alpha.1", versions))
+}
+
+func TestGetUpdateType(t *testing.T) {
+	assert.Equal(t, UpdateTypeMajor, GetUpdateType("1.2.3", "2.0.0"))
+	assert.Equal(t, UpdateTypeMinor, GetUpdateType("v1.2.3", "v1.3.0"))
+	assert.Equal(t, UpdateTypePatch, GetUpdateType("1.2.3", "1.2.4"))
+	assert.Equal(t, UpdateTypeOther, GetUpdateType("1.2.3-rc.1", "1.2.3"))
+	assert.Equal(t, UpdateTypeOther, GetUpdateType("main", "1.2.3"))
+}
+
+func TestSortUpdates(t *testing.T) {
+	updates := []AvailableUpdate{
+		{Location: "b", Kind: UpdateKindHelmChart, Source: "x"},
+		{Location: "a", Kind: UpdateKindOciInclude, Source: "y"},
+		{Location: "a", Kind: UpdateKindGitInclude, Source: "z"},
+		{Location: "a", Kind: UpdateKindGitInclude, Source: "a"},
+	}
+	SortUpdates(updates)
+	assert.Equal(t, []AvailableUpdate{
+		{Location: "a", Kind: UpdateKindGitInclude, Source: "a"},
+		{Location: "a", Kind: UpdateKindGitInclude, Source: "z"},
+		{Location: "a", Kind: UpdateKindOciInclude, Source: "y"},
+		{Location: "b", Kind: UpdateKindHelmChart, Source: "x"},
+	}, updates)
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	assert.True(t, IsNewerVersion("1.0.0", "1.0.1"))
+	assert.False(t, IsNewerVersion("1.0.1", "1.0.0"))
+	assert.False(t, IsNewerVersion("1.0.0", "1.0.0"))
+	assert.True(t, IsNewerVersion("main", "1.0.0"))
+	assert.False(t, IsNewerVersion("main", "main"))
+}
+
+func TestBuildHelmChartUpdate(t *testing.T) {
+	assert.Nil(t, BuildHelmChartUpdate("helm1", "chart", "1.0.0", "1.0.0"))
+	assert.Equal(t, &AvailableUpdate{
+		Kind:           UpdateKindHelmChart,
+		Location:       "helm1",
+		Source:         "chart",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.1.0",
+		UpdateType:     UpdateTypeMinor,
+	}, BuildHelmChartUpdate("helm1", "chart", "1.0.0", "1.1.0"))
+}
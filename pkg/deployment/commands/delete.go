@@ -10,6 +10,7 @@ import (
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"k8s.io/apimachinery/pkg/labels"
 	"time"
 )
 
@@ -18,6 +19,10 @@ type DeleteCommand struct {
 	targetCtx     *target_context.TargetContext
 	inclusion     *utils.Inclusion
 	wait          bool
+
+	// NamespaceCleanupTimeout enables verification of namespace termination when set. Deleted namespaces that did not
+	// terminate within this time are reported as errors, together with the objects that block termination.
+	NamespaceCleanupTimeout time.Duration
 }
 
 func NewDeleteCommand(discriminator string, targetCtx *target_context.TargetContext, inclusion *utils.Inclusion, wait bool) *DeleteCommand {
@@ -36,6 +41,10 @@ func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster, confirmCb
 	if inclusion == nil && cmd.targetCtx != nil {
 		inclusion = cmd.targetCtx.DeploymentCollection.Inclusion
 	}
+	var objectSelector labels.Selector
+	if cmd.targetCtx != nil {
+		objectSelector = cmd.targetCtx.DeploymentCollection.ObjectSelector
+	}
 
 	dew := utils2.NewDeploymentErrorsAndWarnings()
 
@@ -67,7 +76,7 @@ func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster, confirmCb
 		return r
 	}
 
-	deleteRefs, err := utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(inclusion), inclusion.HasType("tags"), nil)
+	deleteRefs, err := utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(inclusion, objectSelector), inclusion.HasType("tags"), nil)
 	if err != nil {
 		dew.AddError(k8s2.ObjectRef{}, err)
 		return r
@@ -81,7 +90,12 @@ func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster, confirmCb
 		}
 	}
 
-	deleted := utils2.DeleteObjects(ctx, k, deleteRefs, dew, cmd.wait)
+	var deleted []k8s2.ObjectRef
+	if cmd.NamespaceCleanupTimeout != 0 {
+		deleted = utils2.DeleteObjectsAndVerifyNamespaces(ctx, k, deleteRefs, discriminator, dew, cmd.wait, cmd.NamespaceCleanupTimeout)
+	} else {
+		deleted = utils2.DeleteObjects(ctx, k, deleteRefs, discriminator, dew, cmd.wait)
+	}
 
 	var c *deployment.DeploymentCollection
 	if cmd.targetCtx != nil {
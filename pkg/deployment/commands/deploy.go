@@ -21,6 +21,15 @@ type DeployCommand struct {
 	NoWait              bool
 	Prune               bool
 	WaitPrune           bool
+	RetryFailedItems    int
+	ProgressByNamespace bool
+
+	AllowBreakingCRDChanges bool
+
+	// MaxDeletes and MaxChanges override the limits configured in the target. IgnoreLimits disables all limits.
+	MaxDeletes   *int
+	MaxChanges   *int
+	IgnoreLimits bool
 }
 
 func NewDeployCommand(targetCtx *target_context.TargetContext) *DeployCommand {
@@ -64,9 +73,15 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		AbortOnError:        false,
 		ReadinessTimeout:    cmd.ReadinessTimeout,
 		NoWait:              cmd.NoWait,
+
+		AllowBreakingCRDChanges: cmd.AllowBreakingCRDChanges,
 	}
 
-	if diffResultCb != nil {
+	maxDeletes := getEffectiveLimit(cmd.MaxDeletes, cmd.targetCtx.Target.MaxDeletes, cmd.IgnoreLimits)
+	maxChanges := getEffectiveLimit(cmd.MaxChanges, cmd.targetCtx.Target.MaxChanges, cmd.IgnoreLimits)
+	checkLimits := (maxDeletes != nil || maxChanges != nil) && !cmd.targetCtx.SharedContext.K.DryRun
+
+	if diffResultCb != nil || checkLimits {
 		diffDew := dew.Clone()
 		au := utils2.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, diffDew, ru, cmd.targetCtx.SharedContext.K, o)
 		au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
@@ -75,23 +90,55 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		du.DiffDeploymentItems(cmd.targetCtx.DeploymentCollection.Deployments)
 
 		orphanObjects, err := FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
-		diffResult := &result.CommandResult{
-			Objects:    collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, orphanObjects, nil),
-			Errors:     diffDew.GetErrorsList(),
-			Warnings:   diffDew.GetWarningsList(),
-			SeenImages: cmd.targetCtx.DeploymentCollection.Images.SeenImages(false),
-		}
-
-		err = diffResultCb(diffResult)
 		if err != nil {
+			// without the orphan objects, neither the diff nor the limits would be complete
 			dew.AddError(k8s2.ObjectRef{}, err)
 			return r
 		}
+
+		// without pruning, only old generations of generated ConfigMaps/Secrets get deleted. These are shown as
+		// deleted in the diff, so that they are part of the confirmation and the limits
+		var pendingDeletes []k8s2.ObjectRef
+		deletes := len(orphanObjects)
+		if !cmd.Prune {
+			pendingDeletes = findOldGenerations(cmd.targetCtx.DeploymentCollection, ru, orphanObjects)
+			deletes = len(pendingDeletes)
+		}
+
+		diffResult := &result.CommandResult{
+			FormatVersion: result.FormatVersion,
+			Objects:       collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, orphanObjects, pendingDeletes),
+			Errors:        diffDew.GetErrorsList(),
+			Warnings:      diffDew.GetWarningsList(),
+			SeenImages:    cmd.targetCtx.DeploymentCollection.Images.SeenImages(false),
+		}
+		diffResult.ChangeImpact = diffResult.BuildChangeImpact()
+
+		if checkLimits {
+			err = checkMaxDeletes(deletes, maxDeletes)
+			if err == nil {
+				err = checkMaxChanges(countChangedObjects(diffResult.Objects), maxChanges)
+			}
+			if err != nil {
+				dew.AddError(k8s2.ObjectRef{}, err)
+				return r
+			}
+		}
+
+		if diffResultCb != nil {
+			err = diffResultCb(diffResult)
+			if err != nil {
+				dew.AddError(k8s2.ObjectRef{}, err)
+				return r
+			}
+		}
 	}
 
 	// modify options to become a deploy
 	o.DryRun = cmd.targetCtx.SharedContext.K.DryRun
 	o.AbortOnError = cmd.AbortOnError
+	o.RetryFailedItems = cmd.RetryFailedItems
+	o.ProgressByNamespace = cmd.ProgressByNamespace
 
 	au := utils2.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, o)
 	au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
@@ -105,15 +152,21 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 	orphanObjects, err = FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
 	if err != nil {
 		dew.AddError(k8s2.ObjectRef{}, err)
-	}
-
-	if cmd.Prune && cmd.targetCtx.Target.Discriminator == "" {
+	} else if cmd.Prune && cmd.targetCtx.Target.Discriminator == "" {
 		dew.AddError(k8s2.ObjectRef{}, fmt.Errorf("pruning without a discriminator is not supported"))
 	} else if cmd.Prune {
-		deleted = utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, orphanObjects, dew, cmd.WaitPrune)
+		deleted = utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, orphanObjects, cmd.targetCtx.Target.Discriminator, dew, cmd.WaitPrune)
 
 		// now clean up the list of orphan objects (remove the ones that got deleted)
 		orphanObjects = filterDeletedOrphans(orphanObjects, deleted)
+	} else if len(dew.GetErrorsList()) == 0 {
+		// old generations of generated ConfigMaps/Secrets are cleaned up even without pruning, but only if everything
+		// succeeded, as failed rollouts might still depend on these
+		oldGenerations := findOldGenerations(cmd.targetCtx.DeploymentCollection, ru, orphanObjects)
+		if len(oldGenerations) != 0 {
+			deleted = utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, oldGenerations, cmd.targetCtx.Target.Discriminator, dew, cmd.WaitPrune)
+			orphanObjects = filterDeletedOrphans(orphanObjects, deleted)
+		}
 	}
 
 	r.Objects = collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, orphanObjects, deleted)
@@ -20,6 +20,8 @@ type DiffCommand struct {
 	IgnoreAnnotations    bool
 	IgnoreKluctlMetadata bool
 
+	AllowBreakingCRDChanges bool
+
 	SkipResourceVersions map[k8s2.ObjectRef]string
 }
 
@@ -61,6 +63,8 @@ func (cmd *DiffCommand) Run() *result.CommandResult {
 		AbortOnError:         false,
 		ReadinessTimeout:     0,
 		SkipResourceVersions: cmd.SkipResourceVersions,
+
+		AllowBreakingCRDChanges: cmd.AllowBreakingCRDChanges,
 	}
 	au := utils.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, o)
 	au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
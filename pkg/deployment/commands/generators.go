@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type generatorKey struct {
+	gk        schema.GroupKind
+	namespace string
+	itemDir   string
+	generator string
+}
+
+func getGeneratorKey(o *uo.UnstructuredObject) (generatorKey, bool) {
+	g := o.GetK8sAnnotation(deployment.GeneratorAnnotation)
+	if g == nil {
+		return generatorKey{}, false
+	}
+	k := generatorKey{
+		gk:        o.GetK8sGVK().GroupKind(),
+		namespace: o.GetK8sNamespace(),
+		generator: *g,
+	}
+	if itemDir := o.GetK8sAnnotation("kluctl.io/deployment-item-dir"); itemDir != nil {
+		k.itemDir = *itemDir
+	}
+	return k, true
+}
+
+// findOldGenerations returns all orphan objects that were generated by a generator which is still part of the
+// deployment. These are old generations (with an outdated hash suffix) of the currently generated objects.
+func findOldGenerations(c *deployment.DeploymentCollection, ru *utils2.RemoteObjectUtils, orphans []k8s2.ObjectRef) []k8s2.ObjectRef {
+	current := map[generatorKey]bool{}
+	for _, o := range c.LocalObjects() {
+		if k, ok := getGeneratorKey(o); ok {
+			current[k] = true
+		}
+	}
+	if len(current) == 0 {
+		return nil
+	}
+
+	var ret []k8s2.ObjectRef
+	for _, ref := range orphans {
+		o := ru.GetRemoteObject(ref)
+		if o == nil {
+			continue
+		}
+		if k, ok := getGeneratorKey(o); ok && current[k] {
+			ret = append(ret, ref)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"io"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sort"
+	"strings"
+)
+
+// HookLogSource identifies the logs of a single container of a hook pod. Previous is set for the logs of the previous
+// instance of a restarted container.
+type HookLogSource struct {
+	HookRef   k8s2.ObjectRef
+	Namespace string
+	Pod       string
+	Container string
+	Previous  bool
+}
+
+func (s HookLogSource) String() string {
+	ret := s.Namespace + "/" + s.Pod + "/" + s.Container
+	if s.Previous {
+		ret += " (previous)"
+	}
+	return ret
+}
+
+// HookLogsCommand finds the pods of all Job and Pod hooks of a target and retrieves their logs, e.g. for
+// `kluctl hook-logs`.
+type HookLogsCommand struct {
+	targetCtx *target_context.TargetContext
+	last      bool
+
+	coreClient  corev1client.CoreV1Interface
+	batchClient batchv1client.BatchV1Interface
+}
+
+func NewHookLogsCommand(targetCtx *target_context.TargetContext, last bool) (*HookLogsCommand, error) {
+	restConfig, err := targetCtx.SharedContext.K.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	coreClient, err := corev1client.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	batchClient, err := batchv1client.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &HookLogsCommand{
+		targetCtx:   targetCtx,
+		last:        last,
+		coreClient:  coreClient,
+		batchClient: batchClient,
+	}, nil
+}
+
+func isHookObject(o *uo.UnstructuredObject) bool {
+	return o.GetK8sAnnotation("kluctl.io/hook") != nil || o.GetK8sAnnotation("helm.sh/hook") != nil
+}
+
+// matchesHookObject returns true if the remote object was created from the rendered hook object, either by having the
+// same name or by having a name generated from the rendered metadata.generateName
+func matchesHookObject(hook *uo.UnstructuredObject, meta metav1.ObjectMeta) bool {
+	if name := hook.GetK8sName(); name != "" {
+		if meta.Name != name {
+			return false
+		}
+	} else if !strings.HasPrefix(meta.Name, hook.GetK8sGenerateName()) {
+		return false
+	}
+	if d := hook.GetK8sLabel("kluctl.io/discriminator"); d != nil && meta.Labels["kluctl.io/discriminator"] != *d {
+		return false
+	}
+	return true
+}
+
+// HookPod is a pod that was created by a hook, either directly or via a Job
+type HookPod struct {
+	HookRef k8s2.ObjectRef
+	Pod     corev1.Pod
+}
+
+// filterLast sorts the given items by creation time, oldest first, and only returns the newest item if last is set
+func filterLast[T any](items []T, last bool, getMeta func(x *T) *metav1.ObjectMeta) []T {
+	sort.SliceStable(items, func(i, j int) bool {
+		return getMeta(&items[i]).CreationTimestamp.Before(&getMeta(&items[j]).CreationTimestamp)
+	})
+	if last && len(items) > 1 {
+		return items[len(items)-1:]
+	}
+	return items
+}
+
+// FindHookPods returns the pods created by all Job and Pod hooks of the target, sorted by creation time. If last is set,
+// only the most recently created instance of each hook is considered, which makes a difference for hooks that use
+// metadata.generateName.
+func (cmd *HookLogsCommand) FindHookPods(ctx context.Context) ([]HookPod, error) {
+	var ret []HookPod
+	seen := map[k8s2.ObjectRef]bool{}
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		for _, o := range d.Objects {
+			ref := o.GetK8sRef()
+			if !isHookObject(o) || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			var pods []corev1.Pod
+			var err error
+			switch {
+			case ref.Group == "" && ref.Kind == "Pod":
+				pods, err = cmd.findPodHookPods(ctx, o)
+			case ref.Group == "batch" && ref.Kind == "Job":
+				pods, err = cmd.findJobHookPods(ctx, o)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range pods {
+				ret = append(ret, HookPod{HookRef: ref, Pod: p})
+			}
+		}
+	}
+	ret = filterLast(ret, false, func(x *HookPod) *metav1.ObjectMeta {
+		return &x.Pod.ObjectMeta
+	})
+	return ret, nil
+}
+
+func (cmd *HookLogsCommand) findPodHookPods(ctx context.Context, hook *uo.UnstructuredObject) ([]corev1.Pod, error) {
+	pods, err := cmd.coreClient.Pods(hook.GetK8sNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var matching []corev1.Pod
+	for _, p := range pods.Items {
+		if matchesHookObject(hook, p.ObjectMeta) {
+			matching = append(matching, p)
+		}
+	}
+	return filterLast(matching, cmd.last, func(x *corev1.Pod) *metav1.ObjectMeta {
+		return &x.ObjectMeta
+	}), nil
+}
+
+func (cmd *HookLogsCommand) findJobHookPods(ctx context.Context, hook *uo.UnstructuredObject) ([]corev1.Pod, error) {
+	jobs, err := cmd.batchClient.Jobs(hook.GetK8sNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var matching []batchv1.Job
+	for _, j := range jobs.Items {
+		if matchesHookObject(hook, j.ObjectMeta) {
+			matching = append(matching, j)
+		}
+	}
+	matching = filterLast(matching, cmd.last, func(x *batchv1.Job) *metav1.ObjectMeta {
+		return &x.ObjectMeta
+	})
+
+	var ret []corev1.Pod
+	for _, j := range matching {
+		if j.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(j.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := cmd.coreClient.Pods(j.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pods.Items...)
+	}
+	return ret, nil
+}
+
+// BuildLogSources returns the log sources of all containers of the given pod, including init containers. For restarted
+// containers, the logs of the previous instance are returned first.
+func BuildLogSources(hp *HookPod) []HookLogSource {
+	restarts := map[string]int32{}
+	for _, l := range [][]corev1.ContainerStatus{hp.Pod.Status.InitContainerStatuses, hp.Pod.Status.ContainerStatuses} {
+		for _, cs := range l {
+			restarts[cs.Name] = cs.RestartCount
+		}
+	}
+
+	var ret []HookLogSource
+	for _, l := range [][]corev1.Container{hp.Pod.Spec.InitContainers, hp.Pod.Spec.Containers} {
+		for _, c := range l {
+			s := HookLogSource{
+				HookRef:   hp.HookRef,
+				Namespace: hp.Pod.Namespace,
+				Pod:       hp.Pod.Name,
+				Container: c.Name,
+			}
+			if restarts[c.Name] > 0 {
+				s2 := s
+				s2.Previous = true
+				ret = append(ret, s2)
+			}
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// StreamLogs opens the log stream of the given source. If follow is set, the stream stays open until the container
+// terminates or ctx is cancelled.
+func (cmd *HookLogsCommand) StreamLogs(ctx context.Context, s HookLogSource, follow bool) (io.ReadCloser, error) {
+	return cmd.coreClient.Pods(s.Namespace).GetLogs(s.Pod, &corev1.PodLogOptions{
+		Container: s.Container,
+		Previous:  s.Previous,
+		Follow:    follow && !s.Previous,
+	}).Stream(ctx)
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestMatchesHookObject(t *testing.T) {
+	named := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  labels:
+    kluctl.io/discriminator: d1
+`)
+	generated := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: migrate-
+`)
+
+	assert.True(t, matchesHookObject(named, metav1.ObjectMeta{Name: "migrate", Labels: map[string]string{"kluctl.io/discriminator": "d1"}}))
+	assert.False(t, matchesHookObject(named, metav1.ObjectMeta{Name: "migrate", Labels: map[string]string{"kluctl.io/discriminator": "d2"}}))
+	assert.False(t, matchesHookObject(named, metav1.ObjectMeta{Name: "migrate-abcde", Labels: map[string]string{"kluctl.io/discriminator": "d1"}}))
+
+	assert.True(t, matchesHookObject(generated, metav1.ObjectMeta{Name: "migrate-abcde"}))
+	assert.False(t, matchesHookObject(generated, metav1.ObjectMeta{Name: "other-abcde"}))
+}
+
+func TestFilterLast(t *testing.T) {
+	now := time.Now()
+	build := func(name string, age time.Duration) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+	getMeta := func(x *corev1.Pod) *metav1.ObjectMeta {
+		return &x.ObjectMeta
+	}
+	names := func(l []corev1.Pod) []string {
+		var ret []string
+		for _, x := range l {
+			ret = append(ret, x.Name)
+		}
+		return ret
+	}
+
+	pods := []corev1.Pod{build("b", time.Minute), build("c", 0), build("a", time.Hour)}
+	assert.Equal(t, []string{"a", "b", "c"}, names(filterLast(pods, false, getMeta)))
+	assert.Equal(t, []string{"c"}, names(filterLast(pods, true, getMeta)))
+	assert.Empty(t, filterLast[corev1.Pod](nil, true, getMeta))
+}
+
+func TestBuildLogSources(t *testing.T) {
+	hp := &HookPod{
+		HookRef: k8s2.ObjectRef{Group: "batch", Version: "v1", Kind: "Job", Name: "migrate", Namespace: "ns"},
+		Pod: corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate-abcde", Namespace: "ns"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "c1"}, {Name: "c2"}},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "c1", RestartCount: 2}, {Name: "c2"}},
+			},
+		},
+	}
+
+	var names []string
+	for _, s := range BuildLogSources(hp) {
+		assert.Equal(t, hp.HookRef, s.HookRef)
+		names = append(names, s.String())
+	}
+	assert.Equal(t, []string{
+		"ns/migrate-abcde/init",
+		"ns/migrate-abcde/c1 (previous)",
+		"ns/migrate-abcde/c1",
+		"ns/migrate-abcde/c2",
+	}, names)
+}
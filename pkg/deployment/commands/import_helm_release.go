@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/pkg/helm"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type ImportHelmReleaseCommand struct {
+	targetCtx *target_context.TargetContext
+
+	ReleaseName string
+	Namespace   string
+
+	// Repo is the Helm repository to pull the chart from. Helm does not store the repository inside the release, so it
+	// must be provided by the user.
+	Repo string
+	// OutputDir is the directory into which the deployment item is written
+	OutputDir string
+
+	RemoveHelmMetadata bool
+	DryRun             bool
+}
+
+type ImportHelmReleaseResult struct {
+	Dir          string `json:"dir"`
+	ChartName    string `json:"chartName"`
+	ChartVersion string `json:"chartVersion"`
+	Revision     int    `json:"revision"`
+
+	PatchedObjects []k8s2.ObjectRef `json:"patchedObjects,omitempty"`
+	RemovedSecrets []k8s2.ObjectRef `json:"removedSecrets,omitempty"`
+}
+
+func NewImportHelmReleaseCommand(targetCtx *target_context.TargetContext) *ImportHelmReleaseCommand {
+	return &ImportHelmReleaseCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+// Run reads the deployed revision of the Helm release, generates a deployment item that renders the same chart with
+// the same values and then labels all live objects of the release with the target's discriminator, so that kluctl can
+// take over management of the release. If RemoveHelmMetadata is set, the Helm release secrets and the Helm ownership
+// annotations are removed as well.
+func (cmd *ImportHelmReleaseCommand) Run() (*ImportHelmReleaseResult, error) {
+	ctx := cmd.targetCtx.SharedContext.Ctx
+	k := cmd.targetCtx.SharedContext.K
+	if k == nil {
+		return nil, fmt.Errorf("can not import Helm releases without a Kubernetes API client")
+	}
+
+	rls, secretRefs, err := helm.GetDeployedHelmRelease(k, cmd.Namespace, cmd.ReleaseName)
+	if err != nil {
+		return nil, err
+	}
+	if rls.Chart == nil || rls.Chart.Metadata == nil {
+		return nil, fmt.Errorf("helm release %s does not contain chart metadata", cmd.ReleaseName)
+	}
+
+	ret := &ImportHelmReleaseResult{
+		Dir:          cmd.OutputDir,
+		ChartName:    rls.Chart.Metadata.Name,
+		ChartVersion: rls.Chart.Metadata.Version,
+		Revision:     rls.Version,
+	}
+
+	if !cmd.DryRun {
+		err = cmd.writeDeploymentItem(rls)
+		if err != nil {
+			return nil, err
+		}
+		status.Infof(ctx, "Wrote deployment item for Helm release %s to %s", cmd.ReleaseName, cmd.OutputDir)
+	}
+
+	objects, err := cmd.buildReleaseRefs(k, rls)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.targetCtx.SharedContext.Discriminator == "" {
+		status.Warning(ctx, "The target has no discriminator, so the objects of the Helm release are not labeled. Orphan detection and pruning will not work for these objects.")
+	}
+
+	patch := cmd.buildPatch()
+	if patch != nil {
+		s := status.Startf(ctx, "Patching %d objects of Helm release %s", len(objects), cmd.ReleaseName)
+		for _, ref := range objects {
+			_, _, err = k.MergePatchObject(ref, patch, k8s.PatchOptions{})
+			if err != nil {
+				s.FailedWithMessage(err.Error())
+				return ret, err
+			}
+			ret.PatchedObjects = append(ret.PatchedObjects, ref)
+		}
+		s.Success()
+	}
+
+	if cmd.RemoveHelmMetadata {
+		s := status.Startf(ctx, "Removing %d Helm release secrets", len(secretRefs))
+		for _, ref := range secretRefs {
+			_, err = k.DeleteSingleObject(ref, k8s.DeleteOptions{IgnoreNotFoundError: true})
+			if err != nil {
+				s.FailedWithMessage(err.Error())
+				return ret, err
+			}
+			ret.RemovedSecrets = append(ret.RemovedSecrets, ref)
+		}
+		s.Success()
+	}
+
+	return ret, nil
+}
+
+func (cmd *ImportHelmReleaseCommand) writeDeploymentItem(rls *release.Release) error {
+	if utils.Exists(filepath.Join(cmd.OutputDir, "helm-chart.yaml")) {
+		return fmt.Errorf("%s already contains a helm-chart.yaml", cmd.OutputDir)
+	}
+
+	chartConfig := types.HelmChartConfig{
+		HelmChartConfig2: types.HelmChartConfig2{
+			Repo:         cmd.Repo,
+			ChartVersion: rls.Chart.Metadata.Version,
+			ReleaseName:  rls.Name,
+			Namespace:    &rls.Namespace,
+		},
+	}
+	if !registry.IsOCI(cmd.Repo) {
+		chartConfig.ChartName = rls.Chart.Metadata.Name
+	}
+	err := yaml.ValidateStructs(&chartConfig)
+	if err != nil {
+		return err
+	}
+
+	values := rls.Config
+	if values == nil {
+		values = map[string]any{}
+	}
+	kustomization := map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []any{"helm-rendered.yaml"},
+	}
+
+	err = os.MkdirAll(cmd.OutputDir, 0o755)
+	if err != nil {
+		return err
+	}
+	err = yaml.WriteYamlFile(filepath.Join(cmd.OutputDir, "helm-chart.yaml"), &chartConfig)
+	if err != nil {
+		return err
+	}
+	err = yaml.WriteYamlFile(filepath.Join(cmd.OutputDir, "helm-values.yaml"), values)
+	if err != nil {
+		return err
+	}
+	return yaml.WriteYamlFile(filepath.Join(cmd.OutputDir, "kustomization.yaml"), kustomization)
+}
+
+// buildReleaseRefs returns the refs of all objects from the release manifest. Hooks are not included, as these are
+// not part of the live state of the release.
+func (cmd *ImportHelmReleaseCommand) buildReleaseRefs(k *k8s.K8sCluster, rls *release.Release) ([]k8s2.ObjectRef, error) {
+	objects, err := uo.FromStringMulti(rls.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of Helm release %s: %w", rls.Name, err)
+	}
+
+	var ret []k8s2.ObjectRef
+	for _, o := range objects {
+		ref := o.GetK8sRef()
+		if ref.Kind == "" || ref.Name == "" {
+			continue
+		}
+		if ref.Namespace == "" {
+			if n := k.IsNamespaced(ref.GroupVersionKind()); n != nil && *n {
+				ref.Namespace = rls.Namespace
+			}
+		}
+		ret = append(ret, ref)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret, nil
+}
+
+func (cmd *ImportHelmReleaseCommand) buildPatch() map[string]any {
+	metadata := map[string]any{}
+	if d := cmd.targetCtx.SharedContext.Discriminator; d != "" {
+		metadata["labels"] = map[string]any{
+			"kluctl.io/discriminator": d,
+		}
+	}
+	// the app.kubernetes.io/managed-by=Helm label is intentionally kept, as it is part of the rendered chart (kluctl
+	// renders charts with Release.Service=Helm) and would be re-added by the next deployment anyway
+	if cmd.RemoveHelmMetadata {
+		metadata["annotations"] = map[string]any{
+			"meta.helm.sh/release-name":      nil,
+			"meta.helm.sh/release-namespace": nil,
+		}
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"metadata": metadata,
+	}
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+)
+
+// getEffectiveLimit returns the limit that should be enforced. An override (e.g. from the command line) has precedence
+// over the value configured in the target. Negative values disable the limit.
+func getEffectiveLimit(override *int, target *int, ignore bool) *int {
+	if ignore {
+		return nil
+	}
+	l := target
+	if override != nil {
+		l = override
+	}
+	if l == nil || *l < 0 {
+		return nil
+	}
+	return l
+}
+
+func countChangedObjects(objects []result.ResultObject) int {
+	cnt := 0
+	for _, o := range objects {
+		if o.Hook {
+			continue
+		}
+		if o.New || len(o.Changes) != 0 {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func checkMaxDeletes(deletes int, maxDeletes *int) error {
+	if maxDeletes != nil && deletes > *maxDeletes {
+		return fmt.Errorf("refusing to delete %d objects as this exceeds the maximum of %d allowed deletions (see maxDeletes)", deletes, *maxDeletes)
+	}
+	return nil
+}
+
+func checkMaxChanges(changes int, maxChanges *int) error {
+	if maxChanges != nil && changes > *maxChanges {
+		return fmt.Errorf("refusing to change %d objects as this exceeds the maximum of %d allowed changes (see maxChanges)", changes, *maxChanges)
+	}
+	return nil
+}
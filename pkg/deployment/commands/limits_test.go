@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGetEffectiveLimit(t *testing.T) {
+	assert.Nil(t, getEffectiveLimit(nil, nil, false))
+	assert.Equal(t, intPtr(3), getEffectiveLimit(nil, intPtr(3), false))
+	assert.Equal(t, intPtr(5), getEffectiveLimit(intPtr(5), intPtr(3), false))
+	assert.Equal(t, intPtr(0), getEffectiveLimit(intPtr(0), intPtr(3), false))
+	assert.Nil(t, getEffectiveLimit(intPtr(-1), intPtr(3), false))
+	assert.Nil(t, getEffectiveLimit(intPtr(5), intPtr(3), true))
+}
+
+func TestCheckLimits(t *testing.T) {
+	objects := []result.ResultObject{
+		{BaseObject: result.BaseObject{New: true}},
+		{BaseObject: result.BaseObject{Changes: []result.Change{{}}}},
+		{BaseObject: result.BaseObject{New: true, Hook: true}},
+		{BaseObject: result.BaseObject{}},
+	}
+	assert.Equal(t, 2, countChangedObjects(objects))
+
+	assert.NoError(t, checkMaxChanges(2, nil))
+	assert.NoError(t, checkMaxChanges(2, intPtr(2)))
+	assert.ErrorContains(t, checkMaxChanges(2, intPtr(1)), "refusing to change 2 objects")
+
+	assert.NoError(t, checkMaxDeletes(0, intPtr(0)))
+	assert.ErrorContains(t, checkMaxDeletes(1, intPtr(0)), "refusing to delete 1 objects")
+}
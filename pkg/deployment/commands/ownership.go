@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+)
+
+type OwnershipCommand struct {
+	targetCtx *target_context.TargetContext
+
+	Refs  []k8s2.ObjectRef
+	Depth int
+}
+
+type OwnershipResult struct {
+	Ref    k8s2.ObjectRef        `json:"ref"`
+	Fields []diff.FieldOwnership `json:"fields,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
+
+func NewOwnershipCommand(targetCtx *target_context.TargetContext) *OwnershipCommand {
+	return &OwnershipCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+func (cmd *OwnershipCommand) matchRef(ref k8s2.ObjectRef) bool {
+	if len(cmd.Refs) == 0 {
+		return true
+	}
+	for _, r := range cmd.Refs {
+		if r.Group == ref.Group && r.Kind == ref.Kind && r.Namespace == ref.Namespace && r.Name == ref.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run retrieves all selected objects from the cluster and reports which field managers own which fields. If Refs is
+// empty, all objects of the target are reported.
+func (cmd *OwnershipCommand) Run() ([]OwnershipResult, error) {
+	k := cmd.targetCtx.SharedContext.K
+	if k == nil {
+		return nil, fmt.Errorf("can not report field ownership without a Kubernetes API client")
+	}
+
+	var objects []*uo.UnstructuredObject
+	found := map[k8s2.ObjectRef]bool{}
+	for _, o := range cmd.targetCtx.DeploymentCollection.LocalObjects() {
+		ref := o.GetK8sRef()
+		if !cmd.matchRef(ref) {
+			continue
+		}
+		objects = append(objects, o)
+		found[k8s2.ObjectRef{Group: ref.Group, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}] = true
+	}
+	for _, r := range cmd.Refs {
+		if !found[r] {
+			return nil, fmt.Errorf("object %s is not part of the rendered target", r.String())
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].GetK8sRef().Less(objects[j].GetK8sRef())
+	})
+
+	var refs []k8s2.ObjectRef
+	for _, o := range objects {
+		refs = append(refs, o.GetK8sRef())
+	}
+
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+	ru := utils2.NewRemoteObjectsUtil(cmd.targetCtx.SharedContext.Ctx, dew)
+	discriminator := cmd.targetCtx.Target.Discriminator
+	err := ru.UpdateRemoteObjects(k, &discriminator, refs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []OwnershipResult
+	for _, ref := range refs {
+		r := OwnershipResult{Ref: ref}
+		remote := ru.GetRemoteObject(ref)
+		if remote == nil {
+			r.Error = "object not found"
+		} else {
+			fields, err := diff.BuildFieldOwnership(remote, cmd.Depth)
+			if err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Fields = fields
+			}
+		}
+		ret = append(ret, r)
+	}
+
+	return ret, nil
+}
@@ -13,6 +13,8 @@ import (
 
 type PokeImagesCommand struct {
 	targetCtx *target_context.TargetContext
+
+	AllowBreakingCRDChanges bool
 }
 
 func NewPokeImagesCommand(targetCtx *target_context.TargetContext) *PokeImagesCommand {
@@ -76,7 +78,10 @@ func (cmd *PokeImagesCommand) Run() *result.CommandResult {
 		return o, nil
 	}
 
-	au := utils2.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, &utils2.ApplyUtilOptions{})
+	au := utils2.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, &utils2.ApplyUtilOptions{
+		AllowBreakingCRDChanges: cmd.AllowBreakingCRDChanges,
+	})
+	defer au.Close()
 
 	for ref, containers := range containersAndImages {
 		ref := ref
@@ -14,6 +14,10 @@ type PruneCommand struct {
 	discriminator string
 	targetCtx     *target_context.TargetContext
 	wait          bool
+
+	// MaxDeletes overrides the limit configured in the target. IgnoreLimits disables the limit.
+	MaxDeletes   *int
+	IgnoreLimits bool
 }
 
 func NewPruneCommand(discriminator string, targetCtx *target_context.TargetContext, wait bool) *PruneCommand {
@@ -55,6 +59,15 @@ func (cmd *PruneCommand) Run(confirmCb func(refs []k8s2.ObjectRef) error) *resul
 		return r
 	}
 
+	if !cmd.targetCtx.SharedContext.K.DryRun {
+		maxDeletes := getEffectiveLimit(cmd.MaxDeletes, cmd.targetCtx.Target.MaxDeletes, cmd.IgnoreLimits)
+		err = checkMaxDeletes(len(orphanObjects), maxDeletes)
+		if err != nil {
+			dew.AddError(k8s2.ObjectRef{}, err)
+			return r
+		}
+	}
+
 	if confirmCb != nil {
 		err = confirmCb(orphanObjects)
 		if err != nil {
@@ -63,7 +76,7 @@ func (cmd *PruneCommand) Run(confirmCb func(refs []k8s2.ObjectRef) error) *resul
 		}
 	}
 
-	deleted := utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, orphanObjects, dew, cmd.wait)
+	deleted := utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, orphanObjects, cmd.targetCtx.Target.Discriminator, dew, cmd.wait)
 	orphanObjects = filterDeletedOrphans(orphanObjects, deleted)
 
 	r.Objects = collectObjects(cmd.targetCtx.DeploymentCollection, ru, nil, nil, orphanObjects, deleted)
@@ -72,5 +85,5 @@ func (cmd *PruneCommand) Run(confirmCb func(refs []k8s2.ObjectRef) error) *resul
 }
 
 func FindOrphanObjects(k *k8s.K8sCluster, ru *utils2.RemoteObjectUtils, c *deployment.DeploymentCollection) ([]k8s2.ObjectRef, error) {
-	return utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(c.Inclusion), c.Inclusion.HasType("tags"), c.LocalObjectRefs())
+	return utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(c.Inclusion, c.ObjectSelector), c.Inclusion.HasType("tags"), c.RenderedObjectRefs())
 }
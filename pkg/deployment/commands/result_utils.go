@@ -13,7 +13,9 @@ import (
 )
 
 func newCommandResult(targetCtx *target_context.TargetContext, startTime time.Time, command string) *result.CommandResult {
-	r := &result.CommandResult{}
+	r := &result.CommandResult{
+		FormatVersion: result.FormatVersion,
+	}
 
 	r.Target = targetCtx.Target
 	r.Command = result.CommandInfo{
@@ -53,7 +55,9 @@ func newCommandResult(targetCtx *target_context.TargetContext, startTime time.Ti
 }
 
 func newValidateCommandResult(targetCtx *target_context.TargetContext, startTime time.Time) *result.ValidateResult {
-	r := &result.ValidateResult{}
+	r := &result.ValidateResult{
+		FormatVersion: result.FormatVersion,
+	}
 
 	r.StartTime = metav1.NewTime(startTime)
 	r.EndTime = metav1.Now()
@@ -78,7 +82,9 @@ func newValidateCommandResult(targetCtx *target_context.TargetContext, startTime
 }
 
 func newDeleteCommandResult(k *k8s2.K8sCluster, startTime time.Time, inclusion *utils.Inclusion) *result.CommandResult {
-	r := &result.CommandResult{}
+	r := &result.CommandResult{
+		FormatVersion: result.FormatVersion,
+	}
 
 	r.Command = result.CommandInfo{
 		StartTime: metav1.NewTime(startTime),
@@ -99,6 +105,8 @@ func newDeleteCommandResult(k *k8s2.K8sCluster, startTime time.Time, inclusion *
 func finishCommandResult(r *result.CommandResult, targetCtx *target_context.TargetContext, dew *utils2.DeploymentErrorsAndWarnings) {
 	r.Errors = append(r.Errors, dew.GetErrorsList()...)
 	r.Warnings = append(r.Warnings, dew.GetWarningsList()...)
+	r.ErrorHints = utils2.BuildErrorHints(r.Errors)
+	r.ChangeImpact = r.BuildChangeImpact()
 	if targetCtx != nil {
 		r.SeenImages = targetCtx.DeploymentCollection.Images.SeenImages(false)
 	}
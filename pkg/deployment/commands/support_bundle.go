@@ -0,0 +1,437 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/version"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SupportBundleInfo is written as info.yaml into support bundles and contains version and diagnostic information
+type SupportBundleInfo struct {
+	KluctlVersion     string    `json:"kluctlVersion"`
+	GoVersion         string    `json:"goVersion"`
+	Os                string    `json:"os"`
+	Arch              string    `json:"arch"`
+	CreationTime      time.Time `json:"creationTime"`
+	Target            string    `json:"target,omitempty"`
+	Discriminator     string    `json:"discriminator,omitempty"`
+	ClusterContext    string    `json:"clusterContext,omitempty"`
+	KubernetesVersion string    `json:"kubernetesVersion,omitempty"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// SupportBundleEvent is a Kubernetes event related to a failed object
+type SupportBundleEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count"`
+}
+
+// SupportBundleCommand collects diagnostic information about a failed command into a single archive that can be
+// attached to bug reports. Secrets are obfuscated and target arguments are removed. All known secret values are
+// additionally redacted from all files, including the logs and error messages.
+type SupportBundleCommand struct {
+	// targetCtx is nil if the command failed before the target could be loaded
+	targetCtx *target_context.TargetContext
+
+	// TargetName is used when no target context is available
+	TargetName string
+	// Logs are the log lines written into kluctl.log
+	Logs []string
+}
+
+func NewSupportBundleCommand(targetCtx *target_context.TargetContext) *SupportBundleCommand {
+	return &SupportBundleCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+// Write writes the support bundle as tar.gz archive to the given path. The result is nil if the command failed before
+// it produced one. Failing to collect the live state or the events of individual objects is not fatal and is recorded
+// in the bundle instead.
+func (cmd *SupportBundleCommand) Write(ctx context.Context, p string, r *result.CommandResult, commandErr error) error {
+	redactor := newSupportBundleRedactor()
+	if cmd.targetCtx != nil {
+		if cmd.targetCtx.DeploymentCollection != nil {
+			for _, o := range cmd.targetCtx.DeploymentCollection.LocalObjects() {
+				redactor.addSecret(o)
+			}
+		}
+	}
+	if r != nil {
+		redactor.addSensitiveVars(r.Deployment)
+		for _, o := range r.Objects {
+			redactor.addSecret(o.Rendered)
+			redactor.addSecret(o.Remote)
+			redactor.addSecret(o.Applied)
+		}
+	}
+
+	files := map[string][]byte{}
+	var fileNames []string
+	addFile := func(name string, o any) error {
+		var b []byte
+		if s, ok := o.(string); ok {
+			b = []byte(s)
+		} else {
+			var err error
+			b, err = yaml.WriteYamlBytes(o)
+			if err != nil {
+				return err
+			}
+		}
+		if _, ok := files[name]; !ok {
+			fileNames = append(fileNames, name)
+		}
+		files[name] = b
+		return nil
+	}
+
+	err := addFile("info.yaml", cmd.buildInfo(commandErr))
+	if err != nil {
+		return err
+	}
+
+	if r != nil {
+		summary := r.BuildSummary()
+		summary.Target.Args = nil
+		summary.Command.Args = nil
+		err = addFile("summary.yaml", summary)
+		if err != nil {
+			return err
+		}
+
+		for _, ref := range getFailedRefs(r) {
+			objFiles, err := cmd.collectObject(ctx, r, ref, redactor)
+			if err != nil {
+				return err
+			}
+			for _, n := range sortedKeys(objFiles) {
+				err = addFile(path.Join(BuildSupportBundleObjectDir(ref), n), objFiles[n])
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var renderErrs deployment.RenderErrors
+	if r == nil && errors.As(commandErr, &renderErrs) {
+		// rendering failed, so there is no command result that would contain the errors
+		err = addFile("errors.yaml", renderErrs.DeploymentErrors())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = addFile("kluctl.log", strings.Join(cmd.Logs, "\n")+"\n")
+	if err != nil {
+		return err
+	}
+
+	// redact at the very end, so that the values of all secrets seen so far (including live ones) are known
+	for n, b := range files {
+		files[n] = redactor.redact(b)
+	}
+
+	b, err := writeTarGz(fileNames, files)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o600)
+}
+
+func (cmd *SupportBundleCommand) buildInfo(commandErr error) *SupportBundleInfo {
+	info := &SupportBundleInfo{
+		KluctlVersion: version.GetVersion(),
+		GoVersion:     runtime.Version(),
+		Os:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		CreationTime:  time.Now(),
+		Target:        cmd.TargetName,
+	}
+	if cmd.targetCtx != nil {
+		info.Target = cmd.targetCtx.Target.Name
+		info.Discriminator = cmd.targetCtx.Target.Discriminator
+		info.ClusterContext = cmd.targetCtx.ClusterContext
+		if k := cmd.targetCtx.SharedContext.K; k != nil && k.ServerVersion != nil {
+			info.KubernetesVersion = k.ServerVersion.String()
+		}
+	}
+	if commandErr != nil {
+		info.Error = commandErr.Error()
+	}
+	return info
+}
+
+func (cmd *SupportBundleCommand) collectObject(ctx context.Context, r *result.CommandResult, ref k8s2.ObjectRef, redactor *supportBundleRedactor) (map[string]any, error) {
+	var obfuscator diff.Obfuscator
+	ret := map[string]any{}
+
+	var errs []result.DeploymentError
+	for _, e := range r.Errors {
+		if e.Ref == ref {
+			errs = append(errs, e)
+		}
+	}
+	ret["errors.yaml"] = errs
+
+	for _, o := range r.Objects {
+		if o.Ref != ref || o.Rendered == nil {
+			continue
+		}
+		x, err := obfuscator.ObfuscateObject(o.Rendered.Clone())
+		if err != nil {
+			return nil, err
+		}
+		ret["rendered.yaml"] = x
+	}
+
+	if cmd.targetCtx == nil || cmd.targetCtx.SharedContext.K == nil {
+		return ret, nil
+	}
+	k := cmd.targetCtx.SharedContext.K
+
+	live, _, err := k.GetSingleObject(ref)
+	if err != nil {
+		status.Warningf(ctx, "Failed to retrieve live state of %s for support bundle: %s", ref.String(), err.Error())
+		ret["live.error.txt"] = err.Error() + "\n"
+	} else {
+		redactor.addSecret(live)
+		live, err = obfuscator.ObfuscateObject(live)
+		if err != nil {
+			return nil, err
+		}
+		ret["live.yaml"] = live
+	}
+
+	watchCmd := NewWatchCommand("", cmd.targetCtx, time.Time{})
+	events, err := watchCmd.collectEvents(map[k8s2.ObjectRef]bool{ref: true})
+	if err != nil {
+		status.Warningf(ctx, "Failed to retrieve events of %s for support bundle: %s", ref.String(), err.Error())
+		ret["events.error.txt"] = err.Error() + "\n"
+	} else {
+		l := make([]SupportBundleEvent, 0, len(events))
+		for _, e := range events {
+			l = append(l, SupportBundleEvent{
+				Time:    e.Time,
+				Type:    e.Type,
+				Reason:  e.Reason,
+				Message: e.Message,
+				Count:   e.Count,
+			})
+		}
+		ret["events.yaml"] = l
+	}
+
+	return ret, nil
+}
+
+// supportBundleMinRedactLen is the minimum length of values to be redacted. Shorter values (e.g. "1" or "true") would
+// render the bundle unreadable while not being worth protecting.
+const supportBundleMinRedactLen = 4
+
+// supportBundleRedactor replaces all known sensitive values in the files of a support bundle
+type supportBundleRedactor struct {
+	values map[string]bool
+}
+
+func newSupportBundleRedactor() *supportBundleRedactor {
+	return &supportBundleRedactor{
+		values: map[string]bool{},
+	}
+}
+
+func (r *supportBundleRedactor) addValue(v string) {
+	if len(v) < supportBundleMinRedactLen {
+		return
+	}
+	r.values[v] = true
+	r.values[base64.StdEncoding.EncodeToString([]byte(v))] = true
+}
+
+// addSensitiveVars adds all leaf values of the rendered vars of all vars sources that are marked as sensitive
+func (r *supportBundleRedactor) addSensitiveVars(d *types.DeploymentProjectConfig) {
+	if d == nil {
+		return
+	}
+	addVars := func(vars []types.VarsSource) {
+		for _, v := range vars {
+			if v.RenderedSensitive {
+				r.addLeafValues(v.RenderedVars)
+			}
+		}
+	}
+	addVars(d.Vars)
+	for _, di := range d.Deployments {
+		addVars(di.Vars)
+		r.addSensitiveVars(di.RenderedInclude)
+	}
+}
+
+// addLeafValues adds all leaf values of the given object. Booleans are skipped, as these are never sensitive.
+func (r *supportBundleRedactor) addLeafValues(o *uo.UnstructuredObject) {
+	if o == nil {
+		return
+	}
+	var walk func(x any)
+	walk = func(x any) {
+		switch v := x.(type) {
+		case map[string]any:
+			for _, y := range v {
+				walk(y)
+			}
+		case []any:
+			for _, y := range v {
+				walk(y)
+			}
+		case string:
+			r.addValue(v)
+		case nil, bool:
+		default:
+			r.addValue(fmt.Sprint(v))
+		}
+	}
+	walk(o.Object)
+}
+
+// addSecret adds all values of the given object if it is a Secret
+func (r *supportBundleRedactor) addSecret(o *uo.UnstructuredObject) {
+	if o == nil || o.GetK8sGVK().GroupKind() != (schema.GroupKind{Kind: "Secret"}) {
+		return
+	}
+	data, _, _ := o.GetNestedStringMapCopy("data")
+	for _, v := range data {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err == nil {
+			r.addValue(string(b))
+		}
+	}
+	stringData, _, _ := o.GetNestedStringMapCopy("stringData")
+	for _, v := range stringData {
+		r.addValue(v)
+	}
+}
+
+func (r *supportBundleRedactor) redact(b []byte) []byte {
+	values := make([]string, 0, len(r.values))
+	for v := range r.values {
+		values = append(values, v)
+	}
+	// longer values first, so that values containing other values are fully redacted
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	s := string(b)
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, "*****")
+	}
+	return []byte(s)
+}
+
+// getFailedRefs returns the unique refs of all objects with errors, in the order of the errors
+func getFailedRefs(r *result.CommandResult) []k8s2.ObjectRef {
+	var ret []k8s2.ObjectRef
+	seen := map[k8s2.ObjectRef]bool{}
+	for _, e := range r.Errors {
+		if e.Ref.Name == "" || seen[e.Ref] {
+			continue
+		}
+		seen[e.Ref] = true
+		ret = append(ret, e.Ref)
+	}
+	return ret
+}
+
+// BuildSupportBundleObjectDir returns the directory inside the support bundle that holds the files of the given object
+func BuildSupportBundleObjectDir(ref k8s2.ObjectRef) string {
+	ns := ref.Namespace
+	if ns == "" {
+		ns = "_cluster"
+	}
+	gk := ref.Kind
+	if ref.Group != "" {
+		gk = fmt.Sprintf("%s.%s", ref.Kind, ref.Group)
+	}
+	return path.Join("objects", ns, gk, ref.Name)
+}
+
+func sortedKeys(m map[string]any) []string {
+	var ret []string
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func writeTarGz(fileNames []string, files map[string][]byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now()
+	dirs := map[string]bool{}
+	for _, n := range fileNames {
+		var parents []string
+		for d := path.Dir(n); d != "."; d = path.Dir(d) {
+			parents = append([]string{d}, parents...)
+		}
+		for _, d := range parents {
+			if dirs[d] {
+				continue
+			}
+			dirs[d] = true
+			err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: d + "/", Mode: 0o755, ModTime: now})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		b := files[n]
+		err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: n, Mode: 0o600, Size: int64(len(b)), ModTime: now})
+		if err != nil {
+			return nil, err
+		}
+		_, err = tw.Write(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := tw.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
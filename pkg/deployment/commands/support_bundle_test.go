@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readTarGz(t *testing.T, p string) map[string]string {
+	f, err := os.Open(p)
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	ret := map[string]string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		ret[h.Name] = string(b)
+	}
+	return ret
+}
+
+func TestBuildSupportBundleObjectDir(t *testing.T) {
+	assert.Equal(t, "objects/ns/ConfigMap/cm", BuildSupportBundleObjectDir(k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"}))
+	assert.Equal(t, "objects/_cluster/ClusterRole.rbac.authorization.k8s.io/cr", BuildSupportBundleObjectDir(k8s2.ObjectRef{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "cr"}))
+}
+
+func TestSupportBundle(t *testing.T) {
+	secret := uo.FromStringMust(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: s1
+  namespace: ns
+stringData:
+  password: secret-password
+`)
+	secretRef := secret.GetK8sRef()
+
+	targetCtx := &target_context.TargetContext{
+		Target: types.Target{
+			Name: "t1",
+			Args: uo.FromMap(map[string]any{"token": "secret-token"}),
+		},
+	}
+	r := &result.CommandResult{
+		Target: targetCtx.Target,
+		Deployment: &types.DeploymentProjectConfig{
+			Vars: []types.VarsSource{
+				{RenderedSensitive: true, RenderedVars: uo.FromMap(map[string]any{"dbPassword": "secret-var"})},
+				{RenderedVars: uo.FromMap(map[string]any{"replicas": "public-value"})},
+			},
+		},
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: secretRef}, Rendered: secret},
+		},
+		Errors: []result.DeploymentError{
+			{Ref: secretRef, Message: "e1 with secret-password"},
+			{Ref: secretRef, Message: "e2"},
+			{Message: "error without ref"},
+		},
+	}
+
+	p := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	cmd := NewSupportBundleCommand(targetCtx)
+	cmd.Logs = []string{"l1", "l2 secret-var", "l3 public-value", "l4 secret-token"}
+	err := cmd.Write(context.Background(), p, r, fmt.Errorf("command failed: secret-password"))
+	assert.NoError(t, err)
+
+	files := readTarGz(t, p)
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	assert.ElementsMatch(t, []string{
+		"info.yaml",
+		"summary.yaml",
+		"objects/ns/Secret/s1/errors.yaml",
+		"objects/ns/Secret/s1/rendered.yaml",
+		"kluctl.log",
+	}, names)
+
+	assert.Contains(t, files["info.yaml"], "error: 'command failed: *****'")
+	assert.Contains(t, files["summary.yaml"], "error without ref")
+	assert.Contains(t, files["objects/ns/Secret/s1/errors.yaml"], "e2")
+	// argument values are not known to be sensitive, so only the target args are removed from the summary
+	assert.Equal(t, "l1\nl2 *****\nl3 public-value\nl4 secret-token\n", files["kluctl.log"])
+	assert.NotContains(t, files["summary.yaml"], "secret-token")
+	for n, c := range files {
+		assert.NotContains(t, c, "secret-password", n)
+		assert.NotContains(t, c, "secret-var", n)
+	}
+
+	// the original result must not be modified
+	assert.Equal(t, "secret-password", secret.Object["stringData"].(map[string]any)["password"])
+}
+
+func TestSupportBundleWithoutTarget(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	cmd := NewSupportBundleCommand(nil)
+	cmd.TargetName = "t1"
+	cmd.Logs = []string{"rendering failed"}
+	err := cmd.Write(context.Background(), p, nil, fmt.Errorf("rendering failed"))
+	assert.NoError(t, err)
+
+	files := readTarGz(t, p)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files["info.yaml"], "target: t1")
+	assert.Equal(t, "rendering failed\n", files["kluctl.log"])
+}
+
+func TestSupportBundleRenderErrors(t *testing.T) {
+	renderErrs := deployment.RenderErrors{
+		{File: "cm1/configmap.yml", Line: 9, Message: "'missing1' is undefined"},
+	}
+
+	p := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	cmd := NewSupportBundleCommand(nil)
+	err := cmd.Write(context.Background(), p, nil, renderErrs)
+	assert.NoError(t, err)
+
+	files := readTarGz(t, p)
+	assert.Len(t, files, 3)
+	assert.Contains(t, files["errors.yaml"], "cm1/configmap.yml:9: ''missing1'' is undefined")
+}
+
+func TestSupportBundleRedactor(t *testing.T) {
+	r := newSupportBundleRedactor()
+	r.addSensitiveVars(&types.DeploymentProjectConfig{
+		Deployments: []types.DeploymentItemConfig{
+			{RenderedInclude: &types.DeploymentProjectConfig{
+				Vars: []types.VarsSource{
+					{RenderedSensitive: true, RenderedVars: uo.FromMap(map[string]any{
+						"short":  "abc",
+						"bool":   true,
+						"number": 123456,
+						"nested": map[string]any{"list": []any{"secret1"}},
+					})},
+					{RenderedVars: uo.FromMap(map[string]any{"k": "not-secret"})},
+				},
+			}},
+		},
+	})
+	r.addSecret(uo.FromStringMust(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: s1
+data:
+  k: c2VjcmV0Mg==
+`))
+	r.addSecret(uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  k: not-secret
+`))
+
+	assert.Equal(t, "abc true ***** ***** ***** ***** not-secret", string(r.redact([]byte("abc true 123456 secret1 secret2 c2VjcmV0Mg== not-secret"))))
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	errors2 "errors"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sort"
+)
+
+type TakeOwnershipCommand struct {
+	targetCtx *target_context.TargetContext
+
+	Refs   []k8s2.ObjectRef
+	Fields []string
+}
+
+type TakeOwnershipResult struct {
+	Ref   k8s2.ObjectRef        `json:"ref"`
+	Taken []diff.TakenOwnership `json:"taken,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+func NewTakeOwnershipCommand(targetCtx *target_context.TargetContext) *TakeOwnershipCommand {
+	return &TakeOwnershipCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+func (cmd *TakeOwnershipCommand) matchRef(ref k8s2.ObjectRef) bool {
+	for _, r := range cmd.Refs {
+		if r.Group == ref.Group && r.Kind == ref.Kind && r.Namespace == ref.Namespace && r.Name == ref.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run performs a forced server-side apply of the rendered version of all selected objects, so that kluctl's field
+// manager becomes the owner of all conflicting fields. If Fields is not empty, only ownership of the matching fields is
+// taken over. The result contains a report about which fields were taken from which field manager.
+func (cmd *TakeOwnershipCommand) Run() ([]TakeOwnershipResult, error) {
+	k := cmd.targetCtx.SharedContext.K
+	if k == nil {
+		return nil, fmt.Errorf("can not take ownership of objects without a Kubernetes API client")
+	}
+
+	var objects []*uo.UnstructuredObject
+	found := map[k8s2.ObjectRef]bool{}
+	for _, o := range cmd.targetCtx.DeploymentCollection.LocalObjects() {
+		ref := o.GetK8sRef()
+		if !cmd.matchRef(ref) {
+			continue
+		}
+		objects = append(objects, o)
+		found[k8s2.ObjectRef{Group: ref.Group, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}] = true
+	}
+	for _, r := range cmd.Refs {
+		if !found[r] {
+			return nil, fmt.Errorf("object %s is not part of the rendered target", r.String())
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].GetK8sRef().Less(objects[j].GetK8sRef())
+	})
+
+	var ret []TakeOwnershipResult
+	hadError := false
+	for _, o := range objects {
+		ref := o.GetK8sRef()
+		s := status.Startf(cmd.targetCtx.SharedContext.Ctx, "Taking ownership of %s", ref.String())
+		r := TakeOwnershipResult{Ref: ref}
+		taken, err := cmd.takeOwnership(k, o)
+		if err != nil {
+			s.FailedWithMessage(err.Error())
+			r.Error = err.Error()
+			hadError = true
+		} else {
+			s.UpdateAndInfoFallbackf("Took ownership of %d fields from %s", len(taken), ref.String())
+			s.Success()
+			r.Taken = taken
+		}
+		ret = append(ret, r)
+	}
+
+	if hadError {
+		return ret, fmt.Errorf("failed to take ownership of some objects")
+	}
+	return ret, nil
+}
+
+func (cmd *TakeOwnershipCommand) takeOwnership(k *k8s.K8sCluster, local *uo.UnstructuredObject) ([]diff.TakenOwnership, error) {
+	ref := local.GetK8sRef()
+
+	remote, _, err := k.GetSingleObject(ref)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("%s does not exist on the cluster", ref.String())
+		}
+		return nil, err
+	}
+
+	x := k.FixObjectForPatch(local)
+
+	if len(cmd.Fields) != 0 {
+		// find out which fields are conflicting and only keep the ones that were selected
+		_, _, err = k.ApplyObject(x, k8s.PatchOptions{ForceDryRun: true})
+		if err != nil {
+			var statusError *errors.StatusError
+			if !errors.IsConflict(err) || !errors2.As(err, &statusError) {
+				return nil, err
+			}
+			cr := diff.ConflictResolver{
+				Configs: []types.ConflictResolutionConfig{
+					{FieldPath: []string{".."}, Action: types.ConflictResolutionIgnore},
+					{FieldPath: cmd.Fields, Action: types.ConflictResolutionForceApply},
+				},
+			}
+			x, _, err = cr.ResolveConflicts(x, remote, statusError.ErrStatus)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	applied, _, err := k.ApplyObject(x, k8s.PatchOptions{ForceApply: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return diff.FindTakenOwnership(remote, applied, "kluctl")
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"sort"
 )
 
@@ -23,15 +24,28 @@ func collectObjects(c *deployment.DeploymentCollection, ru *utils.RemoteObjectUt
 		return x
 	}
 
+	// objects with generateName are recorded with their generated names
+	generatedSources := map[*uo.UnstructuredObject]bool{}
+	if au != nil {
+		for ref, x := range au.GetGeneratedObjects() {
+			generatedSources[x] = true
+			o := getOrCreate(ref)
+			o.Rendered = x
+		}
+	}
+
 	if c != nil {
 		for _, x := range c.LocalObjects() {
+			if generatedSources[x] {
+				continue
+			}
 			dn := du.GetDiffRef(x)
 			o := getOrCreate(dn)
 			o.Rendered = x
 		}
 	}
 	if ru != nil {
-		for _, x := range ru.GetFilteredRemoteObjects(nil) {
+		for _, x := range ru.GetFilteredRemoteObjects(nil, nil) {
 			dn := du.GetDiffRef(x)
 			remoteDiffNames[x.GetK8sRef()] = dn
 
@@ -54,6 +68,14 @@ func collectObjects(c *deployment.DeploymentCollection, ru *utils.RemoteObjectUt
 			o := getOrCreate(dn)
 			o.Hook = true
 		}
+		for _, x := range au.GetDryRunObjectRefs() {
+			dn, ok := appliedDiffNames[x]
+			if !ok {
+				dn = x
+			}
+			o := getOrCreate(dn)
+			o.DryRun = true
+		}
 		for _, x := range au.GetDeletedObjects() {
 			dn, ok := remoteDiffNames[x]
 			if !ok {
@@ -60,6 +60,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context) *result.ValidateResult {
 	}
 
 	ad := utils2.NewApplyDeploymentsUtil(ctx, cmd.dew, cmd.ru, cmd.targetCtx.SharedContext.K, &utils2.ApplyUtilOptions{})
+	defer ad.Close()
 	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
 		for _, o := range d.Objects {
 			if o.GetK8sAnnotationBoolNoError("kluctl.io/delete", false) {
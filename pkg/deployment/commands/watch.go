@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"context"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+type WatchObjectState string
+
+const (
+	WatchObjectReady    WatchObjectState = "Ready"
+	WatchObjectNotReady WatchObjectState = "NotReady"
+	WatchObjectError    WatchObjectState = "Error"
+	WatchObjectMissing  WatchObjectState = "Missing"
+)
+
+type WatchObjectStatus struct {
+	Ref     k8s2.ObjectRef
+	State   WatchObjectState
+	Message string
+}
+
+type WatchEvent struct {
+	Ref     k8s2.ObjectRef
+	Time    time.Time
+	Type    string
+	Reason  string
+	Message string
+	Count   int32
+}
+
+type WatchPollResult struct {
+	Objects []WatchObjectStatus
+	// ChangedObjects contains all objects from Objects whose status changed since the previous poll
+	ChangedObjects []WatchObjectStatus
+	Events         []WatchEvent
+}
+
+// WatchCommand periodically determines the readiness of all objects belonging to a target and collects the events
+// that were emitted for these objects. It is meant to be polled repeatedly, e.g. by `kluctl watch`.
+type WatchCommand struct {
+	targetCtx     *target_context.TargetContext
+	discriminator string
+
+	eventsSince time.Time
+	// seenEvents maps the UIDs of already reported events to their last reported resourceVersion. Only events that
+	// still exist are kept, so this does not grow beyond the number of events in the watched namespaces.
+	seenEvents map[types.UID]string
+	lastStates map[k8s2.ObjectRef]WatchObjectStatus
+}
+
+func NewWatchCommand(discriminator string, targetCtx *target_context.TargetContext, eventsSince time.Time) *WatchCommand {
+	return &WatchCommand{
+		targetCtx:     targetCtx,
+		discriminator: discriminator,
+		eventsSince:   eventsSince,
+		seenEvents:    map[types.UID]string{},
+		lastStates:    map[k8s2.ObjectRef]WatchObjectStatus{},
+	}
+}
+
+func (cmd *WatchCommand) Poll(ctx context.Context) (*WatchPollResult, error) {
+	k := cmd.targetCtx.SharedContext.K
+
+	// always start with fresh remote objects, as we need the current state on every poll
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+
+	var refs []k8s2.ObjectRef
+	discriminator := cmd.discriminator
+
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		for _, o := range d.Objects {
+			refs = append(refs, o.GetK8sRef())
+		}
+	}
+	if discriminator == "" {
+		discriminator = cmd.targetCtx.Target.Discriminator
+	}
+
+	err := ru.UpdateRemoteObjects(k, &discriminator, refs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &WatchPollResult{}
+	watchedRefs := map[k8s2.ObjectRef]bool{}
+
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		for _, o := range d.Objects {
+			if o.GetK8sAnnotationBoolNoError("kluctl.io/delete", false) {
+				continue
+			}
+
+			if hook := utils2.ParseHook(d, o, dew); hook != nil {
+				if !hook.IsPersistent() || hook.IsOnlyDelete() || hook.IsOnlyRollback() {
+					continue
+				}
+			}
+
+			ref := o.GetK8sRef()
+			watchedRefs[ref] = true
+
+			remoteObject := ru.GetRemoteObject(ref)
+			if remoteObject == nil {
+				ret.Objects = append(ret.Objects, WatchObjectStatus{Ref: ref, State: WatchObjectMissing, Message: "object not found"})
+				continue
+			}
+
+			r := validation.ValidateObject(ctx, k, remoteObject, false, false)
+			s := WatchObjectStatus{Ref: ref}
+			var msgs []string
+			if len(r.Errors) != 0 {
+				s.State = WatchObjectError
+				for _, e := range r.Errors {
+					msgs = append(msgs, e.Message)
+				}
+			} else if !r.Ready {
+				s.State = WatchObjectNotReady
+				for _, e := range r.Warnings {
+					msgs = append(msgs, e.Message)
+				}
+			} else {
+				s.State = WatchObjectReady
+			}
+			s.Message = strings.Join(msgs, "; ")
+			ret.Objects = append(ret.Objects, s)
+		}
+	}
+
+	ret.ChangedObjects = cmd.filterChangedObjects(ret.Objects)
+
+	ret.Events, err = cmd.collectEvents(watchedRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// filterChangedObjects returns all objects whose status changed since the last call
+func (cmd *WatchCommand) filterChangedObjects(objects []WatchObjectStatus) []WatchObjectStatus {
+	var ret []WatchObjectStatus
+	for _, s := range objects {
+		if l, ok := cmd.lastStates[s.Ref]; ok && l == s {
+			continue
+		}
+		cmd.lastStates[s.Ref] = s
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func (cmd *WatchCommand) collectEvents(watchedRefs map[k8s2.ObjectRef]bool) ([]WatchEvent, error) {
+	k := cmd.targetCtx.SharedContext.K
+
+	// events for cluster scoped objects end up in the default namespace
+	namespaces := map[string]bool{}
+	refsByKey := map[string]k8s2.ObjectRef{}
+	for ref := range watchedRefs {
+		ns := ref.Namespace
+		if ns == "" {
+			ns = corev1.NamespaceDefault
+		}
+		namespaces[ns] = true
+		refsByKey[buildEventRefKey(ref.Kind, ref.Name, ref.Namespace)] = ref
+	}
+
+	var events []corev1.Event
+	for ns := range namespaces {
+		l, _, err := k.ListObjects(schema.GroupVersionKind{Version: "v1", Kind: "Event"}, ns, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range l {
+			var e corev1.Event
+			err = o.ToStruct(&e)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, e)
+		}
+	}
+
+	return cmd.filterNewEvents(events, refsByKey), nil
+}
+
+// filterNewEvents returns all events for the watched objects that were not reported before or changed since then,
+// e.g. because their count got increased.
+func (cmd *WatchCommand) filterNewEvents(events []corev1.Event, refsByKey map[string]k8s2.ObjectRef) []WatchEvent {
+	var ret []WatchEvent
+	seenEvents := map[types.UID]string{}
+	for _, e := range events {
+		ref, ok := refsByKey[buildEventRefKey(e.InvolvedObject.Kind, e.InvolvedObject.Name, e.InvolvedObject.Namespace)]
+		if !ok {
+			continue
+		}
+
+		t := e.LastTimestamp.Time
+		if t.IsZero() {
+			t = e.EventTime.Time
+		}
+		if t.IsZero() {
+			t = e.CreationTimestamp.Time
+		}
+		if t.Before(cmd.eventsSince) {
+			continue
+		}
+
+		seenEvents[e.UID] = e.ResourceVersion
+		if rv, ok := cmd.seenEvents[e.UID]; ok && rv == e.ResourceVersion {
+			continue
+		}
+
+		ret = append(ret, WatchEvent{
+			Ref:     ref,
+			Time:    t,
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+		})
+	}
+	// events that got deleted in the meantime (e.g. due to their TTL) are forgotten
+	cmd.seenEvents = seenEvents
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Time.Before(ret[j].Time)
+	})
+
+	return ret
+}
+
+func buildEventRefKey(kind string, name string, namespace string) string {
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+	"time"
+)
+
+func buildTestEvent(uid string, rv string, name string, t time.Time, count int32) corev1.Event {
+	return corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             types.UID(uid),
+			ResourceVersion: rv,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Deployment",
+			Name:      name,
+			Namespace: "ns",
+		},
+		LastTimestamp: metav1.NewTime(t),
+		Reason:        "ScalingReplicaSet",
+		Count:         count,
+	}
+}
+
+func TestWatchFilterNewEvents(t *testing.T) {
+	now := time.Now()
+	cmd := NewWatchCommand("", nil, now.Add(-time.Minute))
+
+	ref := k8s2.ObjectRef{Group: "apps", Version: "v1", Kind: "Deployment", Name: "d1", Namespace: "ns"}
+	refsByKey := map[string]k8s2.ObjectRef{
+		buildEventRefKey(ref.Kind, ref.Name, ref.Namespace): ref,
+	}
+
+	e1 := buildTestEvent("e1", "1", "d1", now, 1)
+	e2 := buildTestEvent("e2", "2", "d1", now.Add(-time.Second), 1)
+	eOld := buildTestEvent("e3", "3", "d1", now.Add(-time.Hour), 1)
+	eOther := buildTestEvent("e4", "4", "other", now, 1)
+
+	r := cmd.filterNewEvents([]corev1.Event{e1, e2, eOld, eOther}, refsByKey)
+	if assert.Len(t, r, 2) {
+		// sorted by time
+		assert.Equal(t, ref, r[0].Ref)
+		assert.Equal(t, now.Add(-time.Second).Unix(), r[0].Time.Unix())
+		assert.Equal(t, now.Unix(), r[1].Time.Unix())
+	}
+
+	// nothing changed
+	r = cmd.filterNewEvents([]corev1.Event{e1, e2, eOld, eOther}, refsByKey)
+	assert.Empty(t, r)
+
+	// updated events are reported again
+	e1 = buildTestEvent("e1", "5", "d1", now, 2)
+	r = cmd.filterNewEvents([]corev1.Event{e1, e2}, refsByKey)
+	if assert.Len(t, r, 1) {
+		assert.Equal(t, int32(2), r[0].Count)
+	}
+
+	// deleted events are forgotten
+	r = cmd.filterNewEvents([]corev1.Event{e1}, refsByKey)
+	assert.Empty(t, r)
+	assert.Equal(t, map[types.UID]string{"e1": "5"}, cmd.seenEvents)
+}
+
+func TestWatchFilterChangedObjects(t *testing.T) {
+	cmd := NewWatchCommand("", nil, time.Time{})
+
+	ref1 := k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm1", Namespace: "ns"}
+	ref2 := k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm2", Namespace: "ns"}
+
+	objects := []WatchObjectStatus{
+		{Ref: ref1, State: WatchObjectNotReady, Message: "waiting"},
+		{Ref: ref2, State: WatchObjectMissing},
+	}
+	assert.Equal(t, objects, cmd.filterChangedObjects(objects))
+	assert.Empty(t, cmd.filterChangedObjects(objects))
+
+	objects = []WatchObjectStatus{
+		{Ref: ref1, State: WatchObjectNotReady, Message: "still waiting"},
+		{Ref: ref2, State: WatchObjectMissing},
+	}
+	assert.Equal(t, objects[:1], cmd.filterChangedObjects(objects))
+
+	objects = []WatchObjectStatus{
+		{Ref: ref1, State: WatchObjectReady},
+		{Ref: ref2, State: WatchObjectReady},
+	}
+	assert.Equal(t, objects, cmd.filterChangedObjects(objects))
+}
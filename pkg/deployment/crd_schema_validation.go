@@ -0,0 +1,91 @@
+package deployment
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// buildCrdSchemaValidators builds schema validators for all versions of the given CRDs that have a schema
+func buildCrdSchemaValidators(crds []*uo.UnstructuredObject) (map[schema.GroupVersionKind]validation.SchemaValidator, error) {
+	ret := map[schema.GroupVersionKind]validation.SchemaValidator{}
+	for _, o := range crds {
+		var crd apiextensionsv1.CustomResourceDefinition
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, &crd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CRD %s: %w", o.GetK8sName(), err)
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			var props apiextensions.JSONSchemaProps
+			err = apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(v.Schema.OpenAPIV3Schema, &props, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert schema of CRD %s, version %s: %w", crd.Name, v.Name, err)
+			}
+			validator, _, err := validation.NewSchemaValidator(&props)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build schema validator for CRD %s, version %s: %w", crd.Name, v.Name, err)
+			}
+			gvk := schema.GroupVersionKind{
+				Group:   crd.Spec.Group,
+				Version: v.Name,
+				Kind:    crd.Spec.Names.Kind,
+			}
+			ret[gvk] = validator
+		}
+	}
+	return ret, nil
+}
+
+// validateExternalCRDSchemas validates custom resources against the schemas of the CRDs loaded from crdSources. This
+// is only done for custom resources that are neither defined by the deployment itself nor known to the target
+// cluster, as the API server validates all others when they are applied.
+func (c *DeploymentCollection) validateExternalCRDSchemas() error {
+	if len(c.ctx.ExternalCRDs) == 0 {
+		return nil
+	}
+
+	s := status.Start(c.ctx.Ctx, "Validating custom resources against CRD sources")
+	defer s.Failed()
+
+	validators, err := buildCrdSchemaValidators(c.ctx.ExternalCRDs)
+	if err != nil {
+		return err
+	}
+	localCRDs := buildNamespacedFromCRDs(c.LocalObjects())
+
+	var errs *multierror.Error
+	for _, d := range c.Deployments {
+		for _, o := range d.Objects {
+			gvk := o.GetK8sGVK()
+			v, ok := validators[gvk]
+			if !ok {
+				continue
+			}
+			if _, ok := localCRDs[gvk.GroupKind()]; ok {
+				continue
+			}
+			if c.ctx.K != nil && c.ctx.K.IsNamespaced(gvk) != nil {
+				continue
+			}
+			fieldErrs := validation.ValidateCustomResource(nil, o.Object, v)
+			if len(fieldErrs) != 0 {
+				errs = multierror.Append(errs, fmt.Errorf("%s is invalid according to the schema from crdSources: %w", o.GetK8sRef().String(), fieldErrs.ToAggregate()))
+			}
+		}
+	}
+	if errs.ErrorOrNil() != nil {
+		return errs.ErrorOrNil()
+	}
+
+	s.Success()
+	return nil
+}
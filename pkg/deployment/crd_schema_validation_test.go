@@ -0,0 +1,68 @@
+package deployment
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testSchemaCrd = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.test.kluctl.io
+spec:
+  group: test.kluctl.io
+  names:
+    kind: Thing
+  scope: Namespaced
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              replicas:
+                type: integer
+  - name: v2
+`
+
+func buildTestThing(apiVersion string, replicas any) *uo.UnstructuredObject {
+	return uo.FromMap(map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       "Thing",
+		"metadata":   map[string]any{"name": "t1", "namespace": "ns"},
+		"spec":       map[string]any{"replicas": replicas},
+	})
+}
+
+func TestValidateExternalCRDSchemas(t *testing.T) {
+	c := &DeploymentCollection{
+		ctx: SharedContext{
+			Ctx:          context.Background(),
+			ExternalCRDs: []*uo.UnstructuredObject{uo.FromStringMust(testSchemaCrd)},
+		},
+	}
+	d := &DeploymentItem{}
+	c.Deployments = []*DeploymentItem{d}
+
+	d.Objects = []*uo.UnstructuredObject{buildTestThing("test.kluctl.io/v1", 1)}
+	assert.NoError(t, c.validateExternalCRDSchemas())
+
+	d.Objects = []*uo.UnstructuredObject{buildTestThing("test.kluctl.io/v1", "one")}
+	err := c.validateExternalCRDSchemas()
+	assert.ErrorContains(t, err, "ns/Thing/t1 is invalid according to the schema from crdSources")
+	assert.ErrorContains(t, err, "spec.replicas")
+
+	// versions without schema are not validated
+	d.Objects = []*uo.UnstructuredObject{buildTestThing("test.kluctl.io/v2", "one")}
+	assert.NoError(t, c.validateExternalCRDSchemas())
+
+	// CRDs that are part of the deployment take precedence
+	d.Objects = []*uo.UnstructuredObject{buildTestThing("test.kluctl.io/v1", "one"), uo.FromStringMust(testSchemaCrd)}
+	assert.NoError(t, c.validateExternalCRDSchemas())
+}
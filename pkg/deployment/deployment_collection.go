@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"github.com/hashicorp/go-multierror"
 	"github.com/kluctl/kluctl/lib/status"
 	"github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/pkg/helm"
@@ -13,6 +14,7 @@ import (
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"path/filepath"
 	"sync"
@@ -25,16 +27,23 @@ type DeploymentCollection struct {
 	Images    *Images
 	Inclusion *utils.Inclusion
 
+	// ObjectSelector optionally restricts the rendered objects to the ones with matching labels
+	ObjectSelector labels.Selector
+
 	Deployments []*DeploymentItem
 	mutex       sync.Mutex
+
+	// renderedObjectRefs contains the refs of all rendered objects, including the ones filtered out by ObjectSelector
+	renderedObjectRefs []k8s2.ObjectRef
 }
 
-func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, images *Images, inclusion *utils.Inclusion) (*DeploymentCollection, error) {
+func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, images *Images, inclusion *utils.Inclusion, objectSelector labels.Selector) (*DeploymentCollection, error) {
 	dc := &DeploymentCollection{
-		ctx:       ctx,
-		Project:   project,
-		Images:    images,
-		Inclusion: inclusion,
+		ctx:            ctx,
+		Project:        project,
+		Images:         images,
+		Inclusion:      inclusion,
+		ObjectSelector: objectSelector,
 	}
 
 	indexes := make(map[string]int)
@@ -42,6 +51,11 @@ func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, imag
 	if err != nil {
 		return nil, err
 	}
+	err = dc.checkItemVars(deployments)
+	if err != nil {
+		return nil, err
+	}
+
 	dc.Deployments = make([]*DeploymentItem, 0, len(deployments))
 	for _, d := range deployments {
 		if d.CheckInclusionForDeploy() {
@@ -51,9 +65,30 @@ func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, imag
 	return dc, nil
 }
 
-func (c *DeploymentCollection) createBarrierDummy(project *DeploymentProject) *DeploymentItem {
+// checkItemVars ensures that all item vars passed via the command line match at least one deployment item, so that
+// typos in item paths don't silently get ignored.
+func (c *DeploymentCollection) checkItemVars(deployments []*DeploymentItem) error {
+	if len(c.ctx.ItemVars) == 0 {
+		return nil
+	}
+	found := map[string]bool{}
+	for _, d := range deployments {
+		if d.dir != nil {
+			found[filepath.ToSlash(d.RelToSourceItemDir)] = true
+		}
+	}
+	for p := range c.ctx.ItemVars {
+		if !found[p] {
+			return fmt.Errorf("no deployment item found for item args with path '%s'", p)
+		}
+	}
+	return nil
+}
+
+func (c *DeploymentCollection) createBarrierDummy(project *DeploymentProject, waitReadiness bool) *DeploymentItem {
 	tmpDiConfig := &types.DeploymentItemConfig{
-		Barrier: true,
+		Barrier:              !waitReadiness,
+		WaitReadinessBarrier: waitReadiness,
 	}
 	di, err := NewDeploymentItem(c.ctx, project, c, tmpDiConfig, nil, 0)
 	if err != nil {
@@ -62,6 +97,24 @@ func (c *DeploymentCollection) createBarrierDummy(project *DeploymentProject) *D
 	return di
 }
 
+// createIncludeHooksItem creates the deployment item for the preIncludeHooks/postIncludeHooks of an include. The path
+// is relative to the including project, while the tags are the ones of the included project, so that the hooks are only
+// executed when the included project is deployed. Objects without a hook annotation are treated as defaultHook.
+func (c *DeploymentCollection) createIncludeHooksItem(project *DeploymentProject, includedProject *DeploymentProject, pth string, defaultHook string, includedItems []*DeploymentItem, indexes map[string]int) (*DeploymentItem, error) {
+	tmpDiConfig := &types.DeploymentItemConfig{
+		Path: &pth,
+	}
+	index, dir := findDeploymentItemIndex(project, &pth, indexes)
+	di, err := NewDeploymentItem(c.ctx, project, c, tmpDiConfig, dir, index)
+	if err != nil {
+		return nil, err
+	}
+	di.Tags = includedProject.getTags()
+	di.IncludeHooksFor = includedItems
+	di.defaultHook = defaultHook
+	return di, nil
+}
+
 func findDeploymentItemIndex(project *DeploymentProject, pth *string, indexes map[string]int) (int, *string) {
 	if pth == nil {
 		return 0, nil
@@ -111,9 +164,24 @@ func (c *DeploymentCollection) collectAllDeployments(project *DeploymentProject,
 			if err != nil {
 				return nil, err
 			}
+			// include hooks are skipped when the included project has nothing to deploy, e.g. due to its 'when'
+			if diConfig.PreIncludeHooks != nil && len(ret2) != 0 {
+				di, err := c.createIncludeHooksItem(project, includedProject, *diConfig.PreIncludeHooks, "pre-deploy", ret2, indexes)
+				if err != nil {
+					return nil, err
+				}
+				ret = append(ret, di, c.createBarrierDummy(project, false))
+			}
 			ret = append(ret, ret2...)
-			if diConfig.Barrier {
-				ret = append(ret, c.createBarrierDummy(project))
+			if diConfig.PostIncludeHooks != nil && len(ret2) != 0 {
+				di, err := c.createIncludeHooksItem(project, includedProject, *diConfig.PostIncludeHooks, "post-deploy", ret2, indexes)
+				if err != nil {
+					return nil, err
+				}
+				ret = append(ret, c.createBarrierDummy(project, false), di)
+			}
+			if diConfig.Barrier || diConfig.WaitReadinessBarrier {
+				ret = append(ret, c.createBarrierDummy(project, diConfig.WaitReadinessBarrier))
 			}
 		} else {
 			index, dir2 := findDeploymentItemIndex(project, diConfig.Path, indexes)
@@ -134,17 +202,41 @@ func (c *DeploymentCollection) RenderDeployments() error {
 
 	g := utils.NewGoHelper(c.ctx.Ctx, 0)
 
+	var mutex sync.Mutex
+	var renderErrs RenderErrors
 	for _, d := range c.Deployments {
 		d := d
 		g.RunE(func() error {
-			return d.render()
+			err := d.render()
+			if re, ok := err.(RenderErrors); ok {
+				// collect these so that they can be presented together
+				mutex.Lock()
+				defer mutex.Unlock()
+				renderErrs = append(renderErrs, re...)
+				return nil
+			}
+			return err
 		})
 	}
 	g.Wait()
-	if g.ErrorOrNil() == nil {
-		s.Success()
+
+	var err error
+	if len(renderErrs) != 0 {
+		renderErrs.sort()
+		err = renderErrs
+		s.FailedWithMessage(fmt.Sprintf("Rendering failed for %d templates", len(renderErrs)))
 	}
-	return g.ErrorOrNil()
+	if g.ErrorOrNil() != nil {
+		if err != nil {
+			return multierror.Append(g.ErrorOrNil(), err)
+		}
+		return g.ErrorOrNil()
+	}
+	if err != nil {
+		return err
+	}
+	s.Success()
+	return nil
 }
 
 func (c *DeploymentCollection) renderHelmCharts() error {
@@ -244,13 +336,14 @@ func (c *DeploymentCollection) writeRenderedYamls() error {
 }
 
 func (c *DeploymentCollection) fixNamespaces() error {
-	if c.ctx.K == nil {
+	namespacedFromCRDs := buildNamespacedFromCRDs(c.LocalObjects())
+	namespacedFromExternalCRDs := buildNamespacedFromCRDs(c.ctx.ExternalCRDs)
+	if c.ctx.K == nil && len(namespacedFromCRDs) == 0 && len(namespacedFromExternalCRDs) == 0 {
 		return nil
 	}
-	namespacedFromCRDs := c.buildNamespacedFromCRDs()
 	for _, d := range c.Deployments {
 		for _, o := range d.Objects {
-			def := "default"
+			def := d.getDefaultNamespace()
 			helmNs := o.GetK8sAnnotation(helm.InstallNamespaceAnnotation)
 			if helmNs != nil {
 				def = *helmNs
@@ -258,9 +351,12 @@ func (c *DeploymentCollection) fixNamespaces() error {
 			}
 
 			namespaced := namespacedFromCRDs[o.GetK8sRef().GroupKind()]
-			if namespaced == nil {
+			if namespaced == nil && c.ctx.K != nil {
 				namespaced = c.ctx.K.IsNamespaced(o.GetK8sRef().GroupVersionKind())
 			}
+			if namespaced == nil {
+				namespaced = namespacedFromExternalCRDs[o.GetK8sRef().GroupKind()]
+			}
 
 			if namespaced != nil {
 				k8s.FixNamespace(o, *namespaced, def)
@@ -270,6 +366,22 @@ func (c *DeploymentCollection) fixNamespaces() error {
 	return nil
 }
 
+func (c *DeploymentCollection) filterObjectsBySelector() {
+	if c.ObjectSelector == nil {
+		return
+	}
+	c.renderedObjectRefs = c.LocalObjectRefs()
+	for _, d := range c.Deployments {
+		var filtered []*uo.UnstructuredObject
+		for _, o := range d.Objects {
+			if c.ObjectSelector.Matches(labels.Set(o.GetK8sLabels())) {
+				filtered = append(filtered, o)
+			}
+		}
+		d.Objects = filtered
+	}
+}
+
 func (c *DeploymentCollection) collectResultObjects() error {
 	for _, d := range c.Deployments {
 		err := d.collectResultObjects()
@@ -280,22 +392,20 @@ func (c *DeploymentCollection) collectResultObjects() error {
 	return nil
 }
 
-func (c *DeploymentCollection) buildNamespacedFromCRDs() map[schema.GroupKind]*bool {
+func buildNamespacedFromCRDs(objects []*uo.UnstructuredObject) map[schema.GroupKind]*bool {
 	namespacedFromCRDs := map[schema.GroupKind]*bool{}
-	for _, d := range c.Deployments {
-		for _, o := range d.Objects {
-			if o.GetK8sRef().GroupKind().String() == "CustomResourceDefinition.apiextensions.k8s.io" {
-				scope, _, _ := o.GetNestedString("spec", "scope")
-				group, _, _ := o.GetNestedString("spec", "group")
-				kind, _, _ := o.GetNestedString("spec", "names", "kind")
-				if scope != "" && group != "" && kind != "" {
-					b := scope == "Namespaced"
-					gk := schema.GroupKind{
-						Group: group,
-						Kind:  kind,
-					}
-					namespacedFromCRDs[gk] = &b
+	for _, o := range objects {
+		if o.GetK8sRef().GroupKind().String() == "CustomResourceDefinition.apiextensions.k8s.io" {
+			scope, _, _ := o.GetNestedString("spec", "scope")
+			group, _, _ := o.GetNestedString("spec", "group")
+			kind, _, _ := o.GetNestedString("spec", "names", "kind")
+			if scope != "" && group != "" && kind != "" {
+				b := scope == "Namespaced"
+				gk := schema.GroupKind{
+					Group: group,
+					Kind:  kind,
 				}
+				namespacedFromCRDs[gk] = &b
 			}
 		}
 	}
@@ -328,6 +438,16 @@ func (c *DeploymentCollection) LocalObjectRefs() []k8s2.ObjectRef {
 	return ret
 }
 
+// RenderedObjectRefs returns the refs of all rendered objects, including the ones filtered out by ObjectSelector.
+// Orphan detection must use these instead of LocalObjectRefs, as objects that are filtered out by the selector are
+// still owned by the project, even if their live labels match the selector.
+func (c *DeploymentCollection) RenderedObjectRefs() []k8s2.ObjectRef {
+	if c.ObjectSelector == nil {
+		return c.LocalObjectRefs()
+	}
+	return c.renderedObjectRefs
+}
+
 func (c *DeploymentCollection) Prepare() error {
 	err := c.RenderDeployments()
 	if err != nil {
@@ -353,6 +473,11 @@ func (c *DeploymentCollection) Prepare() error {
 	if err != nil {
 		return err
 	}
+	c.filterObjectsBySelector()
+	err = c.validateExternalCRDSchemas()
+	if err != nil {
+		return err
+	}
 	err = c.collectResultObjects()
 	if err != nil {
 		return err
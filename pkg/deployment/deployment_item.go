@@ -8,11 +8,14 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/sops"
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/kustomize"
 	securefs "github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/kustomize/filesys"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars"
+	cp "github.com/otiai10/copy"
+	yaml3 "gopkg.in/yaml.v3"
 	"io/fs"
 	"os"
 	"path"
@@ -31,12 +34,18 @@ type DeploymentItem struct {
 	index     int
 
 	// These values come from the metadata of the kustomization.yml
-	Barrier       bool
-	WaitReadiness bool
+	Barrier              bool
+	WaitReadinessBarrier bool
+	WaitReadiness        bool
 
 	Objects []*uo.UnstructuredObject
 	Tags    *utils.OrderedMap[string, bool]
 
+	// IncludeHooksFor is only set for items created from preIncludeHooks/postIncludeHooks and contains the items of
+	// the included project
+	IncludeHooksFor []*DeploymentItem
+	defaultHook     string
+
 	RenderedSourceRootDir string
 	RelToSourceItemDir    string
 	RelToProjectItemDir   string
@@ -88,6 +97,12 @@ func NewDeploymentItem(ctx SharedContext, project *DeploymentProject, collection
 		return nil, err
 	}
 
+	if di.dir != nil {
+		if itemVars, ok := di.ctx.ItemVars[filepath.ToSlash(di.RelToSourceItemDir)]; ok {
+			di.VarsCtx.Update(itemVars)
+		}
+	}
+
 	return di, nil
 }
 
@@ -110,9 +125,28 @@ func (di *DeploymentItem) getCommonAnnotations() map[string]string {
 	if di.Config.SkipDeleteIfTags {
 		a["kluctl.io/skip-delete-if-tags"] = "true"
 	}
+	if di.Config.AlwaysSkipDelete {
+		a["kluctl.io/skip-delete"] = "true"
+	}
 	return a
 }
 
+// getDefaultNamespace returns the namespace to use for namespaced objects that don't specify one. The item's own
+// defaultNamespace has precedence over the ones from parent includes, which have precedence over the target's
+// defaultNamespace.
+func (di *DeploymentItem) getDefaultNamespace() string {
+	if di.Config.DefaultNamespace != nil {
+		return *di.Config.DefaultNamespace
+	}
+	if ns := di.Project.getDefaultNamespace(); ns != nil {
+		return *ns
+	}
+	if di.ctx.DefaultNamespace != "" {
+		return di.ctx.DefaultNamespace
+	}
+	return "default"
+}
+
 func (di *DeploymentItem) render() error {
 	if di.dir == nil {
 		return nil
@@ -153,13 +187,48 @@ func (di *DeploymentItem) render() error {
 	// also add deployment item dir to search dirs
 	searchDirs = append([]string{*di.dir}, searchDirs...)
 
-	return di.VarsCtx.RenderDirectory(
+	err = di.VarsCtx.RenderDirectory(
 		filepath.Join(di.Project.source.dir, di.RelToSourceItemDir),
 		di.RenderedDir,
 		excludePatterns,
 		searchDirs,
 		di.Project.source.dir,
 	)
+	if err != nil {
+		renderErrs, otherErr := buildRenderErrors(err, di.Project.source.dir)
+		if len(renderErrs) == 0 {
+			return err
+		}
+		if otherErr != nil {
+			// don't lose the parsed render errors when other errors happened as well
+			return multierror.Append(otherErr, renderErrs)
+		}
+		return renderErrs
+	}
+	return di.keepRenderStage("post-jinja2")
+}
+
+// keepRenderStage copies the current state of the rendered deployment item dir into the KeepRenderTmpDir, using the
+// given stage as sub-directory name.
+func (di *DeploymentItem) keepRenderStage(stage string) error {
+	if di.ctx.KeepRenderTmpDir == "" || di.dir == nil {
+		return nil
+	}
+	dir := filepath.Join(di.getKeepRenderTmpDir(), stage)
+	// remove leftovers from previous runs
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	err = cp.Copy(di.RenderedDir, dir)
+	if err != nil {
+		return fmt.Errorf("failed to keep %s render stage of %s: %w", stage, di.RelRenderedDir, err)
+	}
+	return nil
+}
+
+func (di *DeploymentItem) getKeepRenderTmpDir() string {
+	return filepath.Join(di.ctx.KeepRenderTmpDir, di.Project.source.id, di.RelRenderedDir)
 }
 
 func (di *DeploymentItem) isHelmChartYaml(p string) bool {
@@ -265,7 +334,7 @@ func (di *DeploymentItem) CheckInclusionForDeploy() bool {
 	if di.Config.AlwaysDeploy {
 		return true
 	}
-	if di.Config.Barrier {
+	if di.Config.Barrier || di.Config.WaitReadinessBarrier {
 		return true
 	}
 	values := di.buildInclusionEntries()
@@ -342,22 +411,22 @@ func (di *DeploymentItem) generateKustomizationYaml(subDir string) (*uo.Unstruct
 	return generated, nil
 }
 
-func (di *DeploymentItem) writeKustomizationYaml(ky *uo.UnstructuredObject) error {
-	kustomizeYamlPath := yaml.FixPathExt(filepath.Join(di.RenderedDir, "kustomization.yml"))
+func (di *DeploymentItem) writeKustomizationYaml(subDir string, ky *uo.UnstructuredObject) error {
+	kustomizeYamlPath := yaml.FixPathExt(filepath.Join(di.RenderedDir, subDir, "kustomization.yml"))
 	return yaml.WriteYamlFile(kustomizeYamlPath, ky)
 }
 
-func (di *DeploymentItem) prepareKustomizationYaml() (*uo.UnstructuredObject, error) {
-	ky, err := di.readKustomizationYaml("")
+func (di *DeploymentItem) prepareKustomizationYaml(subDir string) (*uo.UnstructuredObject, error) {
+	ky, err := di.readKustomizationYaml(subDir)
 	if err != nil {
 		return nil, err
 	}
 	if ky == nil {
-		ky, err = di.generateKustomizationYaml("")
+		ky, err = di.generateKustomizationYaml(subDir)
 		if err != nil {
 			return nil, err
 		}
-		err = di.writeKustomizationYaml(ky)
+		err = di.writeKustomizationYaml(subDir, ky)
 		if err != nil {
 			return nil, err
 		}
@@ -374,12 +443,44 @@ func (di *DeploymentItem) prepareKustomizationYaml() (*uo.UnstructuredObject, er
 		}
 	}
 
+	err = di.addGenerators(ky)
+	if err != nil {
+		return nil, err
+	}
+
 	di.Barrier = ky.GetK8sAnnotationBoolNoError("kluctl.io/barrier", false)
+	di.WaitReadinessBarrier = ky.GetK8sAnnotationBoolNoError("kluctl.io/wait-readiness-barrier", false)
 	di.WaitReadiness = ky.GetK8sAnnotationBoolNoError("kluctl.io/wait-readiness", false)
 
 	return ky, nil
 }
 
+// getOverlayDir returns the directory (relative to the deployment item dir) of the kustomize overlay that matches the
+// target's overlay. An explicit mapping via the deployment item's overlays has precedence over the overlays/<overlay>
+// convention. If no overlay matches, an empty string is returned and the deployment item dir itself is built.
+func (di *DeploymentItem) getOverlayDir() (string, error) {
+	overlay := di.ctx.Overlay
+	if overlay == "" {
+		return "", nil
+	}
+
+	if p, ok := di.Config.Overlays[overlay]; ok {
+		if !filepath.IsLocal(p) {
+			return "", fmt.Errorf("overlay directory '%s' for overlay '%s' must be a relative path inside %s", p, overlay, di.RelToSourceItemDir)
+		}
+		if !utils.IsDirectory(filepath.Join(di.RenderedDir, p)) {
+			return "", fmt.Errorf("overlay directory '%s' for overlay '%s' does not exist in %s", p, overlay, di.RelToSourceItemDir)
+		}
+		return p, nil
+	}
+
+	p := filepath.Join("overlays", overlay)
+	if filepath.IsLocal(p) && utils.IsDirectory(filepath.Join(di.RenderedDir, p)) {
+		return p, nil
+	}
+	return "", nil
+}
+
 func (di *DeploymentItem) buildKustomize() error {
 	if di.dir == nil {
 		return nil
@@ -388,13 +489,23 @@ func (di *DeploymentItem) buildKustomize() error {
 		return nil
 	}
 
-	ky, err := di.prepareKustomizationYaml()
+	overlayDir, err := di.getOverlayDir()
+	if err != nil {
+		return err
+	}
+
+	ky, err := di.prepareKustomizationYaml(overlayDir)
 	if err != nil {
 		return err
 	}
 
 	// Save modified kustomization.yml
-	err = di.writeKustomizationYaml(ky)
+	err = di.writeKustomizationYaml(overlayDir, ky)
+	if err != nil {
+		return err
+	}
+
+	err = di.keepRenderStage("pre-kustomize")
 	if err != nil {
 		return err
 	}
@@ -405,7 +516,7 @@ func (di *DeploymentItem) buildKustomize() error {
 	}
 
 	fs = sops.NewDecryptingFs(fs, di.ctx.SopsDecrypter)
-	rm, err := kustomize.Build(fs, di.RenderedDir)
+	rm, err := kustomize.Build(fs, filepath.Join(di.RenderedDir, overlayDir))
 	if err != nil {
 		return err
 	}
@@ -420,6 +531,17 @@ func (di *DeploymentItem) buildKustomize() error {
 		di.Objects = append(di.Objects, o)
 	}
 
+	if di.ctx.KeepRenderTmpDir != "" {
+		var objects []interface{}
+		for _, o := range di.Objects {
+			objects = append(objects, o.Object)
+		}
+		err = yaml.WriteYamlAllFile(filepath.Join(di.getKeepRenderTmpDir(), "post-kustomize.yaml"), objects)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -441,6 +563,9 @@ func (di *DeploymentItem) postprocessObjects(images *Images) error {
 			for n, v := range commonAnnotations {
 				o.SetK8sAnnotation(n, v)
 			}
+			if di.defaultHook != "" && o.GetK8sAnnotation("kluctl.io/hook") == nil && o.GetK8sAnnotation("helm.sh/hook") == nil {
+				o.SetK8sAnnotation("kluctl.io/hook", di.defaultHook)
+			}
 
 			// Resolve image placeholders
 			err := images.ResolvePlaceholders(di.ctx.Ctx, di.ctx.K, o, di.RelRenderedDir, di.Tags.ListKeys(), di.VarsCtx.Vars)
@@ -466,6 +591,19 @@ func (di *DeploymentItem) writeRenderedYaml() error {
 		return nil
 	}
 
+	if di.ctx.PreserveYamlFormat {
+		nodes, err := di.BuildRenderedYamlNodes()
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(di.renderedYamlPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return yaml.WriteYamlNodesStream(f, nodes)
+	}
+
 	var objects []interface{}
 	for _, o := range di.Objects {
 		objects = append(objects, o.Object)
@@ -478,3 +616,81 @@ func (di *DeploymentItem) writeRenderedYaml() error {
 	}
 	return nil
 }
+
+// BuildRenderedYamlNodes returns the rendered objects as yaml.v3 nodes. Each object is merged into the node of the
+// source manifest it originates from (matched by group, kind, namespace and name), so that key order, comments,
+// anchors and merge keys survive rendering wherever the content did not change.
+func (di *DeploymentItem) BuildRenderedYamlNodes() ([]*yaml3.Node, error) {
+	sourceNodes, err := di.readSourceYamlNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*yaml3.Node
+	for _, o := range di.Objects {
+		ref := o.GetK8sRef()
+		sn, ok := sourceNodes[ref]
+		if !ok {
+			// the namespace might have been set by kustomize or by the default namespace
+			ref.Namespace = ""
+			sn, ok = sourceNodes[ref]
+		}
+		if !ok {
+			sn = &yaml3.Node{Kind: yaml3.MappingNode}
+		}
+		n, err := yaml.MergeIntoNode(sn, o.Object)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, n)
+	}
+	return ret, nil
+}
+
+func (di *DeploymentItem) readSourceYamlNodes() (map[k8s2.ObjectRef]*yaml3.Node, error) {
+	ret := map[k8s2.ObjectRef]*yaml3.Node{}
+	if di.dir == nil {
+		return ret, nil
+	}
+
+	err := filepath.WalkDir(di.RenderedDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || p == di.renderedYamlPath {
+			return nil
+		}
+		lname := strings.ToLower(d.Name())
+		if !strings.HasSuffix(lname, ".yml") && !strings.HasSuffix(lname, ".yaml") {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		nodes, err := yaml.ReadYamlNodesStream(f)
+		if err != nil {
+			// not all yaml files are manifests, e.g. helm values files with template syntax
+			return nil
+		}
+		for _, n := range nodes {
+			var m map[string]any
+			if n.Decode(&m) != nil || m == nil {
+				continue
+			}
+			ref := uo.FromMap(m).GetK8sRef()
+			if ref.Kind == "" || ref.Name == "" {
+				continue
+			}
+			if _, ok := ret[ref]; !ok {
+				ret[ref] = n
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
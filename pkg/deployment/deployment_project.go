@@ -11,6 +11,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -145,24 +146,26 @@ func (p *DeploymentProject) processConfig() error {
 
 func (p *DeploymentProject) checkDeploymentDirs() error {
 	for _, di := range p.Config.Deployments {
-		if di.Path == nil {
-			continue
-		}
+		for _, pth := range []*string{di.Path, di.PreIncludeHooks, di.PostIncludeHooks} {
+			if pth == nil {
+				continue
+			}
 
-		diDir, err := securejoin.SecureJoin(p.source.dir, filepath.Join(p.relDir, *di.Path))
-		if err != nil {
-			return err
-		}
+			diDir, err := securejoin.SecureJoin(p.source.dir, filepath.Join(p.relDir, *pth))
+			if err != nil {
+				return err
+			}
 
-		if !strings.HasPrefix(diDir, p.source.dir) {
-			return fmt.Errorf("path/include is not part of the deployment project: %s", *di.Path)
-		}
+			if !strings.HasPrefix(diDir, p.source.dir) {
+				return fmt.Errorf("path/include is not part of the deployment project: %s", *pth)
+			}
 
-		if !utils.Exists(diDir) {
-			return fmt.Errorf("deployment directory does not exist: %s", *di.Path)
-		}
-		if !utils.IsDirectory(diDir) {
-			return fmt.Errorf("deployment path is not a directory: %s", *di.Path)
+			if !utils.Exists(diDir) {
+				return fmt.Errorf("deployment directory does not exist: %s", *pth)
+			}
+			if !utils.IsDirectory(diDir) {
+				return fmt.Errorf("deployment path is not a directory: %s", *pth)
+			}
 		}
 	}
 	return nil
@@ -238,6 +241,7 @@ func (p *DeploymentProject) loadIncludes() error {
 
 func (p *DeploymentProject) loadLocalInclude(source Source, incDir string, inc *types.DeploymentItemConfig) (*DeploymentProject, error) {
 	varsCtx := vars.NewVarsCtx(p.VarsCtx.J2)
+	varsCtx.Globals = p.VarsCtx.Globals
 
 	libraryFile := yaml.FixPathExt(filepath.Join(source.dir, incDir, ".kluctl-library.yaml"))
 	if yaml.Exists(libraryFile) {
@@ -317,6 +321,38 @@ func (p *DeploymentProject) getChildren(recursive bool, includeSelf bool) []*Dep
 	return children
 }
 
+// RemoteInclude is a git or oci include of a deployment project
+type RemoteInclude struct {
+	// RelDir is the directory of the deployment project that declares the include, relative to the repository root
+	RelDir string
+	Git    *types.GitProject
+	Oci    *types.OciProject
+}
+
+// GetLocalRemoteIncludes returns all loaded git and oci includes that are declared by deployment projects of the same
+// source as p, meaning that these are part of the local project. Includes declared by included remote projects are
+// not returned.
+func (p *DeploymentProject) GetLocalRemoteIncludes() []RemoteInclude {
+	var ret []RemoteInclude
+	for _, d := range p.getChildren(true, true) {
+		if d.source != p.source {
+			continue
+		}
+		for i, inc := range d.Config.Deployments {
+			if _, ok := d.includes[i]; !ok {
+				continue
+			}
+			if inc.Git != nil || inc.Oci != nil {
+				ret = append(ret, RemoteInclude{RelDir: d.relDir, Git: inc.Git, Oci: inc.Oci})
+			}
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].RelDir < ret[j].RelDir
+	})
+	return ret
+}
+
 func (p *DeploymentProject) getRenderSearchDirs() []string {
 	var ret []string
 	for _, d := range p.getParents() {
@@ -358,6 +394,15 @@ func (p *DeploymentProject) getOverrideNamespace() *string {
 	return nil
 }
 
+func (p *DeploymentProject) getDefaultNamespace() *string {
+	for _, e := range p.getParents() {
+		if e.inc != nil && e.inc.DefaultNamespace != nil {
+			return e.inc.DefaultNamespace
+		}
+	}
+	return nil
+}
+
 func (p *DeploymentProject) getTags() *utils.OrderedMap[string, bool] {
 	var tags utils.OrderedMap[string, bool]
 	for _, e := range p.getParents() {
@@ -391,6 +436,14 @@ func (p *DeploymentProject) GetIgnoreForDiffs(ignoreTags, ignoreLabels, ignoreAn
 	return ret
 }
 
+func (p *DeploymentProject) GetDiffNormalizers() []types.DiffNormalizerConfig {
+	var ret []types.DiffNormalizerConfig
+	for _, e := range p.getParents() {
+		ret = append(ret, e.p.Config.DiffNormalizers...)
+	}
+	return ret
+}
+
 func (p *DeploymentProject) GetConflictResolutionConfigs() []types.ConflictResolutionConfig {
 	var ret []types.ConflictResolutionConfig
 	for _, e := range p.getParents() {
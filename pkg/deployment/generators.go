@@ -0,0 +1,74 @@
+package deployment
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+)
+
+// GeneratorAnnotation is added to all ConfigMaps and Secrets that were generated via configMapGenerator or
+// secretGenerator. The value is the name of the generator, which allows to find old generations (with a different name
+// suffix) of the same generator.
+const GeneratorAnnotation = "kluctl.io/generator"
+
+func buildKustomizeGenerator(name string, namespace string, files []string, literals []string, envs []string, labels map[string]string, annotations map[string]string, disableNameSuffixHash bool) *uo.UnstructuredObject {
+	g := uo.New()
+	_ = g.SetNestedField(name, "name")
+	if namespace != "" {
+		_ = g.SetNestedField(namespace, "namespace")
+	}
+	if len(files) != 0 {
+		_ = g.SetNestedField(files, "files")
+	}
+	if len(literals) != 0 {
+		_ = g.SetNestedField(literals, "literals")
+	}
+	if len(envs) != 0 {
+		_ = g.SetNestedField(envs, "envs")
+	}
+
+	a := map[string]string{}
+	for k, v := range annotations {
+		a[k] = v
+	}
+	a[GeneratorAnnotation] = name
+
+	_ = g.SetNestedField(a, "options", "annotations")
+	if len(labels) != 0 {
+		_ = g.SetNestedField(labels, "options", "labels")
+	}
+	if disableNameSuffixHash {
+		_ = g.SetNestedField(true, "options", "disableNameSuffixHash")
+	}
+	return g
+}
+
+// addGenerators adds the configured generators to the kustomization.yml, so that kustomize takes care of generating
+// the objects, appending the hash suffixes and rewriting references to the generated objects.
+func (di *DeploymentItem) addGenerators(ky *uo.UnstructuredObject) error {
+	appendGenerator := func(field string, g *uo.UnstructuredObject) error {
+		l, _, err := ky.GetNestedList(field)
+		if err != nil {
+			return err
+		}
+		l = append(l, g.Object)
+		return ky.SetNestedField(l, field)
+	}
+
+	for _, x := range di.Config.ConfigMapGenerator {
+		g := buildKustomizeGenerator(x.Name, x.Namespace, x.Files, x.Literals, x.Envs, x.Labels, x.Annotations, x.DisableNameSuffixHash)
+		err := appendGenerator("configMapGenerator", g)
+		if err != nil {
+			return err
+		}
+	}
+	for _, x := range di.Config.SecretGenerator {
+		g := buildKustomizeGenerator(x.Name, x.Namespace, x.Files, x.Literals, x.Envs, x.Labels, x.Annotations, x.DisableNameSuffixHash)
+		if x.Type != "" {
+			_ = g.SetNestedField(x.Type, "type")
+		}
+		err := appendGenerator("secretGenerator", g)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RenderError describes a single template that failed to render.
+type RenderError struct {
+	// File is the template that failed to render. It is relative to the project root if possible. For errors that
+	// happened inside included templates, this points to the included template.
+	File string
+	// Line is the line inside File that caused the error or 0 if unknown
+	Line    int
+	Message string
+}
+
+func (e *RenderError) Error() string {
+	if e.Line != 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// RenderErrors aggregates all render errors of all deployment items, so that all of them can be presented together
+// instead of only the first one.
+type RenderErrors []*RenderError
+
+func (e RenderErrors) Error() string {
+	var sb strings.Builder
+	if len(e) == 1 {
+		sb.WriteString("failed to render 1 template:")
+	} else {
+		sb.WriteString(fmt.Sprintf("failed to render %d templates:", len(e)))
+	}
+	for _, x := range e {
+		sb.WriteString("\n  ")
+		sb.WriteString(x.Error())
+	}
+	return sb.String()
+}
+
+// DeploymentErrors converts the render errors into deployment errors, so that these can be added to command results.
+func (e RenderErrors) DeploymentErrors() []result.DeploymentError {
+	ret := make([]result.DeploymentError, 0, len(e))
+	for _, x := range e {
+		ret = append(ret, result.DeploymentError{Message: x.Error()})
+	}
+	return ret
+}
+
+func (e RenderErrors) sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].File != e[j].File {
+			return e[i].File < e[j].File
+		}
+		return e[i].Line < e[j].Line
+	})
+}
+
+var renderTemplateErrorRegex = regexp.MustCompile(`^failed rendering template '([^']*)': `)
+var jinja2TracebackFileRegex = regexp.MustCompile(`File "([^"]*)", line (\d+)`)
+
+// buildRenderErrors converts the errors returned by Jinja2.RenderDirectory into RenderErrors. Errors that are not
+// related to a single template are returned as they are.
+func buildRenderErrors(err error, rootDir string) (RenderErrors, error) {
+	var errs []error
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		errs = merr.Errors
+	} else {
+		errs = []error{err}
+	}
+
+	var ret RenderErrors
+	var other *multierror.Error
+	for _, e := range errs {
+		m := renderTemplateErrorRegex.FindStringSubmatch(e.Error())
+		if m == nil {
+			other = multierror.Append(other, e)
+			continue
+		}
+		ret = append(ret, parseJinja2Error(m[1], errors.Unwrap(e), rootDir))
+	}
+	return ret, other.ErrorOrNil()
+}
+
+func parseJinja2Error(template string, err error, rootDir string) *RenderError {
+	re := &RenderError{
+		File: filepath.FromSlash(template),
+	}
+	if err == nil {
+		return re
+	}
+
+	s := strings.TrimSpace(err.Error())
+	for _, m := range jinja2TracebackFileRegex.FindAllStringSubmatch(s, -1) {
+		if strings.HasSuffix(m[1], ".py") {
+			// full tracebacks are only returned if no template was involved, so these only point into jinja2 itself
+			continue
+		}
+		re.File = m[1]
+		re.Line, _ = strconv.Atoi(m[2])
+	}
+
+	// the actual error is in the last line, everything before is the traceback
+	lines := strings.Split(s, "\n")
+	re.Message = strings.TrimSpace(lines[len(lines)-1])
+
+	if rootDir != "" && filepath.IsAbs(re.File) {
+		if rel, err := filepath.Rel(rootDir, re.File); err == nil && !strings.HasPrefix(rel, "..") {
+			re.File = rel
+		}
+	}
+	return re
+}
@@ -0,0 +1,75 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+const testTemplateError = `  File "/project/cm1/configmap.yml", line 9, in top-level template code
+    {{ missing1.x }}
+UndefinedError: 'missing1' is undefined
+`
+
+const testTracebackError = `Traceback (most recent call last):
+  File "/python/jinja2/environment.py", line 1301, in render
+    self.environment.handle_exception()
+TypeError: unsupported operand type(s)
+`
+
+func TestParseJinja2Error(t *testing.T) {
+	root := filepath.FromSlash("/project")
+
+	re := parseJinja2Error("cm1/configmap.yml", errors.New(testTemplateError), root)
+	assert.Equal(t, &RenderError{
+		File:    filepath.Join("cm1", "configmap.yml"),
+		Line:    9,
+		Message: "UndefinedError: 'missing1' is undefined",
+	}, re)
+
+	// frames that point into jinja2 itself are not useful, so the template is kept
+	re = parseJinja2Error("cm2/configmap.yml", errors.New(testTracebackError), root)
+	assert.Equal(t, &RenderError{
+		File:    filepath.Join("cm2", "configmap.yml"),
+		Message: "TypeError: unsupported operand type(s)",
+	}, re)
+
+	re = parseJinja2Error("cm3/configmap.yml", errors.New("template include.yml not found"), root)
+	assert.Equal(t, &RenderError{
+		File:    filepath.Join("cm3", "configmap.yml"),
+		Message: "template include.yml not found",
+	}, re)
+}
+
+func TestBuildRenderErrors(t *testing.T) {
+	root := filepath.FromSlash("/project")
+
+	var merr *multierror.Error
+	merr = multierror.Append(merr, fmt.Errorf("failed rendering template '%s': %w", "cm1/configmap.yml", errors.New(testTemplateError)))
+	merr = multierror.Append(merr, fmt.Errorf("failed rendering template '%s': %w", "cm2/configmap.yml", errors.New("template include.yml not found")))
+
+	renderErrs, otherErr := buildRenderErrors(merr, root)
+	assert.NoError(t, otherErr)
+	assert.Len(t, renderErrs, 2)
+	assert.Equal(t, "failed to render 2 templates:\n"+
+		"  "+filepath.Join("cm1", "configmap.yml")+":9: UndefinedError: 'missing1' is undefined\n"+
+		"  "+filepath.Join("cm2", "configmap.yml")+": template include.yml not found", renderErrs.Error())
+	assert.Equal(t, []result.DeploymentError{
+		{Message: filepath.Join("cm1", "configmap.yml") + ":9: UndefinedError: 'missing1' is undefined"},
+		{Message: filepath.Join("cm2", "configmap.yml") + ": template include.yml not found"},
+	}, renderErrs.DeploymentErrors())
+
+	// errors not related to a single template are kept
+	merr = multierror.Append(merr, errors.New("something else failed"))
+	renderErrs, otherErr = buildRenderErrors(merr, root)
+	assert.Len(t, renderErrs, 2)
+	assert.ErrorContains(t, otherErr, "something else failed")
+
+	renderErrs, otherErr = buildRenderErrors(errors.New("something else failed"), root)
+	assert.Empty(t, renderErrs)
+	assert.EqualError(t, otherErr, "1 error occurred:\n\t* something else failed\n\n")
+}
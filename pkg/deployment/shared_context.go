@@ -7,6 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars"
 )
 
@@ -21,6 +22,22 @@ type SharedContext struct {
 	HelmAuthProvider helm_auth.HelmAuthProvider
 	OciAuthProvider  auth_provider.OciAuthProvider
 
-	Discriminator string
-	RenderDir     string
+	Discriminator    string
+	DefaultNamespace string
+	Overlay          string
+	RenderDir        string
+
+	// KeepRenderTmpDir is a directory into which the intermediate rendering stages of each deployment item are
+	// copied to, so that templates can be debugged
+	KeepRenderTmpDir string
+
+	PreserveYamlFormat bool
+
+	// ExternalCRDs are CRDs loaded from the crdSources of the project. They are used to determine the scope of
+	// custom resources that are neither known to the cluster nor defined by the deployment itself.
+	ExternalCRDs []*uo.UnstructuredObject
+
+	// ItemVars maps deployment item paths (relative to the source root) to variables that override the vars of
+	// the matching deployment items.
+	ItemVars map[string]*uo.UnstructuredObject
 }
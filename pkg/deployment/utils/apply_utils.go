@@ -11,20 +11,21 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	types2 "github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
-	"github.com/kluctl/kluctl/v2/pkg/validation"
 	"golang.org/x/sync/semaphore"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
-	"testing"
 	"time"
 )
 
@@ -37,6 +38,18 @@ type ApplyUtilOptions struct {
 	ReadinessTimeout    time.Duration
 	NoWait              bool
 
+	// AllowBreakingCRDChanges disables the CRD upgrade safety checks, which otherwise refuse to apply potentially
+	// breaking CRD changes while custom resources of the CRD exist
+	AllowBreakingCRDChanges bool
+
+	// RetryFailedItems specifies how often deployment items that encountered errors are retried after all other
+	// deployment items have been applied
+	RetryFailedItems int
+
+	// ProgressByNamespace replaces the per deployment item progress with a single aggregated progress and prints a
+	// per-namespace summary at the end
+	ProgressByNamespace bool
+
 	SkipResourceVersions map[k8s2.ObjectRef]string
 }
 
@@ -45,6 +58,7 @@ type ApplyUtil struct {
 
 	dew                *DeploymentErrorsAndWarnings
 	errorCount         int
+	errors             []result.DeploymentError
 	warningCount       int
 	newObjects         map[k8s2.ObjectRef]*uo.UnstructuredObject
 	appliedObjects     map[k8s2.ObjectRef]*uo.UnstructuredObject
@@ -53,11 +67,21 @@ type ApplyUtil struct {
 	deletedHookObjects map[k8s2.ObjectRef]bool
 	mutex              sync.Mutex
 
+	// maps objects with metadata.generateName to the refs of the objects that were actually created
+	generatedRefs map[*uo.UnstructuredObject]k8s2.ObjectRef
+
 	abortSignal   *atomic.Value
 	allNamespaces *sync.Map
 	allCRDs       *sync.Map
+	dryRunObjects *sync.Map
+	deferred      *deferredObjects
 
-	crdCache *k8s.CrdCache
+	crdCache   *k8s.CrdCache
+	rw         *readinessWatcher
+	nsProgress *namespaceProgress
+
+	// used to build the run result for hooks
+	ad *ApplyDeploymentsUtil
 
 	ru   *RemoteObjectUtils
 	k    *k8s.K8sCluster
@@ -81,8 +105,20 @@ type ApplyDeploymentsUtil struct {
 	allNamespaces sync.Map
 	allCRDs       sync.Map
 
+	// Used to track all objects that were applied in dry-run mode due to the kluctl.io/dry-run annotation
+	dryRunObjects sync.Map
+
+	// Used to retry objects that were applied before their CRDs got applied
+	deferred *deferredObjects
+
 	crdCache k8s.CrdCache
 
+	// Used to share watches between all objects that are waited for
+	rw *readinessWatcher
+
+	// Only set when ProgressByNamespace is enabled
+	nsProgress *namespaceProgress
+
 	resultsMutex sync.Mutex
 	results      []*ApplyUtil
 }
@@ -95,10 +131,21 @@ func NewApplyDeploymentsUtil(ctx context.Context, dew *DeploymentErrorsAndWarnin
 		k:   k,
 		o:   o,
 	}
+	if k != nil {
+		ret.rw = newReadinessWatcher(ctx, k)
+	}
 	ret.abortSignal.Store(false)
 	return ret
 }
 
+// Close releases the watches that were started while waiting for readiness. ApplyDeployments calls this on its own,
+// but users that only work with NewApplyUtil must call it when done.
+func (ad *ApplyDeploymentsUtil) Close() {
+	if ad.rw != nil {
+		ad.rw.close()
+	}
+}
+
 func (ad *ApplyDeploymentsUtil) NewApplyUtil(ctx context.Context, statusCtx *status.StatusContext) *ApplyUtil {
 	ad.resultsMutex.Lock()
 	defer ad.resultsMutex.Unlock()
@@ -111,10 +158,16 @@ func (ad *ApplyDeploymentsUtil) NewApplyUtil(ctx context.Context, statusCtx *sta
 		appliedHookObjects: map[k8s2.ObjectRef]*uo.UnstructuredObject{},
 		deletedObjects:     map[k8s2.ObjectRef]bool{},
 		deletedHookObjects: map[k8s2.ObjectRef]bool{},
+		generatedRefs:      map[*uo.UnstructuredObject]k8s2.ObjectRef{},
 		abortSignal:        &ad.abortSignal,
 		allNamespaces:      &ad.allNamespaces,
 		allCRDs:            &ad.allCRDs,
+		dryRunObjects:      &ad.dryRunObjects,
+		deferred:           ad.deferred,
 		crdCache:           &ad.crdCache,
+		rw:                 ad.rw,
+		nsProgress:         ad.nsProgress,
+		ad:                 ad,
 		ru:                 ad.ru,
 		k:                  ad.k,
 		o:                  ad.o,
@@ -145,6 +198,7 @@ func (a *ApplyUtil) handleApiWarnings(ref k8s2.ObjectRef, warnings []k8s.ApiWarn
 
 	a.dew.AddApiWarnings(ref, warnings)
 	a.warningCount += len(warnings)
+	a.nsProgress.addWarnings(ref, len(warnings))
 }
 
 func (a *ApplyUtil) HandleWarning(ref k8s2.ObjectRef, warning error) {
@@ -153,6 +207,7 @@ func (a *ApplyUtil) HandleWarning(ref k8s2.ObjectRef, warning error) {
 
 	a.dew.AddWarning(ref, warning)
 	a.warningCount++
+	a.nsProgress.addWarnings(ref, 1)
 }
 
 func (a *ApplyUtil) HandleError(ref k8s2.ObjectRef, err error) {
@@ -168,16 +223,33 @@ func (a *ApplyUtil) HandleError(ref k8s2.ObjectRef, err error) {
 	}
 
 	a.dew.AddError(ref, err)
+	a.errors = append(a.errors, result.DeploymentError{Ref: ref, Message: err.Error()})
 	a.errorCount++
+	a.nsProgress.addErrors(ref, 1)
 }
 
 func (a *ApplyUtil) HadError(ref k8s2.ObjectRef) bool {
 	return a.dew.HadError(ref)
 }
 
+// isDryRun returns true if the object must only be applied in dry-run mode, either because the whole command runs in
+// dry-run mode or because the object has the kluctl.io/dry-run annotation set
+func (a *ApplyUtil) isDryRun(x *uo.UnstructuredObject) bool {
+	return a.o.DryRun || x.GetK8sAnnotationBoolNoError("kluctl.io/dry-run", false)
+}
+
+func (a *ApplyUtil) isDryRunObject(ref k8s2.ObjectRef) bool {
+	_, ok := a.dryRunObjects.Load(ref)
+	return ok
+}
+
 func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
+	return a.deleteObject(ref, hook, a.o.DryRun)
+}
+
+func (a *ApplyUtil) deleteObject(ref k8s2.ObjectRef, hook bool, dryRun bool) bool {
 	o := k8s.DeleteOptions{
-		ForceDryRun: a.o.DryRun,
+		ForceDryRun: dryRun,
 	}
 	apiWarnings, err := a.k.DeleteSingleObject(ref, o)
 	a.handleApiWarnings(ref, apiWarnings)
@@ -196,7 +268,7 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 		a.HandleError(ref, err)
 		return false
 	}
-	if !a.o.DryRun {
+	if !dryRun {
 		// just ignore 404 errors
 		return false
 	}
@@ -230,6 +302,11 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 	return true
 }
 
+// refCtx returns a context that attaches the given ref to all reported status messages
+func (a *ApplyUtil) refCtx(ref k8s2.ObjectRef) context.Context {
+	return status.WithFields(a.ctx, "ref", ref.String())
+}
+
 func (a *ApplyUtil) retryApplyForceReplace(x *uo.UnstructuredObject, hook bool, remoteObject *uo.UnstructuredObject, applyError error) {
 	ref := x.GetK8sRef()
 
@@ -243,23 +320,22 @@ func (a *ApplyUtil) retryApplyForceReplace(x *uo.UnstructuredObject, hook bool,
 		skipDelete = skipDelete || isSkipDelete(remoteObject)
 	}
 	if skipDelete {
-		status.Warningf(a.ctx, "skipped forced replace of %s", ref.String())
+		status.Warningf(a.refCtx(ref), "skipped forced replace of %s", ref.String())
 		a.HandleError(ref, applyError)
 		return
 	}
 
 	warn := fmt.Errorf("patching %s failed, retrying by deleting and re-applying", ref.String())
 	a.HandleWarning(ref, warn)
-	status.Warning(a.ctx, warn.Error())
+	status.Warning(a.refCtx(ref), warn.Error())
 
-	if !a.DeleteObject(ref, hook) {
+	dryRun := a.isDryRun(x)
+	if !a.deleteObject(ref, hook, dryRun) {
 		return
 	}
 
-	if !a.o.DryRun {
-		o := k8s.PatchOptions{
-			ForceDryRun: a.o.DryRun,
-		}
+	if !dryRun {
+		o := k8s.PatchOptions{}
 		r, apiWarnings, err := a.k.ApplyObject(x, o)
 		a.handleApiWarnings(ref, apiWarnings)
 		if err != nil {
@@ -282,14 +358,14 @@ func (a *ApplyUtil) retryApplyWithReplace(x *uo.UnstructuredObject, hook bool, r
 
 	warn := fmt.Errorf("patching %s failed, retrying with replace instead of patch", ref.String())
 	a.HandleWarning(ref, warn)
-	status.Warning(a.ctx, warn.Error())
+	status.Warning(a.refCtx(ref), warn.Error())
 
 	rv := remoteObject.GetK8sResourceVersion()
 	x2 := x.Clone()
 	x2.SetK8sResourceVersion(rv)
 
 	o := k8s.UpdateOptions{
-		ForceDryRun: a.o.DryRun,
+		ForceDryRun: a.isDryRun(x),
 	}
 
 	r, apiWarnings, err := a.k.UpdateObject(x, o)
@@ -334,7 +410,7 @@ func (a *ApplyUtil) retryApplyWithConflicts(d *deployment.DeploymentItem, x *uo.
 	}
 
 	options := k8s.PatchOptions{
-		ForceDryRun: a.o.DryRun,
+		ForceDryRun: a.isDryRun(x),
 		ForceApply:  true,
 	}
 	r, apiWarnings, err := a.k.ApplyObject(x2, options)
@@ -347,8 +423,23 @@ func (a *ApplyUtil) retryApplyWithConflicts(d *deployment.DeploymentItem, x *uo.
 }
 
 func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.UnstructuredObject, replaced bool, hook bool) {
+	if isGenerateNameObject(x) {
+		if !hook {
+			// creating a new object on every deployment would leave an orphan behind on every deployment
+			a.HandleError(x.GetK8sRef(), fmt.Errorf("metadata.generateName is only supported for hooks"))
+			return
+		}
+		a.createGeneratedHook(x)
+		return
+	}
+
 	ref := x.GetK8sRef()
 
+	dryRun := a.isDryRun(x)
+	if dryRun && !a.o.DryRun {
+		a.dryRunObjects.Store(ref, true)
+	}
+
 	x = a.k.FixObjectForPatch(x)
 	remoteObject := a.ru.GetRemoteObject(ref)
 
@@ -371,8 +462,16 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 		}
 	}
 
+	if remoteObject != nil && ref.GroupKind().String() == "CustomResourceDefinition.apiextensions.k8s.io" {
+		err := a.checkCRDUpgrade(x, remoteObject)
+		if err != nil {
+			a.HandleError(ref, err)
+			return
+		}
+	}
+
 	usesDummyName := false
-	if a.o.DryRun && replaced && remoteObject != nil {
+	if dryRun && replaced && remoteObject != nil {
 		// The object got deleted before, which was however only simulated when in dry-run mode. This means, that
 		// trying to patch it will either fail or give different results then when actually re-creating it. To simulate
 		// re-creation, we use a temporary name for the dry-run patch and then undo the rename after getting the patch
@@ -380,7 +479,7 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 		usesDummyName = true
 		x = x.Clone()
 		x.SetK8sName(utils.RandomizeSuffix(ref.Name, 8, 63))
-	} else if a.o.DryRun && remoteNamespace == nil && ref.Namespace != "" {
+	} else if dryRun && remoteNamespace == nil && ref.Namespace != "" {
 		if _, ok := a.allNamespaces.Load(ref.Namespace); ok {
 			// The namespace does not really exist, but would have been created if dryRun would be false.
 			// So let's pretend we deploy it to the default namespace with a dummy name
@@ -402,7 +501,7 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 	}
 
 	options := k8s.PatchOptions{
-		ForceDryRun: a.o.DryRun,
+		ForceDryRun: dryRun,
 	}
 	r, apiWarnings, err := a.k.ApplyObject(x, options)
 
@@ -422,7 +521,7 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 	undoDummyName(x)
 
 	if r == nil && retryWhenCRDExists {
-		if a.o.DryRun {
+		if dryRun {
 			if _, ok := a.allCRDs.Load(x.GetK8sGVK()); ok {
 				// simulate that the apply "succeeded"
 				a.handleResult(x, hook)
@@ -432,16 +531,16 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 		} else {
 			c, tmpErr := a.k.ToClient()
 			if tmpErr != nil {
-				status.Errorf(a.ctx, "Unexpectadly failed to create k8s client: %s", tmpErr.Error())
+				status.Errorf(a.refCtx(ref), "Unexpectadly failed to create k8s client: %s", tmpErr.Error())
 				a.HandleError(ref, err)
 				return
 			}
 			tmpErr = a.crdCache.UpdateForGroup(a.ctx, c, ref.Group)
 			if tmpErr != nil {
-				status.Tracef(a.ctx, "failed figure out if CRD appeared, so we can't retry with invalidated discovery: %s", tmpErr.Error())
+				status.Tracef(a.refCtx(ref), "failed figure out if CRD appeared, so we can't retry with invalidated discovery: %s", tmpErr.Error())
 			} else {
 				if crd := a.crdCache.GetCRDByGK(ref.GroupKind()); crd != nil {
-					status.Tracef(a.ctx, "resource unknown, and CRD %s is available now, retrying with invalidated caches", crd.Name)
+					status.Tracef(a.refCtx(ref), "resource unknown, and CRD %s is available now, retrying with invalidated caches", crd.Name)
 					// retry with invalidated discovery
 					a.k.ResetMapper()
 					r, apiWarnings, err = a.k.ApplyObject(x, options)
@@ -459,6 +558,10 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 	if err == nil {
 		a.handleResult(r, hook)
 	} else if meta.IsNoMatchError(err) {
+		if !hook && a.deferred.add(a, d, x, replaced, err) {
+			status.Tracef(a.refCtx(ref), "deferring %s until its CRD got established", ref.String())
+			return
+		}
 		a.HandleError(ref, err)
 	} else if errors.IsConflict(err) {
 		a.retryApplyWithConflicts(d, x, hook, remoteObject, err)
@@ -468,7 +571,7 @@ func (a *ApplyUtil) ApplyObject(d *deployment.DeploymentItem, x *uo.Unstructured
 }
 
 func (a *ApplyUtil) handleObservedCRD(r *uo.UnstructuredObject) {
-	status.Tracef(a.ctx, "observed CRD %s", r.GetK8sName())
+	status.Tracef(a.refCtx(r.GetK8sRef()), "observed CRD %s", r.GetK8sName())
 
 	y, err := yaml.WriteYamlBytes(r)
 	if err == nil {
@@ -487,112 +590,6 @@ func (a *ApplyUtil) handleObservedCRD(r *uo.UnstructuredObject) {
 	}
 }
 
-func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) bool {
-	if a.o.DryRun {
-		return true
-	}
-
-	if timeout == 0 {
-		timeout = a.o.ReadinessTimeout
-	}
-	timeoutTimer := time.NewTimer(timeout)
-
-	status.Tracef(a.ctx, "Waiting for %s to get ready", ref.String())
-
-	lastLogTime := time.Now()
-	didLog := false
-	seen := false
-	startTime := time.Now()
-	for true {
-		elapsed := int(time.Now().Sub(startTime).Seconds())
-
-		o, apiWarnings, err := a.k.GetSingleObject(ref)
-		a.handleApiWarnings(ref, apiWarnings)
-		if err != nil {
-			if !errors.IsNotFound(err) {
-				a.HandleError(ref, err)
-				return false
-			}
-		}
-
-		if o == nil {
-			if seen {
-				if didLog {
-					status.Warningf(a.ctx, "Cancelled waiting for %s as it disappeared while waiting for it (%ds elapsed)", ref.String(), elapsed)
-				}
-				a.HandleError(ref, fmt.Errorf("%s disappeared while waiting for it to become ready", ref.String()))
-				return false
-			}
-			a.sctx.Update(fmt.Sprintf("Waiting for %s to appear...", ref.String()))
-		} else {
-			seen = true
-
-			v := validation.ValidateObject(a.ctx, a.k, o, false, false)
-			if v.Ready {
-				if didLog {
-					a.sctx.InfoFallbackf("Finished waiting for %s (%ds elapsed)", ref.String(), elapsed)
-				}
-				for _, e := range v.Errors {
-					a.HandleError(ref, errors2.New(e.Message))
-				}
-				for _, e := range v.Warnings {
-					a.HandleWarning(ref, errors2.New(e.Message))
-				}
-				return true
-			}
-			if len(v.Errors) != 0 {
-				if didLog {
-					status.Warningf(a.ctx, "Cancelled waiting for %s due to errors (%ds elapsed)", ref.String(), elapsed)
-				}
-				for _, e := range v.Errors {
-					a.HandleError(ref, errors2.New(e.Message))
-				}
-				for _, e := range v.Warnings {
-					a.HandleWarning(ref, errors2.New(e.Message))
-				}
-				return false
-			}
-			a.sctx.Update(fmt.Sprintf("Waiting for %s to get ready...", ref.String()))
-		}
-
-		reportStillWaitingTime := 10 * time.Second
-		if testing.Testing() {
-			reportStillWaitingTime = 3 * time.Second
-		}
-
-		if !didLog {
-			a.sctx.InfoFallbackf("Waiting for %s to get ready... (%ds elapsed)", ref.String(), elapsed)
-			didLog = true
-			lastLogTime = time.Now()
-		} else if didLog && time.Now().Sub(lastLogTime) >= reportStillWaitingTime {
-			a.sctx.InfoFallbackf("Still waiting for %s to get ready... (%ds elapsed)", ref.String(), elapsed)
-			lastLogTime = time.Now()
-		}
-
-		select {
-		case <-time.After(500 * time.Millisecond):
-			continue
-		case <-timeoutTimer.C:
-			err := fmt.Errorf("timed out while waiting for readiness of %s", ref.String())
-			status.Warningf(a.ctx, "%s (%ds elapsed)", err.Error(), elapsed)
-			if status.IsTraceEnabled(a.ctx) {
-				y, err := yaml.WriteYamlString(o)
-				if err == nil {
-					status.Trace(a.ctx, "yaml:\n"+y)
-				}
-			}
-			a.HandleError(ref, err)
-			return false
-		case <-a.ctx.Done():
-			err := fmt.Errorf("context cancelled while waiting for readiness of %s", ref.String())
-			status.Warningf(a.ctx, "%s (%ds elapsed)", err.Error(), elapsed)
-			a.HandleError(ref, err)
-			return false
-		}
-	}
-	return false
-}
-
 func (a *ApplyUtil) convertObjectRef(x types2.ObjectRefItem, refs map[k8s2.ObjectRef]bool) {
 	ars, err := a.k.GetFilteredPreferredAPIResources(k8s.BuildGVKFilter(x.Group, nil, x.Kind))
 	if err != nil {
@@ -626,9 +623,59 @@ func (a *ApplyUtil) convertObjectRef(x types2.ObjectRefItem, refs map[k8s2.Objec
 	}
 }
 
+// waitForDependencies waits for readiness of all objects referenced via waitFor of the given deployment item. It
+// returns false if any of these objects did not get ready, in which case the deployment item must not be applied.
+func (a *ApplyUtil) waitForDependencies(d *deployment.DeploymentItem) bool {
+	if len(d.Config.WaitFor) == 0 {
+		return true
+	}
+	if a.o.NoWait {
+		a.HandleWarning(k8s2.ObjectRef{}, fmt.Errorf("not waiting for objects referenced via waitFor of %s as --no-wait is set", filepath.ToSlash(d.RelToSourceItemDir)))
+		return true
+	}
+
+	ok := true
+	toWait := map[k8s2.ObjectRef]bool{}
+	for _, x := range d.Config.WaitFor {
+		refs := map[k8s2.ObjectRef]bool{}
+		a.convertObjectRef(x.ObjectRefItem, refs)
+		if len(refs) == 0 {
+			// convertObjectRef has already reported the error
+			ok = false
+		}
+		for ref := range refs {
+			toWait[ref] = true
+		}
+	}
+	refs := make([]k8s2.ObjectRef, 0, len(toWait))
+	for ref := range toWait {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Less(refs[j])
+	})
+
+	if ok {
+		a.sctx.InfoFallbackf("Waiting for %d objects referenced via waitFor", len(refs))
+		for _, r := range a.WaitReadinessMulti(refs, 0) {
+			if !r {
+				ok = false
+			}
+		}
+	}
+	if !ok && !a.abortSignal.Load().(bool) {
+		a.HandleError(k8s2.ObjectRef{}, fmt.Errorf("not applying %s as not all objects referenced via waitFor got ready", filepath.ToSlash(d.RelToSourceItemDir)))
+	}
+	return ok
+}
+
 func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 	h := HooksUtil{a: a}
 
+	if !a.waitForDependencies(d) {
+		return
+	}
+
 	toDelete := map[k8s2.ObjectRef]bool{}
 	toWaitReadiness := map[k8s2.ObjectRef]bool{}
 	for _, x := range d.Config.DeleteObjects {
@@ -658,6 +705,17 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 			initialDeploy = false
 		}
 	}
+	if d.IncludeHooksFor != nil {
+		// include hooks are initial when the included project is deployed initially
+		initialDeploy = true
+		for _, d2 := range d.IncludeHooksFor {
+			for _, o := range d2.Objects {
+				if a.ru.GetRemoteObject(o.GetK8sRef()) != nil {
+					initialDeploy = false
+				}
+			}
+		}
+	}
 
 	var applyObjects []*uo.UnstructuredObject
 	for _, o := range d.Objects {
@@ -669,6 +727,13 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 		}
 		applyObjects = append(applyObjects, o)
 	}
+	if a.nsProgress != nil {
+		refs := make([]k8s2.ObjectRef, 0, len(applyObjects))
+		for _, o := range applyObjects {
+			refs = append(refs, o.GetK8sRef())
+		}
+		a.nsProgress.addObjects(refs)
+	}
 
 	var preHooks []*hook
 	var postHooks []*hook
@@ -711,6 +776,7 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 		a.sctx.Updatef("Applying object %s (%d of %d)", ref.String(), i+1, len(applyObjects))
 		a.ApplyObject(d, o, false, false)
 		a.sctx.Increment()
+		a.nsProgress.objectDone(ref)
 		if time.Now().Sub(startTime) >= 10*time.Second || (didLog && i == len(applyObjects)-1) {
 			a.sctx.InfoFallbackf("...applied %d of %d objects", i+1, len(applyObjects))
 			startTime = time.Now()
@@ -718,19 +784,28 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 		}
 	}
 	// Wait for readiness if needed after we have applied all objects
-	for ref, _ := range toWaitReadiness {
-		if a.abortSignal.Load().(bool) {
-			break
-		}
-
-		if !a.o.NoWait {
-			a.WaitReadiness(ref, 0)
+	if len(toWaitReadiness) != 0 && !a.o.NoWait && !a.abortSignal.Load().(bool) {
+		refs := make([]k8s2.ObjectRef, 0, len(toWaitReadiness))
+		for ref := range toWaitReadiness {
+			refs = append(refs, ref)
 		}
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].Less(refs[j])
+		})
+		a.WaitReadinessMulti(refs, 0)
 	}
 	if a.abortSignal.Load().(bool) {
 		return
 	}
 
+	if len(postHooks) != 0 {
+		// post-deploy hooks expect all objects of the deployment item to be applied
+		a.ad.applyAppliedDeferredObjects(a)
+		if a.deferred.hasEntries(a) {
+			a.HandleWarning(k8s2.ObjectRef{}, fmt.Errorf("running post-deploy hooks of %s while some of its custom resources are still deferred until their CRDs get applied", filepath.ToSlash(d.RelToSourceItemDir)))
+		}
+	}
+
 	h.RunHooks(postHooks)
 
 	finalStatus := ""
@@ -786,6 +861,112 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 		return
 	}
 
+	defer a.Close()
+
+	if a.o.ProgressByNamespace {
+		a.nsProgress = newNamespaceProgress(a.ctx)
+		defer a.nsProgress.finish(a)
+	}
+
+	if a.checkTerminatingNamespaces(deployments) {
+		return
+	}
+
+	a.deferred = newDeferredObjects(deployments)
+
+	applied := a.applyDeployments(deployments, nil)
+	a.applyDeferredObjects(a.deferred.take())
+
+	failedInitially := map[*deployment.DeploymentItem]bool{}
+	for d, a2 := range applied {
+		if a2.errorCount != 0 {
+			failedInitially[d] = true
+		}
+	}
+
+	for i := 0; i < a.o.RetryFailedItems; i++ {
+		failed := map[*deployment.DeploymentItem]*ApplyUtil{}
+		for d, a2 := range applied {
+			if a2.errorCount != 0 {
+				failed[d] = a2
+			}
+		}
+		if len(failed) == 0 || a.abortSignal.Load().(bool) {
+			break
+		}
+
+		status.Infof(a.ctx, "Retrying %d failed deployment items (retry %d of %d)", len(failed), i+1, a.o.RetryFailedItems)
+		for d, a2 := range a.applyDeployments(deployments, failed) {
+			applied[d] = a2
+		}
+	}
+
+	if a.o.RetryFailedItems == 0 {
+		return
+	}
+
+	// report the items that failed in the first pass but succeeded in one of the retries
+	var recovered []string
+	for d := range failedInitially {
+		if applied[d].errorCount == 0 {
+			recovered = append(recovered, a.buildItemName(d))
+		}
+	}
+	sort.Strings(recovered)
+	for _, name := range recovered {
+		status.Infof(a.ctx, "Deployment item %s recovered after retrying", name)
+		a.dew.AddWarning(k8s2.ObjectRef{}, fmt.Errorf("deployment item %s failed initially and recovered after retrying", name))
+	}
+}
+
+// checkTerminatingNamespaces adds an error for every object that would be applied into a namespace that is currently
+// being deleted. Applying into such namespaces would otherwise fail late in the run with confusing conflict/forbidden
+// errors. Returns true if any such object was found, in which case nothing should be applied.
+func (a *ApplyDeploymentsUtil) checkTerminatingNamespaces(deployments []*deployment.DeploymentItem) bool {
+	terminating := map[string]bool{}
+	found := false
+	for _, d := range deployments {
+		for _, o := range d.Objects {
+			ref := o.GetK8sRef()
+			if ref.Namespace == "" || o.GetK8sAnnotationBoolNoError("kluctl.io/delete", false) {
+				continue
+			}
+			t, ok := terminating[ref.Namespace]
+			if !ok {
+				ns, err := a.ru.GetRemoteNamespace(a.k, ref.Namespace)
+				if err != nil {
+					// let the actual apply report the error
+					continue
+				}
+				t = ns != nil && ns.GetK8sDeletionTime() != nil
+				terminating[ref.Namespace] = t
+			}
+			if t {
+				a.dew.AddError(ref, fmt.Errorf("namespace %s is being deleted, refusing to apply %s into it", ref.Namespace, ref.String()))
+				found = true
+			}
+		}
+	}
+	if found {
+		status.Error(a.ctx, "Some objects would be applied into namespaces that are being deleted, aborting")
+	}
+	return found
+}
+
+func (a *ApplyDeploymentsUtil) buildItemName(d *deployment.DeploymentItem) string {
+	if name := a.buildProgressName(d); name != nil {
+		return *name
+	}
+	return "<unnamed>"
+}
+
+// applyDeployments performs a single pass over all deployment items. If retry is not nil, only the deployment items
+// found in retry are applied while all others are skipped, with barriers still being respected. The returned map
+// contains the ApplyUtil of each applied deployment item.
+func (a *ApplyDeploymentsUtil) applyDeployments(deployments []*deployment.DeploymentItem, retry map[*deployment.DeploymentItem]*ApplyUtil) map[*deployment.DeploymentItem]*ApplyUtil {
+	ret := map[*deployment.DeploymentItem]*ApplyUtil{}
+	var retMutex sync.Mutex
+
 	var wg sync.WaitGroup
 	sem := semaphore.NewWeighted(8)
 
@@ -805,31 +986,55 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 			break
 		}
 
-		_ = sem.Acquire(context.Background(), 1)
-
-		progressName := a.buildProgressName(d)
-		var sctx *status.StatusContext
-		if progressName != nil {
-			sctx = status.StartWithOptions(a.ctx,
-				status.WithTotal(-1),
-				status.WithPrefix(*progressName),
-				status.WithStatus("Initializing"),
-			)
+		var prev *ApplyUtil
+		skip := false
+		if retry != nil {
+			prev = retry[d]
+			skip = prev == nil
 		}
-		a2 := a.NewApplyUtil(a.ctx, sctx)
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			defer sem.Release(1)
+		if !skip {
+			_ = sem.Acquire(context.Background(), 1)
+
+			ictx := a.ctx
+			if d.RelToSourceItemDir != "" {
+				ictx = status.WithFields(ictx, "item", filepath.ToSlash(d.RelToSourceItemDir))
+			}
+
+			progressName := a.buildProgressName(d)
+			var sctx *status.StatusContext
+			if progressName != nil && a.nsProgress == nil {
+				sctx = status.StartWithOptions(ictx,
+					status.WithTotal(-1),
+					status.WithPrefix(*progressName),
+					status.WithStatus("Initializing"),
+				)
+			}
+			var a2 *ApplyUtil
+			if prev != nil {
+				a2 = a.newRetryApplyUtil(prev, sctx)
+			} else {
+				a2 = a.NewApplyUtil(ictx, sctx)
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer sem.Release(1)
 
-			a2.applyDeploymentItem(d)
+				a2.applyDeploymentItem(d)
 
-			// if success was not signalled, get into failed status
-			sctx.Failed()
-		}()
+				retMutex.Lock()
+				ret[d] = a2
+				retMutex.Unlock()
+
+				// if success was not signalled, get into failed status
+				sctx.Failed()
+			}()
+		}
 
-		barrier := d.Config.Barrier || d.Barrier
+		waitReadinessBarrier := d.Config.WaitReadinessBarrier || d.WaitReadinessBarrier
+		barrier := d.Config.Barrier || d.Barrier || waitReadinessBarrier
 		if barrier {
 			barrierMessage := "Waiting on barrier..."
 			if d.Config.Message != nil {
@@ -839,9 +1044,118 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 			wg.Wait()
 			sctx.UpdateAndInfoFallback(fmt.Sprintf("Finished waiting"))
 			sctx.Success()
+
+			// everything in front of the barrier must be complete when the barrier is passed
+			a.applyAppliedDeferredObjects(nil)
+		}
+		if waitReadinessBarrier {
+			a.waitReadinessOfApplied(d)
 		}
 	}
 	wg.Wait()
+
+	return ret
+}
+
+// newRetryApplyUtil creates a new ApplyUtil that replaces prev, which is the ApplyUtil of a previous failed attempt of
+// the same deployment item. The errors of the previous attempt are forgotten, while the objects applied or deleted
+// by the previous attempt are kept so that these are still part of the result.
+func (ad *ApplyDeploymentsUtil) newRetryApplyUtil(prev *ApplyUtil, statusCtx *status.StatusContext) *ApplyUtil {
+	for _, e := range prev.errors {
+		ad.dew.RemoveError(e)
+	}
+
+	ret := ad.NewApplyUtil(ad.ctx, statusCtx)
+
+	ad.resultsMutex.Lock()
+	defer ad.resultsMutex.Unlock()
+
+	for i, r := range ad.results {
+		if r == prev {
+			ad.results = append(ad.results[:i], ad.results[i+1:]...)
+			break
+		}
+	}
+
+	for ref, o := range prev.newObjects {
+		ret.newObjects[ref] = o
+	}
+	for ref, o := range prev.appliedObjects {
+		ret.appliedObjects[ref] = o
+	}
+	for ref, o := range prev.appliedHookObjects {
+		ret.appliedHookObjects[ref] = o
+	}
+	for ref := range prev.deletedObjects {
+		ret.deletedObjects[ref] = true
+	}
+	for ref := range prev.deletedHookObjects {
+		ret.deletedHookObjects[ref] = true
+	}
+	for x, ref := range prev.generatedRefs {
+		ret.generatedRefs[x] = ref
+	}
+	return ret
+}
+
+// waitReadinessOfApplied waits for all objects applied so far (by all previous deployment items) to become ready. Hooks
+// are not included, as these have their own readiness handling. Objects that got deleted in the meantime (e.g. via
+// hook delete policies) are not included either, as these would never become ready.
+func (a *ApplyDeploymentsUtil) waitReadinessOfApplied(d *deployment.DeploymentItem) {
+	if a.o.NoWait || a.abortSignal.Load().(bool) {
+		return
+	}
+
+	skip := map[k8s2.ObjectRef]bool{}
+	var applied []k8s2.ObjectRef
+	a.resultsMutex.Lock()
+	for _, r := range a.results {
+		r.mutex.Lock()
+		for ref := range r.appliedObjects {
+			applied = append(applied, ref)
+		}
+		for ref := range r.appliedHookObjects {
+			skip[ref] = true
+		}
+		for ref := range r.deletedObjects {
+			skip[ref] = true
+		}
+		for ref := range r.deletedHookObjects {
+			skip[ref] = true
+		}
+		r.mutex.Unlock()
+	}
+	a.resultsMutex.Unlock()
+
+	var refs []k8s2.ObjectRef
+	seen := map[k8s2.ObjectRef]bool{}
+	for _, ref := range applied {
+		if skip[ref] || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Less(refs[j])
+	})
+
+	message := fmt.Sprintf("Waiting for readiness of %d objects", len(refs))
+	if d.Config.Message != nil {
+		message = fmt.Sprintf("%s: %s", message, *d.Config.Message)
+	}
+	sctx := status.StartWithOptions(a.ctx, status.WithStatus(message), status.WithTotal(1))
+	a2 := a.NewApplyUtil(a.ctx, sctx)
+	a2.WaitReadinessMulti(refs, 0)
+	if a2.errorCount != 0 {
+		sctx.Failed()
+		return
+	}
+	sctx.UpdateAndInfoFallback(fmt.Sprintf("All %d objects are ready", len(refs)))
+	sctx.Success()
 }
 
 func (a *ApplyUtil) ReplaceObject(ref k8s2.ObjectRef, firstVersion *uo.UnstructuredObject, callback func(o *uo.UnstructuredObject) (*uo.UnstructuredObject, error)) {
@@ -885,7 +1199,7 @@ func (a *ApplyUtil) ReplaceObject(ref k8s2.ObjectRef, firstVersion *uo.Unstructu
 		a.dew.AddApiWarnings(ref, apiWarnings)
 		if err != nil {
 			if errors.IsConflict(err) {
-				status.Tracef(a.ctx, "Conflict while patching %s. Retrying...", ref.String())
+				status.Tracef(a.refCtx(ref), "Conflict while patching %s. Retrying...", ref.String())
 				continue
 			} else {
 				a.HandleError(ref, err)
@@ -930,6 +1244,17 @@ func (ad *ApplyDeploymentsUtil) GetNewObjectRefs() []k8s2.ObjectRef {
 	})
 }
 
+// GetDryRunObjectRefs returns the refs of all objects that were only applied in dry-run mode due to the
+// kluctl.io/dry-run annotation
+func (ad *ApplyDeploymentsUtil) GetDryRunObjectRefs() []k8s2.ObjectRef {
+	var ret []k8s2.ObjectRef
+	ad.dryRunObjects.Range(func(key, value any) bool {
+		ret = append(ret, key.(k8s2.ObjectRef))
+		return true
+	})
+	return ret
+}
+
 func (ad *ApplyDeploymentsUtil) GetAppliedObjects() []*uo.UnstructuredObject {
 	return ad.collectObjects(func(au *ApplyUtil) map[k8s2.ObjectRef]*uo.UnstructuredObject {
 		return au.appliedObjects
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strings"
+)
+
+// findBreakingCRDChanges compares the remote and the new version of a CRD and returns a list of changes that could
+// cause existing custom resources to become unreadable or lose data.
+func findBreakingCRDChanges(oldCrd *apiextensionsv1.CustomResourceDefinition, newCrd *apiextensionsv1.CustomResourceDefinition) []string {
+	var ret []string
+
+	if oldCrd.Spec.Scope != newCrd.Spec.Scope {
+		ret = append(ret, fmt.Sprintf("scope changed from %s to %s", oldCrd.Spec.Scope, newCrd.Spec.Scope))
+	}
+
+	oldStorage := getCRDStorageVersion(oldCrd)
+	newStorage := getCRDStorageVersion(newCrd)
+	newVersions := map[string]*apiextensionsv1.CustomResourceDefinitionVersion{}
+	for i := range newCrd.Spec.Versions {
+		newVersions[newCrd.Spec.Versions[i].Name] = &newCrd.Spec.Versions[i]
+	}
+
+	if oldStorage != "" && newStorage != "" && oldStorage != newStorage {
+		// changing the storage version is fine as long as the old version is still served, as existing objects can
+		// then still be read and are migrated to the new storage version when written the next time
+		if nv, ok := newVersions[oldStorage]; !ok || !nv.Served {
+			ret = append(ret, fmt.Sprintf("storage version changed from %s to %s", oldStorage, newStorage))
+		}
+	}
+	for i := range oldCrd.Spec.Versions {
+		ov := &oldCrd.Spec.Versions[i]
+		nv, ok := newVersions[ov.Name]
+		if !ok {
+			ret = append(ret, fmt.Sprintf("version %s was removed", ov.Name))
+			continue
+		}
+		if ov.Served && !nv.Served {
+			ret = append(ret, fmt.Sprintf("version %s is not served anymore", ov.Name))
+		}
+		if ov.Schema != nil && nv.Schema != nil {
+			var removed []string
+			findRemovedSchemaFields(ov.Schema.OpenAPIV3Schema, nv.Schema.OpenAPIV3Schema, "", &removed)
+			for _, f := range removed {
+				ret = append(ret, fmt.Sprintf("field %s was removed from version %s", f, ov.Name))
+			}
+		}
+	}
+
+	return ret
+}
+
+func getCRDStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+func findRemovedSchemaFields(oldSchema *apiextensionsv1.JSONSchemaProps, newSchema *apiextensionsv1.JSONSchemaProps, path string, removed *[]string) {
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+	if newSchema.XPreserveUnknownFields != nil && *newSchema.XPreserveUnknownFields {
+		// unknown fields are kept, so nothing gets lost
+		return
+	}
+
+	var keys []string
+	for k := range oldSchema.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := path + "." + k
+		nv, ok := newSchema.Properties[k]
+		if !ok {
+			if newSchema.AdditionalProperties == nil || !newSchema.AdditionalProperties.Allows {
+				*removed = append(*removed, strings.TrimPrefix(p, "."))
+			}
+			continue
+		}
+		ov := oldSchema.Properties[k]
+		findRemovedSchemaFields(&ov, &nv, p, removed)
+	}
+	if oldSchema.Items != nil && newSchema.Items != nil {
+		findRemovedSchemaFields(oldSchema.Items.Schema, newSchema.Items.Schema, path+"[]", removed)
+	}
+}
+
+// checkCRDUpgrade runs the CRD upgrade safety checks. Breaking changes are only treated as errors if custom resources
+// of the CRD exist and breaking changes were not explicitly allowed.
+func (a *ApplyUtil) checkCRDUpgrade(x *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject) error {
+	var oldCrd, newCrd apiextensionsv1.CustomResourceDefinition
+	if err := remoteObject.ToStruct(&oldCrd); err != nil {
+		return err
+	}
+	if err := x.ToStruct(&newCrd); err != nil {
+		return err
+	}
+
+	changes := findBreakingCRDChanges(&oldCrd, &newCrd)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	ref := x.GetK8sRef()
+	msg := strings.Join(changes, ", ")
+
+	if a.o.AllowBreakingCRDChanges {
+		a.HandleWarning(ref, fmt.Errorf("CRD %s contains potentially breaking changes: %s", oldCrd.Name, msg))
+		return nil
+	}
+
+	servedVersion := ""
+	for _, v := range oldCrd.Spec.Versions {
+		if v.Served {
+			servedVersion = v.Name
+			break
+		}
+	}
+	if servedVersion == "" {
+		// nothing can be read anyway
+		return nil
+	}
+	l, _, err := a.k.ListMetadataLimit(schema.GroupVersionKind{
+		Group:   oldCrd.Spec.Group,
+		Version: servedVersion,
+		Kind:    oldCrd.Spec.Names.Kind,
+	}, "", nil, 1)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing custom resources of CRD %s: %w", oldCrd.Name, err)
+	}
+	if len(l) == 0 {
+		a.HandleWarning(ref, fmt.Errorf("CRD %s contains potentially breaking changes, but no custom resources exist: %s", oldCrd.Name, msg))
+		return nil
+	}
+
+	return fmt.Errorf("CRD %s contains potentially breaking changes while custom resources exist: %s. Use --allow-breaking-crd-changes to apply it anyway", oldCrd.Name, msg)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"testing"
+)
+
+func buildTestCRD(scope apiextensionsv1.ResourceScope, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "test.kluctl.io",
+			Scope:    scope,
+			Versions: versions,
+		},
+	}
+}
+
+func buildTestCRDVersion(name string, served bool, storage bool, fields ...string) apiextensionsv1.CustomResourceDefinitionVersion {
+	props := map[string]apiextensionsv1.JSONSchemaProps{}
+	for _, f := range fields {
+		props[f] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+	}
+	return apiextensionsv1.CustomResourceDefinitionVersion{
+		Name:    name,
+		Served:  served,
+		Storage: storage,
+		Schema: &apiextensionsv1.CustomResourceValidation{
+			OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"spec": {
+						Type:       "object",
+						Properties: props,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFindBreakingCRDChanges(t *testing.T) {
+	old := buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", true, true, "a", "b"))
+
+	// identical
+	assert.Empty(t, findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", true, true, "a", "b"))))
+
+	// added fields and versions are not breaking
+	assert.Empty(t, findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped,
+		buildTestCRDVersion("v1", true, true, "a", "b", "c"),
+		buildTestCRDVersion("v2", true, false, "a"))))
+
+	assert.Equal(t, []string{"scope changed from Namespaced to Cluster"},
+		findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.ClusterScoped, buildTestCRDVersion("v1", true, true, "a", "b"))))
+
+	assert.Equal(t, []string{"field spec.b was removed from version v1"},
+		findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", true, true, "a"))))
+
+	assert.Equal(t, []string{"version v1 is not served anymore"},
+		findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", false, true, "a", "b"))))
+
+	// the old storage version is still served, so existing objects can still be read
+	assert.Empty(t, findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped,
+		buildTestCRDVersion("v1", true, false, "a", "b"),
+		buildTestCRDVersion("v2", true, true, "a", "b"))))
+
+	assert.Equal(t, []string{"storage version changed from v1 to v2", "version v1 is not served anymore"},
+		findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped,
+			buildTestCRDVersion("v1", false, false, "a", "b"),
+			buildTestCRDVersion("v2", true, true, "a", "b"))))
+
+	assert.Equal(t, []string{"storage version changed from v1 to v2", "version v1 was removed"},
+		findBreakingCRDChanges(old, buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v2", true, true, "a", "b"))))
+}
+
+func TestFindBreakingCRDChangesPreserveUnknownFields(t *testing.T) {
+	old := buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", true, true, "a", "b"))
+	n := buildTestCRD(apiextensionsv1.NamespaceScoped, buildTestCRDVersion("v1", true, true))
+
+	spec := n.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	b := true
+	spec.XPreserveUnknownFields = &b
+	n.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"] = spec
+	assert.Empty(t, findBreakingCRDChanges(old, n))
+}
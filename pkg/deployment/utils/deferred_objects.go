@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"sync"
+)
+
+// deferredObjects collects objects that failed to apply because their CRD was not known to the API server yet, while
+// the CRD itself is part of the same deployment. This happens when a CRD and its custom resources are applied by
+// different deployment items without a barrier in-between. Deferred objects are retried as soon as their CRDs got
+// applied and established, which is checked at every barrier, before the post-deploy hooks of the deferring
+// deployment item and finally after all deployment items have been applied.
+type deferredObjects struct {
+	mutex sync.Mutex
+
+	// crdRefs maps all kinds that are provided by CRDs of the deployment to the CRD itself
+	crdRefs map[schema.GroupKind]k8s2.ObjectRef
+
+	entries  []*deferredObject
+	refs     map[k8s2.ObjectRef]bool
+	disabled bool
+}
+
+type deferredObject struct {
+	a        *ApplyUtil
+	d        *deployment.DeploymentItem
+	x        *uo.UnstructuredObject
+	replaced bool
+	err      error
+}
+
+func newDeferredObjects(deployments []*deployment.DeploymentItem) *deferredObjects {
+	ret := &deferredObjects{
+		crdRefs: map[schema.GroupKind]k8s2.ObjectRef{},
+		refs:    map[k8s2.ObjectRef]bool{},
+	}
+	for _, d := range deployments {
+		for _, o := range d.Objects {
+			ref := o.GetK8sRef()
+			if ref.GroupKind().String() != "CustomResourceDefinition.apiextensions.k8s.io" {
+				continue
+			}
+			if o.GetK8sAnnotationBoolNoError("kluctl.io/delete", false) {
+				continue
+			}
+			group, _, _ := o.GetNestedString("spec", "group")
+			kind, _, _ := o.GetNestedString("spec", "names", "kind")
+			if group == "" || kind == "" {
+				continue
+			}
+			ret.crdRefs[schema.GroupKind{Group: group, Kind: kind}] = ref
+		}
+	}
+	return ret
+}
+
+// add defers the given object if its kind is provided by a CRD of the deployment. Returns false if the object can not
+// be deferred, in which case the error must be handled by the caller.
+func (do *deferredObjects) add(a *ApplyUtil, d *deployment.DeploymentItem, x *uo.UnstructuredObject, replaced bool, err error) bool {
+	if do == nil {
+		return false
+	}
+
+	do.mutex.Lock()
+	defer do.mutex.Unlock()
+
+	if do.disabled {
+		return false
+	}
+	ref := x.GetK8sRef()
+	if _, ok := do.crdRefs[ref.GroupKind()]; !ok {
+		return false
+	}
+
+	do.entries = append(do.entries, &deferredObject{
+		a:        a,
+		d:        d,
+		x:        x,
+		replaced: replaced,
+		err:      err,
+	})
+	do.refs[ref] = true
+	return true
+}
+
+func (do *deferredObjects) isDeferred(ref k8s2.ObjectRef) bool {
+	if do == nil {
+		return false
+	}
+
+	do.mutex.Lock()
+	defer do.mutex.Unlock()
+	return do.refs[ref]
+}
+
+// takeApplied returns and removes all deferred objects whose CRDs are contained in appliedCRDs. If owner is not nil,
+// only objects deferred by owner are considered.
+func (do *deferredObjects) takeApplied(appliedCRDs map[k8s2.ObjectRef]bool, owner *ApplyUtil) []*deferredObject {
+	if do == nil {
+		return nil
+	}
+
+	do.mutex.Lock()
+	defer do.mutex.Unlock()
+
+	var ret []*deferredObject
+	var keep []*deferredObject
+	for _, e := range do.entries {
+		if (owner == nil || e.a == owner) && appliedCRDs[do.crdRefs[e.x.GetK8sRef().GroupKind()]] {
+			ret = append(ret, e)
+			delete(do.refs, e.x.GetK8sRef())
+		} else {
+			keep = append(keep, e)
+		}
+	}
+	do.entries = keep
+	return ret
+}
+
+// hasEntries returns true if the given ApplyUtil has objects that are still deferred
+func (do *deferredObjects) hasEntries(owner *ApplyUtil) bool {
+	if do == nil {
+		return false
+	}
+
+	do.mutex.Lock()
+	defer do.mutex.Unlock()
+	for _, e := range do.entries {
+		if e.a == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// take returns all deferred objects and disables deferring of further objects, so that retries report their errors
+func (do *deferredObjects) take() []*deferredObject {
+	do.mutex.Lock()
+	defer do.mutex.Unlock()
+
+	ret := do.entries
+	do.entries = nil
+	do.refs = map[k8s2.ObjectRef]bool{}
+	do.disabled = true
+	return ret
+}
+
+// getAppliedCRDs returns the refs of all CRDs that got applied so far
+func (a *ApplyDeploymentsUtil) getAppliedCRDs() map[k8s2.ObjectRef]bool {
+	a.resultsMutex.Lock()
+	defer a.resultsMutex.Unlock()
+
+	ret := map[k8s2.ObjectRef]bool{}
+	for _, r := range a.results {
+		r.mutex.Lock()
+		for ref := range r.appliedObjects {
+			if ref.GroupKind().String() == "CustomResourceDefinition.apiextensions.k8s.io" {
+				ret[ref] = true
+			}
+		}
+		r.mutex.Unlock()
+	}
+	return ret
+}
+
+// applyAppliedDeferredObjects applies all deferred objects whose CRDs got applied already. If owner is not nil, only
+// objects deferred by owner are applied.
+func (a *ApplyDeploymentsUtil) applyAppliedDeferredObjects(owner *ApplyUtil) {
+	if a.deferred == nil {
+		return
+	}
+	a.applyDeferredObjects(a.deferred.takeApplied(a.getAppliedCRDs(), owner))
+}
+
+// applyDeferredObjects waits for all CRDs required by the given deferred objects to get established and then retries
+// to apply the deferred objects.
+func (a *ApplyDeploymentsUtil) applyDeferredObjects(entries []*deferredObject) {
+	if len(entries) == 0 {
+		return
+	}
+
+	if a.abortSignal.Load().(bool) {
+		for _, e := range entries {
+			e.a.HandleError(e.x.GetK8sRef(), e.err)
+		}
+		return
+	}
+
+	crdRefsMap := map[k8s2.ObjectRef]bool{}
+	for _, e := range entries {
+		crdRefsMap[a.deferred.crdRefs[e.x.GetK8sRef().GroupKind()]] = true
+	}
+	var crdRefs []k8s2.ObjectRef
+	for ref := range crdRefsMap {
+		crdRefs = append(crdRefs, ref)
+	}
+	sort.Slice(crdRefs, func(i, j int) bool {
+		return crdRefs[i].Less(crdRefs[j])
+	})
+
+	sctx := status.StartWithOptions(a.ctx,
+		status.WithStatus(fmt.Sprintf("Waiting for %d CRDs to get established", len(crdRefs))),
+		status.WithTotal(len(entries)+1),
+	)
+	a2 := a.NewApplyUtil(a.ctx, sctx)
+	a2.WaitReadinessMulti(crdRefs, 0)
+	sctx.Increment()
+
+	// the API server now knows about the new kinds, so we need fresh discovery
+	a.k.ResetMapper()
+
+	sctx.UpdateAndInfoFallback(fmt.Sprintf("Applying %d objects that had to wait for their CRDs", len(entries)))
+	errorCount := a2.errorCount
+	var waitRefs []k8s2.ObjectRef
+	for _, e := range entries {
+		before := e.a.errorCount
+		e.a.ApplyObject(e.d, e.x, e.replaced, false)
+		errorCount += e.a.errorCount - before
+		sctx.Increment()
+		ref := e.x.GetK8sRef()
+
+		if e.d.Config.WaitReadiness || e.d.WaitReadiness || e.x.GetK8sAnnotationBoolNoError("kluctl.io/wait-readiness", false) {
+			waitRefs = append(waitRefs, ref)
+		}
+	}
+
+	if len(waitRefs) != 0 && !a.o.NoWait && !a.abortSignal.Load().(bool) {
+		sort.Slice(waitRefs, func(i, j int) bool {
+			return waitRefs[i].Less(waitRefs[j])
+		})
+		before := a2.errorCount
+		a2.WaitReadinessMulti(waitRefs, 0)
+		errorCount += a2.errorCount - before
+	}
+
+	if errorCount != 0 {
+		sctx.Failed()
+		return
+	}
+	sctx.UpdateAndInfoFallback(fmt.Sprintf("Applied %d objects that had to wait for their CRDs", len(entries)))
+	sctx.Success()
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildTestDeferredCRD(kind string) *uo.UnstructuredObject {
+	return uo.FromStringMust(fmt.Sprintf(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: %ss.test.kluctl.io
+spec:
+  group: test.kluctl.io
+  names:
+    kind: %s
+`, kind, kind))
+}
+
+func buildTestDeferredCR(kind string, name string) *uo.UnstructuredObject {
+	return uo.FromStringMust(fmt.Sprintf(`
+apiVersion: test.kluctl.io/v1
+kind: %s
+metadata:
+  name: %s
+  namespace: ns
+`, kind, name))
+}
+
+func TestDeferredObjectsTakeApplied(t *testing.T) {
+	crd1 := buildTestDeferredCRD("Kind1")
+	crd2 := buildTestDeferredCRD("Kind2")
+	do := newDeferredObjects([]*deployment.DeploymentItem{
+		{Objects: []*uo.UnstructuredObject{crd1, crd2}},
+	})
+
+	a1 := &ApplyUtil{}
+	a2 := &ApplyUtil{}
+	cr1 := buildTestDeferredCR("Kind1", "cr1")
+	cr2 := buildTestDeferredCR("Kind1", "cr2")
+	cr3 := buildTestDeferredCR("Kind2", "cr3")
+	assert.True(t, do.add(a1, nil, cr1, false, fmt.Errorf("no match")))
+	assert.True(t, do.add(a2, nil, cr2, false, fmt.Errorf("no match")))
+	assert.True(t, do.add(a1, nil, cr3, false, fmt.Errorf("no match")))
+	// kinds not provided by the deployment's CRDs can not be deferred
+	assert.False(t, do.add(a1, nil, buildTestDeferredCR("Kind3", "cr4"), false, fmt.Errorf("no match")))
+
+	// nothing is taken while the CRDs are not applied
+	assert.Empty(t, do.takeApplied(map[k8s2.ObjectRef]bool{}, nil))
+
+	applied := map[k8s2.ObjectRef]bool{crd1.GetK8sRef(): true}
+
+	// only the objects of a1 that wait for crd1
+	l := do.takeApplied(applied, a1)
+	if assert.Len(t, l, 1) {
+		assert.Same(t, cr1, l[0].x)
+	}
+	assert.False(t, do.isDeferred(cr1.GetK8sRef()))
+	assert.True(t, do.isDeferred(cr2.GetK8sRef()))
+	assert.True(t, do.hasEntries(a1))
+	assert.True(t, do.hasEntries(a2))
+
+	// all objects waiting for crd1
+	l = do.takeApplied(applied, nil)
+	if assert.Len(t, l, 1) {
+		assert.Same(t, cr2, l[0].x)
+	}
+	assert.False(t, do.hasEntries(a2))
+
+	// deferring still works after partial takes
+	assert.True(t, do.add(a2, nil, cr2, false, fmt.Errorf("no match")))
+
+	l = do.take()
+	assert.Len(t, l, 2)
+	assert.False(t, do.hasEntries(a1))
+	assert.False(t, do.add(a1, nil, cr1, false, fmt.Errorf("no match")))
+}
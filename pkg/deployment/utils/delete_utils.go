@@ -7,9 +7,11 @@ import (
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sync"
+	"time"
 )
 
 // either names or apigroups
@@ -188,7 +190,128 @@ func FindObjectsForDelete(k *k8s.K8sCluster, allClusterObjects []*uo.Unstructure
 	return ret, nil
 }
 
-func DeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, dew *DeploymentErrorsAndWarnings, doWait bool) []k8s2.ObjectRef {
+type deleteCollectionKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+type deleteCollectionGroup struct {
+	refs []k8s2.ObjectRef
+	// resourceVersion is the resourceVersion of the list that verified the group, so that the deleteCollection request
+	// only deletes objects that existed at that time
+	resourceVersion string
+}
+
+// findDeleteCollectionGroups groups the given refs by GVK and namespace and returns all groups that cover every object
+// with the given discriminator, meaning that a single deleteCollection request with a discriminator label selector
+// will delete exactly these refs. All refs that can not be deleted this way are returned as well.
+func findDeleteCollectionGroups(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, discriminator string) (map[deleteCollectionKey]deleteCollectionGroup, []k8s2.ObjectRef) {
+	if discriminator == "" {
+		return nil, refs
+	}
+
+	groups := make(map[deleteCollectionKey][]k8s2.ObjectRef)
+	var keys []deleteCollectionKey
+	for _, ref := range refs {
+		key := deleteCollectionKey{gvk: ref.GroupVersionKind(), namespace: ref.Namespace}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], ref)
+	}
+
+	g := utils.NewGoHelper(ctx, 8)
+	var mutex sync.Mutex
+	batched := make(map[deleteCollectionKey]deleteCollectionGroup)
+	var singles []k8s2.ObjectRef
+
+	for _, key_ := range keys {
+		key := key_
+		groupRefs := groups[key]
+		if len(groupRefs) < 2 {
+			// nothing to gain from a deleteCollection request
+			singles = append(singles, groupRefs...)
+			continue
+		}
+		g.Run(func() {
+			resourceVersion, ok := isCompleteDeleteCollectionGroup(k, key, groupRefs, discriminator)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if ok {
+				batched[key] = deleteCollectionGroup{refs: groupRefs, resourceVersion: resourceVersion}
+			} else {
+				singles = append(singles, groupRefs...)
+			}
+		})
+	}
+	g.Wait()
+
+	return batched, singles
+}
+
+// isCompleteDeleteCollectionGroup checks if the live objects with the given discriminator match the refs exactly. It
+// also returns the resourceVersion of the list, which must be passed to the deleteCollection request.
+func isCompleteDeleteCollectionGroup(k *k8s.K8sCluster, key deleteCollectionKey, refs []k8s2.ObjectRef, discriminator string) (string, bool) {
+	l, resourceVersion, _, err := k.ListMetadataWithResourceVersion(key.gvk, key.namespace, map[string]string{
+		"kluctl.io/discriminator": discriminator,
+	})
+	if err != nil || resourceVersion == "" {
+		return "", false
+	}
+	if !isSameObjectSet(l, refs) {
+		return "", false
+	}
+	return resourceVersion, true
+}
+
+func isSameObjectSet(l []*uo.UnstructuredObject, refs []k8s2.ObjectRef) bool {
+	if len(l) != len(refs) {
+		return false
+	}
+
+	names := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		names[ref.Name] = true
+	}
+	for _, o := range l {
+		if !names[o.GetK8sName()] {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteObjects deletes all given refs. Namespaces are deleted first and objects inside deleted namespaces are skipped.
+// If a discriminator is given, all objects of the same GVK and namespace are deleted via a single deleteCollection
+// request, as long as this would delete exactly the given objects. Otherwise, objects are deleted one by one.
+func DeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, discriminator string, dew *DeploymentErrorsAndWarnings, doWait bool) []k8s2.ObjectRef {
+	return deleteObjects(ctx, k, refs, discriminator, dew, doWait, doWait)
+}
+
+// DeleteObjectsAndVerifyNamespaces works like DeleteObjects, but does not block on the termination of deleted namespaces.
+// Instead, it waits up to namespaceTimeout for all deleted namespaces to disappear and reports namespaces that are
+// still terminating afterwards, together with the objects and finalizers that block the termination.
+func DeleteObjectsAndVerifyNamespaces(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, discriminator string, dew *DeploymentErrorsAndWarnings, doWait bool, namespaceTimeout time.Duration) []k8s2.ObjectRef {
+	deleted := deleteObjects(ctx, k, refs, discriminator, dew, doWait, false)
+
+	var namespaces []k8s2.ObjectRef
+	for _, ref := range deleted {
+		if isNamespaceRef(ref) {
+			namespaces = append(namespaces, ref)
+		}
+	}
+	if len(namespaces) != 0 && !k.DryRun {
+		verifyNamespaceCleanup(ctx, k, namespaces, namespaceTimeout, dew)
+	}
+	return deleted
+}
+
+func isNamespaceRef(ref k8s2.ObjectRef) bool {
+	return ref.GroupVersion().String() == "v1" && ref.Kind == "Namespace"
+}
+
+func deleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, discriminator string, dew *DeploymentErrorsAndWarnings, doWait bool, waitNamespaces bool) []k8s2.ObjectRef {
 	g := utils.NewGoHelper(ctx, 8)
 
 	var ret []k8s2.ObjectRef
@@ -207,32 +330,63 @@ func DeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef
 		dew.AddApiWarnings(ref, apiWarnings)
 	}
 
+	deleteSingle := func(ref k8s2.ObjectRef) {
+		g.Run(func() {
+			apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !doWait, IgnoreNotFoundError: true})
+			handleResult(ref, apiWarnings, err)
+		})
+	}
+
 	for _, ref_ := range refs {
 		ref := ref_
-		if ref.GroupVersion().String() == "v1" && ref.Kind == "Namespace" {
+		if isNamespaceRef(ref) {
 			namespaceNames[ref.Name] = true
 			g.Run(func() {
-				apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !doWait, IgnoreNotFoundError: true})
+				apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !waitNamespaces, IgnoreNotFoundError: true})
 				handleResult(ref, apiWarnings, err)
 			})
 		}
 	}
 	g.Wait()
 
-	for _, ref_ := range refs {
-		ref := ref_
-		if ref.GroupVersion().String() == "v1" && ref.Kind == "Namespace" {
+	var remaining []k8s2.ObjectRef
+	for _, ref := range refs {
+		if isNamespaceRef(ref) {
 			continue
 		}
 		if _, ok := namespaceNames[ref.Namespace]; ok {
 			// already deleted via namespace
 			continue
 		}
+		remaining = append(remaining, ref)
+	}
+
+	batched, singles := findDeleteCollectionGroups(ctx, k, remaining, discriminator)
+
+	for key_, group_ := range batched {
+		key := key_
+		group := group_
 		g.Run(func() {
-			apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !doWait, IgnoreNotFoundError: true})
-			handleResult(ref, apiWarnings, err)
+			apiWarnings, err := k.DeleteObjectsByLabels(key.gvk, key.namespace, map[string]string{
+				"kluctl.io/discriminator": discriminator,
+			}, group.resourceVersion, group.refs, k8s.DeleteOptions{NoWait: !doWait})
+			if err != nil && (errors.IsMethodNotSupported(err) || errors.IsForbidden(err) ||
+				errors.IsResourceExpired(err) || errors.IsGone(err) || errors.IsBadRequest(err)) {
+				// deleteCollection is not supported or not allowed for this resource or the listed resourceVersion
+				// is not available anymore, fall back to single deletes
+				for _, ref := range group.refs {
+					deleteSingle(ref)
+				}
+				return
+			}
+			for _, ref := range group.refs {
+				handleResult(ref, apiWarnings, err)
+			}
 		})
 	}
+	for _, ref := range singles {
+		deleteSingle(ref)
+	}
 	g.Wait()
 
 	return ret
@@ -22,6 +22,10 @@ type DiffUtil struct {
 	IgnoreKluctlMetadata bool
 	Swapped              bool
 
+	// LargeObjectThreshold is the serialized size above which objects are only diffed per top-level key and via hashes.
+	// Objects can opt out of this via the kluctl.io/force-full-diff annotation.
+	LargeObjectThreshold int
+
 	remoteDiffObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
 	ChangedObjects    []result.ChangedObject
 	mutex             sync.Mutex
@@ -32,6 +36,8 @@ func NewDiffUtil(dew *DeploymentErrorsAndWarnings, ru *RemoteObjectUtils, applie
 		dew:            dew,
 		ru:             ru,
 		appliedObjects: appliedObjects,
+
+		LargeObjectThreshold: diff.DefaultLargeObjectThreshold,
 	}
 	u.calcRemoteObjectsForDiff()
 	return u
@@ -42,7 +48,8 @@ func (u *DiffUtil) DiffDeploymentItems(deployments []*deployment.DeploymentItem)
 
 	for _, d := range deployments {
 		ignoreForDiffs := d.Project.GetIgnoreForDiffs(u.IgnoreTags, u.IgnoreLabels, u.IgnoreAnnotations, u.IgnoreKluctlMetadata)
-		u.diffObjects(d.Objects, ignoreForDiffs, &wg)
+		normalizers := d.Project.GetDiffNormalizers()
+		u.diffObjects(d.Objects, ignoreForDiffs, normalizers, &wg)
 	}
 	wg.Wait()
 
@@ -51,7 +58,7 @@ func (u *DiffUtil) DiffDeploymentItems(deployments []*deployment.DeploymentItem)
 
 func (u *DiffUtil) DiffObjects(objects []*uo.UnstructuredObject) {
 	var wg sync.WaitGroup
-	u.diffObjects(objects, nil, &wg)
+	u.diffObjects(objects, nil, nil, &wg)
 	wg.Wait()
 	u.sortChanges()
 }
@@ -62,7 +69,7 @@ func (u *DiffUtil) sortChanges() {
 	})
 }
 
-func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig, wg *sync.WaitGroup) {
+func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig, normalizers []types.DiffNormalizerConfig, wg *sync.WaitGroup) {
 	for _, o := range objects {
 		o := o
 		ref := o.GetK8sRef()
@@ -77,15 +84,15 @@ func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs
 		go func() {
 			defer wg.Done()
 			if u.Swapped {
-				u.diffObject(o, diffRef, ro, ao, ignoreForDiffs)
+				u.diffObject(o, diffRef, ro, ao, ignoreForDiffs, normalizers)
 			} else {
-				u.diffObject(o, diffRef, ao, ro, ignoreForDiffs)
+				u.diffObject(o, diffRef, ao, ro, ignoreForDiffs, normalizers)
 			}
 		}()
 	}
 }
 
-func (u *DiffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef, ao *uo.UnstructuredObject, ro *uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig) {
+func (u *DiffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef, ao *uo.UnstructuredObject, ro *uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig, normalizers []types.DiffNormalizerConfig) {
 	if ao != nil && ro == nil {
 		// new?
 		return
@@ -96,17 +103,21 @@ func (u *DiffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef,
 		// did not apply? (e.g. in downscale command)
 		return
 	} else {
-		nao, err := diff.NormalizeObject(ao, ignoreForDiffs, lo)
+		nao, err := diff.NormalizeObject(ao, ignoreForDiffs, normalizers, lo)
 		if err != nil {
 			u.dew.AddError(lo.GetK8sRef(), err)
 			return
 		}
-		nro, err := diff.NormalizeObject(ro, ignoreForDiffs, lo)
+		nro, err := diff.NormalizeObject(ro, ignoreForDiffs, normalizers, lo)
 		if err != nil {
 			u.dew.AddError(lo.GetK8sRef(), err)
 			return
 		}
-		changes, err := diff.Diff(nro, nao)
+		threshold := u.LargeObjectThreshold
+		if lo.GetK8sAnnotationBoolNoError("kluctl.io/force-full-diff", false) {
+			threshold = 0
+		}
+		changes, err := diff.DiffWithThreshold(nro, nao, threshold)
 		if err != nil {
 			u.dew.AddError(lo.GetK8sRef(), err)
 			return
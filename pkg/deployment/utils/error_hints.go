@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sort"
+	"strings"
+)
+
+type errorHintMatcher struct {
+	category string
+	hint     string
+	flags    []string
+	match    func(msg string) bool
+}
+
+func containsAll(msg string, substrs ...string) bool {
+	for _, s := range substrs {
+		if !strings.Contains(msg, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// errorHintMatchers is evaluated in order and the first matching entry wins. Messages are lower-cased before matching.
+var errorHintMatchers = []errorHintMatcher{
+	{
+		category: "immutable-field",
+		hint:     "The objects contain changes to fields that can not be changed after creation. Revert these changes or let kluctl delete and re-create the objects, which might cause downtime or data loss.",
+		flags:    []string{"--force-replace-on-error"},
+		match: func(msg string) bool {
+			return strings.Contains(msg, "field is immutable") || containsAll(msg, "updates to", "are forbidden")
+		},
+	},
+	{
+		category: "webhook-denied",
+		hint:     "An admission webhook (e.g. a policy engine) rejected the objects. Check the error messages for the violated policy and fix the objects or the policy.",
+		match: func(msg string) bool {
+			return containsAll(msg, "admission webhook", "denied the request")
+		},
+	},
+	{
+		category: "quota-exceeded",
+		hint:     "A ResourceQuota does not allow the requested resources. Lower the resource requests/limits of the objects or raise the quota of the namespace.",
+		match: func(msg string) bool {
+			return strings.Contains(msg, "exceeded quota")
+		},
+	},
+	{
+		category: "unknown-field",
+		hint:     "The objects contain fields that are unknown to the API server. Check for typos and ensure that the apiVersion matches the installed Kubernetes version or CRD.",
+		match: func(msg string) bool {
+			return strings.Contains(msg, "unknown field") || strings.Contains(msg, "field not declared in schema")
+		},
+	},
+	{
+		category: "forbidden",
+		hint:     "The user used by kluctl lacks the RBAC permissions for these objects. Check the permissions via 'kubectl auth can-i' or use a different kubeconfig/context.",
+		flags:    []string{"--kubeconfig", "--context"},
+		match: func(msg string) bool {
+			return containsAll(msg, "is forbidden", "cannot")
+		},
+	},
+}
+
+// BuildErrorHints groups all errors that match a known category of common API errors and returns a remediation hint
+// for each category, together with the affected objects and the flags that might help.
+func BuildErrorHints(errors []result.DeploymentError) []result.ErrorHint {
+	refs := map[string]map[k8s.ObjectRef]bool{}
+	for _, e := range errors {
+		msg := strings.ToLower(e.Message)
+		for _, m := range errorHintMatchers {
+			if !m.match(msg) {
+				continue
+			}
+			if _, ok := refs[m.category]; !ok {
+				refs[m.category] = map[k8s.ObjectRef]bool{}
+			}
+			refs[m.category][e.Ref] = true
+			break
+		}
+	}
+
+	var ret []result.ErrorHint
+	for _, m := range errorHintMatchers {
+		r, ok := refs[m.category]
+		if !ok {
+			continue
+		}
+		h := result.ErrorHint{
+			Category: m.category,
+			Hint:     m.hint,
+			Flags:    m.flags,
+		}
+		for ref := range r {
+			if ref != (k8s.ObjectRef{}) {
+				h.Refs = append(h.Refs, ref)
+			}
+		}
+		sort.Slice(h.Refs, func(i, j int) bool {
+			return h.Refs[i].Less(h.Refs[j])
+		})
+		ret = append(ret, h)
+	}
+	return ret
+}
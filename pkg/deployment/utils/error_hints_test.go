@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildErrorHints(t *testing.T) {
+	cm1 := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm1", Namespace: "ns"}
+	cm2 := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm2", Namespace: "ns"}
+	sts := k8s.ObjectRef{Group: "apps", Version: "v1", Kind: "StatefulSet", Name: "sts", Namespace: "ns"}
+	pod := k8s.ObjectRef{Version: "v1", Kind: "Pod", Name: "pod", Namespace: "ns"}
+
+	errors := []result.DeploymentError{
+		{Ref: cm2, Message: `failed to patch ns/ConfigMap/cm2: ConfigMap "cm2" is invalid: data: Forbidden: field is immutable when ` + "`immutable`" + ` is set`},
+		{Ref: sts, Message: `StatefulSet.apps "sts" is invalid: spec: Forbidden: updates to statefulset spec for fields other than 'replicas', 'template' and 'updateStrategy' are forbidden`},
+		{Ref: cm1, Message: `configmaps "cm1" is forbidden: User "test" cannot patch resource "configmaps" in API group "" in the namespace "ns"`},
+		{Ref: pod, Message: `pods "pod" is forbidden: exceeded quota: compute-resources, requested: limits.cpu=2, used: limits.cpu=0, limited: limits.cpu=1`},
+		{Ref: cm1, Message: `admission webhook "validate.kyverno.svc" denied the request: policy violated`},
+		{Ref: cm1, Message: `failed to patch: .data.x: unknown field "x"`},
+		{Message: `some unrelated error`},
+	}
+
+	hints := BuildErrorHints(errors)
+
+	var categories []string
+	for _, h := range hints {
+		categories = append(categories, h.Category)
+	}
+	assert.Equal(t, []string{"immutable-field", "webhook-denied", "quota-exceeded", "unknown-field", "forbidden"}, categories)
+
+	assert.Equal(t, []k8s.ObjectRef{cm2, sts}, hints[0].Refs)
+	assert.Equal(t, []string{"--force-replace-on-error"}, hints[0].Flags)
+	assert.Equal(t, []k8s.ObjectRef{pod}, hints[2].Refs)
+	assert.Equal(t, []k8s.ObjectRef{cm1}, hints[4].Refs)
+}
+
+func TestBuildErrorHintsNoMatch(t *testing.T) {
+	hints := BuildErrorHints([]result.DeploymentError{
+		{Message: "some unrelated error"},
+	})
+	assert.Empty(t, hints)
+}
@@ -74,6 +74,19 @@ func (dew *DeploymentErrorsAndWarnings) AddError(ref k8s.ObjectRef, err error) {
 	m[de] = true
 }
 
+func (dew *DeploymentErrorsAndWarnings) RemoveError(de result.DeploymentError) {
+	dew.mutex.Lock()
+	defer dew.mutex.Unlock()
+	m, ok := dew.errors[de.Ref]
+	if !ok {
+		return
+	}
+	delete(m, de)
+	if len(m) == 0 {
+		delete(dew.errors, de.Ref)
+	}
+}
+
 func (dew *DeploymentErrorsAndWarnings) AddApiWarnings(ref k8s.ObjectRef, warnings []k8s2.ApiWarning) {
 	for _, w := range warnings {
 		dew.AddWarning(ref, fmt.Errorf(w.Text))
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+)
+
+// isGenerateNameObject returns true if the object has no name but relies on the API server to generate one
+func isGenerateNameObject(x *uo.UnstructuredObject) bool {
+	return x.GetK8sName() == "" && x.GetK8sGenerateName() != ""
+}
+
+// GetActualRef returns the ref of the object that was actually created for x. This only differs from the ref of x
+// if x uses metadata.generateName, in which case the returned ref contains the generated name. If x was not created
+// (yet), the ref of x is returned.
+func (a *ApplyUtil) GetActualRef(x *uo.UnstructuredObject) k8s2.ObjectRef {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if ref, ok := a.generatedRefs[x]; ok {
+		return ref
+	}
+	return x.GetK8sRef()
+}
+
+// generateNameIdLabel is set on all hook instances created from the same hook with metadata.generateName, so that
+// instances of previous executions can be found and deleted
+const generateNameIdLabel = "kluctl.io/generate-name-id"
+
+// getGenerateNameId returns a stable identifier for a hook with metadata.generateName, which is the same for all
+// executions of the hook
+func getGenerateNameId(x *uo.UnstructuredObject) string {
+	ref := x.GetK8sRef()
+	var itemDir, discriminator string
+	if s := x.GetK8sAnnotation("kluctl.io/deployment-item-dir"); s != nil {
+		itemDir = *s
+	}
+	if s := x.GetK8sLabel("kluctl.io/discriminator"); s != nil {
+		discriminator = *s
+	}
+	id := fmt.Sprintf("%s/%s/%s/%s/%s/%s", discriminator, itemDir, ref.Group, ref.Kind, ref.Namespace, x.GetK8sGenerateName())
+	// label values are limited to 63 characters
+	return utils.Sha256String(id)[:32]
+}
+
+// deletePreviousGeneratedHooks deletes all instances of the given hook that were created by previous executions. This
+// implements the before-hook-creation delete policy for hooks with metadata.generateName.
+func (a *ApplyUtil) deletePreviousGeneratedHooks(x *uo.UnstructuredObject) bool {
+	ref := x.GetK8sRef()
+	l, apiWarnings, err := a.k.ListMetadata(ref.GroupVersionKind(), ref.Namespace, map[string]string{
+		generateNameIdLabel: getGenerateNameId(x),
+	})
+	a.handleApiWarnings(ref, apiWarnings)
+	if err != nil {
+		a.HandleError(ref, fmt.Errorf("failed to list previous instances of hook with generateName '%s': %w", x.GetK8sGenerateName(), err))
+		return false
+	}
+	ok := true
+	for _, o := range l {
+		if !a.deleteObject(o.GetK8sRef(), true, a.isDryRun(x)) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// createGeneratedHook creates a hook that uses metadata.generateName. Such objects can not be applied via server-side
+// apply, so they are created via POST instead, resulting in a new object with a new name on every execution. The
+// created object is labeled so that it can be cleaned up by the next execution.
+func (a *ApplyUtil) createGeneratedHook(x *uo.UnstructuredObject) {
+	ref := x.GetK8sRef()
+
+	dryRun := a.isDryRun(x)
+
+	x2 := x.Clone()
+	x2.SetK8sLabel(generateNameIdLabel, getGenerateNameId(x))
+	if dryRun && ref.Namespace != "" {
+		remoteNamespace, err := a.ru.GetRemoteNamespace(a.k, ref.Namespace)
+		if err != nil {
+			a.HandleError(ref, err)
+			return
+		}
+		if _, ok := a.allNamespaces.Load(ref.Namespace); ok && remoteNamespace == nil {
+			// The namespace does not really exist, but would have been created if dryRun would be false.
+			x2.SetK8sNamespace("default")
+		}
+	}
+
+	r, apiWarnings, err := a.k.CreateObject(x2, k8s.CreateOptions{ForceDryRun: dryRun})
+	a.handleApiWarnings(ref, apiWarnings)
+	if err != nil {
+		a.HandleError(ref, fmt.Errorf("failed to create hook with generateName '%s': %w", x.GetK8sGenerateName(), err))
+		return
+	}
+	r.SetK8sNamespace(ref.Namespace)
+
+	actualRef := r.GetK8sRef()
+	status.Tracef(a.refCtx(actualRef), "created %s from generateName '%s'", actualRef.String(), x.GetK8sGenerateName())
+	if dryRun && !a.o.DryRun {
+		a.dryRunObjects.Store(actualRef, true)
+	}
+
+	a.mutex.Lock()
+	a.generatedRefs[x] = actualRef
+	a.mutex.Unlock()
+
+	a.handleResult(r, true)
+}
+
+// GetGeneratedObjects returns all objects that were created from objects with metadata.generateName, mapped from the
+// actual ref (including the generated name) to the rendered object.
+func (ad *ApplyDeploymentsUtil) GetGeneratedObjects() map[k8s2.ObjectRef]*uo.UnstructuredObject {
+	ad.resultsMutex.Lock()
+	defer ad.resultsMutex.Unlock()
+
+	ret := map[k8s2.ObjectRef]*uo.UnstructuredObject{}
+	for _, a := range ad.results {
+		a.mutex.Lock()
+		for x, ref := range a.generatedRefs {
+			ret[ref] = x
+		}
+		a.mutex.Unlock()
+	}
+	return ret
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"strings"
+)
+
+// hookPermission is a single entry of the kluctl.io/hook-permissions annotation
+type hookPermission struct {
+	verbs    []string
+	group    string
+	resource string
+}
+
+// parseHookPermissions parses the value of the kluctl.io/hook-permissions annotation, which is a semicolon separated
+// list of '<verbs>:<resource>[.<group>]' entries, e.g. 'get,list:configmaps;create:jobs.batch'.
+func parseHookPermissions(s string) ([]hookPermission, error) {
+	var ret []hookPermission
+	for _, e := range strings.Split(s, ";") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		verbsStr, resource, ok := strings.Cut(e, ":")
+		resource = strings.TrimSpace(resource)
+		if !ok || resource == "" {
+			return nil, fmt.Errorf("invalid hook permission '%s', expected '<verbs>:<resource>[.<group>]'", e)
+		}
+
+		var p hookPermission
+		for _, v := range strings.Split(verbsStr, ",") {
+			v = strings.TrimSpace(v)
+			if v != "" {
+				p.verbs = append(p.verbs, v)
+			}
+		}
+		if len(p.verbs) == 0 {
+			return nil, fmt.Errorf("invalid hook permission '%s', no verbs specified", e)
+		}
+		p.resource, p.group, _ = strings.Cut(resource, ".")
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// getHookPodSpecPath returns the path to the pod spec of hook objects that create pods, or nil if the object does not
+// create pods.
+func getHookPodSpecPath(ref k8s2.ObjectRef) []any {
+	switch {
+	case ref.Group == "" && ref.Kind == "Pod":
+		return []any{"spec"}
+	case ref.Group == "batch" && ref.Kind == "Job":
+		return []any{"spec", "template", "spec"}
+	case ref.Group == "batch" && ref.Kind == "CronJob":
+		return []any{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+}
+
+// getHookServiceAccountName returns the service account used by the pods of the given hook object. An empty string is
+// returned if the object does not create pods or does not explicitly specify a service account.
+func getHookServiceAccountName(o *uo.UnstructuredObject) string {
+	podSpecPath := getHookPodSpecPath(o.GetK8sRef())
+	if podSpecPath == nil {
+		return ""
+	}
+	sa, _, _ := o.GetNestedString(append(podSpecPath, "serviceAccountName")...)
+	return sa
+}
+
+// verifyHookServiceAccount verifies that the service account used by the hook exists and that it has all permissions
+// declared via the kluctl.io/hook-permissions annotation. Errors are reported for the hook and false is returned if
+// the hook must not be applied. Permissions are verified via SubjectAccessReviews, which require kluctl itself to be
+// allowed to create these. If this is not the case, a warning is reported and the hook is applied anyway.
+func (u *HooksUtil) verifyHookServiceAccount(h *hook) bool {
+	ref := h.object.GetK8sRef()
+
+	var permissions []hookPermission
+	if s := h.object.GetK8sAnnotation("kluctl.io/hook-permissions"); s != nil {
+		var err error
+		permissions, err = parseHookPermissions(*s)
+		if err != nil {
+			u.a.HandleError(ref, err)
+			return false
+		}
+	}
+
+	saName := getHookServiceAccountName(h.object)
+	if saName == "" {
+		if len(permissions) == 0 {
+			return true
+		}
+		saName = "default"
+	}
+
+	saRef := k8s2.ObjectRef{Version: "v1", Kind: "ServiceAccount", Name: saName, Namespace: ref.Namespace}
+	_, _, err := u.a.k.GetSingleObjectMetadata(saRef)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			u.a.HandleWarning(ref, fmt.Errorf("failed to verify existence of service account %s: %w", saName, err))
+			return true
+		}
+		if u.a.isDryRun(h.object) {
+			// the service account might have been created if dryRun would be false
+			return true
+		}
+		u.a.HandleError(ref, fmt.Errorf("service account %s used by hook does not exist", saName))
+		return false
+	}
+
+	if u.a.isDryRun(h.object) {
+		return true
+	}
+
+	var denied []string
+	for _, p := range permissions {
+		for _, verb := range p.verbs {
+			allowed, err := u.checkServiceAccountAccess(saRef, ref.Namespace, verb, p.group, p.resource)
+			if err != nil {
+				u.a.HandleWarning(ref, fmt.Errorf("failed to verify permissions of service account %s: %w", saName, err))
+				return true
+			}
+			if !allowed {
+				r := p.resource
+				if p.group != "" {
+					r += "." + p.group
+				}
+				denied = append(denied, fmt.Sprintf("%s %s", verb, r))
+			}
+		}
+	}
+	if len(denied) != 0 {
+		u.a.HandleError(ref, fmt.Errorf("service account %s used by hook lacks the declared permissions: %s", saName, strings.Join(denied, ", ")))
+		return false
+	}
+	return true
+}
+
+func (u *HooksUtil) checkServiceAccountAccess(saRef k8s2.ObjectRef, namespace string, verb string, group string, resource string) (bool, error) {
+	sar := uo.FromMap(map[string]any{
+		"apiVersion": "authorization.k8s.io/v1",
+		"kind":       "SubjectAccessReview",
+		"spec": map[string]any{
+			"user":   fmt.Sprintf("system:serviceaccount:%s:%s", saRef.Namespace, saRef.Name),
+			"groups": []any{"system:serviceaccounts", fmt.Sprintf("system:serviceaccounts:%s", saRef.Namespace)},
+			"resourceAttributes": map[string]any{
+				"namespace": namespace,
+				"verb":      verb,
+				"group":     group,
+				"resource":  resource,
+			},
+		},
+	})
+
+	// SubjectAccessReviews are never persisted, so it's safe to always use a read-write client here
+	r, _, err := u.a.k.ReadWrite().CreateObject(sar, k8s.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	allowed, _, _ := r.GetNestedBool("status", "allowed")
+	return allowed, nil
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseHookPermissions(t *testing.T) {
+	p, err := parseHookPermissions("get, list:configmaps; create:jobs.batch;")
+	assert.NoError(t, err)
+	assert.Equal(t, []hookPermission{
+		{verbs: []string{"get", "list"}, resource: "configmaps"},
+		{verbs: []string{"create"}, group: "batch", resource: "jobs"},
+	}, p)
+
+	_, err = parseHookPermissions("configmaps")
+	assert.ErrorContains(t, err, "expected '<verbs>:<resource>[.<group>]'")
+	_, err = parseHookPermissions(" :configmaps")
+	assert.ErrorContains(t, err, "no verbs specified")
+}
+
+func TestGetHookServiceAccountName(t *testing.T) {
+	job := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  template:
+    spec:
+      serviceAccountName: sa
+`)
+	cronJob := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cj
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          serviceAccountName: sa2
+`)
+	pod := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers: []
+`)
+	cm := uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+	assert.Equal(t, "sa", getHookServiceAccountName(job))
+	assert.Equal(t, "sa2", getHookServiceAccountName(cronJob))
+	assert.Equal(t, "", getHookServiceAccountName(pod))
+	assert.Equal(t, "", getHookServiceAccountName(cm))
+}
@@ -0,0 +1,260 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/lib/yaml"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sort"
+	"strings"
+)
+
+// hookResultVolumeName is the name of the volume that is added to the pod spec of hooks which request the run result
+// via the kluctl.io/hook-result-mount-path annotation
+const hookResultVolumeName = "kluctl-hook-result"
+
+// hookResultAnnotation marks ConfigMaps created to hold hook results and references the hook
+const hookResultAnnotation = "kluctl.io/hook-result-of"
+
+// hookResultFileName is the key inside the result ConfigMap and thus the name of the mounted file
+const hookResultFileName = "result.yaml"
+
+// hookRunResult is the summary of the current run that is made available to post-deploy hooks
+type hookRunResult struct {
+	// AppliedObjects contains all objects applied so far, including unchanged objects
+	AppliedObjects []k8s2.ObjectRef `json:"appliedObjects"`
+	// NewObjects contains all applied objects that did not exist before
+	NewObjects []k8s2.ObjectRef `json:"newObjects"`
+	// ChangedObjects contains all applied objects that were created or modified
+	ChangedObjects []k8s2.ObjectRef         `json:"changedObjects"`
+	DeletedObjects []k8s2.ObjectRef         `json:"deletedObjects"`
+	Errors         []result.DeploymentError `json:"errors"`
+}
+
+// buildHookRunResult collects the objects applied and deleted by all deployment items up to this point. Hooks are not
+// included.
+func (ad *ApplyDeploymentsUtil) buildHookRunResult() *hookRunResult {
+	ad.resultsMutex.Lock()
+	defer ad.resultsMutex.Unlock()
+
+	applied := map[k8s2.ObjectRef]bool{}
+	newObjects := map[k8s2.ObjectRef]bool{}
+	changed := map[k8s2.ObjectRef]bool{}
+	deleted := map[k8s2.ObjectRef]bool{}
+	for _, a := range ad.results {
+		a.mutex.Lock()
+		for ref, o := range a.appliedObjects {
+			if _, ok := a.appliedHookObjects[ref]; ok {
+				continue
+			}
+			applied[ref] = true
+			remoteObject := a.ru.GetRemoteObject(ref)
+			if remoteObject == nil {
+				newObjects[ref] = true
+				changed[ref] = true
+			} else if remoteObject.GetK8sResourceVersion() != o.GetK8sResourceVersion() {
+				changed[ref] = true
+			}
+		}
+		for ref := range a.deletedObjects {
+			deleted[ref] = true
+		}
+		a.mutex.Unlock()
+	}
+
+	errs := ad.dew.GetErrorsList()
+	if errs == nil {
+		errs = []result.DeploymentError{}
+	}
+
+	return &hookRunResult{
+		AppliedObjects: sortedRefs(applied),
+		NewObjects:     sortedRefs(newObjects),
+		ChangedObjects: sortedRefs(changed),
+		DeletedObjects: sortedRefs(deleted),
+		Errors:         errs,
+	}
+}
+
+func sortedRefs(m map[k8s2.ObjectRef]bool) []k8s2.ObjectRef {
+	ret := make([]k8s2.ObjectRef, 0, len(m))
+	for ref := range m {
+		ret = append(ret, ref)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret
+}
+
+// getHookResultConfigMapRef returns the ref of the ConfigMap that holds the run result for the given hook. Hooks that
+// use metadata.generateName share the same ConfigMap.
+func getHookResultConfigMapRef(o *uo.UnstructuredObject) k8s2.ObjectRef {
+	name := o.GetK8sName()
+	if name == "" {
+		name = strings.TrimSuffix(o.GetK8sGenerateName(), "-")
+	}
+	return k8s2.ObjectRef{
+		Version:   "v1",
+		Kind:      "ConfigMap",
+		Name:      name + "-result",
+		Namespace: o.GetK8sNamespace(),
+	}
+}
+
+// mountHookResult adds a volume referencing the ConfigMap with the given name to the pod spec of the hook object and
+// mounts it into all containers at mountPath. Existing volumes and mounts with the same name are replaced, so that
+// calling this multiple times (e.g. on retries) has no additional effect.
+func mountHookResult(o *uo.UnstructuredObject, configMapName string, mountPath string) error {
+	podSpecPath := getHookPodSpecPath(o.GetK8sRef())
+	if podSpecPath == nil {
+		return fmt.Errorf("the kluctl.io/hook-result-mount-path annotation is only supported on Pods, Jobs and CronJobs")
+	}
+	podSpec, ok, err := o.GetNestedObject(podSpecPath...)
+	if err != nil {
+		return err
+	}
+	if !ok || podSpec == nil {
+		return fmt.Errorf("pod spec not found")
+	}
+
+	// podSpec and the containers share their maps with the original object, so all modifications are done in-place
+	volume := uo.FromMap(map[string]any{
+		"name": hookResultVolumeName,
+		"configMap": map[string]any{
+			"name": configMapName,
+		},
+	})
+	err = setNamedListItem(podSpec, volume, "volumes")
+	if err != nil {
+		return err
+	}
+
+	volumeMount := uo.FromMap(map[string]any{
+		"name":      hookResultVolumeName,
+		"mountPath": mountPath,
+		"readOnly":  true,
+	})
+	for _, containersKey := range []string{"initContainers", "containers"} {
+		containers, _, err := podSpec.GetNestedObjectList(containersKey)
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			err = setNamedListItem(c, volumeMount, "volumeMounts")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setNamedListItem replaces the list item with the same name as item or appends item if no such list item exists
+func setNamedListItem(o *uo.UnstructuredObject, item *uo.UnstructuredObject, key string) error {
+	l, _, err := o.GetNestedObjectList(key)
+	if err != nil {
+		return err
+	}
+	name, _, _ := item.GetNestedString("name")
+	found := false
+	for i, x := range l {
+		if n, _, _ := x.GetNestedString("name"); n == name {
+			l[i] = item
+			found = true
+		}
+	}
+	if !found {
+		l = append(l, item)
+	}
+	return o.SetNestedObjectList(l, key)
+}
+
+// prepareHookResult applies the ConfigMap containing the result of the current run and mounts it into the hook, if the
+// hook requested this via the kluctl.io/hook-result-mount-path annotation. Errors are reported for the hook and false
+// is returned if the hook must not be applied.
+func (u *HooksUtil) prepareHookResult(h *hook) bool {
+	mountPath := h.object.GetK8sAnnotation("kluctl.io/hook-result-mount-path")
+	if mountPath == nil {
+		return true
+	}
+	ref := h.object.GetK8sRef()
+
+	if !h.hooks["post-deploy"] && !h.hooks["post-deploy-initial"] && !h.hooks["post-deploy-upgrade"] {
+		u.a.HandleError(ref, fmt.Errorf("the kluctl.io/hook-result-mount-path annotation is only supported on post-deploy hooks"))
+		return false
+	}
+
+	cmRef := getHookResultConfigMapRef(h.object)
+	err := mountHookResult(h.object, cmRef.Name, *mountPath)
+	if err != nil {
+		u.a.HandleError(ref, err)
+		return false
+	}
+
+	r, err := yaml.WriteYamlString(u.a.ad.buildHookRunResult())
+	if err != nil {
+		u.a.HandleError(ref, err)
+		return false
+	}
+
+	err = u.checkHookResultOwnership(cmRef)
+	if err != nil {
+		u.a.HandleError(ref, err)
+		return false
+	}
+
+	cm := uo.New()
+	cm.SetK8sGVK(cmRef.GroupVersionKind())
+	cm.SetK8sName(cmRef.Name)
+	cm.SetK8sNamespace(cmRef.Namespace)
+	// the ConfigMap belongs to the same deployment item as the hook, so that it is handled by prune and delete
+	for n, v := range h.object.GetK8sLabels() {
+		if strings.HasPrefix(n, "kluctl.io/") {
+			cm.SetK8sLabel(n, v)
+		}
+	}
+	if a := h.object.GetK8sAnnotation("kluctl.io/deployment-item-dir"); a != nil {
+		cm.SetK8sAnnotation("kluctl.io/deployment-item-dir", *a)
+	}
+	cm.SetK8sAnnotation(hookResultAnnotation, h.object.GetK8sRef().String())
+	if a := h.object.GetK8sAnnotation("kluctl.io/dry-run"); a != nil {
+		cm.SetK8sAnnotation("kluctl.io/dry-run", *a)
+	}
+	_ = cm.SetNestedField(map[string]any{
+		hookResultFileName: r,
+	}, "data")
+
+	u.a.ApplyObject(h.di, cm, false, true)
+	if u.a.HadError(cmRef) {
+		u.a.HandleError(ref, fmt.Errorf("failed to apply result ConfigMap %s", cmRef.String()))
+		return false
+	}
+	return true
+}
+
+// checkHookResultOwnership ensures that an already existing ConfigMap with the given ref was created by kluctl to hold
+// hook results, so that user owned ConfigMaps are never overwritten
+func (u *HooksUtil) checkHookResultOwnership(cmRef k8s2.ObjectRef) error {
+	o, _, err := u.a.k.GetSingleObjectMetadata(cmRef)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if o.GetK8sAnnotation(hookResultAnnotation) == nil {
+		return fmt.Errorf("ConfigMap %s already exists and does not hold kluctl hook results, refusing to overwrite it", cmRef.String())
+	}
+	return nil
+}
+
+// deleteHookResult deletes the result ConfigMap of the given hook, if the hook requested the run result
+func (u *HooksUtil) deleteHookResult(h *hook) {
+	if h.object.GetK8sAnnotation("kluctl.io/hook-result-mount-path") == nil {
+		return
+	}
+	u.a.DeleteObject(getHookResultConfigMapRef(h.object), true)
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMountHookResult(t *testing.T) {
+	job := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+  namespace: ns
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: c1
+        image: busybox
+        volumeMounts:
+        - name: other
+          mountPath: /other
+      - name: c2
+        image: busybox
+      volumes:
+      - name: other
+        emptyDir: {}
+`)
+	expected := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+  namespace: ns
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+        volumeMounts:
+        - name: kluctl-hook-result
+          mountPath: /result
+          readOnly: true
+      containers:
+      - name: c1
+        image: busybox
+        volumeMounts:
+        - name: other
+          mountPath: /other
+        - name: kluctl-hook-result
+          mountPath: /result
+          readOnly: true
+      - name: c2
+        image: busybox
+        volumeMounts:
+        - name: kluctl-hook-result
+          mountPath: /result
+          readOnly: true
+      volumes:
+      - name: other
+        emptyDir: {}
+      - name: kluctl-hook-result
+        configMap:
+          name: job-result
+`)
+
+	cmRef := getHookResultConfigMapRef(job)
+	assert.Equal(t, k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "job-result", Namespace: "ns"}, cmRef)
+
+	assert.NoError(t, mountHookResult(job, cmRef.Name, "/result"))
+	assert.Equal(t, expected, job)
+
+	// mounting twice must not add duplicate volumes or mounts
+	assert.NoError(t, mountHookResult(job, cmRef.Name, "/result"))
+	assert.Equal(t, expected, job)
+
+	cm := uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+	assert.ErrorContains(t, mountHookResult(cm, "cm-result", "/result"), "only supported on Pods, Jobs and CronJobs")
+}
+
+func TestGetHookResultConfigMapRefGenerateName(t *testing.T) {
+	pod := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  generateName: migrate-
+  namespace: ns
+`)
+	assert.Equal(t, "migrate-result", getHookResultConfigMapRef(pod).Name)
+}
@@ -79,19 +79,27 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 	}
 
 	doDeleteForPolicy := func(h *hook, i int, cnt int) bool {
-		ref := h.object.GetK8sRef()
+		ref := u.a.GetActualRef(h.object)
 		var dpStr []string
 		for p := range h.deletePolicies {
 			dpStr = append(dpStr, p)
 		}
 		u.a.sctx.UpdateAndInfoFallbackf("Deleting hook %s due to hook-delete-policy %s (%d of %d)", ref.String(), strings.Join(dpStr, ","), i+1, cnt)
-		return u.a.DeleteObject(ref, true)
+		// hooks with the kluctl.io/dry-run annotation must only be deleted in dry-run mode as well
+		return u.a.deleteObject(ref, true, u.a.isDryRun(h.object))
 	}
 
 	if len(deleteBeforeObjects) != 0 {
 		u.a.sctx.InfoFallbackf("Deleting %d hooks before hook execution", len(deleteBeforeObjects))
 	}
 	for i, h := range deleteBeforeObjects {
+		if isGenerateNameObject(h.object) {
+			// hooks with generateName get a new name on every execution, so the instances of previous executions
+			// are found via their labels
+			u.a.sctx.UpdateAndInfoFallbackf("Deleting previous instances of hook %s due to hook-delete-policy before-hook-creation (%d of %d)", h.object.GetK8sRef().String(), i+1, len(deleteBeforeObjects))
+			u.a.deletePreviousGeneratedHooks(h.object)
+			continue
+		}
 		doDeleteForPolicy(h, i, len(deleteBeforeObjects))
 	}
 
@@ -103,9 +111,15 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 		ref := h.object.GetK8sRef()
 		_, replaced := h.deletePolicies["before-hook-creation"]
 		u.a.sctx.UpdateAndInfoFallbackf("Applying hook %s (%d of %d)", ref.String(), i+1, len(applyObjects))
+		if !u.verifyHookServiceAccount(h) || !u.prepareHookResult(h) {
+			u.a.sctx.Increment()
+			continue
+		}
 		u.a.ApplyObject(h.di, h.object, replaced, true)
 		u.a.sctx.Increment()
 
+		ref = u.a.GetActualRef(h.object)
+
 		if u.a.HadError(ref) {
 			continue
 		}
@@ -118,7 +132,7 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 	var deleteAfterObjects []*hook
 	for i := len(applyObjects) - 1; i >= 0; i-- {
 		h := applyObjects[i]
-		ref := h.object.GetK8sRef()
+		ref := u.a.GetActualRef(h.object)
 		waitResult, ok := waitResults[ref]
 		if !ok {
 			continue
@@ -139,10 +153,21 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 	}
 	for i, h := range deleteAfterObjects {
 		doDeleteForPolicy(h, i, len(deleteAfterObjects))
+		u.deleteHookResult(h)
 	}
 }
 
 func (u *HooksUtil) GetHook(di *deployment.DeploymentItem, o *uo.UnstructuredObject) *hook {
+	return parseHook(di, o, u.a.HandleError, u.a.HandleWarning)
+}
+
+// ParseHook parses the hook annotations of the given object without the need for an ApplyUtil. Invalid annotations are
+// reported to dew. It returns nil if the object is not a hook.
+func ParseHook(di *deployment.DeploymentItem, o *uo.UnstructuredObject, dew *DeploymentErrorsAndWarnings) *hook {
+	return parseHook(di, o, dew.AddError, dew.AddWarning)
+}
+
+func parseHook(di *deployment.DeploymentItem, o *uo.UnstructuredObject, handleError func(ref k8s.ObjectRef, err error), handleWarning func(ref k8s.ObjectRef, err error)) *hook {
 	ref := o.GetK8sRef()
 	getSet := func(name string) map[string]bool {
 		ret := make(map[string]bool)
@@ -166,14 +191,14 @@ func (u *HooksUtil) GetHook(di *deployment.DeploymentItem, o *uo.UnstructuredObj
 	hooks := getSet("kluctl.io/hook")
 	for h := range hooks {
 		if utils.FindStrInSlice(supportedKluctlHooks, h) == -1 {
-			u.a.HandleError(ref, fmt.Errorf("unsupported kluctl.io/hook '%s'", h))
+			handleError(ref, fmt.Errorf("unsupported kluctl.io/hook '%s'", h))
 		}
 	}
 
 	helmHooks := getSet("helm.sh/hook")
 	for h := range helmHooks {
 		if utils.FindStrInSlice(supportedHelmHooks, h) == -1 {
-			u.a.HandleWarning(ref, fmt.Errorf("unsupported helm.sh/hook '%s'", h))
+			handleWarning(ref, fmt.Errorf("unsupported helm.sh/hook '%s'", h))
 		}
 	}
 
@@ -202,7 +227,7 @@ func (u *HooksUtil) GetHook(di *deployment.DeploymentItem, o *uo.UnstructuredObj
 	}
 	weight, err := strconv.ParseInt(*weightStr, 10, 32)
 	if err != nil {
-		u.a.HandleError(ref, fmt.Errorf("failed to parse hook weight: %w", err))
+		handleError(ref, fmt.Errorf("failed to parse hook weight: %w", err))
 	}
 
 	deletePolicy := getSet("kluctl.io/hook-delete-policy")
@@ -215,13 +240,13 @@ func (u *HooksUtil) GetHook(di *deployment.DeploymentItem, o *uo.UnstructuredObj
 
 	for p := range deletePolicy {
 		if utils.FindStrInSlice(supportedKluctlDeletePolicies, p) == -1 {
-			u.a.HandleError(ref, fmt.Errorf("unsupported kluctl.io/hook-delete-policy '%s'", p))
+			handleError(ref, fmt.Errorf("unsupported kluctl.io/hook-delete-policy '%s'", p))
 		}
 	}
 
 	wait, err := o.GetK8sAnnotationBool("kluctl.io/hook-wait", true)
 	if err != nil {
-		u.a.HandleError(ref, err)
+		handleError(ref, err)
 	}
 
 	timeoutStr := o.GetK8sAnnotation("kluctl.io/hook-timeout")
@@ -229,7 +254,7 @@ func (u *HooksUtil) GetHook(di *deployment.DeploymentItem, o *uo.UnstructuredObj
 	if timeoutStr != nil {
 		t, err := time.ParseDuration(*timeoutStr)
 		if err != nil {
-			u.a.HandleError(ref, fmt.Errorf("failed to parse duration: %w", err))
+			handleError(ref, fmt.Errorf("failed to parse duration: %w", err))
 		} else {
 			timeout = t
 		}
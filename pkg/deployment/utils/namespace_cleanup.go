@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const namespaceCleanupPollInterval = time.Second
+
+// verifyNamespaceCleanup waits for the given (already deleted) namespaces to disappear. Namespaces that are still
+// present after the timeout are reported as errors, together with all objects inside these namespaces that are stuck
+// in Terminating and the finalizers responsible for it.
+func verifyNamespaceCleanup(ctx context.Context, k *k8s.K8sCluster, namespaces []k8s2.ObjectRef, timeout time.Duration, dew *DeploymentErrorsAndWarnings) {
+	baseStatus := fmt.Sprintf("Waiting for %d namespaces to terminate", len(namespaces))
+	s := status.Start(ctx, baseStatus)
+	defer s.Failed()
+
+	pending := map[k8s2.ObjectRef]*uo.UnstructuredObject{}
+	for _, ref := range namespaces {
+		pending[ref] = nil
+	}
+
+	timeoutCh := time.After(timeout)
+	timedOut := false
+	for len(pending) != 0 && !timedOut {
+		for ref := range pending {
+			o, apiWarnings, err := k.GetSingleObject(ref)
+			dew.AddApiWarnings(ref, apiWarnings)
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					dew.AddError(ref, fmt.Errorf("failed to verify namespace termination: %w", err))
+				}
+				delete(pending, ref)
+				continue
+			}
+			pending[ref] = o
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-time.After(namespaceCleanupPollInterval):
+		case <-timeoutCh:
+			timedOut = true
+		case <-ctx.Done():
+			dew.AddError(k8s2.ObjectRef{}, fmt.Errorf("failed waiting for namespace termination: %w", ctx.Err()))
+			return
+		}
+	}
+
+	if len(pending) == 0 {
+		s.UpdateAndInfoFallback(fmt.Sprintf("All %d namespaces terminated", len(namespaces)))
+		s.Success()
+		return
+	}
+
+	s.UpdateAndInfoFallback(fmt.Sprintf("%s: %d namespaces are still terminating", baseStatus, len(pending)))
+
+	var stuckRefs []k8s2.ObjectRef
+	for ref := range pending {
+		stuckRefs = append(stuckRefs, ref)
+	}
+	sort.Slice(stuckRefs, func(i, j int) bool {
+		return stuckRefs[i].Less(stuckRefs[j])
+	})
+	for _, ref := range stuckRefs {
+		reportStuckNamespace(ctx, k, ref, pending[ref], timeout, dew)
+	}
+}
+
+func reportStuckNamespace(ctx context.Context, k *k8s.K8sCluster, ref k8s2.ObjectRef, o *uo.UnstructuredObject, timeout time.Duration, dew *DeploymentErrorsAndWarnings) {
+	var details []string
+	if finalizers, _, _ := o.GetNestedStringList("spec", "finalizers"); len(finalizers) != 0 {
+		details = append(details, fmt.Sprintf("namespace finalizers: %s", strings.Join(finalizers, ", ")))
+	}
+	for _, c := range o.GetNestedObjectListNoErr("status", "conditions") {
+		cs, _, _ := c.GetNestedString("status")
+		if cs != "True" {
+			continue
+		}
+		ct, _, _ := c.GetNestedString("type")
+		msg, _, _ := c.GetNestedString("message")
+		details = append(details, fmt.Sprintf("%s: %s", ct, msg))
+	}
+
+	stuck, err := findStuckObjects(ctx, k, ref.Name)
+	if err != nil {
+		dew.AddWarning(ref, fmt.Errorf("failed to list objects in terminating namespace: %w", err))
+	}
+
+	msg := fmt.Sprintf("namespace is still terminating after %s", timeout.String())
+	if len(stuck) != 0 {
+		msg += fmt.Sprintf(", %d objects are stuck in Terminating", len(stuck))
+	}
+	if len(details) != 0 {
+		msg += fmt.Sprintf(" (%s)", strings.Join(details, "; "))
+	}
+	dew.AddError(ref, fmt.Errorf("%s", msg))
+
+	for _, x := range stuck {
+		dew.AddError(x.ref, fmt.Errorf("object is stuck in Terminating, blocked by finalizers: %s", strings.Join(x.finalizers, ", ")))
+	}
+}
+
+type stuckObject struct {
+	ref        k8s2.ObjectRef
+	finalizers []string
+}
+
+// findStuckObjects returns all objects in the given namespace that are being deleted but are still blocked by
+// finalizers.
+func findStuckObjects(ctx context.Context, k *k8s.K8sCluster, namespace string) ([]stuckObject, error) {
+	ars, err := k.GetFilteredPreferredAPIResources(func(ar *v1.APIResource) bool {
+		return ar.Namespaced && utils.FindStrInSlice(ar.Verbs, "list") != -1
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var mutex sync.Mutex
+	var ret []stuckObject
+
+	g := utils.NewGoHelper(ctx, 8)
+	for _, ar := range ars {
+		gvk := schema.GroupVersionKind{
+			Group:   ar.Group,
+			Version: ar.Version,
+			Kind:    ar.Kind,
+		}
+		g.Run(func() {
+			l, _, err := k.ListMetadata(gvk, namespace, nil)
+			if err != nil {
+				// the namespace controller reports errors for kinds it can't handle via the namespace conditions
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, x := range l {
+				if x.GetK8sDeletionTime() == nil {
+					continue
+				}
+				finalizers, _, _ := x.GetNestedStringList("metadata", "finalizers")
+				if len(finalizers) == 0 {
+					continue
+				}
+				// metadata lists don't contain the real kind of the items, so we must build the ref on our own
+				ret = append(ret, stuckObject{
+					ref: k8s2.ObjectRef{
+						Group:     gvk.Group,
+						Version:   gvk.Version,
+						Kind:      gvk.Kind,
+						Name:      x.GetK8sName(),
+						Namespace: namespace,
+					},
+					finalizers: finalizers,
+				})
+			}
+		})
+	}
+	g.Wait()
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ref.Less(ret[j].ref)
+	})
+	return ret, nil
+}
@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	namespaceProgressUpdateInterval       = 500 * time.Millisecond
+	namespaceProgressInfoFallbackInterval = 10 * time.Second
+
+	clusterScopedNamespaceName = "<cluster-scoped>"
+)
+
+type namespaceProgressEntry struct {
+	total    map[k8s2.ObjectRef]bool
+	done     map[k8s2.ObjectRef]bool
+	errors   int
+	warnings int
+}
+
+// namespaceProgress aggregates the progress of all deployment items into a single, rate-limited status line. This is
+// used instead of one status line per deployment item, which becomes useless for targets spanning hundreds of
+// namespaces.
+type namespaceProgress struct {
+	ctx  context.Context
+	sctx *status.StatusContext
+
+	mutex            sync.Mutex
+	entries          map[string]*namespaceProgressEntry
+	lastUpdate       time.Time
+	lastInfoFallback time.Time
+}
+
+func newNamespaceProgress(ctx context.Context) *namespaceProgress {
+	return &namespaceProgress{
+		ctx: ctx,
+		sctx: status.StartWithOptions(ctx,
+			status.WithTotal(-1),
+			status.WithStatus("Initializing"),
+		),
+		entries:          map[string]*namespaceProgressEntry{},
+		lastInfoFallback: time.Now(),
+	}
+}
+
+func namespaceProgressKey(ref k8s2.ObjectRef) string {
+	if ref.Namespace == "" {
+		return clusterScopedNamespaceName
+	}
+	return ref.Namespace
+}
+
+func (p *namespaceProgress) getEntry(ref k8s2.ObjectRef) *namespaceProgressEntry {
+	key := namespaceProgressKey(ref)
+	e, ok := p.entries[key]
+	if !ok {
+		e = &namespaceProgressEntry{
+			total: map[k8s2.ObjectRef]bool{},
+			done:  map[k8s2.ObjectRef]bool{},
+		}
+		p.entries[key] = e
+	}
+	return e
+}
+
+func (p *namespaceProgress) addObjects(refs []k8s2.ObjectRef) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, ref := range refs {
+		p.getEntry(ref).total[ref] = true
+	}
+	p.update(false)
+}
+
+func (p *namespaceProgress) objectDone(ref k8s2.ObjectRef) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.getEntry(ref).done[ref] = true
+	p.update(false)
+}
+
+func (p *namespaceProgress) addErrors(ref k8s2.ObjectRef, n int) {
+	if p == nil || ref == (k8s2.ObjectRef{}) {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.getEntry(ref).errors += n
+	p.update(false)
+}
+
+func (p *namespaceProgress) addWarnings(ref k8s2.ObjectRef, n int) {
+	if p == nil || ref == (k8s2.ObjectRef{}) {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.getEntry(ref).warnings += n
+	p.update(false)
+}
+
+// update must be called with the mutex being held. It only updates the status line if the last update is older than
+// namespaceProgressUpdateInterval, unless force is true.
+func (p *namespaceProgress) update(force bool) {
+	now := time.Now()
+	if !force && now.Sub(p.lastUpdate) < namespaceProgressUpdateInterval {
+		return
+	}
+	p.lastUpdate = now
+
+	total, done, finished, withErrors, withWarnings := 0, 0, 0, 0, 0
+	for _, e := range p.entries {
+		total += len(e.total)
+		done += len(e.done)
+		if len(e.done) >= len(e.total) {
+			finished++
+		}
+		if e.errors != 0 {
+			withErrors++
+		}
+		if e.warnings != 0 {
+			withWarnings++
+		}
+	}
+
+	msg := fmt.Sprintf("Applied %d of %d objects, %d of %d namespaces finished", done, total, finished, len(p.entries))
+	if withErrors != 0 {
+		msg += fmt.Sprintf(", %d namespaces with errors", withErrors)
+	}
+	if withWarnings != 0 {
+		msg += fmt.Sprintf(", %d namespaces with warnings", withWarnings)
+	}
+
+	p.sctx.SetTotal(total)
+	p.sctx.Update(msg)
+	if force || now.Sub(p.lastInfoFallback) >= namespaceProgressInfoFallbackInterval {
+		p.sctx.InfoFallback(msg)
+		p.lastInfoFallback = now
+	}
+}
+
+type namespaceSummary struct {
+	namespace string
+	applied   int
+	deleted   int
+	errors    int
+	warnings  int
+}
+
+// finish ends the status line and prints a per-namespace summary table. The summary is built from the final results
+// of all ApplyUtil instances and from the errors and warnings that remained after retries.
+func (p *namespaceProgress) finish(ad *ApplyDeploymentsUtil) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	p.update(true)
+	p.mutex.Unlock()
+
+	summaries := map[string]*namespaceSummary{}
+	get := func(ref k8s2.ObjectRef) *namespaceSummary {
+		key := namespaceProgressKey(ref)
+		s, ok := summaries[key]
+		if !ok {
+			s = &namespaceSummary{namespace: key}
+			summaries[key] = s
+		}
+		return s
+	}
+
+	ad.resultsMutex.Lock()
+	for _, a := range ad.results {
+		a.mutex.Lock()
+		for ref := range a.appliedObjects {
+			if _, ok := a.appliedHookObjects[ref]; !ok {
+				get(ref).applied++
+			}
+		}
+		for ref := range a.deletedObjects {
+			get(ref).deleted++
+		}
+		a.mutex.Unlock()
+	}
+	ad.resultsMutex.Unlock()
+
+	hadErrors := false
+	for _, e := range ad.dew.GetErrorsList() {
+		if e.Ref != (k8s2.ObjectRef{}) {
+			get(e.Ref).errors++
+			hadErrors = true
+		}
+	}
+	for _, w := range ad.dew.GetWarningsList() {
+		if w.Ref != (k8s2.ObjectRef{}) {
+			get(w.Ref).warnings++
+		}
+	}
+
+	if hadErrors {
+		p.sctx.Failed()
+	} else {
+		p.sctx.Success()
+	}
+
+	if len(summaries) == 0 {
+		return
+	}
+	status.Info(p.ctx, buildNamespaceSummaryTable(summaries))
+}
+
+func buildNamespaceSummaryTable(summaries map[string]*namespaceSummary) string {
+	var l []*namespaceSummary
+	nameLen := len("NAMESPACE")
+	for _, s := range summaries {
+		l = append(l, s)
+		if len(s.namespace) > nameLen {
+			nameLen = len(s.namespace)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].namespace < l[j].namespace
+	})
+
+	var sb strings.Builder
+	sb.WriteString("Summary per namespace:\n")
+	sb.WriteString(fmt.Sprintf("%-*s %8s %8s %8s %8s", nameLen, "NAMESPACE", "APPLIED", "DELETED", "ERRORS", "WARNINGS"))
+	for _, s := range l {
+		sb.WriteString(fmt.Sprintf("\n%-*s %8d %8d %8d %8d", nameLen, s.namespace, s.applied, s.deleted, s.errors, s.warnings))
+	}
+	return sb.String()
+}
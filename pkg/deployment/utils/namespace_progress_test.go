@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/lib/status"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceProgress(t *testing.T) {
+	var messages []string
+	ctx := status.NewContext(context.Background(), status.NewSimpleStatusHandler(func(level status.Level, message string) {
+		messages = append(messages, message)
+	}, false))
+
+	cm1 := k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm1", Namespace: "ns1"}
+	cm2 := k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm2", Namespace: "ns2"}
+	cm3 := k8s2.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm3", Namespace: "ns2"}
+	ns := k8s2.ObjectRef{Version: "v1", Kind: "Namespace", Name: "ns1"}
+
+	p := newNamespaceProgress(ctx)
+	p.addObjects([]k8s2.ObjectRef{cm1, cm2, cm3, ns})
+	p.objectDone(ns)
+	p.objectDone(cm1)
+	p.objectDone(cm2)
+	// objects applied multiple times (e.g. on retries) must only be counted once
+	p.objectDone(cm2)
+	p.addErrors(cm3, 1)
+	p.addWarnings(cm1, 2)
+	p.addErrors(k8s2.ObjectRef{}, 1)
+
+	p.mutex.Lock()
+	p.update(true)
+	p.mutex.Unlock()
+
+	assert.Equal(t, "Applied 3 of 4 objects, 2 of 3 namespaces finished, 1 namespaces with errors, 1 namespaces with warnings", messages[len(messages)-1])
+}
+
+func TestBuildNamespaceSummaryTable(t *testing.T) {
+	table := buildNamespaceSummaryTable(map[string]*namespaceSummary{
+		"ns2":                      {namespace: "ns2", applied: 3, errors: 1},
+		"a-long-namespace":         {namespace: "a-long-namespace", applied: 10, deleted: 2, warnings: 4},
+		clusterScopedNamespaceName: {namespace: clusterScopedNamespaceName, applied: 1},
+	})
+	assert.Equal(t, strings.Join([]string{
+		"Summary per namespace:",
+		"NAMESPACE         APPLIED  DELETED   ERRORS WARNINGS",
+		"<cluster-scoped>        1        0        0        0",
+		"a-long-namespace       10        2        0        4",
+		"ns2                     3        0        1        0",
+	}, "\n"), table)
+}
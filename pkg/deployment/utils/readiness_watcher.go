@@ -0,0 +1,330 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"sync"
+)
+
+// readinessWatcher maintains one informer per GVK, namespace and label selector. All objects that are waited for share
+// these informers, so that waiting for many objects results in a single list+watch per GVK and namespace instead of
+// polling each object individually.
+type readinessWatcher struct {
+	ctx context.Context
+	k   *k8s.K8sCluster
+
+	mutex     sync.Mutex
+	c         client.WithWatch
+	informers map[readinessInformerKey]*readinessInformer
+	closed    bool
+}
+
+type readinessInformerKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	selector  string
+}
+
+type readinessInformer struct {
+	informer toolscache.SharedIndexInformer
+	cancel   context.CancelFunc
+
+	// synced is closed when the initial list has finished
+	synced chan struct{}
+	// failed is closed when the informer was not able to perform the initial list, e.g. due to missing permissions.
+	// Waiters must fall back to polling in that case.
+	failed     chan struct{}
+	failedOnce sync.Once
+	err        error
+}
+
+// podOwnerGroupKinds are the kinds for which pods are watched as well, so that pod failures can be reported while
+// waiting for the owner to get ready.
+var podOwnerGroupKinds = map[schema.GroupKind]bool{
+	{Group: "apps", Kind: "Deployment"}:  true,
+	{Group: "apps", Kind: "StatefulSet"}: true,
+	{Group: "apps", Kind: "DaemonSet"}:   true,
+	{Group: "apps", Kind: "ReplicaSet"}:  true,
+	{Group: "batch", Kind: "Job"}:        true,
+}
+
+var podGvk = corev1.SchemeGroupVersion.WithKind("Pod")
+
+func newReadinessWatcher(ctx context.Context, k *k8s.K8sCluster) *readinessWatcher {
+	return &readinessWatcher{
+		ctx:       ctx,
+		k:         k,
+		informers: map[readinessInformerKey]*readinessInformer{},
+	}
+}
+
+// getInformer returns a shared informer for the given GVK and namespace. If selector is not nil, only objects matching
+// the selector are listed and watched.
+func (w *readinessWatcher) getInformer(gvk schema.GroupVersionKind, namespace string, selector labels.Selector) (*readinessInformer, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil, fmt.Errorf("readiness watcher is already closed")
+	}
+
+	var selectorStr string
+	if selector != nil {
+		selectorStr = selector.String()
+	}
+
+	key := readinessInformerKey{gvk: gvk, namespace: namespace, selector: selectorStr}
+	if inf, ok := w.informers[key]; ok {
+		return inf, nil
+	}
+
+	if w.c == nil {
+		c, err := w.k.ToClientWithWatch()
+		if err != nil {
+			return nil, err
+		}
+		w.c = c
+	}
+
+	ctx, cancel := context.WithCancel(w.ctx)
+
+	c := w.c
+	listGvk := gvk
+	listGvk.Kind += "List"
+	lw := &toolscache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selectorStr
+			var l unstructured.UnstructuredList
+			l.SetGroupVersionKind(listGvk)
+			err := c.List(ctx, &l, &client.ListOptions{Namespace: namespace, Raw: &options})
+			return &l, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selectorStr
+			var l unstructured.UnstructuredList
+			l.SetGroupVersionKind(listGvk)
+			return c.Watch(ctx, &l, &client.ListOptions{Namespace: namespace, Raw: &options})
+		},
+	}
+
+	inf := &readinessInformer{
+		informer: toolscache.NewSharedIndexInformer(lw, &unstructured.Unstructured{}, 0, toolscache.Indexers{}),
+		cancel:   cancel,
+		synced:   make(chan struct{}),
+		failed:   make(chan struct{}),
+	}
+	err := inf.informer.SetWatchErrorHandler(func(r *toolscache.Reflector, err error) {
+		if !inf.informer.HasSynced() {
+			inf.fail(err)
+			return
+		}
+		toolscache.DefaultWatchErrorHandler(r, err)
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go inf.informer.Run(ctx.Done())
+	go func() {
+		if toolscache.WaitForCacheSync(ctx.Done(), inf.informer.HasSynced) {
+			close(inf.synced)
+		}
+	}()
+
+	w.informers[key] = inf
+	return inf, nil
+}
+
+func (w *readinessWatcher) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, inf := range w.informers {
+		inf.cancel()
+	}
+	w.informers = map[readinessInformerKey]*readinessInformer{}
+	w.closed = true
+}
+
+func (inf *readinessInformer) fail(err error) {
+	inf.failedOnce.Do(func() {
+		inf.err = err
+		close(inf.failed)
+		inf.cancel()
+	})
+}
+
+func (inf *readinessInformer) isSynced() bool {
+	select {
+	case <-inf.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+func (inf *readinessInformer) getObject(namespace string, name string) *uo.UnstructuredObject {
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	x, ok, err := inf.informer.GetStore().GetByKey(key)
+	if err != nil || !ok {
+		return nil
+	}
+	u, ok := x.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	return uo.FromUnstructured(u.DeepCopy())
+}
+
+func (inf *readinessInformer) listObjects(selector labels.Selector) []*uo.UnstructuredObject {
+	var ret []*uo.UnstructuredObject
+	for _, x := range inf.informer.GetStore().List() {
+		u, ok := x.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		if !selector.Matches(labels.Set(u.GetLabels())) {
+			continue
+		}
+		ret = append(ret, uo.FromUnstructured(u.DeepCopy()))
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].GetK8sName() < ret[j].GetK8sName()
+	})
+	return ret
+}
+
+// subscribe will send a notification to ch whenever an object matching the filter is added, updated or deleted. The
+// notification is not blocking, so ch should be buffered.
+func (inf *readinessInformer) subscribe(filter func(o metav1.Object) bool, ch chan struct{}) (func(), error) {
+	notify := func(obj any) {
+		if t, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = t.Obj
+		}
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return
+		}
+		if filter != nil && !filter(m) {
+			return
+		}
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+
+	handle, err := inf.informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: notify,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			notify(newObj)
+		},
+		DeleteFunc: notify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = inf.informer.RemoveEventHandler(handle)
+	}, nil
+}
+
+func refFilter(ref k8s2.ObjectRef) func(o metav1.Object) bool {
+	return func(o metav1.Object) bool {
+		return o.GetName() == ref.Name && o.GetNamespace() == ref.Namespace
+	}
+}
+
+func buildPodSelector(o *uo.UnstructuredObject) (labels.Selector, error) {
+	s, ok, err := o.GetNestedObject("spec", "selector")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	var ls metav1.LabelSelector
+	err = s.ToStruct(&ls)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&ls)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return nil, nil
+	}
+	return selector, nil
+}
+
+var podFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// buildPodFailureMessages returns human-readable messages for all pods that are failing or have containers that are
+// stuck in a failure state. These are not necessarily fatal (e.g. images might become available later), so they are
+// only meant to be reported as intermediate status while waiting.
+func buildPodFailureMessages(pods []*uo.UnstructuredObject) []string {
+	var ret []string
+	for _, pod := range pods {
+		ref := pod.GetK8sRef()
+
+		phase, _, _ := pod.GetNestedString("status", "phase")
+		if phase == string(corev1.PodFailed) {
+			reason, _, _ := pod.GetNestedString("status", "reason")
+			message, _, _ := pod.GetNestedString("status", "message")
+			s := fmt.Sprintf("pod %s failed", ref.String())
+			if reason != "" {
+				s += fmt.Sprintf(" with reason %s", reason)
+			}
+			if message != "" {
+				s += ": " + message
+			}
+			ret = append(ret, s)
+			continue
+		}
+
+		var statuses []*uo.UnstructuredObject
+		statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "initContainerStatuses")...)
+		statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "containerStatuses")...)
+		for _, cs := range statuses {
+			reason, _, _ := cs.GetNestedString("state", "waiting", "reason")
+			if !podFailureReasons[reason] {
+				continue
+			}
+			name, _, _ := cs.GetNestedString("name")
+			message, _, _ := cs.GetNestedString("state", "waiting", "message")
+			s := fmt.Sprintf("container %s of pod %s is in %s", name, ref.String(), reason)
+			if message != "" {
+				s += ": " + message
+			}
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+	"testing"
+)
+
+func TestBuildPodSelector(t *testing.T) {
+	o := uo.FromMap(map[string]any{
+		"spec": map[string]any{
+			"selector": map[string]any{
+				"matchLabels": map[string]any{
+					"app": "a",
+				},
+			},
+		},
+	})
+	s, err := buildPodSelector(o)
+	assert.NoError(t, err)
+	assert.True(t, s.Matches(labels.Set{"app": "a", "x": "y"}))
+	assert.False(t, s.Matches(labels.Set{"app": "b"}))
+
+	s, err = buildPodSelector(uo.New())
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestBuildPodFailureMessages(t *testing.T) {
+	pods := []*uo.UnstructuredObject{
+		uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "p1", "namespace": "ns"},
+			"status": map[string]any{
+				"phase": "Pending",
+				"containerStatuses": []any{
+					map[string]any{
+						"name": "c1",
+						"state": map[string]any{
+							"waiting": map[string]any{"reason": "ContainerCreating"},
+						},
+					},
+					map[string]any{
+						"name": "c2",
+						"state": map[string]any{
+							"waiting": map[string]any{"reason": "ImagePullBackOff", "message": "not found"},
+						},
+					},
+				},
+			},
+		}),
+		uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "p2", "namespace": "ns"},
+			"status": map[string]any{
+				"phase":  "Failed",
+				"reason": "Evicted",
+			},
+		}),
+		uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "p3", "namespace": "ns"},
+			"status": map[string]any{
+				"phase": "Running",
+			},
+		}),
+	}
+
+	assert.Equal(t, []string{
+		"container c2 of pod ns/Pod/p1 is in ImagePullBackOff: not found",
+		"pod ns/Pod/p2 failed with reason Evicted",
+	}, buildPodFailureMessages(pods))
+}
+
+func TestReadinessWatcherClosed(t *testing.T) {
+	w := newReadinessWatcher(context.Background(), nil)
+	w.close()
+
+	// no new informers must be started after closing, as nobody would stop them
+	_, err := w.getInformer(podGvk, "ns", nil)
+	assert.Error(t, err)
+}
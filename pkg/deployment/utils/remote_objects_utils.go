@@ -11,6 +11,7 @@ import (
 	errors2 "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sync"
 )
@@ -256,10 +257,13 @@ func (u *RemoteObjectUtils) ForgetRemoteObject(ref k8s2.ObjectRef) {
 	delete(u.remoteObjects, ref)
 }
 
-func (u *RemoteObjectUtils) GetFilteredRemoteObjects(inclusion *utils.Inclusion) []*uo.UnstructuredObject {
+func (u *RemoteObjectUtils) GetFilteredRemoteObjects(inclusion *utils.Inclusion, selector labels.Selector) []*uo.UnstructuredObject {
 	var ret []*uo.UnstructuredObject
 
 	for _, o := range u.remoteObjects {
+		if selector != nil && !selector.Matches(labels.Set(o.GetK8sLabels())) {
+			continue
+		}
 		iv := u.getInclusionEntries(o)
 		if inclusion.CheckIncluded(iv, false) {
 			ret = append(ret, o)
@@ -0,0 +1,389 @@
+package utils
+
+import (
+	errors2 "errors"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/lib/yaml"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitProgress aggregates the intermediate status of multiple objects that are waited for concurrently.
+type waitProgress struct {
+	sctx  *status.StatusContext
+	total int
+
+	mutex   sync.Mutex
+	ready   int
+	pending map[k8s2.ObjectRef]string
+	last    k8s2.ObjectRef
+}
+
+func (wp *waitProgress) update(ref k8s2.ObjectRef, message string) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	wp.pending[ref] = message
+	wp.last = ref
+	wp.updateStatusLocked()
+}
+
+func (wp *waitProgress) done(ref k8s2.ObjectRef, ready bool) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	delete(wp.pending, ref)
+	if ready {
+		wp.ready++
+	}
+	if len(wp.pending) == 0 {
+		return
+	}
+	if _, ok := wp.pending[wp.last]; !ok {
+		for r := range wp.pending {
+			wp.last = r
+			break
+		}
+	}
+	wp.updateStatusLocked()
+}
+
+func (wp *waitProgress) updateStatusLocked() {
+	message := wp.pending[wp.last]
+	if wp.total > 1 {
+		message = fmt.Sprintf("%s (%d of %d objects ready)", message, wp.ready, wp.total)
+	}
+	wp.sctx.Update(message)
+}
+
+func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) bool {
+	return a.WaitReadinessMulti([]k8s2.ObjectRef{ref}, timeout)[ref]
+}
+
+// WaitReadinessMulti waits for all given objects concurrently. The timeout is shared by all objects, meaning that all
+// objects must get ready before the timeout elapses. The returned map contains the readiness result for every ref.
+func (a *ApplyUtil) WaitReadinessMulti(refs []k8s2.ObjectRef, timeout time.Duration) map[k8s2.ObjectRef]bool {
+	ret := make(map[k8s2.ObjectRef]bool, len(refs))
+	if a.o.DryRun {
+		for _, ref := range refs {
+			ret[ref] = true
+		}
+		return ret
+	}
+
+	var waitRefs []k8s2.ObjectRef
+	for _, ref := range refs {
+		if a.isDryRunObject(ref) {
+			// objects applied via the kluctl.io/dry-run annotation never get created, so there is nothing to wait for
+			ret[ref] = true
+			continue
+		}
+		if a.deferred.isDeferred(ref) {
+			// deferred objects are waited for after they got applied
+			ret[ref] = true
+			continue
+		}
+		waitRefs = append(waitRefs, ref)
+	}
+	refs = waitRefs
+
+	if timeout == 0 {
+		timeout = a.o.ReadinessTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	wp := &waitProgress{
+		sctx:    a.sctx,
+		total:   len(refs),
+		pending: map[k8s2.ObjectRef]string{},
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, ref := range refs {
+		ref := ref
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := a.waitReadiness(ref, deadline, wp)
+			wp.done(ref, r)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			ret[ref] = r
+		}()
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (a *ApplyUtil) getReadinessInformer(ref k8s2.ObjectRef, selector labels.Selector) *readinessInformer {
+	if a.rw == nil {
+		return nil
+	}
+	inf, err := a.rw.getInformer(ref.GroupVersionKind(), ref.Namespace, selector)
+	if err != nil {
+		status.Tracef(a.refCtx(ref), "Failed to create informer for %s, falling back to polling: %s", ref.String(), err.Error())
+		return nil
+	}
+	return inf
+}
+
+func (a *ApplyUtil) waitReadiness(ref k8s2.ObjectRef, deadline time.Time, wp *waitProgress) bool {
+	status.Tracef(a.refCtx(ref), "Waiting for %s to get ready", ref.String())
+
+	// we initially trigger an evaluation so that polling and already synced informers start immediately
+	notifyCh := make(chan struct{}, 1)
+	notifyCh <- struct{}{}
+
+	var syncedCh, failedCh <-chan struct{}
+	var pollCh <-chan time.Time
+	var pollTicker *time.Ticker
+	startPolling := func() {
+		if pollTicker == nil {
+			pollTicker = time.NewTicker(500 * time.Millisecond)
+			pollCh = pollTicker.C
+		}
+	}
+	defer func() {
+		if pollTicker != nil {
+			pollTicker.Stop()
+		}
+	}()
+
+	inf := a.getReadinessInformer(ref, nil)
+	if inf != nil {
+		unsubscribe, err := inf.subscribe(refFilter(ref), notifyCh)
+		if err != nil {
+			inf = nil
+		} else {
+			defer unsubscribe()
+			syncedCh = inf.synced
+			failedCh = inf.failed
+		}
+	}
+	if inf == nil {
+		startPolling()
+	}
+
+	// pods are only watched once the owner is known, so that the informer can be limited to the owner's selector
+	var podInf *readinessInformer
+	podInfStarted := false
+	var podUnsubscribe func()
+	defer func() {
+		if podUnsubscribe != nil {
+			podUnsubscribe()
+		}
+	}()
+	startPodInformer := func(o *uo.UnstructuredObject) {
+		if podInfStarted || inf == nil || !podOwnerGroupKinds[ref.GroupKind()] {
+			return
+		}
+		podInfStarted = true
+		selector, err := buildPodSelector(o)
+		if err != nil || selector == nil {
+			return
+		}
+		podInf = a.getReadinessInformer(k8s2.ObjectRef{Version: podGvk.Version, Kind: podGvk.Kind, Namespace: ref.Namespace}, selector)
+		if podInf == nil {
+			return
+		}
+		podUnsubscribe, err = podInf.subscribe(nil, notifyCh)
+		if err != nil {
+			podInf = nil
+		}
+	}
+
+	reportStillWaitingTime := 10 * time.Second
+	if testing.Testing() {
+		reportStillWaitingTime = 3 * time.Second
+	}
+	stillWaitingTicker := time.NewTicker(reportStillWaitingTime)
+	defer stillWaitingTicker.Stop()
+
+	timeoutTimer := time.NewTimer(time.Until(deadline))
+	defer timeoutTimer.Stop()
+
+	didLog := false
+	seen := false
+	startTime := time.Now()
+	reportedPodFailures := map[string]bool{}
+	var o *uo.UnstructuredObject
+
+	appliedUid := a.getAppliedUid(ref)
+	getObject := func() (*uo.UnstructuredObject, bool, error) {
+		if inf != nil {
+			if !inf.isSynced() {
+				return nil, false, nil
+			}
+			o := inf.getObject(ref.Namespace, ref.Name)
+			if o != nil && appliedUid != "" && o.GetK8sUid() != appliedUid {
+				// the informer is lagging behind and still has an old version (e.g. a hook that got replaced)
+				return nil, false, nil
+			}
+			return o, true, nil
+		}
+		o, apiWarnings, err := a.k.GetSingleObject(ref)
+		a.handleApiWarnings(ref, apiWarnings)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, false, err
+			}
+			return nil, true, nil
+		}
+		return o, true, nil
+	}
+
+	for true {
+		select {
+		case <-notifyCh:
+		case <-pollCh:
+		case <-syncedCh:
+			syncedCh = nil
+		case <-failedCh:
+			status.Tracef(a.refCtx(ref), "Watching %s failed, falling back to polling: %s", ref.String(), inf.err.Error())
+			inf = nil
+			podInf = nil
+			syncedCh = nil
+			failedCh = nil
+			startPolling()
+		case <-stillWaitingTicker.C:
+			if a.abortSignal.Load().(bool) {
+				return false
+			}
+			if didLog {
+				elapsed := int(time.Now().Sub(startTime).Seconds())
+				a.sctx.InfoFallbackf("Still waiting for %s to get ready... (%ds elapsed)", ref.String(), elapsed)
+			}
+			continue
+		case <-timeoutTimer.C:
+			elapsed := int(time.Now().Sub(startTime).Seconds())
+			err := fmt.Errorf("timed out while waiting for readiness of %s", ref.String())
+			status.Warningf(a.refCtx(ref), "%s (%ds elapsed)", err.Error(), elapsed)
+			if status.IsTraceEnabled(a.refCtx(ref)) {
+				y, err := yaml.WriteYamlString(o)
+				if err == nil {
+					status.Trace(a.refCtx(ref), "yaml:\n"+y)
+				}
+			}
+			a.HandleError(ref, err)
+			return false
+		case <-a.ctx.Done():
+			elapsed := int(time.Now().Sub(startTime).Seconds())
+			err := fmt.Errorf("context cancelled while waiting for readiness of %s", ref.String())
+			status.Warningf(a.refCtx(ref), "%s (%ds elapsed)", err.Error(), elapsed)
+			a.HandleError(ref, err)
+			return false
+		}
+
+		elapsed := int(time.Now().Sub(startTime).Seconds())
+
+		var known bool
+		var err error
+		o, known, err = getObject()
+		if err != nil {
+			a.HandleError(ref, err)
+			return false
+		}
+		if !known {
+			continue
+		}
+
+		if o == nil {
+			if seen {
+				if didLog {
+					status.Warningf(a.refCtx(ref), "Cancelled waiting for %s as it disappeared while waiting for it (%ds elapsed)", ref.String(), elapsed)
+				}
+				a.HandleError(ref, fmt.Errorf("%s disappeared while waiting for it to become ready", ref.String()))
+				return false
+			}
+			wp.update(ref, fmt.Sprintf("Waiting for %s to appear...", ref.String()))
+		} else {
+			seen = true
+			startPodInformer(o)
+
+			v := validation.ValidateObject(a.ctx, a.k, o, false, false)
+			if v.Ready {
+				if didLog {
+					a.sctx.InfoFallbackf("Finished waiting for %s (%ds elapsed)", ref.String(), elapsed)
+				}
+				for _, e := range v.Errors {
+					a.HandleError(ref, errors2.New(e.Message))
+				}
+				for _, e := range v.Warnings {
+					a.HandleWarning(ref, errors2.New(e.Message))
+				}
+				return true
+			}
+			if len(v.Errors) != 0 {
+				if didLog {
+					status.Warningf(a.refCtx(ref), "Cancelled waiting for %s due to errors (%ds elapsed)", ref.String(), elapsed)
+				}
+				for _, e := range v.Errors {
+					a.HandleError(ref, errors2.New(e.Message))
+				}
+				for _, e := range v.Warnings {
+					a.HandleWarning(ref, errors2.New(e.Message))
+				}
+				return false
+			}
+
+			var details []string
+			if len(v.Warnings) != 0 {
+				details = append(details, v.Warnings[0].Message)
+			}
+			if podInf != nil && podInf.isSynced() {
+				podFailures := a.getPodFailures(o, podInf)
+				for _, m := range podFailures {
+					if !reportedPodFailures[m] {
+						a.sctx.InfoFallbackf("%s", m)
+						reportedPodFailures[m] = true
+					}
+				}
+				if len(podFailures) != 0 {
+					details = append(details, podFailures[0])
+				}
+			}
+
+			message := fmt.Sprintf("Waiting for %s to get ready...", ref.String())
+			if len(details) != 0 {
+				message += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+			}
+			wp.update(ref, message)
+		}
+
+		if !didLog {
+			a.sctx.InfoFallbackf("Waiting for %s to get ready... (%ds elapsed)", ref.String(), elapsed)
+			didLog = true
+		}
+	}
+	return false
+}
+
+func (a *ApplyUtil) getPodFailures(o *uo.UnstructuredObject, podInf *readinessInformer) []string {
+	selector, err := buildPodSelector(o)
+	if err != nil || selector == nil {
+		return nil
+	}
+	return buildPodFailureMessages(podInf.listObjects(selector))
+}
+
+func (a *ApplyUtil) getAppliedUid(ref k8s2.ObjectRef) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	o, ok := a.appliedObjects[ref]
+	if !ok {
+		return ""
+	}
+	return o.GetK8sUid()
+}
@@ -0,0 +1,63 @@
+package deploystatus
+
+import (
+	"bytes"
+	"html/template"
+)
+
+const (
+	badgeColorGreen  = "#4c1"
+	badgeColorYellow = "#dfb317"
+	badgeColorRed    = "#e05d44"
+	badgeColorLabel  = "#555"
+)
+
+var badgeTemplate = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="20" role="img" aria-label="{{ .Label }}: {{ .Message }}">
+<title>{{ .Label }}: {{ .Message }}</title>
+<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="{{ .Width }}" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)">
+<rect width="{{ .LabelWidth }}" height="20" fill="{{ .LabelColor }}"/>
+<rect x="{{ .LabelWidth }}" width="{{ .MessageWidth }}" height="20" fill="{{ .Color }}"/>
+<rect width="{{ .Width }}" height="20" fill="url(#s)"/>
+</g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{ .LabelX }}" y="15" fill="#010101" fill-opacity=".3">{{ .Label }}</text>
+<text x="{{ .LabelX }}" y="14">{{ .Label }}</text>
+<text x="{{ .MessageX }}" y="15" fill="#010101" fill-opacity=".3">{{ .Message }}</text>
+<text x="{{ .MessageX }}" y="14">{{ .Message }}</text>
+</g>
+</svg>
+`))
+
+// textWidth roughly estimates the rendered width of s in Verdana 11px. It does not need to be exact, as it is only
+// used to size the badge boxes.
+func textWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
+
+// BuildBadgeSvg renders a shields.io style flat badge.
+func BuildBadgeSvg(label string, message string, color string) string {
+	lw := textWidth(label)
+	mw := textWidth(message)
+
+	data := map[string]any{
+		"Label":        label,
+		"Message":      message,
+		"Color":        color,
+		"LabelColor":   badgeColorLabel,
+		"LabelWidth":   lw,
+		"MessageWidth": mw,
+		"Width":        lw + mw,
+		"LabelX":       lw / 2,
+		"MessageX":     lw + mw/2,
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := badgeTemplate.Execute(buf, data)
+	if err != nil {
+		// the template is static, so this can only happen due to a programming error
+		panic(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,167 @@
+package deploystatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+type DeployResult string
+
+const (
+	DeployResultSuccess  DeployResult = "success"
+	DeployResultWarnings DeployResult = "warnings"
+	DeployResultFailed   DeployResult = "failed"
+)
+
+// DeployStatus is a small summary of the last deployment of a target. It is meant to be published next to the
+// project (e.g. in a bucket) so that repositories and dashboards can show the current deployment state without
+// requiring access to the cluster.
+type DeployStatus struct {
+	Target        string       `json:"target"`
+	Discriminator string       `json:"discriminator,omitempty"`
+	ClusterId     string       `json:"clusterId,omitempty"`
+	ResultId      string       `json:"resultId,omitempty"`
+	Command       string       `json:"command"`
+	DryRun        bool         `json:"dryRun,omitempty"`
+	Time          metav1.Time  `json:"time"`
+	Result        DeployResult `json:"result"`
+
+	GitUrl    string `json:"gitUrl,omitempty"`
+	GitRef    string `json:"gitRef,omitempty"`
+	GitCommit string `json:"gitCommit,omitempty"`
+	GitDirty  bool   `json:"gitDirty,omitempty"`
+
+	NewObjects     int `json:"newObjects"`
+	ChangedObjects int `json:"changedObjects"`
+	DeletedObjects int `json:"deletedObjects"`
+	OrphanObjects  int `json:"orphanObjects"`
+	Errors         int `json:"errors"`
+	Warnings       int `json:"warnings"`
+}
+
+func BuildDeployStatus(cr *result.CommandResult) *DeployStatus {
+	summary := cr.BuildSummary()
+
+	s := &DeployStatus{
+		Target:         cr.TargetKey.TargetName,
+		Discriminator:  cr.TargetKey.Discriminator,
+		ClusterId:      cr.ClusterInfo.ClusterId,
+		ResultId:       cr.Id,
+		Command:        cr.Command.Command,
+		DryRun:         cr.Command.DryRun,
+		Time:           cr.Command.EndTime,
+		GitCommit:      cr.GitInfo.Commit,
+		GitDirty:       cr.GitInfo.Dirty,
+		NewObjects:     summary.NewObjects,
+		ChangedObjects: summary.ChangedObjects,
+		DeletedObjects: summary.DeletedObjects,
+		OrphanObjects:  summary.OrphanObjects,
+		Errors:         len(cr.Errors),
+		Warnings:       len(cr.Warnings),
+	}
+	if cr.GitInfo.Url != nil {
+		s.GitUrl = cr.GitInfo.Url.String()
+	}
+	if cr.GitInfo.Ref != nil {
+		s.GitRef = cr.GitInfo.Ref.String()
+	}
+
+	if s.Errors != 0 {
+		s.Result = DeployResultFailed
+	} else if s.Warnings != 0 {
+		s.Result = DeployResultWarnings
+	} else {
+		s.Result = DeployResultSuccess
+	}
+	return s
+}
+
+// BuildFailedDeployStatus builds the status for a command that failed before a command result was available, e.g.
+// because loading or rendering the project failed. The error message itself is not included, as it might contain
+// sensitive values and the status is meant to be published.
+func BuildFailedDeployStatus(targetName string, discriminator string, command string, errorCount int) *DeployStatus {
+	if errorCount == 0 {
+		errorCount = 1
+	}
+	return &DeployStatus{
+		Target:        targetName,
+		Discriminator: discriminator,
+		Command:       command,
+		Time:          metav1.Now(),
+		Result:        DeployResultFailed,
+		Errors:        errorCount,
+	}
+}
+
+var invalidFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// BaseName returns the file name (without extension) used for the status files of this target.
+func (s *DeployStatus) BaseName() string {
+	name := s.Target
+	if name == "" {
+		name = "no-name"
+	}
+	return invalidFileNameChars.ReplaceAllString(name, "_")
+}
+
+func (s *DeployStatus) BuildJson() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func (s *DeployStatus) BuildBadge() []byte {
+	var color string
+	switch s.Result {
+	case DeployResultSuccess:
+		color = badgeColorGreen
+	case DeployResultWarnings:
+		color = badgeColorYellow
+	default:
+		color = badgeColorRed
+	}
+	return []byte(BuildBadgeSvg(s.BadgeLabel(), string(s.Result), color))
+}
+
+func (s *DeployStatus) BadgeLabel() string {
+	if s.Target == "" {
+		return "deploy"
+	}
+	return fmt.Sprintf("deploy %s", s.Target)
+}
+
+// StatusFile is a single rendered status file, ready to be written or uploaded.
+type StatusFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// BuildFiles renders the JSON status file and the SVG badge.
+func (s *DeployStatus) BuildFiles() ([]StatusFile, error) {
+	j, err := s.BuildJson()
+	if err != nil {
+		return nil, err
+	}
+	return []StatusFile{
+		{Name: s.BaseName() + ".json", ContentType: "application/json", Data: j},
+		{Name: s.BaseName() + ".svg", ContentType: "image/svg+xml", Data: s.BuildBadge()},
+	}, nil
+}
+
+func WriteFiles(dir string, files []StatusFile) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		err = os.WriteFile(filepath.Join(dir, f.Name), f.Data, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write status file %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
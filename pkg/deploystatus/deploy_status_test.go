@@ -0,0 +1,86 @@
+package deploystatus
+
+import (
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestBuildDeployStatus(t *testing.T) {
+	cr := &result.CommandResult{
+		Id: "id1",
+		TargetKey: result.TargetKey{
+			TargetName:    "prod/eu",
+			Discriminator: "d",
+		},
+		Command: result.CommandInfo{
+			Command: "deploy",
+		},
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: k8s.ObjectRef{Name: "a"}, New: true}},
+			{BaseObject: result.BaseObject{Ref: k8s.ObjectRef{Name: "b"}, Changes: []result.Change{{}}}},
+		},
+	}
+
+	s := BuildDeployStatus(cr)
+	assert.Equal(t, DeployResultSuccess, s.Result)
+	assert.Equal(t, 1, s.NewObjects)
+	assert.Equal(t, 1, s.ChangedObjects)
+	assert.Equal(t, "prod_eu", s.BaseName())
+
+	cr.Warnings = append(cr.Warnings, result.DeploymentError{Message: "w"})
+	assert.Equal(t, DeployResultWarnings, BuildDeployStatus(cr).Result)
+
+	cr.Errors = append(cr.Errors, result.DeploymentError{Message: "e"})
+	s = BuildDeployStatus(cr)
+	assert.Equal(t, DeployResultFailed, s.Result)
+
+	files, err := s.BuildFiles()
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "prod_eu.json", files[0].Name)
+	assert.Equal(t, "prod_eu.svg", files[1].Name)
+
+	var s2 DeployStatus
+	err = json.Unmarshal(files[0].Data, &s2)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Result, s2.Result)
+	assert.Equal(t, 1, s2.Errors)
+
+	assert.True(t, strings.HasPrefix(string(files[1].Data), "<svg"))
+	assert.Contains(t, string(files[1].Data), "deploy prod/eu: failed")
+	assert.Contains(t, string(files[1].Data), badgeColorRed)
+}
+
+func TestBuildFailedDeployStatus(t *testing.T) {
+	s := BuildFailedDeployStatus("prod", "d", "deploy", 0)
+	assert.Equal(t, DeployResultFailed, s.Result)
+	assert.Equal(t, "prod", s.Target)
+	assert.Equal(t, "d", s.Discriminator)
+	assert.Equal(t, 1, s.Errors)
+	assert.False(t, s.Time.IsZero())
+
+	s = BuildFailedDeployStatus("", "", "deploy", 3)
+	assert.Equal(t, 3, s.Errors)
+	assert.Equal(t, "no-name", s.BaseName())
+	assert.Contains(t, string(s.BuildBadge()), "deploy: failed")
+}
+
+func TestParseUploadTarget(t *testing.T) {
+	ut, err := ParseUploadTarget("s3://my-bucket/some/prefix/")
+	assert.NoError(t, err)
+	assert.Equal(t, &UploadTarget{Scheme: "s3", Bucket: "my-bucket", Prefix: "some/prefix"}, ut)
+	assert.Equal(t, "some/prefix/t.json", ut.objectKey("t.json"))
+
+	ut, err = ParseUploadTarget("gs://my-bucket")
+	assert.NoError(t, err)
+	assert.Equal(t, "t.json", ut.objectKey("t.json"))
+
+	_, err = ParseUploadTarget("http://my-bucket")
+	assert.ErrorContains(t, err, "unsupported upload url")
+	_, err = ParseUploadTarget("s3:///prefix")
+	assert.ErrorContains(t, err, "missing bucket")
+}
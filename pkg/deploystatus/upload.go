@@ -0,0 +1,63 @@
+package deploystatus
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/clouds/aws"
+	"github.com/kluctl/kluctl/v2/pkg/clouds/gcp"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"net/url"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+type UploadTarget struct {
+	Scheme string
+	Bucket string
+	Prefix string
+}
+
+func ParseUploadTarget(s string) (*UploadTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return nil, fmt.Errorf("unsupported upload url %s, only s3:// and gs:// are supported", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing bucket in upload url %s", s)
+	}
+	return &UploadTarget{
+		Scheme: u.Scheme,
+		Bucket: u.Host,
+		Prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (t *UploadTarget) objectKey(name string) string {
+	if t.Prefix == "" {
+		return name
+	}
+	return path.Join(t.Prefix, name)
+}
+
+// Upload uploads all files to the given target. The AWS config and client are used to resolve AWS credentials the same
+// way as it is done for other AWS related features of the target.
+func (t *UploadTarget) Upload(ctx context.Context, c client.Client, awsConfig *types.AwsConfig, files []StatusFile) error {
+	for _, f := range files {
+		key := t.objectKey(f.Name)
+		var err error
+		switch t.Scheme {
+		case "s3":
+			err = aws.PutS3Object(ctx, c, awsConfig, t.Bucket, key, f.ContentType, f.Data)
+		case "gs":
+			err = gcp.PutStorageObject(ctx, t.Bucket, key, f.ContentType, f.Data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
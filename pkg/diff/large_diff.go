@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sort"
+)
+
+// DefaultLargeObjectThreshold is the serialized size (in bytes) above which objects are diffed via diffLarge
+const DefaultLargeObjectThreshold = 1024 * 1024
+
+// largeDiffFullValueThreshold is the serialized size (in bytes) of top-level values below which diffLarge still
+// performs a full diff, so that small changes (e.g. in metadata) remain readable
+const largeDiffFullValueThreshold = 16 * 1024
+
+// DiffWithThreshold performs a full Diff if both objects are smaller than threshold and falls back to diffLarge
+// otherwise. A threshold <= 0 disables the fallback.
+func DiffWithThreshold(oldObject *uo.UnstructuredObject, newObject *uo.UnstructuredObject, threshold int) ([]result.Change, error) {
+	if threshold <= 0 {
+		return Diff(oldObject, newObject)
+	}
+
+	oldValues, oldSize, err := marshalTopLevelValues(oldObject)
+	if err != nil {
+		return nil, err
+	}
+	newValues, newSize, err := marshalTopLevelValues(newObject)
+	if err != nil {
+		return nil, err
+	}
+	if oldSize < threshold && newSize < threshold {
+		return Diff(oldObject, newObject)
+	}
+	return diffLarge(oldObject, newObject, oldValues, newValues)
+}
+
+func marshalTopLevelValues(o *uo.UnstructuredObject) (map[string][]byte, int, error) {
+	ret := make(map[string][]byte, len(o.Object))
+	size := 0
+	for k, v := range o.Object {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		ret[k] = b
+		size += len(b)
+	}
+	return ret, size, nil
+}
+
+// diffLarge compares both objects per top-level key (e.g. metadata, spec or data) by comparing the hashes of the
+// serialized values. Large values that differ are reported as a single change which only contains the hashes and sizes
+// of the values, avoiding the memory and CPU overhead of a full diff.
+func diffLarge(oldObject *uo.UnstructuredObject, newObject *uo.UnstructuredObject, oldValues map[string][]byte, newValues map[string][]byte) ([]result.Change, error) {
+	var keys []string
+	for k := range oldValues {
+		keys = append(keys, k)
+	}
+	for k := range newValues {
+		if _, ok := oldValues[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []result.Change
+	for _, k := range keys {
+		ov, oldOk := oldValues[k]
+		nv, newOk := newValues[k]
+		oldHash, newHash := hashValue(ov), hashValue(nv)
+		if oldOk && newOk && oldHash == newHash {
+			continue
+		}
+
+		if len(ov) < largeDiffFullValueThreshold && len(nv) < largeDiffFullValueThreshold {
+			o1 := uo.New()
+			o2 := uo.New()
+			if oldOk {
+				o1.Object[k] = oldObject.Object[k]
+			}
+			if newOk {
+				o2.Object[k] = newObject.Object[k]
+			}
+			c, err := Diff(o1, o2)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, c...)
+			continue
+		}
+
+		c := result.Change{
+			Type:     "update",
+			JsonPath: uo.KeyPath{k}.ToJsonPath(),
+		}
+		var err error
+		if oldOk {
+			c.OldValue, err = buildLargeValueSummary(oldHash, len(ov))
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			c.Type = "insert"
+		}
+		if newOk {
+			c.NewValue, err = buildLargeValueSummary(newHash, len(nv))
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			c.Type = "delete"
+		}
+		err = updateUnifiedDiff(&c)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	stableSortChanges(changes)
+	return changes, nil
+}
+
+func hashValue(b []byte) string {
+	if b == nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func buildLargeValueSummary(hash string, size int) (*apiextensionsv1.JSON, error) {
+	j, err := yaml.WriteJsonString(fmt.Sprintf("(large value not diffed, sha256:%s, %d bytes)", hash, size))
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: []byte(j)}, nil
+}
@@ -0,0 +1,71 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func buildLargeConfigMap(value string, label string) *uo.UnstructuredObject {
+	o := uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "cm",
+			"namespace": "ns",
+			"labels": map[string]any{
+				"l": label,
+			},
+		},
+		"data": map[string]any{
+			"large": strings.Repeat(value, 32*1024),
+			"small": "x",
+		},
+	})
+	return o
+}
+
+func TestDiffWithThreshold(t *testing.T) {
+	o1 := buildLargeConfigMap("a", "v1")
+	o2 := buildLargeConfigMap("b", "v2")
+
+	changes, err := DiffWithThreshold(o1, o2, 1024)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+
+	assert.Equal(t, "data", changes[0].JsonPath)
+	assert.Equal(t, "update", changes[0].Type)
+	assert.Contains(t, string(changes[0].OldValue.Raw), "(large value not diffed, sha256:")
+	assert.Contains(t, changes[0].UnifiedDiff, "large value not diffed")
+	assert.Less(t, len(changes[0].UnifiedDiff), 1024)
+
+	// small top-level values are still diffed in full
+	assert.Equal(t, `metadata.labels["l"]`, changes[1].JsonPath)
+	assert.Equal(t, "update", changes[1].Type)
+	assert.Equal(t, "-v1\n+v2", changes[1].UnifiedDiff)
+
+	// below the threshold, a full diff is performed
+	changes, err = DiffWithThreshold(o1, o2, 10*1024*1024)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "data.large", changes[0].JsonPath)
+
+	// a threshold of 0 disables the fallback
+	changes, err = DiffWithThreshold(o1, o2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "data.large", changes[0].JsonPath)
+
+	// unchanged large values are not reported
+	changes, err = DiffWithThreshold(o1, buildLargeConfigMap("a", "v1"), 1024)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 0)
+
+	o3 := o1.Clone()
+	o3.RemoveNestedField("data")
+	changes, err = DiffWithThreshold(o1, o3, 1024)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "delete", changes[0].Type)
+	assert.Nil(t, changes[0].NewValue)
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/value"
+	"sort"
 )
 
 type LostOwnership struct {
@@ -437,3 +438,126 @@ func (cr *ConflictResolver) ResolveConflicts(local *uo.UnstructuredObject, remot
 
 	return ret, lostOwnership, nil
 }
+
+type TakenOwnership struct {
+	Field   string `json:"field"`
+	Manager string `json:"manager"`
+}
+
+func buildFieldSetsByManager(o *uo.UnstructuredObject) (map[string]*fieldpath.Set, error) {
+	ret := map[string]*fieldpath.Set{}
+	for _, mf := range o.GetK8sManagedFields() {
+		mgr, ok, err := mf.GetNestedString("manager")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("manager field is missing")
+		}
+		fields, ok, err := mf.GetNestedObject("fieldsV1")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		fieldSet, _, err := convertManagedFields(fields.Object)
+		if err != nil {
+			return nil, err
+		}
+		if fieldSet == nil {
+			continue
+		}
+		if s, ok := ret[mgr]; ok {
+			ret[mgr] = s.Union(fieldSet)
+		} else {
+			ret[mgr] = fieldSet
+		}
+	}
+	return ret, nil
+}
+
+// FindTakenOwnership compares the managed fields of both objects and returns all fields that are owned by the given
+// manager in after and that were owned by other managers in before, but are not owned by them anymore in after.
+func FindTakenOwnership(before *uo.UnstructuredObject, after *uo.UnstructuredObject, manager string) ([]TakenOwnership, error) {
+	beforeSets, err := buildFieldSetsByManager(before)
+	if err != nil {
+		return nil, err
+	}
+	afterSets, err := buildFieldSetsByManager(after)
+	if err != nil {
+		return nil, err
+	}
+
+	owned, ok := afterSets[manager]
+	if !ok {
+		return nil, nil
+	}
+
+	var ret []TakenOwnership
+	for mgr, s := range beforeSets {
+		if mgr == manager {
+			continue
+		}
+		taken := s.Intersection(owned)
+		if s2, ok := afterSets[mgr]; ok {
+			taken = taken.Difference(s2)
+		}
+		taken.Iterate(func(path fieldpath.Path) {
+			ret = append(ret, TakenOwnership{
+				Field:   path.String(),
+				Manager: mgr,
+			})
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Manager != ret[j].Manager {
+			return ret[i].Manager < ret[j].Manager
+		}
+		return ret[i].Field < ret[j].Field
+	})
+	return ret, nil
+}
+
+type FieldOwnership struct {
+	Field    string   `json:"field"`
+	Managers []string `json:"managers"`
+}
+
+// BuildFieldOwnership digests the managed fields of the given object into a list of fields and the managers owning
+// them. Fields are truncated to the given depth (e.g. depth 2 results in fields like .spec.replicas or
+// .metadata.labels), so that the result stays readable for large objects.
+func BuildFieldOwnership(o *uo.UnstructuredObject, depth int) ([]FieldOwnership, error) {
+	sets, err := buildFieldSetsByManager(o)
+	if err != nil {
+		return nil, err
+	}
+
+	managersByField := map[string]map[string]bool{}
+	for mgr, s := range sets {
+		s.Iterate(func(path fieldpath.Path) {
+			if depth > 0 && len(path) > depth {
+				path = path[:depth]
+			}
+			f := path.String()
+			if _, ok := managersByField[f]; !ok {
+				managersByField[f] = map[string]bool{}
+			}
+			managersByField[f][mgr] = true
+		})
+	}
+
+	ret := make([]FieldOwnership, 0, len(managersByField))
+	for f, managers := range managersByField {
+		fo := FieldOwnership{Field: f}
+		for mgr := range managers {
+			fo.Managers = append(fo.Managers, mgr)
+		}
+		sort.Strings(fo.Managers)
+		ret = append(ret, fo)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Field < ret[j].Field
+	})
+	return ret, nil
+}
@@ -263,3 +263,94 @@ func TestResolveFieldManagerConflicts(t *testing.T) {
 		})
 	}
 }
+
+func TestFindTakenOwnership(t *testing.T) {
+	buildObject := func(fieldsByManager map[string][]fieldpath.Path) *uo.UnstructuredObject {
+		o := uo.FromMap(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "name",
+				"namespace": "namespace",
+			},
+		})
+		var managedFields []any
+		for manager, pathes := range fieldsByManager {
+			json, _ := fieldpath.NewSet(pathes...).ToJSON()
+			fsY, _ := uo.FromString(string(json))
+			managedFields = append(managedFields, map[string]interface{}{
+				"apiVersion": "v1",
+				"fieldsType": "FieldsV1",
+				"manager":    manager,
+				"operation":  "Apply",
+				"fieldsV1":   fsY.Object,
+			})
+		}
+		_ = o.SetNestedField(managedFields, "metadata", "managedFields")
+		return o
+	}
+
+	before := buildObject(map[string][]fieldpath.Path{
+		"kluctl":       {fieldpath.MakePathOrDie("data", "a")},
+		"kubectl-edit": {fieldpath.MakePathOrDie("data", "b"), fieldpath.MakePathOrDie("data", "c")},
+		"other":        {fieldpath.MakePathOrDie("data", "d")},
+	})
+	after := buildObject(map[string][]fieldpath.Path{
+		"kluctl":       {fieldpath.MakePathOrDie("data", "a"), fieldpath.MakePathOrDie("data", "b"), fieldpath.MakePathOrDie("data", "d")},
+		"kubectl-edit": {fieldpath.MakePathOrDie("data", "c")},
+		"other":        {fieldpath.MakePathOrDie("data", "d")},
+	})
+
+	taken, err := FindTakenOwnership(before, after, "kluctl")
+	assert.NoError(t, err)
+	assert.Equal(t, []TakenOwnership{
+		{Field: ".data.b", Manager: "kubectl-edit"},
+	}, taken)
+}
+
+func TestBuildFieldOwnership(t *testing.T) {
+	o := uo.FromMap(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":      "name",
+			"namespace": "namespace",
+		},
+	})
+	var managedFields []any
+	for manager, pathes := range map[string][]fieldpath.Path{
+		"kluctl": {
+			fieldpath.MakePathOrDie("metadata", "labels", "a"),
+			fieldpath.MakePathOrDie("spec", "replicas"),
+			fieldpath.MakePathOrDie("spec", "template", "spec", "containers"),
+		},
+		"hpa": {fieldpath.MakePathOrDie("spec", "replicas")},
+	} {
+		json, _ := fieldpath.NewSet(pathes...).ToJSON()
+		fsY, _ := uo.FromString(string(json))
+		managedFields = append(managedFields, map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"fieldsType": "FieldsV1",
+			"manager":    manager,
+			"operation":  "Apply",
+			"fieldsV1":   fsY.Object,
+		})
+	}
+	_ = o.SetNestedField(managedFields, "metadata", "managedFields")
+
+	fo, err := BuildFieldOwnership(o, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldOwnership{
+		{Field: ".metadata.labels", Managers: []string{"kluctl"}},
+		{Field: ".spec.replicas", Managers: []string{"hpa", "kluctl"}},
+		{Field: ".spec.template", Managers: []string{"kluctl"}},
+	}, fo)
+
+	fo, err = BuildFieldOwnership(o, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldOwnership{
+		{Field: ".metadata.labels.a", Managers: []string{"kluctl"}},
+		{Field: ".spec.replicas", Managers: []string{"hpa", "kluctl"}},
+		{Field: ".spec.template.spec.containers", Managers: []string{"kluctl"}},
+	}, fo)
+}
@@ -132,11 +132,19 @@ func normalizeFloats(o *uo.UnstructuredObject) {
 	})
 }
 
+func checkMatch(v string, m *string) bool {
+	if v == "" || m == nil {
+		return true
+	}
+	return v == *m
+}
+
 var ignoreDiffFieldAnnotationRegex = regexp.MustCompile(`^kluctl.io/ignore-diff-field(-\d*)?$`)
 var ignoreDiffFieldRegexAnnotationRegex = regexp.MustCompile(`^kluctl.io/ignore-diff-field-regex(-\d*)?$`)
 
-// NormalizeObject Performs some deterministic sorting and other normalizations to avoid ugly diffs due to order changes
-func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig, localObject *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+// NormalizeObject Performs some deterministic sorting and other normalizations to avoid ugly diffs due to order changes.
+// The built-in diff normalizers are always applied, followed by the passed normalizers.
+func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []types.IgnoreForDiffItemConfig, normalizers []types.DiffNormalizerConfig, localObject *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
 	gvk := o_.GetK8sGVK()
 	name := o_.GetK8sName()
 	ns := o_.GetK8sNamespace()
@@ -155,18 +163,20 @@ func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []types.IgnoreFor
 		normalizeServiceAccount(o)
 	}
 
+	err := applyDiffNormalizers(o, builtinDiffNormalizers)
+	if err != nil {
+		return nil, err
+	}
+	err = applyDiffNormalizers(o, normalizers)
+	if err != nil {
+		return nil, err
+	}
+
 	if localObject.GetK8sAnnotationBoolNoError("kluctl.io/ignore-diff", false) {
 		// Return empty object so that diffs will always be empty
 		return &uo.UnstructuredObject{Object: map[string]interface{}{}}, nil
 	}
 
-	checkMatch := func(v string, m *string) bool {
-		if v == "" || m == nil {
-			return true
-		}
-		return v == *m
-	}
-
 	ignoreForDiffs = append([]types.IgnoreForDiffItemConfig{}, ignoreForDiffs...)
 	for _, v := range localObject.GetK8sAnnotationsWithRegex(ignoreDiffFieldAnnotationRegex) {
 		ignoreForDiffs = append(ignoreForDiffs, types.IgnoreForDiffItemConfig{
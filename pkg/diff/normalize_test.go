@@ -41,12 +41,13 @@ type testCase struct {
 	local          *uo.UnstructuredObject
 	result         *uo.UnstructuredObject
 	ignoreForDiffs []types.IgnoreForDiffItemConfig
+	normalizers    []types.DiffNormalizerConfig
 }
 
 func runTests(t *testing.T, tests []testCase) {
 	for i, tc := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
-			r, err := NormalizeObject(tc.remote, tc.ignoreForDiffs, tc.local)
+			r, err := NormalizeObject(tc.remote, tc.ignoreForDiffs, tc.normalizers, tc.local)
 			if err != nil {
 				t.Error(err)
 			} else {
@@ -203,3 +204,59 @@ func TestNormalizeIgnoreForDiffsByAnnotations(t *testing.T) {
 	}
 	runTests(t, testCases)
 }
+
+func TestNormalizeBuiltinDiffNormalizers(t *testing.T) {
+	testCases := []testCase{
+		{
+			remote: buildObject(`{"spec": {"template": {"spec": {"containers": [{"ports": [{"containerPort": 8080}, {"containerPort": 80}]}]}}}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"template": {"spec": {"containers": [{"ports": [{"containerPort": 80}, {"containerPort": 8080}]}]}}}}`),
+		},
+		{
+			remote: buildObject(`{"spec": {"template": {"spec": {"volumes": [{"name": "b"}, {"name": "a"}], "containers": [{"volumeMounts": [{"name": "b", "mountPath": "/b"}, {"name": "a", "mountPath": "/a"}]}]}}}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"template": {"spec": {"volumes": [{"name": "a"}, {"name": "b"}], "containers": [{"volumeMounts": [{"name": "a", "mountPath": "/a"}, {"name": "b", "mountPath": "/b"}]}]}}}}`),
+		},
+		{
+			// lists with elements missing the key are left untouched
+			remote: buildObject(`{"spec": {"template": {"spec": {"volumes": [{"name": "b"}, {"x": "a"}]}}}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"template": {"spec": {"volumes": [{"name": "b"}, {"x": "a"}]}}}}`),
+		},
+		{
+			remote: buildObject(`{"spec": {"template": {"spec": {"initContainers": [{"resources": {"limits": {"cpu": "1000m", "memory": "1Gi"}, "requests": {"cpu": 0.5, "memory": "1024Mi"}}}]}}}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"template": {"spec": {"initContainers": [{"resources": {"limits": {"cpu": "1", "memory": "1Gi"}, "requests": {"cpu": "500m", "memory": "1Gi"}}}]}}}}`),
+		},
+		{
+			// builtin normalizers only apply to matching kinds
+			remote: buildObject(`{"apiVersion": "example.com/v1", "kind": "Deployment", "spec": {"template": {"spec": {"volumes": [{"name": "b"}, {"name": "a"}]}}}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"apiVersion": "example.com/v1", "kind": "Deployment", "spec": {"template": {"spec": {"volumes": [{"name": "b"}, {"name": "a"}]}}}}`),
+		},
+	}
+	runTests(t, testCases)
+}
+
+func TestNormalizeCustomDiffNormalizers(t *testing.T) {
+	testCases := []testCase{
+		{
+			remote: buildObject(`{"spec": {"items": [{"id": "b"}, {"id": "a"}], "size": "2048Mi"}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"items": [{"id": "a"}, {"id": "b"}], "size": "2Gi"}}`),
+			normalizers: []types.DiffNormalizerConfig{
+				{Normalizer: types.DiffNormalizerSortListByKey, FieldPath: []string{"spec.items"}, Key: "id"},
+				{Normalizer: types.DiffNormalizerQuantity, FieldPath: []string{"spec.size"}, Kind: utils.Ptr("Deployment")},
+			},
+		},
+		{
+			remote: buildObject(`{"spec": {"items": [{"id": "b"}, {"id": "a"}]}}`),
+			local:  buildObject(),
+			result: buildResultObject(`{"spec": {"items": [{"id": "b"}, {"id": "a"}]}}`),
+			normalizers: []types.DiffNormalizerConfig{
+				{Normalizer: types.DiffNormalizerSortListByKey, FieldPath: []string{"spec.items"}, Key: "id", Name: utils.Ptr("Nope")},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
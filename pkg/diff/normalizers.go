@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sort"
+	"strconv"
+)
+
+type diffNormalizerFunc func(o *uo.UnstructuredObject, kp uo.KeyPath, n *types.DiffNormalizerConfig) error
+
+// diffNormalizers is the registry of all known normalizers. Normalizers are referenced by name from the built-in
+// normalizers and from the diffNormalizers list in deployment.yml
+var diffNormalizers = map[types.DiffNormalizerType]diffNormalizerFunc{
+	types.DiffNormalizerSortListByKey: normalizeSortListByKey,
+	types.DiffNormalizerQuantity:      normalizeQuantity,
+}
+
+var builtinDiffNormalizers = buildBuiltinDiffNormalizers()
+
+func buildBuiltinDiffNormalizers() []types.DiffNormalizerConfig {
+	var ret []types.DiffNormalizerConfig
+
+	add := func(group string, kind string, normalizer types.DiffNormalizerType, key string, fieldPaths ...string) {
+		ret = append(ret, types.DiffNormalizerConfig{
+			Normalizer: normalizer,
+			FieldPath:  fieldPaths,
+			Key:        key,
+			Group:      &group,
+			Kind:       &kind,
+		})
+	}
+
+	addPodSpec := func(group string, kind string, podSpecPath string) {
+		for _, c := range []string{"containers", "initContainers"} {
+			cp := fmt.Sprintf("%s.%s[*]", podSpecPath, c)
+			add(group, kind, types.DiffNormalizerSortListByKey, "containerPort", cp+".ports")
+			add(group, kind, types.DiffNormalizerSortListByKey, "mountPath", cp+".volumeMounts")
+			add(group, kind, types.DiffNormalizerQuantity, "", cp+".resources.limits.*", cp+".resources.requests.*")
+		}
+		add(group, kind, types.DiffNormalizerSortListByKey, "name", podSpecPath+".volumes")
+	}
+
+	addPodSpec("", "Pod", "spec")
+	for _, kind := range []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"} {
+		addPodSpec("apps", kind, "spec.template.spec")
+	}
+	addPodSpec("batch", "Job", "spec.template.spec")
+	addPodSpec("batch", "CronJob", "spec.jobTemplate.spec.template.spec")
+
+	add("", "Service", types.DiffNormalizerSortListByKey, "port", "spec.ports")
+	add("", "ResourceQuota", types.DiffNormalizerQuantity, "", "spec.hard.*")
+	add("", "PersistentVolumeClaim", types.DiffNormalizerQuantity, "", "spec.resources.requests.*", "spec.resources.limits.*")
+
+	return ret
+}
+
+func applyDiffNormalizers(o *uo.UnstructuredObject, normalizers []types.DiffNormalizerConfig) error {
+	gvk := o.GetK8sGVK()
+	name := o.GetK8sName()
+	ns := o.GetK8sNamespace()
+
+	for i := range normalizers {
+		n := &normalizers[i]
+		if !checkMatch(gvk.Group, n.Group) || !checkMatch(gvk.Kind, n.Kind) || !checkMatch(ns, n.Namespace) || !checkMatch(name, n.Name) {
+			continue
+		}
+
+		f, ok := diffNormalizers[n.Normalizer]
+		if !ok {
+			return fmt.Errorf("unknown diff normalizer %s", n.Normalizer)
+		}
+
+		for _, fp := range n.FieldPath {
+			jp, err := uo.NewMyJsonPath(fp)
+			if err != nil {
+				return err
+			}
+			kps, err := jp.ListMatchingFields(o)
+			if err != nil {
+				return err
+			}
+			for _, kp := range kps {
+				err = f(o, kp, n)
+				if err != nil {
+					return fmt.Errorf("diff normalizer %s failed for %s: %w", n.Normalizer, kp.ToJsonPath(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeSortListByKey(o *uo.UnstructuredObject, kp uo.KeyPath, n *types.DiffNormalizerConfig) error {
+	l, found, err := o.GetNestedObjectList(kp...)
+	if err != nil || !found {
+		// not a list of objects, so nothing to sort
+		return nil
+	}
+
+	keys := make([]any, 0, len(l))
+	for _, e := range l {
+		k, found, _ := e.GetNestedField(n.Key)
+		if !found {
+			// only sort lists where all elements have the key, we'd produce random results otherwise
+			return nil
+		}
+		keys = append(keys, k)
+	}
+
+	idx := make([]int, len(l))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return lessSortKey(keys[idx[i]], keys[idx[j]])
+	})
+
+	newList := make([]any, 0, len(l))
+	for _, i := range idx {
+		newList = append(newList, l[i].Object)
+	}
+	return o.SetNestedField(newList, kp...)
+}
+
+func lessSortKey(a any, b any) bool {
+	af, aErr := strconv.ParseFloat(fmt.Sprint(a), 64)
+	bf, bErr := strconv.ParseFloat(fmt.Sprint(b), 64)
+	if aErr == nil && bErr == nil {
+		return af < bf
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func normalizeQuantity(o *uo.UnstructuredObject, kp uo.KeyPath, n *types.DiffNormalizerConfig) error {
+	v, found, err := o.GetNestedField(kp...)
+	if err != nil || !found || v == nil {
+		return nil
+	}
+	switch v.(type) {
+	case string, int64, int, float64:
+	default:
+		return nil
+	}
+
+	q, err := resource.ParseQuantity(fmt.Sprint(v))
+	if err != nil {
+		// invalid quantities are left as they are, the apiserver would reject them anyway
+		return nil
+	}
+	return o.SetNestedField(q.String(), kp...)
+}
@@ -0,0 +1,103 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"helm.sh/helm/v3/pkg/release"
+	"io"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strconv"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// decodeHelmRelease decodes the release data as stored by Helm's secrets storage driver, which is a base64 encoded
+// and (optionally) gzipped JSON representation of the release.
+func decodeHelmRelease(data string) (*release.Release, error) {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(b, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		b, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rls release.Release
+	err = json.Unmarshal(b, &rls)
+	if err != nil {
+		return nil, err
+	}
+	return &rls, nil
+}
+
+func decodeHelmReleaseSecret(s *uo.UnstructuredObject) (*release.Release, error) {
+	data, _, _ := s.GetNestedString("data", "release")
+	if data == "" {
+		return nil, fmt.Errorf("secret %s does not contain a Helm release", s.GetK8sRef().String())
+	}
+	// secret data is base64 encoded by Kubernetes, on top of the base64 encoding done by Helm
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	rls, err := decodeHelmRelease(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Helm release from secret %s: %w", s.GetK8sRef().String(), err)
+	}
+	return rls, nil
+}
+
+// GetDeployedHelmRelease reads the currently deployed revision of the given Helm release from the cluster. Only the
+// default secrets storage driver of Helm is supported. It also returns the refs of the secrets of all revisions of the
+// release.
+func GetDeployedHelmRelease(k *k8s.K8sCluster, namespace string, name string) (*release.Release, []k8s2.ObjectRef, error) {
+	secrets, _, err := k.ListObjects(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, namespace, map[string]string{
+		"owner": "helm",
+		"name":  name,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var deployed *uo.UnstructuredObject
+	deployedVersion := -1
+	var refs []k8s2.ObjectRef
+	for _, s := range secrets {
+		refs = append(refs, s.GetK8sRef())
+		labels := s.GetK8sLabels()
+		if labels["status"] != release.StatusDeployed.String() {
+			continue
+		}
+		v, err := strconv.Atoi(labels["version"])
+		if err != nil {
+			continue
+		}
+		if v > deployedVersion {
+			deployed = s
+			deployedVersion = v
+		}
+	}
+	if deployed == nil {
+		return nil, nil, fmt.Errorf("no deployed Helm release with name %s found in namespace %s", name, namespace)
+	}
+
+	rls, err := decodeHelmReleaseSecret(deployed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rls, refs, nil
+}
@@ -0,0 +1,63 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"testing"
+)
+
+func encodeTestRelease(t *testing.T, rls *release.Release) string {
+	b, err := json.Marshal(rls)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(b)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// Helm encodes the release with base64 and Kubernetes encodes the secret data with base64 again
+	s := base64.StdEncoding.EncodeToString(buf.Bytes())
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestDecodeHelmReleaseSecret(t *testing.T) {
+	rls := &release.Release{
+		Name:      "redis",
+		Namespace: "ns",
+		Version:   3,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "redis", Version: "1.2.3"},
+		},
+		Config:   map[string]any{"replicas": float64(2)},
+		Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+	}
+
+	s := uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "sh.helm.release.v1.redis.v3", "namespace": "ns"},
+		"data":       map[string]any{"release": encodeTestRelease(t, rls)},
+	})
+
+	decoded, err := decodeHelmReleaseSecret(s)
+	assert.NoError(t, err)
+	assert.Equal(t, rls.Name, decoded.Name)
+	assert.Equal(t, rls.Version, decoded.Version)
+	assert.Equal(t, rls.Chart.Metadata, decoded.Chart.Metadata)
+	assert.Equal(t, rls.Config, decoded.Config)
+	assert.Equal(t, rls.Manifest, decoded.Manifest)
+
+	_, err = decodeHelmReleaseSecret(uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "s", "namespace": "ns"},
+	}))
+	assert.ErrorContains(t, err, "does not contain a Helm release")
+}
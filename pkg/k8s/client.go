@@ -54,12 +54,18 @@ func newK8sClients(k *K8sCluster, count int) (*k8sClients, error) {
 	return kc, nil
 }
 
+// buildClientConfig returns a copy of the cluster's rest config with the client settings used for all clients
+func (k *K8sCluster) buildClientConfig() *rest.Config {
+	config := rest.CopyConfig(k.config)
+	config.QPS = 10
+	config.Burst = 20
+	return config
+}
+
 func (kc *k8sClients) newClientEntry() (*parallelClientEntry, error) {
 	p := &parallelClientEntry{}
 
-	p.config = rest.CopyConfig(kc.k.config)
-	p.config.QPS = 10
-	p.config.Burst = 20
+	p.config = kc.k.buildClientConfig()
 	p.config.WarningHandler = p
 
 	var err error
@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"k8s.io/client-go/rest"
+	"sync"
+	"time"
+)
+
+// maxPooledClusterAge limits how long clients and discovery information are reused. After that, everything is
+// re-created so that changes to the cluster (e.g. API server upgrades) are eventually picked up.
+const maxPooledClusterAge = time.Minute * 10
+
+// ClusterPool allows to reuse clients, the discovery client and the REST mapper for all targets that are deployed to
+// the same cluster with the same credentials. Without pooling, every target would re-create all clients and re-discover
+// all APIs, which becomes a noticeable constant cost when many targets are handled in the same process.
+type ClusterPool struct {
+	pool sync.Map
+}
+
+type clusterPoolEntry struct {
+	m        sync.Mutex
+	current  *pooledCluster
+	lastUsed time.Time
+	// evicted is set when the entry got removed from the pool. Get must not use such entries anymore.
+	evicted bool
+}
+
+// pooledCluster tracks how many users a pooled K8sCluster has. Its clients are closed when it got replaced by a newer
+// one and the last user released it.
+type pooledCluster struct {
+	k     *K8sCluster
+	time  time.Time
+	refs  int
+	stale bool
+}
+
+// Get returns a K8sCluster for the given config. If a K8sCluster for the same cluster and credentials was requested
+// before, the returned K8sCluster shares clients, discovery and REST mapper with it. The returned release function
+// must be called when the K8sCluster is not used anymore.
+func (p *ClusterPool) Get(ctx context.Context, config *rest.Config, dryRun bool) (*K8sCluster, func(), error) {
+	h, ok, err := p.buildHash(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		// can't pool configs with custom dialers/proxies as we can't compare them
+		k, err := newK8sClusterFromConfig(ctx, config, dryRun)
+		if err != nil {
+			return nil, nil, err
+		}
+		var once sync.Once
+		return k, func() { once.Do(k.close) }, nil
+	}
+
+	p.evictUnused(time.Now())
+
+	var pe *clusterPoolEntry
+	for {
+		pe1, _ := p.pool.LoadOrStore(h, &clusterPoolEntry{})
+		pe, _ = pe1.(*clusterPoolEntry)
+		pe.m.Lock()
+		if !pe.evicted {
+			break
+		}
+		// got evicted in-between, retry with a fresh entry
+		pe.m.Unlock()
+	}
+	defer pe.m.Unlock()
+
+	if pe.current == nil || time.Now().Sub(pe.current.time) > maxPooledClusterAge {
+		k, err := newK8sClusterFromConfig(ctx, config, dryRun)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pe.current != nil {
+			// the old clients might still be in use, so they are only closed when the last user releases them
+			pe.current.stale = true
+			pe.current.closeIfUnused()
+		}
+		pe.current = &pooledCluster{
+			k:    k,
+			time: time.Now(),
+		}
+	}
+
+	pc := pe.current
+	pc.refs++
+	pe.lastUsed = time.Now()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			pe.m.Lock()
+			defer pe.m.Unlock()
+			pc.refs--
+			pe.lastUsed = time.Now()
+			pc.closeIfUnused()
+		})
+	}
+	return pc.k.withContext(ctx, dryRun), release, nil
+}
+
+// evictUnused removes and closes all entries that have not been used for longer than maxPooledClusterAge. Without
+// this, entries for old credentials (e.g. after a credential rotation) would stay alive forever.
+func (p *ClusterPool) evictUnused(now time.Time) {
+	p.pool.Range(func(key, value any) bool {
+		pe := value.(*clusterPoolEntry)
+		pe.m.Lock()
+		defer pe.m.Unlock()
+		if pe.evicted || (pe.current != nil && pe.current.refs != 0) || now.Sub(pe.lastUsed) <= maxPooledClusterAge {
+			return true
+		}
+		pe.evicted = true
+		p.pool.CompareAndDelete(key, pe)
+		if pe.current != nil {
+			pe.current.stale = true
+			pe.current.closeIfUnused()
+		}
+		return true
+	})
+}
+
+func (pc *pooledCluster) closeIfUnused() {
+	if pc.stale && pc.refs == 0 {
+		pc.k.close()
+	}
+}
+
+func newK8sClusterFromConfig(ctx context.Context, config *rest.Config, dryRun bool) (*K8sCluster, error) {
+	discovery, mapper, err := CreateDiscoveryAndMapper(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewK8sCluster(ctx, config, discovery, mapper, dryRun)
+}
+
+// withContext returns a copy of the K8sCluster that shares clients, discovery and mapper with the original one but
+// uses the given context and dryRun setting. The CRD cache is not shared, as it is only valid for a single run.
+func (k *K8sCluster) withContext(ctx context.Context, dryRun bool) *K8sCluster {
+	k2 := *k
+	k2.ctx = ctx
+	k2.DryRun = dryRun
+	k2.crdCache = map[k8s.ObjectRef]any{}
+	k2.crdCacheMutex = &sync.Mutex{}
+	return &k2
+}
+
+func (p *ClusterPool) buildHash(config *rest.Config) (string, bool, error) {
+	if config.Proxy != nil || config.Dial != nil || config.WrapTransport != nil || config.Transport != nil ||
+		(config.ExecProvider != nil && config.ExecProvider.Config != nil) {
+		return "", false, nil
+	}
+
+	// only include what identifies the cluster and the credentials
+	x := map[string]any{
+		"host":            config.Host,
+		"apiPath":         config.APIPath,
+		"username":        config.Username,
+		"password":        config.Password,
+		"bearerToken":     config.BearerToken,
+		"bearerTokenFile": config.BearerTokenFile,
+		"impersonate":     config.Impersonate,
+		"authProvider":    config.AuthProvider,
+		"execProvider":    config.ExecProvider,
+		"userAgent":       config.UserAgent,
+		"timeout":         config.Timeout,
+		"tls": map[string]any{
+			"insecure":   config.Insecure,
+			"serverName": config.ServerName,
+			"certFile":   config.CertFile,
+			"keyFile":    config.KeyFile,
+			"caFile":     config.CAFile,
+			"certData":   config.CertData,
+			"keyData":    config.KeyData,
+			"caData":     config.CAData,
+			"nextProtos": config.NextProtos,
+		},
+	}
+	b, err := json.Marshal(x)
+	if err != nil {
+		return "", false, err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), true, nil
+}
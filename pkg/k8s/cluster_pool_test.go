@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClusterPoolHash(t *testing.T) {
+	var p ClusterPool
+
+	c1 := &rest.Config{Host: "https://cluster1", BearerToken: "token1"}
+	h1, ok, err := p.buildHash(c1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// QPS and friends are overridden per client anyway, so they don't matter
+	c2 := rest.CopyConfig(c1)
+	c2.QPS = 100
+	h2, _, _ := p.buildHash(c2)
+	assert.Equal(t, h1, h2)
+
+	c2 = rest.CopyConfig(c1)
+	c2.BearerToken = "token2"
+	h2, _, _ = p.buildHash(c2)
+	assert.NotEqual(t, h1, h2)
+
+	c2 = rest.CopyConfig(c1)
+	c2.Impersonate.UserName = "system:serviceaccount:ns:sa"
+	h2, _, _ = p.buildHash(c2)
+	assert.NotEqual(t, h1, h2)
+
+	c2 = rest.CopyConfig(c1)
+	c2.Host = "https://cluster2"
+	h2, _, _ = p.buildHash(c2)
+	assert.NotEqual(t, h1, h2)
+
+	c2 = rest.CopyConfig(c1)
+	c2.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, nil
+	}
+	_, ok, err = p.buildHash(c2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPooledClusterClose(t *testing.T) {
+	pc := &pooledCluster{
+		k: &K8sCluster{clients: &k8sClients{
+			clientPool: make(chan *parallelClientEntry, 1),
+			count:      1,
+		}},
+		refs: 1,
+	}
+	pc.k.clients.clientPool <- &parallelClientEntry{httpClient: &http.Client{}}
+
+	// the current cluster is never closed, even if unused
+	pc.refs--
+	pc.closeIfUnused()
+	assert.NotNil(t, pc.k.clients.clientPool)
+
+	// stale clusters are only closed after the last user released them
+	pc.refs++
+	pc.stale = true
+	pc.closeIfUnused()
+	assert.NotNil(t, pc.k.clients.clientPool)
+	pc.refs--
+	pc.closeIfUnused()
+	assert.Nil(t, pc.k.clients.clientPool)
+}
+
+func TestClusterPoolEvictUnused(t *testing.T) {
+	var p ClusterPool
+
+	newEntry := func(refs int, lastUsed time.Time) *clusterPoolEntry {
+		pe := &clusterPoolEntry{
+			current: &pooledCluster{
+				k: &K8sCluster{clients: &k8sClients{
+					clientPool: make(chan *parallelClientEntry, 1),
+					count:      1,
+				}},
+				refs: refs,
+			},
+			lastUsed: lastUsed,
+		}
+		pe.current.k.clients.clientPool <- &parallelClientEntry{httpClient: &http.Client{}}
+		return pe
+	}
+
+	now := time.Now()
+	recent := newEntry(0, now.Add(-time.Minute))
+	old := newEntry(0, now.Add(-maxPooledClusterAge-time.Minute))
+	oldInUse := newEntry(1, now.Add(-maxPooledClusterAge-time.Minute))
+	p.pool.Store("recent", recent)
+	p.pool.Store("old", old)
+	p.pool.Store("oldInUse", oldInUse)
+
+	p.evictUnused(now)
+
+	_, ok := p.pool.Load("recent")
+	assert.True(t, ok)
+	assert.NotNil(t, recent.current.k.clients.clientPool)
+
+	_, ok = p.pool.Load("old")
+	assert.False(t, ok)
+	assert.True(t, old.evicted)
+	assert.Nil(t, old.current.k.clients.clientPool)
+
+	_, ok = p.pool.Load("oldInUse")
+	assert.True(t, ok)
+	assert.NotNil(t, oldInUse.current.k.clients.clientPool)
+}
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/kluctl/kluctl/lib/envutils"
 	"github.com/kluctl/kluctl/lib/status"
@@ -23,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -39,7 +41,8 @@ type K8sCluster struct {
 	mapper         meta.RESTMapper
 	discoveryMutex *sync.Mutex
 
-	clients *k8sClients
+	clients  *k8sClients
+	throttle *throttleTracker
 
 	ServerVersion *version.Info
 
@@ -54,10 +57,17 @@ func NewK8sCluster(ctx context.Context,
 	dryRun bool) (*K8sCluster, error) {
 	var err error
 
+	// all clients created from this config share the same throttle tracker, so that a Retry-After returned to one
+	// client also delays requests of all other clients
+	throttle := newThrottleTracker()
+	config = rest.CopyConfig(config)
+	config.Wrap(throttle.wrap)
+
 	k := &K8sCluster{
 		ctx:            ctx,
 		DryRun:         dryRun,
 		config:         config,
+		throttle:       throttle,
 		discovery:      discovery,
 		mapper:         mapper,
 		discoveryMutex: &sync.Mutex{},
@@ -79,6 +89,11 @@ func NewK8sCluster(ctx context.Context,
 	return k, nil
 }
 
+// GetThrottleSummary returns a summary of all throttled requests, e.g. caused by API Priority and Fairness.
+func (k *K8sCluster) GetThrottleSummary() ThrottleSummary {
+	return k.throttle.summary()
+}
+
 func (k *K8sCluster) ReadWrite() *K8sCluster {
 	k2 := *k
 	k2.DryRun = false
@@ -98,9 +113,10 @@ func (k *K8sCluster) GetClusterId() (string, error) {
 	return clusterId, nil
 }
 
-func (k *K8sCluster) doList(l client.ObjectList, namespace string, labels map[string]string) ([]*uo.UnstructuredObject, []ApiWarning, error) {
+func (k *K8sCluster) doList(l client.ObjectList, namespace string, labels map[string]string, opts ...client.ListOption) ([]*uo.UnstructuredObject, []ApiWarning, error) {
+	opts = append([]client.ListOption{client.InNamespace(namespace), client.MatchingLabels(labels)}, opts...)
 	apiWarnings, err := k.clients.withCClientFromPool(k.ctx, true, func(c client.Client) error {
-		return c.List(k.ctx, l, client.InNamespace(namespace), client.MatchingLabels(labels))
+		return c.List(k.ctx, l, opts...)
 	})
 	if err != nil {
 		return nil, apiWarnings, err
@@ -140,6 +156,28 @@ func (k *K8sCluster) ListMetadata(gvk schema.GroupVersionKind, namespace string,
 	return k.doList(&l, namespace, labels)
 }
 
+// ListMetadataWithResourceVersion is like ListMetadata, but also returns the resourceVersion of the list. This allows to
+// refer to exactly the returned snapshot in later requests.
+func (k *K8sCluster) ListMetadataWithResourceVersion(gvk schema.GroupVersionKind, namespace string, labels map[string]string) ([]*uo.UnstructuredObject, string, []ApiWarning, error) {
+	var l v1.PartialObjectMetadataList
+	gvk.Kind += "List"
+	l.SetGroupVersionKind(gvk)
+	ret, apiWarnings, err := k.doList(&l, namespace, labels)
+	if err != nil {
+		return nil, "", apiWarnings, err
+	}
+	return ret, l.GetResourceVersion(), apiWarnings, nil
+}
+
+// ListMetadataLimit is like ListMetadata, but lets the server return at most limit objects. This is useful to cheaply
+// check if any objects exist at all.
+func (k *K8sCluster) ListMetadataLimit(gvk schema.GroupVersionKind, namespace string, labels map[string]string, limit int64) ([]*uo.UnstructuredObject, []ApiWarning, error) {
+	var l v1.PartialObjectMetadataList
+	gvk.Kind += "List"
+	l.SetGroupVersionKind(gvk)
+	return k.doList(&l, namespace, labels, client.Limit(limit))
+}
+
 func (k *K8sCluster) doGet(ref k8s.ObjectRef, o client.Object) ([]ApiWarning, error) {
 	o.SetName(ref.Name)
 	o.SetNamespace(ref.Namespace)
@@ -211,6 +249,55 @@ func (k *K8sCluster) DeleteSingleObject(ref k8s.ObjectRef, options DeleteOptions
 	return apiWarnings, nil
 }
 
+// DeleteObjectsByLabels deletes all objects of the given GVK and namespace that match the given labels with a single
+// deleteCollection request. The passed refs are the objects that are expected to be deleted by this request and are
+// used to invalidate caches and to wait for the deletion to finish. If resourceVersion is not empty, the server only
+// deletes the objects that matched at exactly this resourceVersion, so that objects created in the meantime are kept.
+func (k *K8sCluster) DeleteObjectsByLabels(gvk schema.GroupVersionKind, namespace string, labels map[string]string, resourceVersion string, refs []k8s.ObjectRef, options DeleteOptions) ([]ApiWarning, error) {
+	dryRun := k.DryRun || options.ForceDryRun
+
+	k.crdCacheMutex.Lock()
+	for _, ref := range refs {
+		delete(k.crdCache, ref)
+	}
+	k.crdCacheMutex.Unlock()
+
+	var o unstructured.Unstructured
+	o.SetGroupVersionKind(gvk)
+
+	opts := []client.DeleteAllOfOption{
+		client.MatchingLabels(labels),
+		client.PropagationPolicy(v1.DeletePropagationBackground),
+	}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if resourceVersion != "" {
+		opts = append(opts, &client.DeleteAllOfOptions{ListOptions: client.ListOptions{Raw: &v1.ListOptions{
+			ResourceVersion:      resourceVersion,
+			ResourceVersionMatch: v1.ResourceVersionMatchExact,
+		}}})
+	}
+
+	apiWarnings, err := k.clients.withCClientFromPool(k.ctx, dryRun, func(c client.Client) error {
+		return c.DeleteAllOf(k.ctx, &o, opts...)
+	})
+	if err != nil {
+		return apiWarnings, err
+	}
+
+	if !dryRun && !options.NoWait {
+		for _, ref := range refs {
+			err = k.waitForDeletedObject(ref)
+			if err != nil {
+				return apiWarnings, err
+			}
+		}
+	}
+
+	return apiWarnings, nil
+}
+
 func (k *K8sCluster) waitForDeletedObject(ref k8s.ObjectRef) error {
 	for true {
 		_, _, err := k.GetSingleObject(ref)
@@ -360,6 +447,51 @@ func (k *K8sCluster) ApplyObject(o *uo.UnstructuredObject, options PatchOptions)
 	return uo.FromUnstructured(obj), apiWarnings, nil
 }
 
+// MergePatchObject applies the given JSON merge patch to the object referenced by ref. Setting a field to nil inside the
+// patch removes the field.
+func (k *K8sCluster) MergePatchObject(ref k8s.ObjectRef, patch map[string]any, options PatchOptions) (*uo.UnstructuredObject, []ApiWarning, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(ref.GroupVersionKind())
+	obj.SetName(ref.Name)
+	obj.SetNamespace(ref.Namespace)
+	apiWarnings, err := k.doPatch(ref, obj, client.RawPatch(types.MergePatchType, data), options)
+	if err != nil {
+		return nil, apiWarnings, err
+	}
+	return uo.FromUnstructured(obj), apiWarnings, nil
+}
+
+type CreateOptions struct {
+	ForceDryRun bool
+}
+
+// CreateObject creates the given object via POST. This is required for objects that use metadata.generateName, as
+// these can not be server-side applied. The returned object contains the name that was generated by the API server.
+func (k *K8sCluster) CreateObject(o *uo.UnstructuredObject, options CreateOptions) (*uo.UnstructuredObject, []ApiWarning, error) {
+	ref := o.GetK8sRef()
+	obj := o.Clone().ToUnstructured()
+
+	status.Tracef(k.ctx, "creating %s with generateName %s", ref.String(), o.GetK8sGenerateName())
+
+	var opts []client.CreateOption
+	if options.ForceDryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+	opts = append(opts, client.FieldOwner("kluctl"))
+
+	apiWarnings, err := k.clients.withCClientFromPool(k.ctx, k.DryRun, func(c client.Client) error {
+		return c.Create(k.ctx, obj, opts...)
+	})
+	if err != nil {
+		return nil, apiWarnings, err
+	}
+	return uo.FromUnstructured(obj), apiWarnings, nil
+}
+
 type UpdateOptions struct {
 	ForceDryRun bool
 }
@@ -556,3 +688,20 @@ func (k *K8sCluster) ToClient() (client.Client, error) {
 	}
 	return p.client, nil
 }
+
+// close releases all pooled clients. It must only be called when the K8sCluster and all copies returned by withContext
+// are not used anymore.
+func (k *K8sCluster) close() {
+	k.clients.close()
+}
+
+// ToClientWithWatch returns a new client that is able to watch objects. Watches are long-running, so the returned client
+// is not part of the client pool.
+func (k *K8sCluster) ToClientWithWatch() (client.WithWatch, error) {
+	return client.NewWithWatch(k.buildClientConfig(), client.Options{
+		Mapper: k.mapper,
+		WarningHandler: client.WarningHandlerOptions{
+			SuppressWarnings: true,
+		},
+	})
+}
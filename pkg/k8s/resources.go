@@ -48,7 +48,7 @@ func (k *K8sCluster) doGetApiGroupResources() ([]*restmapper.APIGroupResources,
 	return ret, nil
 }
 
-func (k *K8sCluster) filterResource(ar *v1.APIResource) bool {
+func (k *K8sCluster) filterResource(ar *v1.APIResource, includeDeprecated bool) bool {
 	if strings.Index(ar.Name, "/") != -1 {
 		// skip sub-resources
 		return false
@@ -58,7 +58,7 @@ func (k *K8sCluster) filterResource(ar *v1.APIResource) bool {
 		Group:    ar.Group,
 		Resource: ar.Name,
 	}
-	if _, ok := deprecatedResources[gr]; ok {
+	if _, ok := deprecatedResources[gr]; ok && !includeDeprecated {
 		return false
 	}
 
@@ -66,6 +66,16 @@ func (k *K8sCluster) filterResource(ar *v1.APIResource) bool {
 }
 
 func (k *K8sCluster) GetAllAPIResources() ([]v1.APIResource, error) {
+	return k.getAllAPIResources(false)
+}
+
+// GetAllServedAPIResources is like GetAllAPIResources, but also includes the deprecated resources that kluctl
+// otherwise ignores (e.g. extensions/v1beta1 Ingresses).
+func (k *K8sCluster) GetAllServedAPIResources() ([]v1.APIResource, error) {
+	return k.getAllAPIResources(true)
+}
+
+func (k *K8sCluster) getAllAPIResources(includeDeprecated bool) ([]v1.APIResource, error) {
 	agrs, err := k.doGetApiGroupResources()
 	if err != nil {
 		return nil, err
@@ -79,7 +89,7 @@ func (k *K8sCluster) GetAllAPIResources() ([]v1.APIResource, error) {
 				ar.Group = agr.Group.Name
 				ar.Version = v
 
-				if !k.filterResource(&ar) {
+				if !k.filterResource(&ar, includeDeprecated) {
 					continue
 				}
 				ret = append(ret, ar)
@@ -105,7 +115,7 @@ func (k *K8sCluster) GetAllPreferredAPIResources() ([]v1.APIResource, error) {
 				ar.Group = agr.Group.Name
 				ar.Version = v
 
-				if !k.filterResource(&ar) {
+				if !k.filterResource(&ar, false) {
 					continue
 				}
 
@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter limits how long a single Retry-After header can delay requests, so that a misbehaving server can't
+// block us forever.
+const maxRetryAfter = 60 * time.Second
+
+// ThrottleSummary summarizes how often the API server asked us to back off, e.g. due to API Priority and Fairness.
+type ThrottleSummary struct {
+	// Count is the number of throttled (429) responses that were received
+	Count int
+	// TotalBackoff is the accumulated time that requests were delayed because of throttling
+	TotalBackoff time.Duration
+	// PriorityLevels contains the UIDs of the priority levels (as reported by API Priority and Fairness) which caused
+	// the throttling
+	PriorityLevels []string
+}
+
+// throttleTracker is shared by all clients of a K8sCluster. When the API server responds with 429 and a Retry-After
+// header, all clients back off until the given time instead of only the client that received the response. This
+// avoids hammering an already overloaded API server from all parallel clients.
+type throttleTracker struct {
+	mutex          sync.Mutex
+	backoffUntil   time.Time
+	count          int
+	totalBackoff   time.Duration
+	priorityLevels map[string]bool
+}
+
+func newThrottleTracker() *throttleTracker {
+	return &throttleTracker{
+		priorityLevels: map[string]bool{},
+	}
+}
+
+func (t *throttleTracker) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &throttleRoundTripper{t: t, next: rt}
+}
+
+func (t *throttleTracker) getBackoff() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return time.Until(t.backoffUntil)
+}
+
+func (t *throttleTracker) addBackoff(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.totalBackoff += d
+}
+
+func (t *throttleTracker) handleThrottled(resp *http.Response) {
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.count++
+	if pl := resp.Header.Get("X-Kubernetes-PF-PriorityLevel-UID"); pl != "" {
+		t.priorityLevels[pl] = true
+	}
+	until := time.Now().Add(retryAfter)
+	if until.After(t.backoffUntil) {
+		t.backoffUntil = until
+	}
+}
+
+func (t *throttleTracker) summary() ThrottleSummary {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s := ThrottleSummary{
+		Count:        t.count,
+		TotalBackoff: t.totalBackoff,
+	}
+	for pl := range t.priorityLevels {
+		s.PriorityLevels = append(s.PriorityLevels, pl)
+	}
+	return s
+}
+
+func parseRetryAfter(v string) time.Duration {
+	// client-go uses 1 second if the header is missing or invalid, so we do the same
+	d := time.Second
+	if v != "" {
+		if i, err := strconv.Atoi(v); err == nil && i >= 0 {
+			d = time.Duration(i) * time.Second
+		}
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d
+}
+
+type throttleRoundTripper struct {
+	t    *throttleTracker
+	next http.RoundTripper
+}
+
+func (rt *throttleRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if d := rt.t.getBackoff(); d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+			rt.t.addBackoff(d)
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rt.t.handleThrottled(resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Second, parseRetryAfter(""))
+	assert.Equal(t, time.Second, parseRetryAfter("invalid"))
+	assert.Equal(t, 0*time.Second, parseRetryAfter("0"))
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, maxRetryAfter, parseRetryAfter("3600"))
+}
+
+func TestThrottleRoundTripper(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("X-Kubernetes-PF-PriorityLevel-UID", "pl1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tt := newThrottleTracker()
+	c := &http.Client{Transport: tt.wrap(http.DefaultTransport)}
+
+	resp, err := c.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	// the second request must wait for the Retry-After from the first request
+	start := time.Now()
+	resp, err = c.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+
+	s := tt.summary()
+	assert.Equal(t, 1, s.Count)
+	assert.Greater(t, s.TotalBackoff, time.Duration(0))
+	assert.Equal(t, []string{"pl1"}, s.PriorityLevels)
+}
@@ -0,0 +1,30 @@
+package kluctl_jinja2
+
+import (
+	"context"
+	"github.com/kluctl/go-jinja2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClusterFunctions(t *testing.T) {
+	j2, err := NewKluctlJinja2(context.Background(), true, false)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	globals := map[string]any{
+		"_kluctl_cluster": map[string]any{
+			"version": "1.28.3",
+			"apis":    []any{"/v1/ConfigMap", "policy/v1/PodDisruptionBudget"},
+		},
+	}
+
+	r, err := j2.RenderString(`{{ cluster_version() }} {{ cluster_has_api("policy", "v1", "PodDisruptionBudget") }} {{ cluster_has_api("policy", "v1beta1", "PodDisruptionBudget") }} {{ cluster_has_api("", "v1", "ConfigMap") }}`, jinja2.WithGlobals(globals))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.28.3 True False True", r)
+
+	// no cluster info available, e.g. in offline mode
+	r, err = j2.RenderString(`"{{ cluster_version() }}" {{ cluster_has_api("", "v1", "ConfigMap") }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `"" False`, r)
+}
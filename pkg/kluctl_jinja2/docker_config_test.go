@@ -0,0 +1,38 @@
+package kluctl_jinja2
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/kluctl/go-jinja2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDockerConfigJson(t *testing.T) {
+	j2, err := NewKluctlJinja2(context.Background(), true, false)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	r, err := j2.RenderString(`{{ docker_config_json(a, b) }}`, jinja2.WithGlobals(map[string]any{
+		"a": map[string]any{
+			"ghcr.io":   map[string]any{"username": "u1", "password": "p1"},
+			"docker.io": map[string]any{"username": "u2", "password": "p2"},
+		},
+		"b": []any{
+			map[string]any{"registry": "docker.io", "auth": "dTM6cDM=", "email": "a@b.c"},
+		},
+	}))
+	assert.NoError(t, err)
+
+	var c map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(r), &c))
+	assert.Equal(t, map[string]any{
+		"auths": map[string]any{
+			"ghcr.io":   map[string]any{"username": "u1", "password": "p1", "auth": "dTE6cDE="},
+			"docker.io": map[string]any{"auth": "dTM6cDM=", "email": "a@b.c"},
+		},
+	}, c)
+
+	_, err = j2.RenderString(`{{ docker_config_json({"ghcr.io": {"username": "u1"}}) }}`)
+	assert.ErrorContains(t, err, "both username and password must be set for registry ghcr.io")
+}
@@ -1,3 +1,9 @@
 from .images_ext import ImagesExtension
+from .docker_config_ext import DockerConfigExtension
+from .files_ext import FilesExtension
+from .cluster_ext import ClusterExtension
 
 images = ImagesExtension
+docker_config = DockerConfigExtension
+files = FilesExtension
+cluster = ClusterExtension
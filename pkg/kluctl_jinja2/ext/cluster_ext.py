@@ -0,0 +1,38 @@
+import jinja2
+from jinja2.ext import Extension
+
+
+class ClusterExtension(Extension):
+    def __init__(self, environment):
+        super().__init__(environment)
+        environment.globals["cluster_has_api"] = cluster_has_api
+        environment.globals["cluster_version"] = cluster_version
+
+
+def _get_cluster_info(ctx):
+    # passed by kluctl as internal global (not as part of the vars), filled from the discovery information of the
+    # target cluster
+    info = ctx.get("_kluctl_cluster")
+    if not isinstance(info, dict):
+        return {}
+    return info
+
+
+@jinja2.pass_context
+def cluster_has_api(ctx, group, version, kind):
+    """
+    Returns true if the target cluster serves the given group/version/kind. Pass an empty string as group for the core
+    API. Always returns false if no cluster is available, e.g. when rendering in offline mode.
+    """
+    apis = _get_cluster_info(ctx).get("apis") or []
+    return "%s/%s/%s" % (group or "", version, kind) in apis
+
+
+@jinja2.pass_context
+def cluster_version(ctx):
+    """
+    Returns the Kubernetes version of the target cluster without the leading "v" and without distribution specific
+    suffixes, e.g. "1.28.3" for "v1.28.3+k3s1". When rendering in
+    offline mode, the version passed via --kubernetes-version is returned, or an empty string if none was passed.
+    """
+    return _get_cluster_info(ctx).get("version") or ""
@@ -0,0 +1,60 @@
+import base64
+import json
+
+from jinja2.ext import Extension
+
+
+class DockerConfigExtension(Extension):
+    def __init__(self, environment):
+        super().__init__(environment)
+        environment.globals["docker_config_json"] = docker_config_json
+
+
+def _add_registry(auths, registry, creds):
+    if not registry:
+        raise ValueError("registry must not be empty")
+    if not isinstance(creds, dict):
+        raise ValueError("credentials for registry %s must be a dictionary" % registry)
+
+    e = {}
+    username = creds.get("username")
+    password = creds.get("password")
+    auth = creds.get("auth")
+    if username is not None or password is not None:
+        if username is None or password is None:
+            raise ValueError("both username and password must be set for registry %s" % registry)
+        e["username"] = str(username)
+        e["password"] = str(password)
+        if auth is None:
+            auth = base64.b64encode(("%s:%s" % (username, password)).encode("utf8")).decode("utf8")
+    if auth is None:
+        raise ValueError("either username/password or auth must be set for registry %s" % registry)
+    e["auth"] = auth
+    if creds.get("email") is not None:
+        e["email"] = str(creds["email"])
+
+    auths[registry] = e
+
+
+def docker_config_json(*registries):
+    """
+    Builds the content of a .dockerconfigjson from the given registry credentials. Each argument is either a
+    dictionary mapping registry hosts to credentials or a list of credentials that contain the registry host in the
+    "registry" field. Credentials can contain "username", "password", "auth" and "email". If the same registry is
+    specified multiple times, the last one wins.
+    """
+    auths = {}
+    for r in registries:
+        if r is None:
+            continue
+        if isinstance(r, dict):
+            for registry, creds in r.items():
+                _add_registry(auths, registry, creds)
+        elif isinstance(r, (list, tuple)):
+            for creds in r:
+                if not isinstance(creds, dict):
+                    raise ValueError("registry credentials must be dictionaries")
+                _add_registry(auths, creds.get("registry"), creds)
+        else:
+            raise ValueError("registry credentials must be passed as dictionary or list")
+    return json.dumps({"auths": auths}, sort_keys=True)
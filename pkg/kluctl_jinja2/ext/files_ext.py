@@ -0,0 +1,86 @@
+import base64
+import glob
+import os
+import posixpath
+import textwrap
+
+import jinja2
+from jinja2 import TemplateNotFound
+from jinja2.ext import Extension
+from jinja2.loaders import split_template_path
+
+
+class FilesExtension(Extension):
+    def __init__(self, environment):
+        super().__init__(environment)
+        environment.globals["read_file_b64"] = read_file_b64
+        environment.globals["file_glob"] = file_glob
+
+
+def _is_sandboxed(environment, p):
+    # resolve symlinks, so that links pointing outside of the search dirs can't be used to escape the sandbox
+    rp = os.path.realpath(p)
+    for s in environment.loader.searchpath:
+        rs = os.path.realpath(s)
+        if rp == rs or rp.startswith(rs + os.path.sep):
+            return True
+    return False
+
+
+def _join_path(ctx, path):
+    p = path.replace(os.path.sep, '/')
+    if ctx.name:
+        p = ctx.environment.join_path(p, ctx.name)
+    return p
+
+
+@jinja2.pass_context
+def read_file_b64(ctx, path, width=None):
+    """
+    Reads the given file as binary and returns its base64 representation. The path is resolved the same way as in
+    load_template, which means that only files inside the search dirs (e.g. the project root) can be read.
+    """
+    ctx.environment.print_debug("read_file_b64(%s)" % path)
+    p = ctx.environment.loader._find_path(_join_path(ctx, path))
+    if not p or not _is_sandboxed(ctx.environment, p):
+        raise TemplateNotFound(path)
+
+    contents, _, _ = ctx.environment.loader.read_template_helper(ctx.environment, p, True)
+
+    contents = base64.b64encode(contents).decode("utf-8")
+    if width:
+        contents = textwrap.fill(contents, width=width)
+    return contents
+
+
+@jinja2.pass_context
+def file_glob(ctx, pattern):
+    """
+    Returns a sorted list of all files that match the given glob pattern. The pattern is resolved the same way as in
+    load_template and only files inside the search dirs are returned. The returned paths are relative to the search
+    dir they were found in and can be passed to load_template, read_file_b64 and friends.
+    """
+    ctx.environment.print_debug("file_glob(%s)" % pattern)
+    p = _join_path(ctx, pattern)
+
+    searchpath = ctx.environment.loader.searchpath
+    patterns = []
+    if os.path.isabs(p):
+        ap = os.path.abspath(p)
+        for s in searchpath:
+            if ap.startswith(os.path.abspath(s) + os.path.sep):
+                patterns.append((s, ap))
+    else:
+        # this raises TemplateNotFound for ".." segments
+        pieces = split_template_path(p)
+        for s in searchpath:
+            patterns.append((s, posixpath.join(s, *pieces)))
+
+    result = set()
+    for s, gp in patterns:
+        for f in glob.glob(gp, recursive=True):
+            if not os.path.isfile(f) or not _is_sandboxed(ctx.environment, f):
+                continue
+            rel = os.path.relpath(f, s).replace(os.path.sep, '/')
+            result.add(rel)
+    return sorted(result)
@@ -0,0 +1,50 @@
+package kluctl_jinja2
+
+import (
+	"context"
+	"github.com/kluctl/go-jinja2"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadFileB64AndFileGlob(t *testing.T) {
+	j2, err := NewKluctlJinja2(context.Background(), true, false)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "certs", "sub"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "certs", "a.pem"), []byte{0, 1, 2, 3}, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "certs", "b.pem"), []byte("b"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "certs", "sub", "c.pem"), []byte("c"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "certs", "other.txt"), []byte("x"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "secret.pem"), []byte("secret"), 0o600))
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "secret.pem"), filepath.Join(root, "certs", "link.pem")))
+
+	r, err := j2.RenderString(`{{ read_file_b64("certs/a.pem") }}`, jinja2.WithSearchDir(root))
+	assert.NoError(t, err)
+	assert.Equal(t, "AAECAw==", r)
+
+	r, err = j2.RenderString(`{{ file_glob("certs/*.pem") | join(",") }}`, jinja2.WithSearchDir(root))
+	assert.NoError(t, err)
+	assert.Equal(t, "certs/a.pem,certs/b.pem", r)
+
+	r, err = j2.RenderString(`{{ file_glob("certs/**/*.pem") | join(",") }}`, jinja2.WithSearchDir(root))
+	assert.NoError(t, err)
+	assert.Equal(t, "certs/a.pem,certs/b.pem,certs/sub/c.pem", r)
+
+	r, err = j2.RenderString(`{% for f in file_glob("certs/*.pem") %}{{ f }}={{ read_file_b64(f) }};{% endfor %}`, jinja2.WithSearchDir(root))
+	assert.NoError(t, err)
+	assert.Equal(t, "certs/a.pem=AAECAw==;certs/b.pem=Yg==;", r)
+
+	// sandboxing
+	_, err = j2.RenderString(`{{ read_file_b64("certs/link.pem") }}`, jinja2.WithSearchDir(root))
+	assert.ErrorContains(t, err, "certs/link.pem not found")
+	_, err = j2.RenderString(`{{ read_file_b64("../secret.pem") }}`, jinja2.WithSearchDir(filepath.Join(root, "certs")))
+	assert.ErrorContains(t, err, "not found")
+	_, err = j2.RenderString(`{{ read_file_b64("`+filepath.ToSlash(filepath.Join(outside, "secret.pem"))+`") }}`, jinja2.WithSearchDir(root))
+	assert.ErrorContains(t, err, "not found")
+}
@@ -40,6 +40,9 @@ func NewKluctlJinja2(ctx context.Context, strict bool, useSystemPython bool) (*x
 		x.WithExtension("go_jinja2.ext.kluctl"),
 		x.WithExtension("go_jinja2.ext.time"),
 		x.WithExtension("ext.images_ext.ImagesExtension"),
+		x.WithExtension("ext.docker_config_ext.DockerConfigExtension"),
+		x.WithExtension("ext.files_ext.FilesExtension"),
+		x.WithExtension("ext.cluster_ext.ClusterExtension"),
 		x.WithPythonPath(extSrc.GetExtractedPath()),
 		x.WithEmbeddedExtractDir(tmpDir),
 	)
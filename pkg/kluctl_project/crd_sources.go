@@ -0,0 +1,236 @@
+package kluctl_project
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/lib/yaml"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"io"
+	"io/fs"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// crdSourceUrlCacheTime is the time after which CRDs loaded from URLs are re-fetched. Cached CRDs are used regardless
+// of their age when running in offline mode.
+const crdSourceUrlCacheTime = 24 * time.Hour
+
+var crdSourceHttpClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// LoadCrdSources loads all CRDs from the crdSources declared in the project config. These CRDs are used to determine
+// the scope of custom resources and to validate them when they are unknown to the target cluster or when running in
+// offline mode. In offline mode, URL and context sources are only loaded from the local cache.
+func (c *LoadedKluctlProject) LoadCrdSources(ctx context.Context, offline bool) ([]*uo.UnstructuredObject, error) {
+	if len(c.Config.CrdSources) == 0 {
+		return nil, nil
+	}
+
+	s := status.Start(ctx, "Loading CRD sources")
+	defer s.Failed()
+
+	var ret []*uo.UnstructuredObject
+	for _, src := range c.Config.CrdSources {
+		var docs []interface{}
+		var err error
+		if src.Path != nil {
+			docs, err = c.loadCrdSourcePath(*src.Path)
+		} else if src.Url != nil {
+			docs, err = loadCrdSourceUrl(ctx, src.Url, offline)
+		} else if src.Context != nil {
+			docs, err = c.loadCrdSourceContext(ctx, *src.Context, offline)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, filterCrds(docs)...)
+	}
+
+	s.UpdateAndInfoFallbackf("Loaded %d CRDs from CRD sources", len(ret))
+	s.Success()
+	return ret, nil
+}
+
+func (c *LoadedKluctlProject) loadCrdSourcePath(p string) ([]interface{}, error) {
+	if filepath.IsAbs(p) {
+		return nil, fmt.Errorf("crdSources path %s must be relative", p)
+	}
+	p = filepath.Join(c.LoadArgs.ProjectDir, p)
+	err := utils.CheckInDir(c.LoadArgs.ProjectDir, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.IsDirectory(p) {
+		docs, err := yaml.ReadYamlAllFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CRDs from %s: %w", p, err)
+		}
+		return docs, nil
+	}
+
+	var ret []interface{}
+	err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (!strings.HasSuffix(path, ".yml") && !strings.HasSuffix(path, ".yaml")) {
+			return nil
+		}
+		docs, err := yaml.ReadYamlAllFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load CRDs from %s: %w", path, err)
+		}
+		ret = append(ret, docs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func loadCrdSourceUrl(ctx context.Context, u *types.YamlUrl, offline bool) ([]interface{}, error) {
+	return loadCachedCrdSource(ctx, u.String(), u.String(), crdSourceUrlCacheTime, offline, func() ([]byte, error) {
+		return downloadCrdSourceUrl(ctx, u)
+	})
+}
+
+// loadCrdSourceContext exports all CRDs from the cluster of the given kubeconfig context. The export is cached so that
+// it can be used in offline mode.
+func (c *LoadedKluctlProject) loadCrdSourceContext(ctx context.Context, contextName string, offline bool) ([]interface{}, error) {
+	name := fmt.Sprintf("context %s", contextName)
+	return loadCachedCrdSource(ctx, name, "context:"+contextName, 0, offline, func() ([]byte, error) {
+		return c.exportCrdsFromContext(ctx, contextName)
+	})
+}
+
+func (c *LoadedKluctlProject) exportCrdsFromContext(ctx context.Context, contextName string) ([]byte, error) {
+	if c.LoadArgs.ClientConfigGetter == nil {
+		return nil, fmt.Errorf("failed to export CRDs from context %s: no kubeconfig available", contextName)
+	}
+	restConfig, _, err := c.LoadArgs.ClientConfigGetter(&contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export CRDs from context %s: %w", contextName, err)
+	}
+	dc, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export CRDs from context %s: %w", contextName, err)
+	}
+	l, err := dc.Resource(apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export CRDs from context %s: %w", contextName, err)
+	}
+
+	docs := make([]interface{}, 0, len(l.Items))
+	for _, x := range l.Items {
+		x.SetManagedFields(nil)
+		docs = append(docs, x.Object)
+	}
+	return yaml.WriteYamlAllBytes(docs)
+}
+
+// loadCachedCrdSource loads CRDs via fetch and caches the result. Cached CRDs are used if they are younger than
+// maxAge, or regardless of their age in offline mode. fetch is never called in offline mode.
+func loadCachedCrdSource(ctx context.Context, name string, cacheKey string, maxAge time.Duration, offline bool, fetch func() ([]byte, error)) ([]interface{}, error) {
+	cachePath := filepath.Join(utils.GetCacheDir(ctx), "crd-sources", utils.Sha256String(cacheKey)+".yaml")
+
+	st, err := os.Stat(cachePath)
+	if err == nil && (offline || time.Since(st.ModTime()) < maxAge) {
+		docs, err := yaml.ReadYamlAllFile(cachePath)
+		if err == nil {
+			return docs, nil
+		}
+		status.Warningf(ctx, "Failed to read cached CRDs for %s: %s", name, err.Error())
+	}
+	if offline {
+		return nil, fmt.Errorf("failed to load CRDs from %s: not available in offline mode and not cached", name)
+	}
+
+	b, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	docs, err := yaml.ReadYamlAllBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRDs from %s: %w", name, err)
+	}
+
+	err = writeCrdSourceCache(cachePath, b)
+	if err != nil {
+		status.Warningf(ctx, "Failed to cache CRDs for %s: %s", name, err.Error())
+	}
+	return docs, nil
+}
+
+func downloadCrdSourceUrl(ctx context.Context, u *types.YamlUrl) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := crdSourceHttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRDs from %s: %w", u.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to load CRDs from %s: http request failed with status code %d", u.String(), resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRDs from %s: %w", u.String(), err)
+	}
+	return b, nil
+}
+
+// writeCrdSourceCache writes the cache file through a temporary file, so that parallel runs never see partial files
+func writeCrdSourceCache(cachePath string, b []byte) error {
+	err := os.MkdirAll(filepath.Dir(cachePath), 0o700)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	_ = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cachePath)
+}
+
+// filterCrds returns all CRDs found in the given documents. Lists (e.g. from `kubectl get crds -o yaml`) are
+// flattened.
+func filterCrds(docs []interface{}) []*uo.UnstructuredObject {
+	var ret []*uo.UnstructuredObject
+	for _, d := range docs {
+		m, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		o := uo.FromMap(m)
+		gk := o.GetK8sGVK().GroupKind()
+		if gk.Group == "" && gk.Kind == "List" {
+			items, _, _ := o.GetNestedList("items")
+			ret = append(ret, filterCrds(items)...)
+		} else if gk.String() == "CustomResourceDefinition.apiextensions.k8s.io" {
+			ret = append(ret, o)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,58 @@
+package kluctl_project
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+const testCrdSource = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.test.kluctl.io
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+
+func TestLoadCrdSourceUrl(t *testing.T) {
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(testCrdSource))
+	}))
+	defer s.Close()
+
+	ctx := utils.WithCacheDir(context.Background(), t.TempDir())
+
+	u, err := url.Parse(s.URL + "/crds.yaml")
+	assert.NoError(t, err)
+	yu := &types.YamlUrl{URL: *u}
+
+	// not cached yet
+	_, err = loadCrdSourceUrl(ctx, yu, true)
+	assert.ErrorContains(t, err, "not available in offline mode")
+	assert.Equal(t, int32(0), requests.Load())
+
+	docs, err := loadCrdSourceUrl(ctx, yu, false)
+	assert.NoError(t, err)
+	assert.Len(t, filterCrds(docs), 1)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// served from the cache
+	docs, err = loadCrdSourceUrl(ctx, yu, false)
+	assert.NoError(t, err)
+	assert.Len(t, filterCrds(docs), 1)
+	docs, err = loadCrdSourceUrl(ctx, yu, true)
+	assert.NoError(t, err)
+	assert.Len(t, filterCrds(docs), 1)
+	assert.Equal(t, int32(1), requests.Load())
+}
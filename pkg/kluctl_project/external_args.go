@@ -6,6 +6,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 )
@@ -27,6 +28,26 @@ func ParseArgs(argsList []string) (map[string]string, error) {
 	return args, nil
 }
 
+// ParseItemArgs parses args in the form of path/to/item:name=value and groups them by deployment item path.
+func ParseItemArgs(argsList []string) (map[string]map[string]string, error) {
+	ret := map[string]map[string]string{}
+	for _, arg := range argsList {
+		s := strings.SplitN(arg, "=", 2)
+		i := strings.LastIndex(s[0], ":")
+		if len(s) != 2 || i <= 0 || !argPattern.MatchString(arg[i+1:]) {
+			return nil, fmt.Errorf("invalid --item-arg argument. Must be --item-arg=path/to/item:some_var_name=value, not '%s'", arg)
+		}
+
+		itemPath := path.Clean(strings.TrimSuffix(s[0][:i], "/"))
+		name := s[0][i+1:]
+		if _, ok := ret[itemPath]; !ok {
+			ret[itemPath] = map[string]string{}
+		}
+		ret[itemPath][name] = s[1]
+	}
+	return ret, nil
+}
+
 func ConvertArgsToVars(args map[string]string, allowLoadFromFiles bool) (*uo.UnstructuredObject, error) {
 	vars := uo.New()
 	for n, v := range args {
@@ -96,3 +117,56 @@ func checkRequiredArgs(argsDef []types.DeploymentArg, args *uo.UnstructuredObjec
 
 	return nil
 }
+
+const envArgPrefix = "KLUCTL_ARG_"
+
+var envArgIndexPattern = regexp.MustCompile(`^\d+$`)
+
+// BuildEnvArgs builds the args that are passed via environment variables. KLUCTL_ARG_<NAME> sets the arg with the
+// lower-cased name, while the argsFromEnv of the target maps arg names to arbitrary environment variables. Indexed
+// variables (KLUCTL_ARG_<idx>) are skipped, as these are equivalent to --arg. Returns an empty object if environ is nil,
+// which is the case for GitOps deployments, as the environment of the controller must not leak into deployments.
+func BuildEnvArgs(environ []string, target *types.Target) (*uo.UnstructuredObject, error) {
+	if environ == nil {
+		return uo.New(), nil
+	}
+
+	envMap := map[string]string{}
+	for _, e := range environ {
+		n, v, ok := strings.Cut(e, "=")
+		if ok {
+			envMap[n] = v
+		}
+	}
+
+	args := map[string]string{}
+	for n, v := range envMap {
+		if !strings.HasPrefix(n, envArgPrefix) {
+			continue
+		}
+		name := n[len(envArgPrefix):]
+		if name == "" || envArgIndexPattern.MatchString(name) {
+			continue
+		}
+		args[strings.ToLower(name)] = v
+	}
+	ret, err := ConvertArgsToVars(args, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if target != nil && len(target.ArgsFromEnv) != 0 {
+		targetArgs := map[string]string{}
+		for argName, envName := range target.ArgsFromEnv {
+			if v, ok := envMap[envName]; ok {
+				targetArgs[argName] = v
+			}
+		}
+		x, err := ConvertArgsToVars(targetArgs, false)
+		if err != nil {
+			return nil, err
+		}
+		ret.Merge(x)
+	}
+	return ret, nil
+}
@@ -0,0 +1,31 @@
+package kluctl_project
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+)
+
+// BuildFeatureFlags merges the feature flags set by the target with the defaults of all declared feature flags. All
+// declared flags are always present in the result, so that templates don't need to handle missing flags. Setting a
+// flag in the target that was not declared results in an error, which catches typos early.
+func BuildFeatureFlags(declared []types.FeatureFlag, target *types.Target) (*uo.UnstructuredObject, error) {
+	ret := uo.New()
+	for _, f := range declared {
+		if _, found, _ := ret.GetNestedField(f.Name); found {
+			return nil, fmt.Errorf("feature flag %s is declared multiple times", f.Name)
+		}
+		_ = ret.SetNestedField(f.Default, f.Name)
+	}
+
+	if target == nil {
+		return ret, nil
+	}
+	for n, v := range target.FeatureFlags {
+		if _, found, _ := ret.GetNestedField(n); !found {
+			return nil, fmt.Errorf("target %s sets the undeclared feature flag %s", target.Name, n)
+		}
+		_ = ret.SetNestedField(v, n)
+	}
+	return ret, nil
+}
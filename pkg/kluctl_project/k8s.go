@@ -2,7 +2,9 @@ package kluctl_project
 
 import (
 	"context"
+	"fmt"
 	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -14,16 +16,25 @@ func (p *LoadedKluctlProject) LoadK8sConfig(ctx context.Context, targetName stri
 	}
 
 	var contextName *string
+	var tlsConfig *types.TLSConfig
 	if targetName != "" {
 		t, err := p.FindTarget(targetName)
 		if err != nil {
 			return nil, "", err
 		}
 		contextName = t.Context
+		if p.LoadArgs.UseTargetTLS {
+			tlsConfig = t.TLS
+		} else if t.TLS != nil {
+			status.Warningf(ctx, "Ignoring the tls config of target %s, as it is only supported by the CLI", targetName)
+		}
 	}
 	if contextOverride != "" {
 		contextName = &contextOverride
 	}
+	if p.LoadArgs.TLSOverride != nil && (p.LoadArgs.TLSOverride.CAData != "" || p.LoadArgs.TLSOverride.InsecureSkipTLSVerify) {
+		tlsConfig = p.LoadArgs.TLSOverride
+	}
 
 	var err error
 	var clientConfig *rest.Config
@@ -36,6 +47,31 @@ func (p *LoadedKluctlProject) LoadK8sConfig(ctx context.Context, targetName stri
 		}
 		return nil, "", err
 	}
+	err = applyTLSConfig(ctx, clientConfig, tlsConfig)
+	if err != nil {
+		return nil, "", err
+	}
 	contextName = &restConfig.CurrentContext
 	return clientConfig, *contextName, nil
 }
+
+func applyTLSConfig(ctx context.Context, clientConfig *rest.Config, tlsConfig *types.TLSConfig) error {
+	if tlsConfig == nil || clientConfig == nil {
+		return nil
+	}
+	if tlsConfig.InsecureSkipTLSVerify && tlsConfig.CAData != "" {
+		return fmt.Errorf("caData and insecureSkipTLSVerify can not be used at the same time")
+	}
+
+	if tlsConfig.InsecureSkipTLSVerify {
+		status.Warningf(ctx, "TLS certificate verification for the Kubernetes API server %s is DISABLED. The connection is vulnerable to man-in-the-middle attacks, never do this for production clusters!", clientConfig.Host)
+		clientConfig.TLSClientConfig.Insecure = true
+		clientConfig.TLSClientConfig.CAFile = ""
+		clientConfig.TLSClientConfig.CAData = nil
+	} else if tlsConfig.CAData != "" {
+		clientConfig.TLSClientConfig.Insecure = false
+		clientConfig.TLSClientConfig.CAFile = ""
+		clientConfig.TLSClientConfig.CAData = []byte(tlsConfig.CAData)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package kluctl_project
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"testing"
+)
+
+func TestLoadK8sConfigTargetTLS(t *testing.T) {
+	build := func(useTargetTLS bool) *LoadedKluctlProject {
+		return &LoadedKluctlProject{
+			LoadArgs: LoadKluctlProjectArgs{
+				ClientConfigGetter: func(context *string) (*rest.Config, *api.Config, error) {
+					return &rest.Config{Host: "https://example.com", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}}, &api.Config{CurrentContext: "ctx"}, nil
+				},
+				UseTargetTLS: useTargetTLS,
+			},
+			Targets: []*types.Target{
+				{Name: "t1", TLS: &types.TLSConfig{InsecureSkipTLSVerify: true}},
+			},
+		}
+	}
+
+	c, _, err := build(true).LoadK8sConfig(context.Background(), "t1", "", false)
+	assert.NoError(t, err)
+	assert.True(t, c.TLSClientConfig.Insecure)
+	assert.Nil(t, c.TLSClientConfig.CAData)
+
+	c, _, err = build(false).LoadK8sConfig(context.Background(), "t1", "", false)
+	assert.NoError(t, err)
+	assert.False(t, c.TLSClientConfig.Insecure)
+	assert.Equal(t, []byte("ca"), c.TLSClientConfig.CAData)
+}
@@ -0,0 +1,179 @@
+package kluctl_project
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMaintenanceWindowDuration limits the duration of a single window, as open windows are determined by searching
+// backwards for the last matching start time
+const maxMaintenanceWindowDuration = 31 * 24 * time.Hour
+
+// IsInMaintenanceWindow returns true if no maintenance windows are specified or if now is inside at least one of the
+// specified windows.
+func IsInMaintenanceWindow(windows []types.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+	for i, w := range windows {
+		open, err := isInMaintenanceWindow(w, now)
+		if err != nil {
+			return false, fmt.Errorf("invalid maintenance window at index %d: %w", i, err)
+		}
+		if open {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isInMaintenanceWindow(w types.MaintenanceWindow, now time.Time) (bool, error) {
+	s, err := parseCronSchedule(w.Schedule)
+	if err != nil {
+		return false, err
+	}
+	if w.Duration.Duration <= 0 {
+		return false, fmt.Errorf("duration must be greater than zero")
+	}
+	if w.Duration.Duration > maxMaintenanceWindowDuration {
+		return false, fmt.Errorf("duration must not be longer than %s", maxMaintenanceWindowDuration)
+	}
+	loc := time.UTC
+	if w.Timezone != "" {
+		loc, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// a window is open if it started within the last 'duration'
+	notBefore := now.Add(-w.Duration.Duration)
+	for t := now.In(loc).Truncate(time.Minute); t.After(notBefore); t = t.Add(-time.Minute) {
+		if s.matches(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDowNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseCronSchedule parses a standard 5 field cron expression. Fields support '*', single values, ranges ('1-5'),
+// lists ('1,3,5') and steps ('*/15', '0-30/10'). Months and days of week can also be specified by their english
+// three-letter names.
+func parseCronSchedule(s string) (*cronSchedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule '%s' must have exactly 5 fields", s)
+	}
+
+	var ret cronSchedule
+	var err error
+	if ret.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if ret.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if ret.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if ret.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, err
+	}
+	if ret.dow, err = parseCronField(fields[4], 0, 7, cronDowNames); err != nil {
+		return nil, err
+	}
+	// 7 is an alias for sunday
+	if ret.dow&(1<<7) != 0 {
+		ret.dow |= 1
+	}
+	ret.domStar = fields[2] == "*"
+	ret.dowStar = fields[4] == "*"
+	return &ret, nil
+}
+
+func parseCronField(f string, lo int, hi int, names map[string]int) (uint64, error) {
+	parseValue := func(v string) (int, error) {
+		if x, ok := names[strings.ToLower(v)]; ok {
+			return x, nil
+		}
+		x, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value '%s'", v)
+		}
+		if x < lo || x > hi {
+			return 0, fmt.Errorf("value %d out of range [%d-%d]", x, lo, hi)
+		}
+		return x, nil
+	}
+
+	var ret uint64
+	for _, part := range strings.Split(f, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s'", stepPart)
+			}
+		}
+
+		var start, end int
+		if rangePart == "*" {
+			start, end = lo, hi
+		} else {
+			startStr, endStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			start, err = parseValue(startStr)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				end, err = parseValue(endStr)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = hi
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		}
+		for i := start; i <= end; i += step {
+			ret |= 1 << i
+		}
+	}
+	return ret, nil
+}
+
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<t.Day()) != 0
+	dowMatch := s.dow&(1<<int(t.Weekday())) != 0
+	// same as in classic cron, if both day of month and day of week are restricted, either of them needs to match
+	if !s.domStar && !s.dowStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
@@ -0,0 +1,59 @@
+package kluctl_project
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	s, err := parseCronSchedule("*/15 22-23,0 1 jan-mar mon-fri")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1|1<<15|1<<30|1<<45), s.minute)
+	assert.Equal(t, uint64(1|1<<22|1<<23), s.hour)
+	assert.Equal(t, uint64(1<<1), s.dom)
+	assert.Equal(t, uint64(1<<1|1<<2|1<<3), s.month)
+	assert.Equal(t, uint64(1<<1|1<<2|1<<3|1<<4|1<<5), s.dow)
+
+	s, err = parseCronSchedule("0 0 * * 7")
+	assert.NoError(t, err)
+	assert.True(t, s.matches(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)))
+
+	for _, x := range []string{"0 0 * *", "60 * * * *", "* * 0 * *", "* * * * foo", "5-1 * * * *", "*/0 * * * *"} {
+		_, err = parseCronSchedule(x)
+		assert.Error(t, err, x)
+	}
+}
+
+func TestIsInMaintenanceWindow(t *testing.T) {
+	windows := []types.MaintenanceWindow{
+		// weekdays from 22:00 to 02:00 in Berlin
+		{Schedule: "0 22 * * mon-fri", Duration: metav1.Duration{Duration: 4 * time.Hour}, Timezone: "Europe/Berlin"},
+	}
+
+	check := func(s string, expected bool) {
+		now, err := time.Parse(time.RFC3339, s)
+		assert.NoError(t, err)
+		open, err := IsInMaintenanceWindow(windows, now)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, open, s)
+	}
+
+	// 2024-01-08 is a monday, Berlin is UTC+1 in january
+	check("2024-01-08T20:59:00Z", false)
+	check("2024-01-08T21:00:00Z", true)
+	check("2024-01-09T00:59:00Z", true)
+	check("2024-01-09T01:00:00Z", false)
+	// saturday night
+	check("2024-01-13T22:00:00Z", false)
+
+	open, err := IsInMaintenanceWindow(nil, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, open)
+
+	windows[0].Timezone = "Invalid/Zone"
+	_, err = IsInMaintenanceWindow(windows, time.Now())
+	assert.ErrorContains(t, err, "invalid maintenance window at index 0")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"k8s.io/client-go/rest"
@@ -20,6 +21,13 @@ type LoadKluctlProjectArgs struct {
 	ProjectConfig string
 	ExternalArgs  *uo.UnstructuredObject
 
+	// ExternalItemArgs maps deployment item paths to variables that are only set for these items
+	ExternalItemArgs map[string]*uo.UnstructuredObject
+
+	// Environ is the list of environment variables (in the form NAME=value) used to resolve KLUCTL_ARG_<NAME> args and
+	// the argsFromEnv of targets. Args from environment variables are disabled when this is nil.
+	Environ []string
+
 	GitRP *repocache.GitRepoCache
 	OciRP *repocache.OciRepoCache
 
@@ -28,6 +36,13 @@ type LoadKluctlProjectArgs struct {
 
 	AddKeyServersFunc  func(ctx context.Context, d *decryptor.Decryptor) error
 	ClientConfigGetter func(context *string) (*rest.Config, *api.Config, error)
+
+	// TLSOverride overrides the TLS settings of the kubeconfig and of the tls config of the target
+	TLSOverride *types.TLSConfig
+	// UseTargetTLS enables the tls config of targets. The tls config comes from the project itself, so it must only be
+	// honored when the project is trusted to configure the connection, e.g. by the CLI. The controller must not honor
+	// it, as anyone with push access to the project could otherwise disable TLS verification of the controller.
+	UseTargetTLS bool
 }
 
 func (c *LoadedKluctlProject) getConfigPath() string {
@@ -0,0 +1,62 @@
+package target_context
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sort"
+	"strings"
+)
+
+// clusterInfoGlobalName is the name of the internal Jinja2 global used by the cluster_has_api and cluster_version
+// functions, see pkg/kluctl_jinja2/ext/cluster_ext.py
+const clusterInfoGlobalName = "_kluctl_cluster"
+
+// normalizeClusterVersion strips the leading "v" and distribution specific suffixes, e.g. "v1.28.3+k3s1" becomes
+// "1.28.3". Versions that can't be parsed are returned without the leading "v".
+func normalizeClusterVersion(v string) string {
+	if v == "" {
+		return ""
+	}
+	pv, err := version.ParseGeneric(v)
+	if err != nil {
+		return strings.TrimPrefix(v, "v")
+	}
+	return pv.String()
+}
+
+// buildClusterInfo gathers the server version and all served APIs of the target cluster. If no cluster is available
+// (e.g. offline rendering), only the version passed via --kubernetes-version is returned.
+func buildClusterInfo(k *k8s.K8sCluster, k8sVersionOverride string) (map[string]any, error) {
+	v := k8sVersionOverride
+	if v == "" && k != nil && k.ServerVersion != nil {
+		v = k.ServerVersion.String()
+	}
+	ret := map[string]any{
+		"version": normalizeClusterVersion(v),
+	}
+
+	if k == nil {
+		return ret, nil
+	}
+
+	// deprecated resources are included here, as these are still served and templates might want to check for them
+	ars, err := k.GetAllServedAPIResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover cluster APIs: %w", err)
+	}
+	apis := map[string]bool{}
+	for _, ar := range ars {
+		apis[fmt.Sprintf("%s/%s/%s", ar.Group, ar.Version, ar.Kind)] = true
+	}
+	l := make([]any, 0, len(apis))
+	for a := range apis {
+		l = append(l, a)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].(string) < l[j].(string)
+	})
+	ret["apis"] = l
+
+	return ret, nil
+}
@@ -0,0 +1,15 @@
+package target_context
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeClusterVersion(t *testing.T) {
+	assert.Equal(t, "", normalizeClusterVersion(""))
+	assert.Equal(t, "1.28.3", normalizeClusterVersion("v1.28.3"))
+	assert.Equal(t, "1.28.3", normalizeClusterVersion("v1.28.3+k3s1"))
+	assert.Equal(t, "1.27.8", normalizeClusterVersion("v1.27.8-gke.1067004"))
+	assert.Equal(t, "1.29.0", normalizeClusterVersion("1.29.0"))
+	assert.Equal(t, "invalid", normalizeClusterVersion("vinvalid"))
+}
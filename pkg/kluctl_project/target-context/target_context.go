@@ -14,8 +14,11 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/vars"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"path/filepath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 type TargetContext struct {
@@ -40,9 +43,12 @@ type TargetContextParams struct {
 	DryRun             bool
 	Images             *deployment.Images
 	Inclusion          *utils.Inclusion
+	ObjectSelector     labels.Selector
 	HelmAuthProvider   auth.HelmAuthProvider
 	OciAuthProvider    auth_provider.OciAuthProvider
 	RenderOutputDir    string
+	PreserveYamlFormat bool
+	KeepRenderTmpDir   string
 }
 
 func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject, contextName string, k *k8s.K8sCluster, params TargetContextParams) (*TargetContext, error) {
@@ -78,6 +84,12 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 		target.Discriminator = params.Discriminator
 	}
 
+	if target.DefaultNamespace != "" {
+		if errs := validation.IsDNS1123Label(target.DefaultNamespace); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid defaultNamespace %s in target %s: %s", target.DefaultNamespace, target.Name, strings.Join(errs, ", "))
+		}
+	}
+
 	params.Images.PrependFixedImages(target.Images)
 
 	target.Context = &contextName
@@ -87,6 +99,14 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 		return nil, err
 	}
 
+	clusterInfo, err := buildClusterInfo(k, params.K8sVersion)
+	if err != nil {
+		return nil, err
+	}
+	varsCtx.Globals = map[string]any{
+		clusterInfoGlobalName: clusterInfo,
+	}
+
 	var client client.Client
 	if k != nil {
 		client, err = k.ToClient()
@@ -99,6 +119,11 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 	if err != nil {
 		return nil, err
 	}
+	externalCRDs, err := p.LoadCrdSources(ctx, params.OfflineK8s)
+	if err != nil {
+		return nil, err
+	}
+
 	varsLoader := vars.NewVarsLoader(ctx, k, sopsDecryptor, p.GitRP, aws.NewClientFactory(client, target.Aws), gcp.NewClientFactory())
 
 	dctx := deployment.SharedContext{
@@ -112,7 +137,14 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 		HelmAuthProvider: params.HelmAuthProvider,
 		OciAuthProvider:  params.OciAuthProvider,
 		Discriminator:    target.Discriminator,
+		DefaultNamespace: target.DefaultNamespace,
+		Overlay:          target.Overlay,
 		RenderDir:        params.RenderOutputDir,
+		KeepRenderTmpDir: params.KeepRenderTmpDir,
+
+		PreserveYamlFormat: params.PreserveYamlFormat,
+		ExternalCRDs:       externalCRDs,
+		ItemVars:           p.LoadArgs.ExternalItemArgs,
 	}
 
 	targetCtx := &TargetContext{
@@ -129,7 +161,7 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 	}
 	targetCtx.DeploymentProject = d
 
-	c, err := deployment.NewDeploymentCollection(dctx, d, params.Images, params.Inclusion)
+	c, err := deployment.NewDeploymentCollection(dctx, d, params.Images, params.Inclusion, params.ObjectSelector)
 	if err != nil {
 		return targetCtx, err
 	}
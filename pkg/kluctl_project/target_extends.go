@@ -0,0 +1,79 @@
+package kluctl_project
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"slices"
+	"strings"
+)
+
+// resolveTargetExtends returns a copy of the given target with all targets referenced via 'extends' merged into it.
+// Dictionaries (e.g. args) are merged recursively, images are concatenated so that later targets take precedence and
+// all other lists and values are replaced by later targets.
+func resolveTargetExtends(target *types.Target, configTargets map[string]*types.Target) (*types.Target, error) {
+	if len(target.Extends) == 0 {
+		return target, nil
+	}
+
+	merged, err := resolveTargetExtends2(target, configTargets, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret types.Target
+	err = merged.ToStruct(&ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.Name = target.Name
+	ret.Extends = target.Extends
+	ret.Abstract = target.Abstract
+	return &ret, nil
+}
+
+func resolveTargetExtends2(target *types.Target, configTargets map[string]*types.Target, stack []string) (*uo.UnstructuredObject, error) {
+	if slices.Contains(stack, target.Name) {
+		return nil, fmt.Errorf("cyclic extends detected: %s", strings.Join(append(stack, target.Name), " -> "))
+	}
+	stack = append(stack, target.Name)
+
+	ret := uo.New()
+	for _, n := range target.Extends {
+		base, ok := configTargets[n]
+		if !ok {
+			return nil, fmt.Errorf("target %s extends the unknown target %s", target.Name, n)
+		}
+		o, err := resolveTargetExtends2(base, configTargets, stack)
+		if err != nil {
+			return nil, err
+		}
+		mergeTargetObject(ret, o)
+	}
+
+	o, err := uo.FromStruct(target)
+	if err != nil {
+		return nil, err
+	}
+	mergeTargetObject(ret, o)
+	return ret, nil
+}
+
+func mergeTargetObject(dst *uo.UnstructuredObject, src *uo.UnstructuredObject) {
+	// these are specific to each target and are never inherited
+	for _, k := range []string{"name", "extends", "abstract"} {
+		delete(src.Object, k)
+	}
+
+	dstImages, _ := dst.Object["images"].([]any)
+	srcImages, _ := src.Object["images"].([]any)
+
+	dst.Merge(src)
+
+	if len(dstImages) != 0 && len(srcImages) != 0 {
+		var images []any
+		images = append(images, dstImages...)
+		images = append(images, srcImages...)
+		dst.Object["images"] = images
+	}
+}
@@ -0,0 +1,102 @@
+package kluctl_project
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildConfigTargets(t *testing.T, s string) map[string]*types.Target {
+	var kp types.KluctlProject
+	err := uo.FromStringMust(s).ToStruct(&kp)
+	assert.NoError(t, err)
+
+	ret := map[string]*types.Target{}
+	for i := range kp.Targets {
+		ret[kp.Targets[i].Name] = &kp.Targets[i]
+	}
+	return ret
+}
+
+func TestResolveTargetExtends(t *testing.T) {
+	targets := buildConfigTargets(t, `
+targets:
+- name: base
+  abstract: true
+  context: base-context
+  args:
+    env: base
+    replicas: 1
+    nested:
+      a: 1
+  images:
+  - image: nginx
+    resultImage: nginx:1
+  maxDeletes: 10
+- name: eu
+  abstract: true
+  args:
+    region: eu
+    nested:
+      b: 2
+  images:
+  - image: redis
+    resultImage: redis:1
+- name: prod
+  extends: [base, eu]
+  args:
+    env: prod
+  images:
+  - image: nginx
+    resultImage: nginx:2
+`)
+
+	r, err := resolveTargetExtends(targets["prod"], targets)
+	assert.NoError(t, err)
+
+	expected := buildConfigTargets(t, `
+targets:
+- name: prod
+  extends: [base, eu]
+  context: base-context
+  args:
+    env: prod
+    replicas: 1
+    region: eu
+    nested:
+      a: 1
+      b: 2
+  images:
+  - image: nginx
+    resultImage: nginx:1
+  - image: redis
+    resultImage: redis:1
+  - image: nginx
+    resultImage: nginx:2
+  maxDeletes: 10
+`)
+	assert.Equal(t, expected["prod"], r)
+
+	// the config targets must not be modified
+	assert.Equal(t, map[string]any{"env": "prod"}, targets["prod"].Args.Object)
+	assert.Len(t, targets["prod"].Images, 1)
+}
+
+func TestResolveTargetExtendsErrors(t *testing.T) {
+	targets := buildConfigTargets(t, `
+targets:
+- name: a
+  extends: [b]
+- name: b
+  extends: [a]
+- name: c
+  extends: [missing]
+`)
+
+	_, err := resolveTargetExtends(targets["a"], targets)
+	assert.ErrorContains(t, err, "cyclic extends detected: a -> b -> a")
+
+	_, err = resolveTargetExtends(targets["c"], targets)
+	assert.ErrorContains(t, err, "target c extends the unknown target missing")
+}
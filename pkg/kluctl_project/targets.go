@@ -28,13 +28,30 @@ func (c *LoadedKluctlProject) loadTargets(ctx context.Context) error {
 		return nil
 	}
 
+	configTargets := map[string]*types.Target{}
+	for i := range c.Config.Targets {
+		t := &c.Config.Targets[i]
+		if _, ok := configTargets[t.Name]; t.Name != "" && !ok {
+			configTargets[t.Name] = t
+		}
+	}
+
 	for i, configTarget := range c.Config.Targets {
 		if configTarget.Name == "" {
 			status.Errorf(ctx, "Target at index %d has no name", i)
 			continue
 		}
+		if configTarget.Abstract {
+			continue
+		}
 
-		target, err := c.buildTarget(&configTarget)
+		resolvedTarget, err := resolveTargetExtends(&configTarget, configTargets)
+		if err != nil {
+			status.Warningf(ctx, "Failed to resolve extends of target %s: %v", configTarget.Name, err)
+			continue
+		}
+
+		target, err := c.buildTarget(resolvedTarget)
 		if err != nil {
 			status.Warningf(ctx, "Failed to load target config for project: %v", err)
 			continue
@@ -101,6 +118,27 @@ func (c *LoadedKluctlProject) buildTarget(configTarget *types.Target) (*types.Ta
 			target.Aws.ServiceAccount = c.Config.Aws.ServiceAccount
 		}
 	}
+	if target.DeployDefaults == nil {
+		target.DeployDefaults = c.Config.DeployDefaults
+	} else if c.Config.DeployDefaults != nil {
+		d := target.DeployDefaults
+		pd := c.Config.DeployDefaults
+		if d.ForceApply == nil {
+			d.ForceApply = pd.ForceApply
+		}
+		if d.ReplaceOnError == nil {
+			d.ReplaceOnError = pd.ReplaceOnError
+		}
+		if d.AbortOnError == nil {
+			d.AbortOnError = pd.AbortOnError
+		}
+		if d.NoWait == nil {
+			d.NoWait = pd.NoWait
+		}
+		if d.WaitTimeout == nil {
+			d.WaitTimeout = pd.WaitTimeout
+		}
+	}
 	// just to make sure we don't later overwrite stuff from c.Config, which we might have copied into the target a few
 	// lines above this
 	target, err = utils.DeepClone(target)
@@ -20,6 +20,13 @@ func (p *LoadedKluctlProject) BuildVars(target *types.Target) (*vars.VarsCtx, er
 	if target != nil && target.Args != nil {
 		allArgs.Merge(target.Args)
 	}
+
+	envArgs, err := BuildEnvArgs(p.LoadArgs.Environ, target)
+	if err != nil {
+		return nil, err
+	}
+	allArgs.Merge(envArgs)
+
 	if p.LoadArgs.ExternalArgs != nil {
 		allArgs.Merge(p.LoadArgs.ExternalArgs)
 	}
@@ -31,5 +38,11 @@ func (p *LoadedKluctlProject) BuildVars(target *types.Target) (*vars.VarsCtx, er
 
 	varsCtx.UpdateChild("args", allArgs)
 
+	featureFlags, err := BuildFeatureFlags(p.Config.FeatureFlags, target)
+	if err != nil {
+		return nil, err
+	}
+	varsCtx.UpdateChild("featureFlags", featureFlags)
+
 	return varsCtx, nil
 }
@@ -121,6 +121,21 @@ func (c *Client) List(ctx context.Context, url string, opts ListOptions) ([]Meta
 	return metas, nil
 }
 
+// ListTags fetches the tags of the given OCI repository, excluding cosign artifacts.
+func (c *Client) ListTags(ctx context.Context, url string) ([]string, error) {
+	tags, err := crane.ListTags(url, c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags failed: %w", err)
+	}
+	ret := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !IsCosignArtifact(tag) {
+			ret = append(ret, tag)
+		}
+	}
+	return ret, nil
+}
+
 // IsCosignArtifact will return true if the tag has one of the following suffices:
 // ".att", ".sbom", or ".sig". These are the suffices used by cosign to store the
 // attestations, SBOMs, and signatures respectively.
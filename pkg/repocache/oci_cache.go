@@ -154,6 +154,14 @@ func (rp *OciRepoCache) GetEntry(urlIn string) (*OciCacheEntry, error) {
 	return e, nil
 }
 
+// ListTags returns all tags of the repository
+func (e *OciCacheEntry) ListTags() ([]string, error) {
+	if e.ociClient == nil {
+		return nil, fmt.Errorf("oci repository %s is overridden by a local directory", e.url.String())
+	}
+	return e.ociClient.ListTags(e.rp.ctx, strings.TrimPrefix(e.url.String(), "oci://"))
+}
+
 func (e *OciCacheEntry) GetExtractedDir(ref *types.OciRef) (string, git.CheckoutInfo, error) {
 	e.updateMutex.Lock()
 	defer e.updateMutex.Unlock()
@@ -0,0 +1,111 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/lib/status"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetWriteDeployResults enables writing of compact KluctlDeployResult objects next to the full command results.
+func (s *ResultStoreSecrets) SetWriteDeployResults(b bool) {
+	s.writeDeployResults = b
+}
+
+func buildDeployResult(cr *result.CommandResult, summary *result.CommandResultSummary) kluctlv1.KluctlDeployResult {
+	ret := kluctlv1.KluctlDeployResult{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kluctlv1.GroupVersion.String(),
+			Kind:       "KluctlDeployResult",
+		},
+		Spec: kluctlv1.KluctlDeployResultSpec{
+			CommandResultId: cr.Id,
+			Command:         cr.Command.Command,
+			Initiator:       string(cr.Command.Initiator),
+			StartTime:       cr.Command.StartTime,
+			EndTime:         cr.Command.EndTime,
+			DryRun:          cr.Command.DryRun,
+			ProjectRepoKey:  cr.ProjectKey.RepoKey.String(),
+			ProjectSubDir:   cr.ProjectKey.SubDir,
+			TargetName:      cr.TargetKey.TargetName,
+			Discriminator:   cr.TargetKey.Discriminator,
+			GitCommit:       cr.GitInfo.Commit,
+			NewObjects:      summary.NewObjects,
+			ChangedObjects:  summary.ChangedObjects,
+			OrphanObjects:   summary.OrphanObjects,
+			DeletedObjects:  summary.DeletedObjects,
+			AppliedObjects:  summary.AppliedObjects,
+			Errors:          len(summary.Errors),
+			Warnings:        len(summary.Warnings),
+		},
+	}
+	if cr.KluctlDeployment != nil {
+		ret.Spec.KluctlDeployment = &kluctlv1.KluctlDeployResultDeploymentRef{
+			Name:      cr.KluctlDeployment.Name,
+			Namespace: cr.KluctlDeployment.Namespace,
+		}
+	}
+	return ret
+}
+
+// isDeployResultUnavailable returns true if the error indicates that the CRD is not installed or that we lack the
+// permissions to write KluctlDeployResult objects. Both cases are not fatal, as writing them is best-effort only.
+func isDeployResultUnavailable(err error) bool {
+	return meta.IsNoMatchError(err) || errors.IsForbidden(err) || errors.IsNotFound(err)
+}
+
+func (s *ResultStoreSecrets) writeDeployResult(cr *result.CommandResult, summary *result.CommandResultSummary) error {
+	if !s.writeDeployResults {
+		return nil
+	}
+
+	dr := buildDeployResult(cr, summary)
+	dr.Name = s.buildName("dr", cr.Id, cr.ProjectKey)
+	dr.Namespace = s.writeNamespace
+	dr.Labels = map[string]string{
+		"kluctl.io/result":            "true",
+		"kluctl.io/command-result-id": cr.Id,
+	}
+	if cr.KluctlDeployment != nil {
+		dr.Labels["kluctl.io/result-deployment-name"] = cr.KluctlDeployment.Name
+		dr.Labels["kluctl.io/result-deployment-namespace"] = cr.KluctlDeployment.Namespace
+	}
+
+	// we're using unstructured here so that we don't depend on the scheme of the passed client
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&dr)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: m}
+
+	err = s.client.Patch(s.ctx, u, client.Apply, client.FieldOwner("kluctl-results"))
+	if err != nil {
+		if isDeployResultUnavailable(err) {
+			status.Warningf(s.ctx, "Failed to write KluctlDeployResult, ensure that the CRD is installed and that you have the necessary permissions: %s", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteDeployResults deletes the KluctlDeployResult objects of the given command result. This is done even if writing
+// of KluctlDeployResult objects is disabled, as these might have been written by earlier invocations.
+func (s *ResultStoreSecrets) deleteDeployResults(rsId string) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(kluctlv1.GroupVersion.WithKind("KluctlDeployResult"))
+	err := s.client.DeleteAllOf(s.ctx, u,
+		client.InNamespace(s.writeNamespace),
+		client.MatchingLabels{
+			"kluctl.io/command-result-id": rsId,
+		})
+	if err != nil && !isDeployResultUnavailable(err) {
+		return err
+	}
+	return nil
+}
@@ -41,6 +41,7 @@ type ResultStoreSecrets struct {
 	writeNamespace           string
 	keepCommandResultsCount  int
 	keepValidateResultsCount int
+	writeDeployResults       bool
 
 	mutex sync.Mutex
 }
@@ -211,6 +212,11 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 		return err
 	}
 
+	err = s.writeDeployResult(cr, summary)
+	if err != nil {
+		return err
+	}
+
 	err = s.cleanupOldCommandResults(cr.ProjectKey, cr.TargetKey)
 	if err != nil {
 		return err
@@ -236,7 +242,7 @@ func (s *ResultStoreSecrets) DeleteCommandResult(rsId string) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return s.deleteDeployResults(rsId)
 }
 
 func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) error {
@@ -334,6 +340,9 @@ func (s *ResultStoreSecrets) cleanupOldCommandResults(project gittypes.ProjectKe
 			err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
 				"kluctl.io/command-result-id": rs.Id,
 			})
+			if err == nil {
+				err = s.deleteDeployResults(rs.Id)
+			}
 			if err != nil {
 				status.Warningf(s.ctx, "Failed to delete old command result %s: %s", rs.Id, err)
 			} else {
@@ -394,6 +403,9 @@ func (s *ResultStoreSecrets) cleanupOrphanedResults() error {
 			continue
 		}
 		tryDeleteResult(e.name, e.summary.KluctlDeployment, e.summary.Id, "command result")
+		if _, ok := deploymentsMap[*e.summary.KluctlDeployment]; !ok {
+			_ = s.deleteDeployResults(e.summary.Id)
+		}
 	}
 	for _, e := range validateResults {
 		if e.summary.KluctlDeployment == nil {
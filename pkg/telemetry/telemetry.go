@@ -0,0 +1,220 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/version"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+const recordsFileName = "records.jsonl"
+
+// Record is a single anonymized telemetry record. It must never contain names, messages or any other data that could
+// identify projects, targets, clusters or objects.
+type Record struct {
+	Time           time.Time `json:"time"`
+	KluctlVersion  string    `json:"kluctlVersion"`
+	Os             string    `json:"os"`
+	Arch           string    `json:"arch"`
+	Command        string    `json:"command"`
+	DurationMs     int64     `json:"durationMs"`
+	ClusterVersion string    `json:"clusterVersion,omitempty"`
+
+	RenderedObjects int `json:"renderedObjects"`
+	RemoteObjects   int `json:"remoteObjects"`
+	AppliedObjects  int `json:"appliedObjects"`
+	NewObjects      int `json:"newObjects"`
+	ChangedObjects  int `json:"changedObjects"`
+	OrphanObjects   int `json:"orphanObjects"`
+	DeletedObjects  int `json:"deletedObjects"`
+	Errors          int `json:"errors"`
+	Warnings        int `json:"warnings"`
+
+	ErrorCategories []string `json:"errorCategories,omitempty"`
+	Success         bool     `json:"success"`
+}
+
+// Recorder collects the data of a single command invocation and stores it when the command has finished.
+// All methods are safe to be called on a nil Recorder, which allows callers to record data without checking whether
+// telemetry is enabled.
+type Recorder struct {
+	dir      string
+	endpoint string
+	start    time.Time
+
+	mutex  sync.Mutex
+	record Record
+}
+
+func NewRecorder(dir string, endpoint string, command string) *Recorder {
+	r := &Recorder{
+		dir:      dir,
+		endpoint: endpoint,
+		start:    time.Now(),
+	}
+	r.record = Record{
+		KluctlVersion: version.GetVersion(),
+		Os:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Command:       command,
+	}
+	return r
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+func FromContext(ctx context.Context) *Recorder {
+	v := ctx.Value(contextKey{})
+	if v == nil {
+		return nil
+	}
+	return v.(*Recorder)
+}
+
+func (r *Recorder) SetClusterVersion(v string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.record.ClusterVersion = v
+}
+
+func (r *Recorder) AddCommandResult(cr *result.CommandResult) {
+	if r == nil || cr == nil {
+		return
+	}
+	s := cr.BuildSummary()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.record.RenderedObjects += s.RenderedObjects
+	r.record.RemoteObjects += s.RemoteObjects
+	r.record.AppliedObjects += s.AppliedObjects
+	r.record.NewObjects += s.NewObjects
+	r.record.ChangedObjects += s.ChangedObjects
+	r.record.OrphanObjects += s.OrphanObjects
+	r.record.DeletedObjects += s.DeletedObjects
+	r.record.Errors += len(s.Errors)
+	r.record.Warnings += len(s.Warnings)
+	if len(s.Errors) != 0 {
+		r.addErrorCategory("deployment")
+	}
+}
+
+func (r *Recorder) addErrorCategory(c string) {
+	for _, x := range r.record.ErrorCategories {
+		if x == c {
+			return
+		}
+	}
+	r.record.ErrorCategories = append(r.record.ErrorCategories, c)
+	sort.Strings(r.record.ErrorCategories)
+}
+
+// Finish completes the record with the duration and the final error of the command. The record is then appended to
+// the local records file and, if configured, uploaded to the telemetry endpoint.
+func (r *Recorder) Finish(ctx context.Context, cmdErr error) (*Record, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	r.mutex.Lock()
+	r.record.Time = r.start.UTC()
+	r.record.DurationMs = time.Since(r.start).Milliseconds()
+	if cmdErr != nil {
+		r.addErrorCategory(CategorizeError(cmdErr))
+	}
+	r.record.Success = cmdErr == nil && r.record.Errors == 0
+	record := r.record
+	r.mutex.Unlock()
+
+	b, err := json.Marshal(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.writeLocal(b)
+	if err != nil {
+		return &record, fmt.Errorf("failed to store telemetry record: %w", err)
+	}
+
+	if r.endpoint != "" {
+		err = r.upload(ctx, b)
+		if err != nil {
+			return &record, fmt.Errorf("failed to upload telemetry record: %w", err)
+		}
+	}
+	return &record, nil
+}
+
+func (r *Recorder) writeLocal(b []byte) error {
+	err := os.MkdirAll(r.dir, 0o700)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(r.dir, recordsFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+func (r *Recorder) upload(ctx context.Context, b []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// CategorizeError maps an error to a coarse category that does not contain any details of the error itself.
+func CategorizeError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return "api" + string(reason)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return "network"
+	}
+	return "other"
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderNil(t *testing.T) {
+	var r *Recorder
+	r.SetClusterVersion("v1.28.0")
+	r.AddCommandResult(&result.CommandResult{})
+	record, err := r.Finish(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+	assert.Nil(t, FromContext(context.Background()))
+}
+
+func TestRecorderWritesLocal(t *testing.T) {
+	dir := t.TempDir()
+
+	r := NewRecorder(dir, "", "deploy")
+	r.SetClusterVersion("v1.28.0")
+	r.AddCommandResult(&result.CommandResult{
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: k8s.ObjectRef{Kind: "ConfigMap", Name: "secret-name"}, New: true}},
+		},
+		Errors: []result.DeploymentError{{Message: "secret message"}},
+	})
+
+	record, err := r.Finish(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", record.Command)
+	assert.Equal(t, "v1.28.0", record.ClusterVersion)
+	assert.Equal(t, 1, record.NewObjects)
+	assert.Equal(t, 1, record.Errors)
+	assert.Equal(t, []string{"deployment"}, record.ErrorCategories)
+	assert.False(t, record.Success)
+
+	b, err := os.ReadFile(filepath.Join(dir, recordsFileName))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret")
+
+	r = NewRecorder(dir, "", "diff")
+	_, err = r.Finish(context.Background(), nil)
+	assert.NoError(t, err)
+
+	b, err = os.ReadFile(filepath.Join(dir, recordsFileName))
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 2)
+
+	var record2 Record
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record2))
+	assert.Equal(t, "diff", record2.Command)
+	assert.True(t, record2.Success)
+}
+
+func TestRecorderUpload(t *testing.T) {
+	var received []Record
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var record Record
+		err := json.NewDecoder(req.Body).Decode(&record)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, record)
+	}))
+	defer srv.Close()
+
+	r := NewRecorder(t.TempDir(), srv.URL, "prune")
+	_, err := r.Finish(context.Background(), fmt.Errorf("wrapped: %w", context.DeadlineExceeded))
+	assert.NoError(t, err)
+	assert.Len(t, received, 1)
+	assert.Equal(t, "prune", received[0].Command)
+	assert.Equal(t, []string{"timeout"}, received[0].ErrorCategories)
+
+	r = NewRecorder(t.TempDir(), srv.URL+"/invalid\x00", "prune")
+	_, err = r.Finish(context.Background(), nil)
+	assert.ErrorContains(t, err, "failed to upload telemetry record")
+}
+
+func TestCategorizeError(t *testing.T) {
+	assert.Equal(t, "timeout", CategorizeError(context.DeadlineExceeded))
+	assert.Equal(t, "canceled", CategorizeError(context.Canceled))
+	assert.Equal(t, "apiForbidden", CategorizeError(apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "x", fmt.Errorf("x"))))
+	assert.Equal(t, "other", CategorizeError(fmt.Errorf("something")))
+}
@@ -1,10 +1,13 @@
 package types
 
 import (
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	yaml2 "github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
 )
 
 type DeploymentItemConfig struct {
@@ -14,18 +17,34 @@ type DeploymentItemConfig struct {
 	Oci           *OciProject              `json:"oci,omitempty"`
 	DeleteObjects []DeleteObjectItemConfig `json:"deleteObjects,omitempty"`
 
-	Tags    []string `json:"tags,omitempty"`
-	Barrier bool     `json:"barrier,omitempty"`
-	Message *string  `json:"message,omitempty"`
+	Tags                 []string `json:"tags,omitempty"`
+	Barrier              bool     `json:"barrier,omitempty"`
+	WaitReadinessBarrier bool     `json:"waitReadinessBarrier,omitempty"`
+	Message              *string  `json:"message,omitempty"`
+
+	// PreIncludeHooks and PostIncludeHooks are only allowed for includes and point to kustomize deployments with hooks
+	// that are executed before/after the whole included project
+	PreIncludeHooks  *string `json:"preIncludeHooks,omitempty"`
+	PostIncludeHooks *string `json:"postIncludeHooks,omitempty"`
 
 	WaitReadiness        bool                            `json:"waitReadiness,omitempty"`
 	WaitReadinessObjects []WaitReadinessObjectItemConfig `json:"waitReadinessObjects,omitempty"`
+	WaitFor              []WaitForObjectItemConfig       `json:"waitFor,omitempty"`
 
 	Args     *uo.UnstructuredObject `json:"args,omitempty"`
 	PassVars bool                   `json:"passVars,omitempty"`
 	Vars     []VarsSource           `json:"vars,omitempty"`
 
+	DefaultNamespace *string `json:"defaultNamespace,omitempty"`
+
+	ConfigMapGenerator []ConfigMapGeneratorConfig `json:"configMapGenerator,omitempty"`
+	SecretGenerator    []SecretGeneratorConfig    `json:"secretGenerator,omitempty"`
+
+	Artifacts []string          `json:"artifacts,omitempty"`
+	Overlays  map[string]string `json:"overlays,omitempty"`
+
 	SkipDeleteIfTags bool   `json:"skipDeleteIfTags,omitempty"`
+	AlwaysSkipDelete bool   `json:"alwaysSkipDelete,omitempty"`
 	OnlyRender       bool   `json:"onlyRender,omitempty"`
 	AlwaysDeploy     bool   `json:"alwaysDeploy,omitempty"`
 	When             string `json:"when,omitempty"`
@@ -61,12 +80,64 @@ func ValidateDeploymentItemConfig(sl validator.StructLevel) {
 	if s.Path == nil && s.WaitReadiness {
 		sl.ReportError(s, "waitReadiness", "WaitReadiness", "only kustomize deployments are allowed to have waitReadiness set", "")
 	}
+	if s.Path == nil && len(s.WaitFor) != 0 {
+		sl.ReportError(s, "waitFor", "WaitFor", "only kustomize deployments are allowed to have waitFor set", "")
+	}
 	if !s.Args.IsZero() && !isInclude {
 		sl.ReportError(s, "self", "self", "args are only allowed when another project is included (via include, git or oci)", "")
 	}
+	if (s.PreIncludeHooks != nil || s.PostIncludeHooks != nil) && !isInclude {
+		sl.ReportError(s, "self", "self", "preIncludeHooks and postIncludeHooks are only allowed when another project is included (via include, git or oci)", "")
+	}
 	if s.PassVars && !isInclude {
 		sl.ReportError(s, "self", "self", "passVars is only allowed when another project is included (via include, git or oci)", "")
 	}
+	if s.DefaultNamespace != nil {
+		if s.Path == nil && !isInclude {
+			sl.ReportError(s, "defaultNamespace", "DefaultNamespace", "defaultNamespace is only allowed for kustomize deployments and includes", "")
+		} else if errs := validation.IsDNS1123Label(*s.DefaultNamespace); len(errs) != 0 {
+			sl.ReportError(s, "defaultNamespace", "DefaultNamespace", fmt.Sprintf("invalid defaultNamespace: %s", strings.Join(errs, ", ")), "")
+		}
+	}
+	if s.Path == nil && len(s.ConfigMapGenerator) != 0 {
+		sl.ReportError(s, "configMapGenerator", "ConfigMapGenerator", "configMapGenerator is only allowed for kustomize deployments", "")
+	}
+	if s.Path == nil && len(s.SecretGenerator) != 0 {
+		sl.ReportError(s, "secretGenerator", "SecretGenerator", "secretGenerator is only allowed for kustomize deployments", "")
+	}
+	if s.Path == nil && len(s.Artifacts) != 0 {
+		sl.ReportError(s, "artifacts", "Artifacts", "artifacts are only allowed for kustomize deployments", "")
+	}
+	if s.Path == nil && len(s.Overlays) != 0 {
+		sl.ReportError(s, "overlays", "Overlays", "overlays are only allowed for kustomize deployments", "")
+	}
+}
+
+type ConfigMapGeneratorConfig struct {
+	Name      string `json:"name" validate:"required"`
+	Namespace string `json:"namespace,omitempty"`
+
+	Files    []string `json:"files,omitempty"`
+	Literals []string `json:"literals,omitempty"`
+	Envs     []string `json:"envs,omitempty"`
+
+	Labels                map[string]string `json:"labels,omitempty"`
+	Annotations           map[string]string `json:"annotations,omitempty"`
+	DisableNameSuffixHash bool              `json:"disableNameSuffixHash,omitempty"`
+}
+
+type SecretGeneratorConfig struct {
+	Name      string `json:"name" validate:"required"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type,omitempty"`
+
+	Files    []string `json:"files,omitempty"`
+	Literals []string `json:"literals,omitempty"`
+	Envs     []string `json:"envs,omitempty"`
+
+	Labels                map[string]string `json:"labels,omitempty"`
+	Annotations           map[string]string `json:"annotations,omitempty"`
+	DisableNameSuffixHash bool              `json:"disableNameSuffixHash,omitempty"`
 }
 
 type ObjectRefItem struct {
@@ -98,6 +169,17 @@ func ValidateWaitReadinessObjectItemConfig(sl validator.StructLevel) {
 	}
 }
 
+type WaitForObjectItemConfig struct {
+	ObjectRefItem
+}
+
+func ValidateWaitForObjectItemConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(WaitForObjectItemConfig)
+	if s.Group == nil && s.Kind == nil {
+		sl.ReportError(s, "self", "self", "at least one of group or kind must be set", "")
+	}
+}
+
 type SingleStringOrList []string
 
 func (s *SingleStringOrList) UnmarshalJSON(b []byte) error {
@@ -132,6 +214,30 @@ func ValidateIgnoreForDiffItemConfig(sl validator.StructLevel) {
 	}
 }
 
+type DiffNormalizerType string
+
+const (
+	DiffNormalizerSortListByKey DiffNormalizerType = "sortListByKey"
+	DiffNormalizerQuantity      DiffNormalizerType = "quantity"
+)
+
+type DiffNormalizerConfig struct {
+	Normalizer DiffNormalizerType `json:"normalizer" validate:"required,oneof=sortListByKey quantity"`
+	FieldPath  SingleStringOrList `json:"fieldPath" validate:"required"`
+	Key        string             `json:"key,omitempty"`
+	Group      *string            `json:"group,omitempty"`
+	Kind       *string            `json:"kind,omitempty"`
+	Name       *string            `json:"name,omitempty"`
+	Namespace  *string            `json:"namespace,omitempty"`
+}
+
+func ValidateDiffNormalizerConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(DiffNormalizerConfig)
+	if s.Normalizer == DiffNormalizerSortListByKey && s.Key == "" {
+		sl.ReportError(s, "key", "key", "key must be set for the sortListByKey normalizer", "")
+	}
+}
+
 type ConflictResolutionAction string
 
 const (
@@ -170,6 +276,7 @@ type DeploymentProjectConfig struct {
 	Tags              []string          `json:"tags,omitempty"`
 
 	IgnoreForDiff      []IgnoreForDiffItemConfig  `json:"ignoreForDiff,omitempty"`
+	DiffNormalizers    []DiffNormalizerConfig     `json:"diffNormalizers,omitempty"`
 	ConflictResolution []ConflictResolutionConfig `json:"conflictResolution,omitempty"`
 }
 
@@ -177,6 +284,8 @@ func init() {
 	yaml2.Validator.RegisterStructValidation(ValidateDeploymentItemConfig, DeploymentItemConfig{})
 	yaml2.Validator.RegisterStructValidation(ValidateDeleteObjectItemConfig, DeleteObjectItemConfig{})
 	yaml2.Validator.RegisterStructValidation(ValidateWaitReadinessObjectItemConfig, WaitReadinessObjectItemConfig{})
+	yaml2.Validator.RegisterStructValidation(ValidateWaitForObjectItemConfig, WaitForObjectItemConfig{})
 	yaml2.Validator.RegisterStructValidation(ValidateIgnoreForDiffItemConfig, IgnoreForDiffItemConfig{})
+	yaml2.Validator.RegisterStructValidation(ValidateDiffNormalizerConfig, DiffNormalizerConfig{})
 	yaml2.Validator.RegisterStructValidation(ValidateConflictResolutionConfig, ConflictResolutionConfig{})
 }
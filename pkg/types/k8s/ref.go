@@ -4,6 +4,7 @@ package k8s
 import (
 	"fmt"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
 )
 
 type ObjectRef struct {
@@ -71,3 +72,25 @@ func NewObjectRef(group string, version string, kind string, name string, namesp
 		Namespace: namespace,
 	}
 }
+
+// ParseObjectRef parses refs in the form group/Kind/namespace/name or group/Kind/name (for cluster scoped objects).
+// The group can be empty or "core" for objects of the core API group, e.g. core/ConfigMap/my-ns/my-cm.
+func ParseObjectRef(s string) (ObjectRef, error) {
+	var ret ObjectRef
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 3:
+		ret.Group, ret.Kind, ret.Name = parts[0], parts[1], parts[2]
+	case 4:
+		ret.Group, ret.Kind, ret.Namespace, ret.Name = parts[0], parts[1], parts[2], parts[3]
+	default:
+		return ret, fmt.Errorf("invalid object ref %s, must be in the form group/Kind/namespace/name or group/Kind/name", s)
+	}
+	if ret.Group == "core" {
+		ret.Group = ""
+	}
+	if ret.Kind == "" || ret.Name == "" {
+		return ret, fmt.Errorf("invalid object ref %s, kind and name must not be empty", s)
+	}
+	return ret, nil
+}
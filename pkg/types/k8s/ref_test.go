@@ -0,0 +1,25 @@
+package k8s
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseObjectRef(t *testing.T) {
+	r, err := ParseObjectRef("apps/Deployment/ns/name")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectRef{Group: "apps", Kind: "Deployment", Namespace: "ns", Name: "name"}, r)
+
+	r, err = ParseObjectRef("core/Namespace/ns")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectRef{Kind: "Namespace", Name: "ns"}, r)
+
+	r, err = ParseObjectRef("/ConfigMap/ns/name")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectRef{Kind: "ConfigMap", Namespace: "ns", Name: "name"}, r)
+
+	_, err = ParseObjectRef("Deployment/name")
+	assert.ErrorContains(t, err, "invalid object ref")
+	_, err = ParseObjectRef("apps//ns/name")
+	assert.ErrorContains(t, err, "must not be empty")
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"github.com/go-playground/validator/v10"
+	"github.com/kluctl/kluctl/lib/yaml"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ServiceAccountRef struct {
@@ -15,13 +18,63 @@ type AwsConfig struct {
 	ServiceAccount *ServiceAccountRef `json:"serviceAccount,omitempty"`
 }
 
+// TLSConfig allows to override the TLS settings of the kubeconfig for a cluster, e.g. for clusters with self-signed
+// certificates that are not part of the kubeconfig
+type TLSConfig struct {
+	// CAData is the PEM encoded CA bundle that is used to verify the API server certificate
+	CAData string `json:"caData,omitempty"`
+	// InsecureSkipTLSVerify disables verification of the API server certificate
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
 type Target struct {
-	Name          string                 `json:"name"`
+	Name string `json:"name"`
+	// Extends is a list of targets that this target inherits from. The targets are merged in the given order, with
+	// later targets and finally this target itself taking precedence.
+	Extends []string `json:"extends,omitempty"`
+	// Abstract marks a target that is only used as a base for other targets and can not be deployed on its own
+	Abstract bool `json:"abstract,omitempty"`
+
 	Context       *string                `json:"context,omitempty"`
 	Args          *uo.UnstructuredObject `json:"args,omitempty"`
+	ArgsFromEnv   map[string]string      `json:"argsFromEnv,omitempty"`
 	Aws           *AwsConfig             `json:"aws,omitempty"`
+	TLS           *TLSConfig             `json:"tls,omitempty"`
 	Images        []FixedImage           `json:"images,omitempty"`
 	Discriminator string                 `json:"discriminator,omitempty"`
+
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+	Overlay          string `json:"overlay,omitempty"`
+
+	MaxDeletes *int `json:"maxDeletes,omitempty"`
+	MaxChanges *int `json:"maxChanges,omitempty"`
+
+	DeployDefaults *DeployDefaults `json:"deployDefaults,omitempty"`
+
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"`
+}
+
+// MaintenanceWindow specifies a time window in which changes to the target are allowed. A window opens at every point in
+// time matched by Schedule and stays open for Duration.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with the fields minute, hour, day of month, month and day of week
+	Schedule string          `json:"schedule" validate:"required"`
+	Duration metav1.Duration `json:"duration"`
+	// Timezone is the IANA time zone name used to evaluate Schedule, e.g. Europe/Berlin. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DeployDefaults specifies defaults for command line flags of the deploy and diff commands. Flags that are explicitly
+// passed on the command line always take precedence.
+type DeployDefaults struct {
+	ForceApply     *bool `json:"forceApply,omitempty"`
+	ReplaceOnError *bool `json:"replaceOnError,omitempty"`
+	AbortOnError   *bool `json:"abortOnError,omitempty"`
+	NoWait         *bool `json:"noWait,omitempty"`
+	// WaitTimeout is the default for --readiness-timeout
+	WaitTimeout *metav1.Duration `json:"waitTimeout,omitempty"`
 }
 
 type DeploymentArg struct {
@@ -29,11 +82,48 @@ type DeploymentArg struct {
 	Default *apiextensionsv1.JSON `json:"default,omitempty"`
 }
 
+type FeatureFlag struct {
+	Name        string `json:"name" validate:"required"`
+	Default     bool   `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type CrdSource struct {
+	Path    *string  `json:"path,omitempty"`
+	Url     *YamlUrl `json:"url,omitempty"`
+	Context *string  `json:"context,omitempty"`
+}
+
+func ValidateCrdSource(sl validator.StructLevel) {
+	s := sl.Current().Interface().(CrdSource)
+	cnt := 0
+	for _, b := range []bool{s.Path != nil, s.Url != nil, s.Context != nil} {
+		if b {
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		sl.ReportError(s, "path", "path", "one of path, url or context must be set", "")
+	} else if cnt != 1 {
+		sl.ReportError(s, "path", "path", "only one of path, url or context can be set", "")
+	}
+}
+
+func init() {
+	yaml.Validator.RegisterStructValidation(ValidateCrdSource, CrdSource{})
+}
+
 type KluctlProject struct {
 	Targets       []Target        `json:"targets,omitempty"`
 	Args          []DeploymentArg `json:"args,omitempty"`
 	Discriminator string          `json:"discriminator,omitempty"`
 	Aws           *AwsConfig      `json:"aws,omitempty"`
+
+	CrdSources []CrdSource `json:"crdSources,omitempty"`
+
+	DeployDefaults *DeployDefaults `json:"deployDefaults,omitempty"`
+
+	FeatureFlags []FeatureFlag `json:"featureFlags,omitempty"`
 }
 
 type KluctlLibraryProject struct {
@@ -0,0 +1,109 @@
+package result
+
+import (
+	"strings"
+)
+
+type ChangeImpactLevel string
+
+const (
+	ChangeImpactNone   ChangeImpactLevel = "none"
+	ChangeImpactLow    ChangeImpactLevel = "low"
+	ChangeImpactMedium ChangeImpactLevel = "medium"
+	ChangeImpactHigh   ChangeImpactLevel = "high"
+)
+
+// The weights used to compute the ChangeImpact score. The higher the weight, the more likely the change is to cause
+// disruption or to require a careful review.
+const (
+	changeImpactWeightWorkloadRestart = 3
+	changeImpactWeightSecret          = 2
+	changeImpactWeightRbac            = 3
+	changeImpactWeightCrd             = 5
+	changeImpactWeightDeletion        = 4
+
+	changeImpactMediumThreshold = 5
+	changeImpactHighThreshold   = 15
+)
+
+// ChangeImpact is a weighted summary of the changes of a command result, allowing to gauge the risk of a deployment
+// at a glance.
+type ChangeImpact struct {
+	Score int               `json:"score"`
+	Level ChangeImpactLevel `json:"level"`
+
+	// WorkloadRestarts is the number of Deployments, StatefulSets and DaemonSets with changes to the pod template,
+	// which will cause a rollout
+	WorkloadRestarts int `json:"workloadRestarts,omitempty"`
+	// SecretChanges is the number of new, changed or deleted Secrets
+	SecretChanges int `json:"secretChanges,omitempty"`
+	// RbacChanges is the number of new, changed or deleted RBAC objects
+	RbacChanges int `json:"rbacChanges,omitempty"`
+	// CrdChanges is the number of changed or deleted CustomResourceDefinitions
+	CrdChanges int `json:"crdChanges,omitempty"`
+	// Deletions is the number of deleted objects
+	Deletions int `json:"deletions,omitempty"`
+}
+
+func isWorkloadKind(group string, kind string) bool {
+	if group != "apps" {
+		return false
+	}
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return true
+	}
+	return false
+}
+
+// BuildChangeImpact computes the ChangeImpact from the objects of the command result.
+func (cr *CommandResult) BuildChangeImpact() *ChangeImpact {
+	ret := &ChangeImpact{}
+	for _, o := range cr.Objects {
+		changed := len(o.Changes) != 0
+		if !changed && !o.New && !o.Deleted {
+			continue
+		}
+
+		if o.Deleted {
+			ret.Deletions++
+		}
+
+		switch {
+		case isWorkloadKind(o.Ref.Group, o.Ref.Kind):
+			for _, c := range o.Changes {
+				if c.JsonPath == "spec.template" || strings.HasPrefix(c.JsonPath, "spec.template.") {
+					ret.WorkloadRestarts++
+					break
+				}
+			}
+		case o.Ref.Group == "" && o.Ref.Kind == "Secret":
+			ret.SecretChanges++
+		case o.Ref.Group == "rbac.authorization.k8s.io":
+			ret.RbacChanges++
+		case o.Ref.Group == "apiextensions.k8s.io" && o.Ref.Kind == "CustomResourceDefinition":
+			// new CRDs can't break existing resources
+			if changed || o.Deleted {
+				ret.CrdChanges++
+			}
+		}
+	}
+
+	ret.Score = ret.WorkloadRestarts*changeImpactWeightWorkloadRestart +
+		ret.SecretChanges*changeImpactWeightSecret +
+		ret.RbacChanges*changeImpactWeightRbac +
+		ret.CrdChanges*changeImpactWeightCrd +
+		ret.Deletions*changeImpactWeightDeletion
+
+	switch {
+	case ret.Score == 0:
+		ret.Level = ChangeImpactNone
+	case ret.Score < changeImpactMediumThreshold:
+		ret.Level = ChangeImpactLow
+	case ret.Score < changeImpactHighThreshold:
+		ret.Level = ChangeImpactMedium
+	default:
+		ret.Level = ChangeImpactHigh
+	}
+	return ret
+}
@@ -0,0 +1,68 @@
+package result
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildChangeImpact(t *testing.T) {
+	obj := func(group string, kind string, name string) BaseObject {
+		return BaseObject{Ref: k8s.ObjectRef{Group: group, Version: "v1", Kind: kind, Name: name, Namespace: "ns"}}
+	}
+	changes := func(paths ...string) []Change {
+		var ret []Change
+		for _, p := range paths {
+			ret = append(ret, Change{Type: "update", JsonPath: p})
+		}
+		return ret
+	}
+
+	var objects []BaseObject
+	add := func(o BaseObject, f func(o *BaseObject)) {
+		f(&o)
+		objects = append(objects, o)
+	}
+
+	add(obj("apps", "Deployment", "restarted"), func(o *BaseObject) {
+		o.Changes = changes("spec.template.spec.containers[0].image")
+	})
+	add(obj("apps", "Deployment", "scaled"), func(o *BaseObject) { o.Changes = changes("spec.replicas") })
+	add(obj("apps", "Deployment", "new"), func(o *BaseObject) { o.New = true })
+	add(obj("", "Secret", "new"), func(o *BaseObject) { o.New = true })
+	add(obj("", "Secret", "changed"), func(o *BaseObject) { o.Changes = changes("data.x") })
+	add(obj("", "Secret", "unchanged"), func(o *BaseObject) {})
+	add(obj("rbac.authorization.k8s.io", "Role", "role"), func(o *BaseObject) { o.Changes = changes("rules") })
+	add(obj("apiextensions.k8s.io", "CustomResourceDefinition", "new"), func(o *BaseObject) { o.New = true })
+	add(obj("apiextensions.k8s.io", "CustomResourceDefinition", "changed"), func(o *BaseObject) { o.Changes = changes("spec.versions") })
+	add(obj("", "ConfigMap", "deleted"), func(o *BaseObject) { o.Deleted = true })
+	add(obj("", "ConfigMap", "orphan"), func(o *BaseObject) { o.Orphan = true })
+
+	cr := &CommandResult{}
+	for _, o := range objects {
+		cr.Objects = append(cr.Objects, ResultObject{BaseObject: o})
+	}
+
+	ci := cr.BuildChangeImpact()
+	assert.Equal(t, &ChangeImpact{
+		Score:            3 + 2*2 + 3 + 5 + 4,
+		Level:            ChangeImpactHigh,
+		WorkloadRestarts: 1,
+		SecretChanges:    2,
+		RbacChanges:      1,
+		CrdChanges:       1,
+		Deletions:        1,
+	}, ci)
+}
+
+func TestBuildChangeImpactLevels(t *testing.T) {
+	cr := &CommandResult{}
+	assert.Equal(t, ChangeImpactNone, cr.BuildChangeImpact().Level)
+
+	secret := ResultObject{BaseObject: BaseObject{Ref: k8s.ObjectRef{Version: "v1", Kind: "Secret", Name: "s"}, New: true}}
+	cr.Objects = append(cr.Objects, secret)
+	assert.Equal(t, ChangeImpactLow, cr.BuildChangeImpact().Level)
+
+	cr.Objects = append(cr.Objects, secret, secret)
+	assert.Equal(t, ChangeImpactMedium, cr.BuildChangeImpact().Level)
+}
@@ -9,6 +9,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// FormatVersion is the version of the serialized format of CommandResult, ValidateResult and their summaries. It is
+// only increased when incompatible changes are made, e.g. when fields are removed, renamed or change their meaning.
+// Adding new fields is considered compatible, so consumers must ignore unknown fields. Results written before format
+// versions were introduced have no formatVersion set, which must be treated as version 1.
+//
+// The JSON schemas of these types are published in the schema sub-package.
+const FormatVersion = 1
+
 type Change struct {
 	Type        string                `json:"type" validate:"required"`
 	JsonPath    string                `json:"jsonPath" validate:"required"`
@@ -27,6 +35,14 @@ type DeploymentError struct {
 	Message string        `json:"message"`
 }
 
+// ErrorHint groups errors of the same category and provides a remediation hint for these
+type ErrorHint struct {
+	Category string          `json:"category"`
+	Hint     string          `json:"hint"`
+	Flags    []string        `json:"flags,omitempty"`
+	Refs     []k8s.ObjectRef `json:"refs,omitempty"`
+}
+
 type KluctlDeploymentInfo struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
@@ -93,6 +109,10 @@ type BaseObject struct {
 	Orphan  bool `json:"orphan,omitempty"`
 	Deleted bool `json:"deleted,omitempty"`
 	Hook    bool `json:"hook,omitempty"`
+
+	// DryRun is true if the object was only applied in dry-run mode due to the kluctl.io/dry-run annotation. New and
+	// Changes then describe what would have been applied.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 type ResultObject struct {
@@ -104,6 +124,7 @@ type ResultObject struct {
 }
 
 type CommandResult struct {
+	FormatVersion    int                            `json:"formatVersion,omitempty"`
 	Id               string                         `json:"id"`
 	ReconcileId      string                         `json:"reconcileId"`
 	ProjectKey       gittypes.ProjectKey            `json:"projectKey"`
@@ -121,7 +142,10 @@ type CommandResult struct {
 
 	Errors     []DeploymentError  `json:"errors,omitempty"`
 	Warnings   []DeploymentError  `json:"warnings,omitempty"`
+	ErrorHints []ErrorHint        `json:"errorHints,omitempty"`
 	SeenImages []types.FixedImage `json:"seenImages,omitempty"`
+
+	ChangeImpact *ChangeImpact `json:"changeImpact,omitempty"`
 }
 
 func (cr *CommandResult) ToCompacted() *CompactedCommandResult {
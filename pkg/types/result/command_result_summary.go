@@ -6,6 +6,7 @@ import (
 )
 
 type CommandResultSummary struct {
+	FormatVersion    int                   `json:"formatVersion,omitempty"`
 	Id               string                `json:"id"`
 	ReconcileId      string                `json:"reconcileId"`
 	ProjectKey       gittypes.ProjectKey   `json:"projectKey"`
@@ -32,6 +33,8 @@ type CommandResultSummary struct {
 	Warnings []DeploymentError `json:"warnings"`
 
 	TotalChanges int `json:"totalChanges"`
+
+	ChangeImpact *ChangeImpact `json:"changeImpact,omitempty"`
 }
 
 func (cr *CommandResult) BuildSummary() *CommandResultSummary {
@@ -50,6 +53,7 @@ func (cr *CommandResult) BuildSummary() *CommandResultSummary {
 	}
 
 	ret := &CommandResultSummary{
+		FormatVersion:       cr.FormatVersion,
 		Id:                  cr.Id,
 		ReconcileId:         cr.ReconcileId,
 		ProjectKey:          cr.ProjectKey,
@@ -70,6 +74,7 @@ func (cr *CommandResult) BuildSummary() *CommandResultSummary {
 		DeletedObjects:      count(func(o ResultObject) bool { return o.Deleted }),
 		Errors:              cr.Errors,
 		Warnings:            cr.Warnings,
+		ChangeImpact:        cr.ChangeImpact,
 	}
 	for _, o := range cr.Objects {
 		ret.TotalChanges += len(o.Changes)
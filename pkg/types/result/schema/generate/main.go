@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result/schema"
+	"os"
+)
+
+func main() {
+	for name := range schema.Schemas {
+		b, err := schema.Generate(name)
+		if err != nil {
+			panic(err.Error())
+		}
+		err = os.WriteFile(name, b, 0o644)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+}
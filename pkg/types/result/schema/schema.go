@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/invopop/jsonschema"
+	gittypes "github.com/kluctl/kluctl/lib/git/types"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+//go:embed *.json
+var schemasFS embed.FS
+
+// Schemas maps the file names of the published JSON schemas to the types they describe. These are the result types
+// that external integrations can rely on. See result.FormatVersion for the compatibility guarantees.
+var Schemas = map[string]any{
+	"command-result.json":          result.CommandResult{},
+	"command-result-summary.json":  result.CommandResultSummary{},
+	"validate-result.json":         result.ValidateResult{},
+	"validate-result-summary.json": result.ValidateResultSummary{},
+	"progress-events.json":         status.ProgressEvent{},
+}
+
+// Get returns the published JSON schema with the given file name.
+func Get(name string) ([]byte, error) {
+	if _, ok := Schemas[name]; !ok {
+		return nil, fmt.Errorf("unknown schema %s", name)
+	}
+	return schemasFS.ReadFile(name)
+}
+
+// Generate generates the JSON schema with the given file name from the Go types.
+func Generate(name string) ([]byte, error) {
+	v, ok := Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %s", name)
+	}
+
+	r := &jsonschema.Reflector{
+		// consumers must ignore unknown fields, as new fields can be added without increasing the format version
+		AllowAdditionalProperties: true,
+		Anonymous:                 true,
+		Mapper:                    mapType,
+	}
+	s := r.Reflect(v)
+	s.Title = reflect.TypeOf(v).Name()
+	s.Description = fmt.Sprintf("Format version %d", result.FormatVersion)
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+var stringSchema = &jsonschema.Schema{Type: "string"}
+
+func mapType(t reflect.Type) *jsonschema.Schema {
+	switch t {
+	case reflect.TypeOf(uo.UnstructuredObject{}):
+		return &jsonschema.Schema{Type: "object"}
+	case reflect.TypeOf(apiextensionsv1.JSON{}):
+		return &jsonschema.Schema{}
+	case reflect.TypeOf(metav1.Time{}):
+		return &jsonschema.Schema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(gittypes.GitUrl{}), reflect.TypeOf(gittypes.RepoKey{}), reflect.TypeOf(types.YamlUrl{}), reflect.TypeOf(metav1.Duration{}):
+		return stringSchema
+	case reflect.TypeOf(gittypes.GitRef{}):
+		// GitRef is either a plain string (legacy format) or an object with exactly one of the fields set
+		props := orderedmap.New[string, *jsonschema.Schema]()
+		props.Set("branch", stringSchema)
+		props.Set("tag", stringSchema)
+		props.Set("commit", stringSchema)
+		return &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				stringSchema,
+				{Type: "object", Properties: props},
+			},
+		}
+	}
+	return nil
+}
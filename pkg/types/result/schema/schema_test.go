@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestSchemasUpToDate ensures that the published schemas are regenerated whenever the result types change, so that
+// changes to the format are always visible in review.
+func TestSchemasUpToDate(t *testing.T) {
+	for name := range Schemas {
+		t.Run(name, func(t *testing.T) {
+			expected, err := Generate(name)
+			assert.NoError(t, err)
+			actual, err := Get(name)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual), "schema %s is outdated, run 'make generate'", name)
+		})
+	}
+}
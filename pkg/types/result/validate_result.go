@@ -14,6 +14,7 @@ type ValidateResultEntry struct {
 }
 
 type ValidateResult struct {
+	FormatVersion       int                    `json:"formatVersion,omitempty"`
 	Id                  string                 `json:"id"`
 	ReconcileId         string                 `json:"reconcileId"`
 	ProjectKey          gittypes.ProjectKey    `json:"projectKey"`
@@ -30,6 +31,7 @@ type ValidateResult struct {
 }
 
 type ValidateResultSummary struct {
+	FormatVersion       int                   `json:"formatVersion,omitempty"`
 	Id                  string                `json:"id"`
 	ReconcileId         string                `json:"reconcileId"`
 	ProjectKey          gittypes.ProjectKey   `json:"projectKey"`
@@ -47,6 +49,7 @@ type ValidateResultSummary struct {
 
 func (vr *ValidateResult) BuildSummary() ValidateResultSummary {
 	return ValidateResultSummary{
+		FormatVersion:       vr.FormatVersion,
 		Id:                  vr.Id,
 		ReconcileId:         vr.ReconcileId,
 		ProjectKey:          vr.ProjectKey,
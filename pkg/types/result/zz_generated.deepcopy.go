@@ -22,6 +22,7 @@ package result
 
 import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
@@ -73,6 +74,21 @@ func (in *Change) DeepCopy() *Change {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeImpact) DeepCopyInto(out *ChangeImpact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeImpact.
+func (in *ChangeImpact) DeepCopy() *ChangeImpact {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeImpact)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChangedObject) DeepCopyInto(out *ChangedObject) {
 	*out = *in
@@ -199,6 +215,13 @@ func (in *CommandResult) DeepCopyInto(out *CommandResult) {
 		*out = make([]DeploymentError, len(*in))
 		copy(*out, *in)
 	}
+	if in.ErrorHints != nil {
+		in, out := &in.ErrorHints, &out.ErrorHints
+		*out = make([]ErrorHint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.SeenImages != nil {
 		in, out := &in.SeenImages, &out.SeenImages
 		*out = make([]types.FixedImage, len(*in))
@@ -206,6 +229,11 @@ func (in *CommandResult) DeepCopyInto(out *CommandResult) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ChangeImpact != nil {
+		in, out := &in.ChangeImpact, &out.ChangeImpact
+		*out = new(ChangeImpact)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResult.
@@ -242,6 +270,11 @@ func (in *CommandResultSummary) DeepCopyInto(out *CommandResultSummary) {
 		*out = make([]DeploymentError, len(*in))
 		copy(*out, *in)
 	}
+	if in.ChangeImpact != nil {
+		in, out := &in.ChangeImpact, &out.ChangeImpact
+		*out = new(ChangeImpact)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResultSummary.
@@ -387,6 +420,31 @@ func (in *DriftedObject) DeepCopy() *DriftedObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorHint) DeepCopyInto(out *ErrorHint) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make([]k8s.ObjectRef, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorHint.
+func (in *ErrorHint) DeepCopy() *ErrorHint {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorHint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KluctlDeploymentInfo) DeepCopyInto(out *KluctlDeploymentInfo) {
 	*out = *in
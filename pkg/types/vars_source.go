@@ -123,6 +123,35 @@ type VarsSourceVault struct {
 	Path    string `json:"path" validate:"required"`
 }
 
+type VarsSourceConjur struct {
+	// URL of the Conjur appliance
+	ApplianceUrl string `json:"applianceUrl" validate:"required"`
+	// The Conjur account
+	Account string `json:"account" validate:"required"`
+	// The authentication method, either apiKey (default), jwt or tokenFile
+	AuthnMethod string `json:"authnMethod,omitempty" validate:"omitempty,oneof=apiKey jwt tokenFile"`
+	// The service id of the authn-jwt authenticator. Required when authnMethod is jwt
+	ServiceId string `json:"serviceId,omitempty"`
+
+	// Id of a single variable which contains a variables YAML
+	SecretPath string `json:"secretPath,omitempty"`
+	// Arbitrary yaml where the leaf values are variable ids. Each leaf is replaced with the variable's value
+	Secrets *uo.UnstructuredObject `json:"secrets,omitempty"`
+}
+
+func ValidateVarsSourceConjur(sl validator.StructLevel) {
+	s := sl.Current().Interface().(VarsSourceConjur)
+
+	if s.SecretPath == "" && s.Secrets == nil {
+		sl.ReportError(s, "self", "self", "either secretPath or secrets must be set", "")
+	} else if s.SecretPath != "" && s.Secrets != nil {
+		sl.ReportError(s, "self", "self", "only one of secretPath or secrets can be set", "")
+	}
+	if s.AuthnMethod == "jwt" && s.ServiceId == "" {
+		sl.ReportError(s, "serviceId", "ServiceId", "serviceId is required when authnMethod is jwt", "")
+	}
+}
+
 type VarsSource struct {
 	IgnoreMissing *bool `json:"ignoreMissing,omitempty"`
 	NoOverride    *bool `json:"noOverride,omitempty"`
@@ -141,6 +170,7 @@ type VarsSource struct {
 	GcpSecretManager  *VarsSourceGcpSecretManager         `json:"gcpSecretManager,omitempty" isVarsSource:"true"`
 	Vault             *VarsSourceVault                    `json:"vault,omitempty" isVarsSource:"true"`
 	AzureKeyVault     *VarSourceAzureKeyVault             `json:"azureKeyVault,omitempty" isVarsSource:"true"`
+	Conjur            *VarsSourceConjur                   `json:"conjur,omitempty" isVarsSource:"true"`
 
 	TargetPath string `json:"targetPath,omitempty"`
 
@@ -173,5 +203,6 @@ func ValidateVarsSource(sl validator.StructLevel) {
 func init() {
 	yaml.Validator.RegisterStructValidation(ValidateVarsSourceClusterConfigMapOrSecret, VarsSourceClusterConfigMapOrSecret{})
 	yaml.Validator.RegisterStructValidation(ValidateVarsSourceClusterObject, VarsSourceClusterObject{})
+	yaml.Validator.RegisterStructValidation(ValidateVarsSourceConjur, VarsSourceConjur{})
 	yaml.Validator.RegisterStructValidation(ValidateVarsSource, VarsSource{})
 }
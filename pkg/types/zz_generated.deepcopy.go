@@ -24,6 +24,7 @@ import (
 	gittypes "github.com/kluctl/kluctl/lib/git/types"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -52,6 +53,50 @@ func (in *AwsConfig) DeepCopy() *AwsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapGeneratorConfig) DeepCopyInto(out *ConfigMapGeneratorConfig) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Literals != nil {
+		in, out := &in.Literals, &out.Literals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapGeneratorConfig.
+func (in *ConfigMapGeneratorConfig) DeepCopy() *ConfigMapGeneratorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapGeneratorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConflictResolutionConfig) DeepCopyInto(out *ConflictResolutionConfig) {
 	*out = *in
@@ -102,6 +147,36 @@ func (in *ConflictResolutionConfig) DeepCopy() *ConflictResolutionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrdSource) DeepCopyInto(out *CrdSource) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(YamlUrl)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrdSource.
+func (in *CrdSource) DeepCopy() *CrdSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CrdSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeleteObjectItemConfig) DeepCopyInto(out *DeleteObjectItemConfig) {
 	*out = *in
@@ -118,6 +193,46 @@ func (in *DeleteObjectItemConfig) DeepCopy() *DeleteObjectItemConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployDefaults) DeepCopyInto(out *DeployDefaults) {
+	*out = *in
+	if in.ForceApply != nil {
+		in, out := &in.ForceApply, &out.ForceApply
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplaceOnError != nil {
+		in, out := &in.ReplaceOnError, &out.ReplaceOnError
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AbortOnError != nil {
+		in, out := &in.AbortOnError, &out.AbortOnError
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NoWait != nil {
+		in, out := &in.NoWait, &out.NoWait
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WaitTimeout != nil {
+		in, out := &in.WaitTimeout, &out.WaitTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployDefaults.
+func (in *DeployDefaults) DeepCopy() *DeployDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentArg) DeepCopyInto(out *DeploymentArg) {
 	*out = *in
@@ -178,6 +293,16 @@ func (in *DeploymentItemConfig) DeepCopyInto(out *DeploymentItemConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PreIncludeHooks != nil {
+		in, out := &in.PreIncludeHooks, &out.PreIncludeHooks
+		*out = new(string)
+		**out = **in
+	}
+	if in.PostIncludeHooks != nil {
+		in, out := &in.PostIncludeHooks, &out.PostIncludeHooks
+		*out = new(string)
+		**out = **in
+	}
 	if in.WaitReadinessObjects != nil {
 		in, out := &in.WaitReadinessObjects, &out.WaitReadinessObjects
 		*out = make([]WaitReadinessObjectItemConfig, len(*in))
@@ -185,6 +310,13 @@ func (in *DeploymentItemConfig) DeepCopyInto(out *DeploymentItemConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WaitFor != nil {
+		in, out := &in.WaitFor, &out.WaitFor
+		*out = make([]WaitForObjectItemConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Args != nil {
 		in, out := &in.Args, &out.Args
 		*out = (*in).DeepCopy()
@@ -196,6 +328,37 @@ func (in *DeploymentItemConfig) DeepCopyInto(out *DeploymentItemConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DefaultNamespace != nil {
+		in, out := &in.DefaultNamespace, &out.DefaultNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapGenerator != nil {
+		in, out := &in.ConfigMapGenerator, &out.ConfigMapGenerator
+		*out = make([]ConfigMapGeneratorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecretGenerator != nil {
+		in, out := &in.SecretGenerator, &out.SecretGenerator
+		*out = make([]SecretGeneratorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.RenderedHelmChartConfig != nil {
 		in, out := &in.RenderedHelmChartConfig, &out.RenderedHelmChartConfig
 		*out = new(HelmChartConfig)
@@ -271,6 +434,13 @@ func (in *DeploymentProjectConfig) DeepCopyInto(out *DeploymentProjectConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DiffNormalizers != nil {
+		in, out := &in.DiffNormalizers, &out.DiffNormalizers
+		*out = make([]DiffNormalizerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ConflictResolution != nil {
 		in, out := &in.ConflictResolution, &out.ConflictResolution
 		*out = make([]ConflictResolutionConfig, len(*in))
@@ -290,6 +460,61 @@ func (in *DeploymentProjectConfig) DeepCopy() *DeploymentProjectConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiffNormalizerConfig) DeepCopyInto(out *DiffNormalizerConfig) {
+	*out = *in
+	if in.FieldPath != nil {
+		in, out := &in.FieldPath, &out.FieldPath
+		*out = make(SingleStringOrList, len(*in))
+		copy(*out, *in)
+	}
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(string)
+		**out = **in
+	}
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffNormalizerConfig.
+func (in *DiffNormalizerConfig) DeepCopy() *DiffNormalizerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DiffNormalizerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureFlag) DeepCopyInto(out *FeatureFlag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureFlag.
+func (in *FeatureFlag) DeepCopy() *FeatureFlag {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureFlag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FixedImage) DeepCopyInto(out *FixedImage) {
 	*out = *in
@@ -603,6 +828,23 @@ func (in *KluctlProject) DeepCopyInto(out *KluctlProject) {
 		*out = new(AwsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CrdSources != nil {
+		in, out := &in.CrdSources, &out.CrdSources
+		*out = make([]CrdSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeployDefaults != nil {
+		in, out := &in.DeployDefaults, &out.DeployDefaults
+		*out = new(DeployDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make([]FeatureFlag, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlProject.
@@ -615,6 +857,22 @@ func (in *KluctlProject) DeepCopy() *KluctlProject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectRefItem) DeepCopyInto(out *ObjectRefItem) {
 	*out = *in
@@ -675,6 +933,50 @@ func (in *OciRef) DeepCopy() *OciRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGeneratorConfig) DeepCopyInto(out *SecretGeneratorConfig) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Literals != nil {
+		in, out := &in.Literals, &out.Literals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGeneratorConfig.
+func (in *SecretGeneratorConfig) DeepCopy() *SecretGeneratorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGeneratorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountRef) DeepCopyInto(out *ServiceAccountRef) {
 	*out = *in
@@ -709,9 +1011,29 @@ func (in SingleStringOrList) DeepCopy() SingleStringOrList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Target) DeepCopyInto(out *Target) {
 	*out = *in
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Context != nil {
 		in, out := &in.Context, &out.Context
 		*out = new(string)
@@ -721,11 +1043,23 @@ func (in *Target) DeepCopyInto(out *Target) {
 		in, out := &in.Args, &out.Args
 		*out = (*in).DeepCopy()
 	}
+	if in.ArgsFromEnv != nil {
+		in, out := &in.ArgsFromEnv, &out.ArgsFromEnv
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Aws != nil {
 		in, out := &in.Aws, &out.Aws
 		*out = new(AwsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		**out = **in
+	}
 	if in.Images != nil {
 		in, out := &in.Images, &out.Images
 		*out = make([]FixedImage, len(*in))
@@ -733,6 +1067,33 @@ func (in *Target) DeepCopyInto(out *Target) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxDeletes != nil {
+		in, out := &in.MaxDeletes, &out.MaxDeletes
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxChanges != nil {
+		in, out := &in.MaxChanges, &out.MaxChanges
+		*out = new(int)
+		**out = **in
+	}
+	if in.DeployDefaults != nil {
+		in, out := &in.DeployDefaults, &out.DeployDefaults
+		*out = new(DeployDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Target.
@@ -841,6 +1202,11 @@ func (in *VarsSource) DeepCopyInto(out *VarsSource) {
 		*out = new(VarSourceAzureKeyVault)
 		**out = **in
 	}
+	if in.Conjur != nil {
+		in, out := &in.Conjur, &out.Conjur
+		*out = new(VarsSourceConjur)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RenderedVars != nil {
 		in, out := &in.RenderedVars, &out.RenderedVars
 		*out = (*in).DeepCopy()
@@ -926,6 +1292,25 @@ func (in *VarsSourceClusterObject) DeepCopy() *VarsSourceClusterObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VarsSourceConjur) DeepCopyInto(out *VarsSourceConjur) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VarsSourceConjur.
+func (in *VarsSourceConjur) DeepCopy() *VarsSourceConjur {
+	if in == nil {
+		return nil
+	}
+	out := new(VarsSourceConjur)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VarsSourceGcpSecretManager) DeepCopyInto(out *VarsSourceGcpSecretManager) {
 	*out = *in
@@ -1041,6 +1426,22 @@ func (in *VarsSourceVault) DeepCopy() *VarsSourceVault {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitForObjectItemConfig) DeepCopyInto(out *WaitForObjectItemConfig) {
+	*out = *in
+	in.ObjectRefItem.DeepCopyInto(&out.ObjectRefItem)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitForObjectItemConfig.
+func (in *WaitForObjectItemConfig) DeepCopy() *WaitForObjectItemConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitForObjectItemConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WaitReadinessObjectItemConfig) DeepCopyInto(out *WaitReadinessObjectItemConfig) {
 	*out = *in
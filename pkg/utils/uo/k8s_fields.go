@@ -60,6 +60,14 @@ func (uo *UnstructuredObject) SetK8sName(name string) {
 	}
 }
 
+func (uo *UnstructuredObject) GetK8sGenerateName() string {
+	s, _, err := uo.GetNestedString("metadata", "generateName")
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func (uo *UnstructuredObject) GetK8sNamespace() string {
 	s, _, err := uo.GetNestedString("metadata", "namespace")
 	if err != nil {
@@ -282,6 +290,19 @@ func (uo *UnstructuredObject) GetK8sCreationTime() time.Time {
 	return t
 }
 
+// GetK8sDeletionTime returns the deletionTimestamp of the object or nil if the object is not being deleted
+func (uo *UnstructuredObject) GetK8sDeletionTime() *time.Time {
+	v, ok, _ := uo.GetNestedString("metadata", "deletionTimestamp")
+	if !ok {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 func (ui *UnstructuredObject) getRegexp(r interface{}) *regexp.Regexp {
 	if x, ok := r.(*regexp.Regexp); ok {
 		return x
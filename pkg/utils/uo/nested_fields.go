@@ -221,7 +221,7 @@ func (uo *UnstructuredObject) GetNestedObjectList(keys ...interface{}) ([]*Unstr
 }
 
 func (uo *UnstructuredObject) SetNestedObjectList(items []*UnstructuredObject, keys ...interface{}) error {
-	var l []map[string]interface{}
+	l := make([]interface{}, 0, len(items))
 	for _, i := range items {
 		l = append(l, i.Object)
 	}
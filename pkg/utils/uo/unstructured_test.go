@@ -66,3 +66,30 @@ func TestSetGetChildIntSlice(t *testing.T) {
 	v, _, _ = GetChild(x, 1)
 	assert.Equal(t, 42, v)
 }
+
+func TestSetNestedObjectList(t *testing.T) {
+	x := New()
+	err := x.SetNestedObjectList([]*UnstructuredObject{
+		FromMap(map[string]any{"name": "a"}),
+		FromMap(map[string]any{"name": "b"}),
+	}, "spec", "items")
+	assert.NoError(t, err)
+
+	// the list must be stored as []any, as otherwise it can't be read back or deep copied
+	assert.IsType(t, []any{}, x.Object["spec"].(map[string]any)["items"])
+	l, found, err := x.GetNestedObjectList("spec", "items")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, l, 2)
+	assert.Equal(t, "b", l[1].Object["name"])
+	assert.NotPanics(t, func() {
+		x.Clone()
+	})
+
+	err = x.SetNestedObjectList(nil, "spec", "items")
+	assert.NoError(t, err)
+	l, found, err = x.GetNestedObjectList("spec", "items")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, l)
+}
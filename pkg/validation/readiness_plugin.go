@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const readinessPluginTimeout = 30 * time.Second
+
+type ReadinessPluginStatus string
+
+const (
+	ReadinessPluginReady   ReadinessPluginStatus = "ready"
+	ReadinessPluginPending ReadinessPluginStatus = "pending"
+	ReadinessPluginError   ReadinessPluginStatus = "error"
+)
+
+// ReadinessPlugin is an executable that determines readiness of all objects of a single GroupKind. The executable
+// receives the live object as JSON on stdin and must print a ReadinessPluginResult as JSON to stdout.
+type ReadinessPlugin struct {
+	GroupKind schema.GroupKind
+	Command   string
+}
+
+type ReadinessPluginResult struct {
+	Status  ReadinessPluginStatus `json:"status"`
+	Message string                `json:"message,omitempty"`
+}
+
+// ParseReadinessPlugin parses a plugin definition in the form of Kind.group=/path/to/executable. The group is omitted
+// for core kinds, e.g. Pod=/path/to/executable.
+func ParseReadinessPlugin(s string) (ReadinessPlugin, error) {
+	gkStr, command, ok := strings.Cut(s, "=")
+	if !ok || gkStr == "" || command == "" {
+		return ReadinessPlugin{}, fmt.Errorf("invalid readiness plugin '%s', must be in the form Kind.group=/path/to/executable", s)
+	}
+	return ReadinessPlugin{
+		GroupKind: schema.ParseGroupKind(gkStr),
+		Command:   command,
+	}, nil
+}
+
+type readinessPluginsKey struct{}
+
+func WithReadinessPlugins(ctx context.Context, plugins []ReadinessPlugin) context.Context {
+	if len(plugins) == 0 {
+		return ctx
+	}
+	m := make(map[schema.GroupKind]ReadinessPlugin, len(plugins))
+	for _, p := range plugins {
+		m[p.GroupKind] = p
+	}
+	return context.WithValue(ctx, readinessPluginsKey{}, m)
+}
+
+func getReadinessPlugin(ctx context.Context, gk schema.GroupKind) *ReadinessPlugin {
+	m, ok := ctx.Value(readinessPluginsKey{}).(map[schema.GroupKind]ReadinessPlugin)
+	if !ok {
+		return nil
+	}
+	p, ok := m[gk]
+	if !ok {
+		return nil
+	}
+	return &p
+}
+
+func (p *ReadinessPlugin) Run(ctx context.Context, o *uo.UnstructuredObject) (*ReadinessPluginResult, error) {
+	in, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessPluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("readiness plugin %s failed: %w, stderr=%s", p.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var r ReadinessPluginResult
+	err = json.Unmarshal(stdout.Bytes(), &r)
+	if err != nil {
+		return nil, fmt.Errorf("readiness plugin %s returned invalid output: %w", p.Command, err)
+	}
+	switch r.Status {
+	case ReadinessPluginReady, ReadinessPluginPending, ReadinessPluginError:
+	default:
+		return nil, fmt.Errorf("readiness plugin %s returned unknown status '%s'", p.Command, r.Status)
+	}
+	return &r, nil
+}
+
+func (r *ReadinessPluginResult) getMessage(def string) string {
+	if r.Message == "" {
+		return def
+	}
+	return r.Message
+}
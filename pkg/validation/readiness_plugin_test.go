@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeReadinessPlugin(t *testing.T, script string) string {
+	p := filepath.Join(t.TempDir(), "plugin.sh")
+	err := os.WriteFile(p, []byte("#!/bin/sh\n"+script), 0o700)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestParseReadinessPlugin(t *testing.T) {
+	p, err := ParseReadinessPlugin("Widget.example.com=/bin/plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Group: "example.com", Kind: "Widget"}, p.GroupKind)
+	assert.Equal(t, "/bin/plugin", p.Command)
+
+	p, err = ParseReadinessPlugin("Pod=/bin/plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Kind: "Pod"}, p.GroupKind)
+
+	_, err = ParseReadinessPlugin("Pod")
+	assert.Error(t, err)
+	_, err = ParseReadinessPlugin("Pod=")
+	assert.Error(t, err)
+}
+
+func TestValidateWithReadinessPlugin(t *testing.T) {
+	type testCase struct {
+		name     string
+		script   string
+		ready    bool
+		hasError bool
+		message  string
+	}
+
+	tests := []testCase{
+		{
+			name:   "ready",
+			script: `grep -q '"phase":"Done"' && echo '{"status": "ready"}' || echo '{"status": "pending", "message": "not done"}'`,
+			ready:  true,
+		},
+		{
+			name:    "pending",
+			script:  `echo '{"status": "pending", "message": "still working"}'`,
+			message: "still working",
+		},
+		{
+			name:     "error",
+			script:   `echo '{"status": "error"}'`,
+			hasError: true,
+			message:  "readiness plugin reported an error",
+		},
+		{
+			name:     "invalid-status",
+			script:   `echo '{"status": "maybe"}'`,
+			hasError: true,
+		},
+		{
+			name:     "failing",
+			script:   `exit 1`,
+			hasError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := WithReadinessPlugins(context.TODO(), []ReadinessPlugin{{
+				GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"},
+				Command:   writeReadinessPlugin(t, tc.script),
+			}})
+			o := buildTestObject("example.com/v1", "Widget", 1, nil, map[string]any{
+				"phase": "Done",
+			})
+
+			r := ValidateObject(ctx, nil, o, false, false)
+			assert.Equal(t, tc.ready, r.Ready)
+			if tc.hasError {
+				assert.Len(t, r.Errors, 1)
+				if tc.message != "" {
+					assert.Equal(t, tc.message, r.Errors[0].Message)
+				}
+			} else {
+				assert.Empty(t, r.Errors)
+				if tc.message != "" {
+					assert.Len(t, r.Warnings, 1)
+					assert.Equal(t, tc.message, r.Warnings[0].Message)
+				}
+			}
+		})
+	}
+
+	// kinds without plugin are not affected
+	ctx := WithReadinessPlugins(context.TODO(), []ReadinessPlugin{{
+		GroupKind: schema.GroupKind{Group: "example.com", Kind: "Other"},
+		Command:   "/does/not/exist",
+	}})
+	r := ValidateObject(ctx, nil, buildTestObject("v1", "ConfigMap", 1, nil, nil), false, false)
+	assert.True(t, r.Ready)
+	assert.Empty(t, r.Errors)
+}
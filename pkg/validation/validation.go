@@ -27,9 +27,10 @@ func (err *validationFailed) Error() string {
 }
 
 type condition struct {
-	status  string
-	reason  string
-	message string
+	status             string
+	reason             string
+	message            string
+	observedGeneration int64
 }
 
 func (c condition) getMessage(def string) string {
@@ -129,6 +130,21 @@ func ValidateObject(ctx context.Context, k *k8s.K8sCluster, o *uo.UnstructuredOb
 		return
 	}
 
+	if p := getReadinessPlugin(ctx, ref.GroupKind()); p != nil {
+		r, err := p.Run(ctx, o)
+		if err != nil {
+			addError(err.Error())
+			return
+		}
+		switch r.Status {
+		case ReadinessPluginPending:
+			addNotReady(r.getMessage("readiness plugin reported the object as pending"))
+		case ReadinessPluginError:
+			addError(r.getMessage("readiness plugin reported an error"))
+		}
+		return
+	}
+
 	status, _, _ := o.GetNestedObject("status")
 	if status == nil {
 		if forceStatusRequired {
@@ -162,11 +178,16 @@ func ValidateObject(ctx context.Context, k *k8s.K8sCluster, o *uo.UnstructuredOb
 				status, _, _ := c.GetNestedString("status")
 				reason, _, _ := c.GetNestedString("reason")
 				message, _, _ := c.GetNestedString("message")
+				observedGeneration, ok, _ := c.GetNestedInt("observedGeneration")
+				if !ok {
+					observedGeneration = -1
+				}
 				if t == typ {
 					ret = append(ret, condition{
-						status:  status,
-						reason:  reason,
-						message: message,
+						status:             status,
+						reason:             reason,
+						message:            message,
+						observedGeneration: observedGeneration,
 					})
 				}
 			}
@@ -191,6 +212,16 @@ func ValidateObject(ctx context.Context, k *k8s.K8sCluster, o *uo.UnstructuredOb
 		}
 		return c[0]
 	}
+	// checkReadyCondition is used for kinds that follow the common convention of reporting readiness via a single
+	// condition, which might also carry its own observedGeneration
+	checkReadyCondition := func(typ string) {
+		c := getCondition(typ, reactNotReady, true)
+		if c.observedGeneration != -1 && c.observedGeneration != o.GetK8sGeneration() {
+			addNotReady("Waiting for reconciliation")
+		} else if c.status != "True" {
+			addNotReady(c.getMessage("Not ready"))
+		}
+	}
 	getStatusField := func(field string, er errorReaction, doRaise bool, def interface{}) interface{} {
 		v, ok, _ := status.GetNestedField(field)
 		if !ok {
@@ -380,6 +411,75 @@ func ValidateObject(ctx context.Context, k *k8s.K8sCluster, o *uo.UnstructuredOb
 				}
 			}
 		}
+	case schema.GroupKind{Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"},
+		schema.GroupKind{Group: "kustomize.toolkit.fluxcd.io", Kind: "Kustomization"},
+		schema.GroupKind{Group: "source.toolkit.fluxcd.io", Kind: "GitRepository"},
+		schema.GroupKind{Group: "source.toolkit.fluxcd.io", Kind: "OCIRepository"},
+		schema.GroupKind{Group: "source.toolkit.fluxcd.io", Kind: "HelmChart"},
+		schema.GroupKind{Group: "source.toolkit.fluxcd.io", Kind: "Bucket"}:
+		// Flux marks objects as stalled when reconciliation can't succeed without changes to the spec
+		c := getCondition("Stalled", reactIgnore, false)
+		if c.status == "True" {
+			addError(c.getMessage("Stalled"))
+		} else {
+			checkReadyCondition("Ready")
+		}
+	case schema.GroupKind{Group: "argoproj.io", Kind: "Application"}:
+		phase, _, _ := status.GetNestedString("operationState", "phase")
+		if phase == "Failed" || phase == "Error" {
+			message, _, _ := status.GetNestedString("operationState", "message")
+			addNotReady(fmt.Sprintf("Sync operation is in phase %s: %s", phase, message))
+			return
+		}
+		health, _, _ := status.GetNestedString("health", "status")
+		syncStatus, _, _ := status.GetNestedString("sync", "status")
+		if health != "Healthy" {
+			message, _, _ := status.GetNestedString("health", "message")
+			if message == "" {
+				addNotReady(fmt.Sprintf("Application health is %s", health))
+			} else {
+				addNotReady(fmt.Sprintf("Application health is %s: %s", health, message))
+			}
+		} else if syncStatus != "Synced" {
+			addNotReady(fmt.Sprintf("Application sync status is %s", syncStatus))
+		}
+	case schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"},
+		schema.GroupKind{Group: "cert-manager.io", Kind: "Issuer"},
+		schema.GroupKind{Group: "cert-manager.io", Kind: "ClusterIssuer"}:
+		checkReadyCondition("Ready")
+	case schema.GroupKind{Group: "kafka.strimzi.io", Kind: "Kafka"},
+		schema.GroupKind{Group: "kafka.strimzi.io", Kind: "KafkaTopic"},
+		schema.GroupKind{Group: "kafka.strimzi.io", Kind: "KafkaUser"}:
+		checkReadyCondition("Ready")
+	case schema.GroupKind{Group: "postgres-operator.crunchydata.com", Kind: "PostgresCluster"}:
+		specInstances, _, _ := o.GetNestedObjectList("spec", "instances")
+		statusInstances, _, _ := status.GetNestedObjectList("instances")
+		for i, si := range specInstances {
+			name, _, _ := si.GetNestedString("name")
+			replicas, ok, _ := si.GetNestedInt("replicas")
+			if !ok {
+				replicas = 1
+			}
+			var st *uo.UnstructuredObject
+			for j, x := range statusInstances {
+				n, _, _ := x.GetNestedString("name")
+				if n == name || (name == "" && i == j) {
+					st = x
+					break
+				}
+			}
+			if st == nil {
+				addNotReady(fmt.Sprintf("Instance set %s has no status yet", name))
+				continue
+			}
+			readyReplicas, _, _ := st.GetNestedInt("readyReplicas")
+			updatedReplicas, _, _ := st.GetNestedInt("updatedReplicas")
+			if updatedReplicas < replicas {
+				addNotReady(fmt.Sprintf("Instance set %s is not ready. %d out of %d expected pods have been updated", name, updatedReplicas, replicas))
+			} else if readyReplicas < replicas {
+				addNotReady(fmt.Sprintf("Instance set %s is not ready. %d out of %d expected pods are ready", name, readyReplicas, replicas))
+			}
+		}
 	case schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "MachineDeployment"}:
 		c := getCondition("Ready", reactNotReady, true)
 		if c.status != "True" {
@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildTestObject(apiVersion string, kind string, generation int64, spec map[string]any, status map[string]any) *uo.UnstructuredObject {
+	o := uo.FromMap(map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":       "o",
+			"namespace":  "ns",
+			"generation": generation,
+		},
+		"status": status,
+	})
+	if spec != nil {
+		_ = o.SetNestedField(spec, "spec")
+	}
+	return o
+}
+
+func readyCondition(status string, observedGeneration int64, message string) map[string]any {
+	return map[string]any{
+		"type":               "Ready",
+		"status":             status,
+		"observedGeneration": observedGeneration,
+		"message":            message,
+	}
+}
+
+func TestValidateWellKnownCRs(t *testing.T) {
+	type testCase struct {
+		name     string
+		o        *uo.UnstructuredObject
+		ready    bool
+		hasError bool
+		message  string
+	}
+
+	tests := []testCase{
+		{
+			name: "flux-helmrelease-ready",
+			o: buildTestObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", 2, nil, map[string]any{
+				"conditions": []any{readyCondition("True", 2, "")},
+			}),
+			ready: true,
+		},
+		{
+			name: "flux-helmrelease-old-generation",
+			o: buildTestObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", 2, nil, map[string]any{
+				"conditions": []any{readyCondition("True", 1, "")},
+			}),
+			message: "Waiting for reconciliation",
+		},
+		{
+			name: "flux-kustomization-not-ready",
+			o: buildTestObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", 1, nil, map[string]any{
+				"conditions": []any{readyCondition("Unknown", 1, "Reconciliation in progress")},
+			}),
+			message: "Reconciliation in progress",
+		},
+		{
+			name: "flux-kustomization-stalled",
+			o: buildTestObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", 1, nil, map[string]any{
+				"conditions": []any{
+					readyCondition("False", 1, "build failed"),
+					map[string]any{"type": "Stalled", "status": "True", "message": "build failed"},
+				},
+			}),
+			hasError: true,
+			message:  "build failed",
+		},
+		{
+			name: "argo-application-ready",
+			o: buildTestObject("argoproj.io/v1alpha1", "Application", 1, nil, map[string]any{
+				"health": map[string]any{"status": "Healthy"},
+				"sync":   map[string]any{"status": "Synced"},
+			}),
+			ready: true,
+		},
+		{
+			name: "argo-application-progressing",
+			o: buildTestObject("argoproj.io/v1alpha1", "Application", 1, nil, map[string]any{
+				"health": map[string]any{"status": "Progressing"},
+				"sync":   map[string]any{"status": "Synced"},
+			}),
+			message: "Application health is Progressing",
+		},
+		{
+			name: "argo-application-out-of-sync",
+			o: buildTestObject("argoproj.io/v1alpha1", "Application", 1, nil, map[string]any{
+				"health": map[string]any{"status": "Healthy"},
+				"sync":   map[string]any{"status": "OutOfSync"},
+			}),
+			message: "Application sync status is OutOfSync",
+		},
+		{
+			name: "certificate-ready",
+			o: buildTestObject("cert-manager.io/v1", "Certificate", 1, nil, map[string]any{
+				"conditions": []any{readyCondition("True", 1, "")},
+			}),
+			ready: true,
+		},
+		{
+			name: "clusterissuer-not-ready",
+			o: buildTestObject("cert-manager.io/v1", "ClusterIssuer", 1, nil, map[string]any{
+				"conditions": []any{readyCondition("False", 1, "ACME account not registered")},
+			}),
+			message: "ACME account not registered",
+		},
+		{
+			name:    "kafka-no-conditions",
+			o:       buildTestObject("kafka.strimzi.io/v1beta2", "Kafka", 1, nil, map[string]any{}),
+			message: "Ready condition not in status",
+		},
+		{
+			name: "postgrescluster-ready",
+			o: buildTestObject("postgres-operator.crunchydata.com/v1beta1", "PostgresCluster", 1, map[string]any{
+				"instances": []any{map[string]any{"name": "instance1", "replicas": int64(2)}},
+			}, map[string]any{
+				"instances": []any{map[string]any{"name": "instance1", "readyReplicas": int64(2), "updatedReplicas": int64(2)}},
+			}),
+			ready: true,
+		},
+		{
+			name: "postgrescluster-not-ready",
+			o: buildTestObject("postgres-operator.crunchydata.com/v1beta1", "PostgresCluster", 1, map[string]any{
+				"instances": []any{map[string]any{"name": "instance1", "replicas": int64(2)}},
+			}, map[string]any{
+				"instances": []any{map[string]any{"name": "instance1", "readyReplicas": int64(1), "updatedReplicas": int64(2)}},
+			}),
+			message: "Instance set instance1 is not ready. 1 out of 2 expected pods are ready",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := ValidateObject(context.TODO(), nil, tc.o, false, false)
+			assert.Equal(t, tc.ready, r.Ready)
+			if tc.hasError {
+				assert.Len(t, r.Errors, 1)
+				assert.Equal(t, tc.message, r.Errors[0].Message)
+			} else {
+				assert.Empty(t, r.Errors)
+				if tc.message != "" {
+					assert.Len(t, r.Warnings, 1)
+					assert.Equal(t, tc.message, r.Warnings[0].Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package conjur
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	AuthnMethodApiKey    = "apiKey"
+	AuthnMethodJwt       = "jwt"
+	AuthnMethodTokenFile = "tokenFile"
+)
+
+const (
+	defaultJwtTokenPath    = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	defaultAccessTokenFile = "/run/conjur/access-token"
+)
+
+type Config struct {
+	ApplianceUrl string
+	Account      string
+	AuthnMethod  string
+	// ServiceId is the service id of the authn-jwt authenticator
+	ServiceId string
+}
+
+// Client is a minimal client for the Conjur REST API. Credentials are read from the same environment variables that are
+// used by the official Conjur clients:
+//   - apiKey: CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY
+//   - jwt: JWT_TOKEN_PATH, defaults to the Kubernetes service account token
+//   - tokenFile: CONJUR_AUTHN_TOKEN_FILE, defaults to the file written by the Conjur authenticator sidecar
+//
+// CONJUR_CERT_FILE or CONJUR_SSL_CERTIFICATE can be used to specify the CA of the Conjur appliance.
+type Client struct {
+	config     Config
+	baseUrl    string
+	httpClient *http.Client
+	token      string
+}
+
+func NewClient(ctx context.Context, config Config) (*Client, error) {
+	if config.AuthnMethod == "" {
+		config.AuthnMethod = AuthnMethodApiKey
+	}
+
+	httpClient, err := buildHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		config:     config,
+		baseUrl:    strings.TrimSuffix(config.ApplianceUrl, "/"),
+		httpClient: httpClient,
+	}
+	err = c.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func buildHttpClient() (*http.Client, error) {
+	httpClient := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
+	var certPem []byte
+	if p := os.Getenv("CONJUR_CERT_FILE"); p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Conjur certificate: %w", err)
+		}
+		certPem = b
+	} else if s := os.Getenv("CONJUR_SSL_CERTIFICATE"); s != "" {
+		certPem = []byte(s)
+	}
+	if certPem != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(certPem) {
+			return nil, fmt.Errorf("failed to parse Conjur certificate")
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+		httpClient.Transport = t
+	}
+	return httpClient, nil
+}
+
+func (c *Client) authenticate(ctx context.Context) error {
+	var token []byte
+	var err error
+	switch c.config.AuthnMethod {
+	case AuthnMethodApiKey:
+		login := os.Getenv("CONJUR_AUTHN_LOGIN")
+		apiKey := os.Getenv("CONJUR_AUTHN_API_KEY")
+		if login == "" || apiKey == "" {
+			return fmt.Errorf("CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY must be set for the apiKey authn method")
+		}
+		u := fmt.Sprintf("%s/authn/%s/%s/authenticate", c.baseUrl, url.PathEscape(c.config.Account), url.PathEscape(login))
+		token, err = c.doAuthenticate(ctx, u, "text/plain", apiKey)
+	case AuthnMethodJwt:
+		if c.config.ServiceId == "" {
+			return fmt.Errorf("serviceId must be set for the jwt authn method")
+		}
+		p := os.Getenv("JWT_TOKEN_PATH")
+		if p == "" {
+			p = defaultJwtTokenPath
+		}
+		jwt, err2 := os.ReadFile(p)
+		if err2 != nil {
+			return fmt.Errorf("failed to read JWT: %w", err2)
+		}
+		u := fmt.Sprintf("%s/authn-jwt/%s/%s/authenticate", c.baseUrl, url.PathEscape(c.config.ServiceId), url.PathEscape(c.config.Account))
+		body := url.Values{"jwt": []string{strings.TrimSpace(string(jwt))}}.Encode()
+		token, err = c.doAuthenticate(ctx, u, "application/x-www-form-urlencoded", body)
+	case AuthnMethodTokenFile:
+		p := os.Getenv("CONJUR_AUTHN_TOKEN_FILE")
+		if p == "" {
+			p = defaultAccessTokenFile
+		}
+		token, err = os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read Conjur access token: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown Conjur authn method %s", c.config.AuthnMethod)
+	}
+	if err != nil {
+		return err
+	}
+	c.token = base64.StdEncoding.EncodeToString(token)
+	return nil
+}
+
+func (c *Client) doAuthenticate(ctx context.Context, u string, contentType string, body string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating against Conjur failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("authenticating against Conjur failed with status %d", resp.StatusCode)
+	}
+	return b, nil
+}
+
+// GetSecret retrieves the value of the given variable. It returns nil if the variable does not exist or has no value.
+func (c *Client) GetSecret(ctx context.Context, variableId string) (*string, error) {
+	u := fmt.Sprintf("%s/secrets/%s/variable/%s", c.baseUrl, url.PathEscape(c.config.Account), url.PathEscape(variableId))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=\"%s\"", c.token))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading from Conjur failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading variable %s from Conjur failed with status %d", variableId, resp.StatusCode)
+	}
+	ret := string(b)
+	return &ret, nil
+}
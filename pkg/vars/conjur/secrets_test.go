@@ -0,0 +1,64 @@
+package conjur
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	token := base64.StdEncoding.EncodeToString([]byte(`{"protected":"x"}`))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/authn/acc/host%2Fkluctl/authenticate":
+			b, _ := io.ReadAll(r.Body)
+			if string(b) != "api-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"protected":"x"}`))
+		case "/secrets/acc/variable/prod%2Fdb%2Fpassword":
+			if r.Header.Get("Authorization") != `Token token="`+token+`"` {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("secret"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestConjurApiKey(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	t.Setenv("CONJUR_AUTHN_LOGIN", "host/kluctl")
+	t.Setenv("CONJUR_AUTHN_API_KEY", "api-key")
+
+	c, err := NewClient(context.Background(), Config{ApplianceUrl: s.URL + "/", Account: "acc"})
+	assert.NoError(t, err)
+
+	v, err := c.GetSecret(context.Background(), "prod/db/password")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", *v)
+
+	v, err = c.GetSecret(context.Background(), "prod/missing")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestConjurApiKeyInvalid(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	t.Setenv("CONJUR_AUTHN_LOGIN", "host/kluctl")
+	t.Setenv("CONJUR_AUTHN_API_KEY", "invalid")
+
+	_, err := NewClient(context.Background(), Config{ApplianceUrl: s.URL, Account: "acc"})
+	assert.ErrorContains(t, err, "authenticating against Conjur failed with status 401")
+}
@@ -10,6 +10,11 @@ import (
 type VarsCtx struct {
 	J2   *jinja2.Jinja2
 	Vars *uo.UnstructuredObject
+
+	// Globals are passed to Jinja2 in addition to Vars. These are meant for internal information used by kluctl's
+	// Jinja2 extensions (e.g. the discovered cluster APIs), which must not be merged with or overridden by vars
+	// sources. Globals are shared between copies and must not be modified after rendering has started.
+	Globals map[string]any
 }
 
 func NewVarsCtx(j2 *jinja2.Jinja2) *VarsCtx {
@@ -22,12 +27,27 @@ func NewVarsCtx(j2 *jinja2.Jinja2) *VarsCtx {
 
 func (vc *VarsCtx) Copy() *VarsCtx {
 	cp := &VarsCtx{
-		J2:   vc.J2,
-		Vars: vc.Vars.Clone(),
+		J2:      vc.J2,
+		Vars:    vc.Vars.Clone(),
+		Globals: vc.Globals,
 	}
 	return cp
 }
 
+func (vc *VarsCtx) buildGlobals() (map[string]any, error) {
+	globals, err := vc.Vars.ToMap()
+	if err != nil {
+		return nil, err
+	}
+	if globals == nil {
+		globals = map[string]any{}
+	}
+	for k, v := range vc.Globals {
+		globals[k] = v
+	}
+	return globals, nil
+}
+
 func (vc *VarsCtx) Update(vars *uo.UnstructuredObject) {
 	vc.Vars.Merge(vars)
 }
@@ -46,7 +66,7 @@ func (vc *VarsCtx) UpdateChildFromStruct(child string, o interface{}) error {
 }
 
 func (vc *VarsCtx) RenderString(t string, searchDirs []string) (string, error) {
-	globals, err := vc.Vars.ToMap()
+	globals, err := vc.buildGlobals()
 	if err != nil {
 		return "", err
 	}
@@ -57,7 +77,7 @@ func (vc *VarsCtx) RenderString(t string, searchDirs []string) (string, error) {
 }
 
 func (vc *VarsCtx) RenderStruct(o interface{}) (bool, error) {
-	globals, err := vc.Vars.ToMap()
+	globals, err := vc.buildGlobals()
 	if err != nil {
 		return false, err
 	}
@@ -65,7 +85,7 @@ func (vc *VarsCtx) RenderStruct(o interface{}) (bool, error) {
 }
 
 func (vc *VarsCtx) RenderFile(p string, searchDirs []string) (string, error) {
-	globals, err := vc.Vars.ToMap()
+	globals, err := vc.buildGlobals()
 	if err != nil {
 		return "", err
 	}
@@ -93,7 +113,7 @@ func (vc *VarsCtx) RenderYamlFile(p string, searchDirs []string, out interface{}
 }
 
 func (vc *VarsCtx) RenderDirectory(sourceDir string, targetDir string, excludePatterns []string, searchDirs []string, templateIgnoreRoot string) error {
-	globals, err := vc.Vars.ToMap()
+	globals, err := vc.buildGlobals()
 	if err != nil {
 		return err
 	}
@@ -105,7 +125,7 @@ func (vc *VarsCtx) CheckConditional(c string) (bool, error) {
 		return true, nil
 	}
 
-	m, err := vc.Vars.ToMap()
+	m, err := vc.buildGlobals()
 	if err != nil {
 		return false, err
 	}
@@ -21,6 +21,7 @@ import (
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/vars/conjur"
 	"github.com/kluctl/kluctl/v2/pkg/vars/vault"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -80,7 +81,7 @@ func (v *VarsLoader) LoadVars(ctx context.Context, varsCtx *VarsCtx, sourceIn *t
 		return err
 	}
 
-	globals, err := varsCtx.Vars.ToMap()
+	globals, err := varsCtx.buildGlobals()
 	if err != nil {
 		return err
 	}
@@ -144,6 +145,9 @@ func (v *VarsLoader) LoadVars(ctx context.Context, varsCtx *VarsCtx, sourceIn *t
 	} else if source.AzureKeyVault != nil {
 		newValue, err = v.loadAzureKeyVault(varsCtx, &source, ignoreMissing)
 		sensitive = true
+	} else if source.Conjur != nil {
+		newValue, err = v.loadConjur(varsCtx, &source, ignoreMissing)
+		sensitive = true
 	} else {
 		return fmt.Errorf("invalid vars source")
 	}
@@ -343,6 +347,59 @@ func (v *VarsLoader) loadVault(varsCtx *VarsCtx, source *types.VarsSource, ignor
 	return v.loadFromString(varsCtx, *secret)
 }
 
+func (v *VarsLoader) loadConjur(varsCtx *VarsCtx, source *types.VarsSource, ignoreMissing bool) (*uo.UnstructuredObject, error) {
+	c, err := conjur.NewClient(v.ctx, conjur.Config{
+		ApplianceUrl: source.Conjur.ApplianceUrl,
+		Account:      source.Conjur.Account,
+		AuthnMethod:  source.Conjur.AuthnMethod,
+		ServiceId:    source.Conjur.ServiceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Conjur.SecretPath != "" {
+		secret, err := c.GetSecret(v.ctx, source.Conjur.SecretPath)
+		if err != nil {
+			return nil, err
+		}
+		if secret == nil {
+			if ignoreMissing {
+				return uo.New(), nil
+			}
+			return nil, fmt.Errorf("the specified conjur variable %s was not found", source.Conjur.SecretPath)
+		}
+		return v.loadFromString(varsCtx, *secret)
+	}
+
+	newVars := uo.New()
+	err = source.Conjur.Secrets.NewIterator().IterateLeafs(func(it *uo.ObjectIterator) error {
+		variableId, ok := it.Value().(string)
+		if !ok {
+			return fmt.Errorf("value at %s is not a string", it.KeyPath().ToJsonPath())
+		}
+		secret, err := c.GetSecret(v.ctx, variableId)
+		if err != nil {
+			return err
+		}
+		if secret == nil {
+			if ignoreMissing {
+				return nil
+			}
+			return fmt.Errorf("conjur variable %s not found for %s", variableId, it.KeyPath().ToJsonPath())
+		}
+		err = newVars.SetNestedField(*secret, it.KeyPath()...)
+		if err != nil {
+			return fmt.Errorf("failed to set value for %s: %w", it.KeyPath().ToJsonPath(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newVars, nil
+}
+
 func (v *VarsLoader) loadGit(ctx context.Context, varsCtx *VarsCtx, gitFile *types.VarsSourceGit, ignoreMissing bool) (*uo.UnstructuredObject, bool, error) {
 	ge, err := v.rp.GetEntry(gitFile.Url.String())
 	if err != nil {
@@ -67,3 +67,23 @@ func TestVarsCtxStruct(t *testing.T) {
 	v, _, _ := varsCtx.Vars.GetNestedInt("child", "test1", "test2")
 	assert.Equal(t, int64(42), v)
 }
+
+func TestVarsCtxGlobals(t *testing.T) {
+	j2 := newJinja2Must(t)
+
+	varsCtx := NewVarsCtx(j2)
+	varsCtx.Update(uo.FromMap(map[string]interface{}{
+		"a": "v1",
+	}))
+	varsCtx.Globals = map[string]any{
+		"b": "v2",
+	}
+
+	r, err := varsCtx.Copy().RenderString("{{ a }} {{ b }}", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 v2", r)
+
+	// globals are not part of the vars
+	_, found, _ := varsCtx.Vars.GetNestedField("b")
+	assert.False(t, found)
+}
@@ -2,7 +2,7 @@ package webui
 
 import (
 	"embed"
-	log "github.com/sirupsen/logrus"
+	"fmt"
 	"io/fs"
 )
 
@@ -14,7 +14,7 @@ func init() {
 	var err error
 	uiFS, err = fs.Sub(uiBuildFS, "ui/build")
 	if err != nil {
-		log.Fatal("failed to get ui fs", err)
+		panic(fmt.Sprintf("failed to get ui fs: %v", err))
 	}
 }
 
@@ -255,6 +255,7 @@ func (h *eventsHandler) handler(c *gin.Context) {
 		return
 	}
 	defer conn.Close(websocket.StatusInternalError, "the sky is falling")
+	defer h.server.metrics.trackWebsocket("events")()
 
 	err = h.wsHandle(conn, filter)
 	if err != nil {
@@ -0,0 +1,139 @@
+package webui
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/lib/status"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const webuiMetricsSubsystem = "webui"
+
+// webuiMetrics holds all metrics exported by the webui. The metrics are registered in a dedicated registry which is
+// served on a separate listener, so that they are never exposed through the (potentially public) webui port.
+type webuiMetrics struct {
+	registry *prometheus.Registry
+
+	httpRequests         *prometheus.CounterVec
+	httpRequestDuration  *prometheus.HistogramVec
+	websocketConnections *prometheus.GaugeVec
+}
+
+func newWebuiMetrics(s *CommandResultsServer) *webuiMetrics {
+	m := &webuiMetrics{
+		registry: prometheus.NewRegistry(),
+	}
+
+	m.httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: webuiMetricsSubsystem,
+		Name:      "http_requests_total",
+		Help:      "How many HTTP requests have been handled, partitioned by method, path and status code.",
+	}, []string{"method", "path", "code"})
+	m.httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: webuiMetricsSubsystem,
+		Name:      "http_request_duration_seconds",
+		Help:      "How long handling a single HTTP request takes in seconds.",
+	}, []string{"method", "path"})
+	m.websocketConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: webuiMetricsSubsystem,
+		Name:      "websocket_connections",
+		Help:      "How many websocket connections are currently open, partitioned by endpoint.",
+	}, []string{"endpoint"})
+
+	countGauge := func(name string, help string, f func() (int, error)) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Subsystem: webuiMetricsSubsystem,
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			n, err := f()
+			if err != nil {
+				return 0
+			}
+			return float64(n)
+		})
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.websocketConnections,
+		countGauge("clusters", "How many clusters are accessed by the webui.", func() (int, error) {
+			return len(s.cam.accessors), nil
+		}),
+		countGauge("command_results", "How many command results are known to the webui.", func() (int, error) {
+			l, err := s.store.ListCommandResultSummaries(results.ListResultSummariesOptions{})
+			return len(l), err
+		}),
+		countGauge("validate_results", "How many validate results are known to the webui.", func() (int, error) {
+			l, err := s.store.ListValidateResultSummaries(results.ListResultSummariesOptions{})
+			return len(l), err
+		}),
+		countGauge("kluctl_deployments", "How many KluctlDeployments are known to the webui.", func() (int, error) {
+			l, err := s.store.ListKluctlDeployments()
+			return len(l), err
+		}),
+	)
+
+	return m
+}
+
+func (m *webuiMetrics) httpMiddleware(c *gin.Context) {
+	startTime := time.Now()
+
+	c.Next()
+
+	p := c.FullPath()
+	if p == "" {
+		p = "<unmatched>"
+	}
+	m.httpRequests.WithLabelValues(c.Request.Method, p, strconv.Itoa(c.Writer.Status())).Inc()
+	m.httpRequestDuration.WithLabelValues(c.Request.Method, p).Observe(time.Since(startTime).Seconds())
+}
+
+// trackWebsocket counts the websocket connection as open until the returned function is called
+func (m *webuiMetrics) trackWebsocket(endpoint string) func() {
+	g := m.websocketConnections.WithLabelValues(endpoint)
+	g.Inc()
+	return g.Dec
+}
+
+// serve starts serving the metrics on /metrics of the given address. The server is shut down when ctx is cancelled.
+func (m *webuiMetrics) serve(ctx context.Context, address string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			status.Errorf(ctx, "Metrics server failed: %v", err)
+		}
+	}()
+
+	return listener.Addr(), nil
+}
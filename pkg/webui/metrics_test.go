@@ -0,0 +1,91 @@
+package webui
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testMetricsStore struct {
+	results.ResultStore
+}
+
+func (s *testMetricsStore) ListCommandResultSummaries(options results.ListResultSummariesOptions) ([]result.CommandResultSummary, error) {
+	return []result.CommandResultSummary{{}, {}}, nil
+}
+
+func (s *testMetricsStore) ListValidateResultSummaries(options results.ListResultSummariesOptions) ([]result.ValidateResultSummary, error) {
+	return []result.ValidateResultSummary{{}}, nil
+}
+
+func (s *testMetricsStore) ListKluctlDeployments() ([]results.WatchKluctlDeploymentEvent, error) {
+	return nil, fmt.Errorf("not available")
+}
+
+func getMetrics(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func TestWebuiMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &CommandResultsServer{
+		store: &testMetricsStore{},
+		cam:   &clusterAccessorManager{},
+	}
+	m := newWebuiMetrics(s)
+
+	engine := gin.New()
+	engine.Use(m.httpMiddleware)
+	engine.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+	closeWs := m.trackWebsocket("/api/events")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	addr, err := m.serve(ctx, "127.0.0.1:0")
+	assert.NoError(t, err)
+	url := fmt.Sprintf("http://%s/metrics", addr.String())
+
+	body, err := getMetrics(url)
+	assert.NoError(t, err)
+	assert.Contains(t, body, `webui_http_requests_total{code="200",method="GET",path="/healthz"} 1`)
+	assert.Contains(t, body, `webui_http_requests_total{code="404",method="GET",path="<unmatched>"} 1`)
+	assert.Contains(t, body, `webui_websocket_connections{endpoint="/api/events"} 1`)
+	assert.Contains(t, body, "webui_clusters 0")
+	assert.Contains(t, body, "webui_command_results 2")
+	assert.Contains(t, body, "webui_validate_results 1")
+	assert.Contains(t, body, "webui_kluctl_deployments 0")
+
+	closeWs()
+	body, err = getMetrics(url)
+	assert.NoError(t, err)
+	assert.Contains(t, body, `webui_websocket_connections{endpoint="/api/events"} 0`)
+
+	// the metrics server must stop when the context is cancelled
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, err := getMetrics(url)
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
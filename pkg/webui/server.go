@@ -45,8 +45,9 @@ type CommandResultsServer struct {
 	serverClient       client.Client
 	serverCoreV1Client *corev1.CoreV1Client
 
-	auth   *authHandler
-	events *eventsHandler
+	auth    *authHandler
+	events  *eventsHandler
+	metrics *webuiMetrics
 
 	pathPrefix string
 	onlyApi    bool
@@ -83,6 +84,7 @@ func NewCommandResultsServer(
 	}
 
 	ret.events = newEventsHandler(ret)
+	ret.metrics = newWebuiMetrics(ret)
 
 	ret.auth, err = newAuthHandler(ctx, serverClient, controllerNamespace, authConfig)
 	if err != nil {
@@ -96,7 +98,9 @@ func NewCommandResultsServer(
 	return ret, nil
 }
 
-func (s *CommandResultsServer) Run(host string, port int, openBrowser bool) error {
+// Run starts serving the webui on the given host and port. If metricsBindAddress is neither empty nor "0", Prometheus
+// metrics are additionally served on /metrics of the given address.
+func (s *CommandResultsServer) Run(host string, port int, openBrowser bool, metricsBindAddress string) error {
 	err := s.startUpdateLogs()
 	if err != nil {
 		return err
@@ -113,6 +117,7 @@ func (s *CommandResultsServer) Run(host string, port int, openBrowser bool) erro
 		SkipPaths: []string{"/healthz", "/readyz"},
 	}))
 	engine.Use(gin.Recovery())
+	engine.Use(s.metrics.httpMiddleware)
 
 	engine.GET("/healthz", func(c *gin.Context) {
 		c.Status(http.StatusOK)
@@ -161,6 +166,14 @@ func (s *CommandResultsServer) Run(host string, port int, openBrowser bool) erro
 		return err
 	}
 
+	if metricsBindAddress != "" && metricsBindAddress != "0" {
+		_, err = s.metrics.serve(s.ctx, metricsBindAddress)
+		if err != nil {
+			_ = listener.Close()
+			return err
+		}
+	}
+
 	httpServer := http.Server{
 		Addr: address,
 		BaseContext: func(listener net.Listener) context.Context {
@@ -32,6 +32,7 @@ func (s *CommandResultsServer) logsHandler(gctx *gin.Context) {
 		return
 	}
 	defer conn.Close(websocket.StatusInternalError, "the sky is falling")
+	defer s.metrics.trackWebsocket("logs")()
 
 	ctx := conn.CloseRead(gctx)
 
@@ -2,6 +2,58 @@
 
 import { GitRef } from './models-static'
 
+export class ChangeImpact {
+    score: number;
+    level: string;
+    workloadRestarts?: number;
+    secretChanges?: number;
+    rbacChanges?: number;
+    crdChanges?: number;
+    deletions?: number;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.score = source["score"];
+        this.level = source["level"];
+        this.workloadRestarts = source["workloadRestarts"];
+        this.secretChanges = source["secretChanges"];
+        this.rbacChanges = source["rbacChanges"];
+        this.crdChanges = source["crdChanges"];
+        this.deletions = source["deletions"];
+    }
+}
+export class ErrorHint {
+    category: string;
+    hint: string;
+    flags?: string[];
+    refs?: ObjectRef[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.category = source["category"];
+        this.hint = source["hint"];
+        this.flags = source["flags"];
+        this.refs = this.convertValues(source["refs"], ObjectRef);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
 export class DeploymentError {
     ref: ObjectRef;
     message: string;
@@ -53,6 +105,7 @@ export class ResultObject {
     orphan?: boolean;
     deleted?: boolean;
     hook?: boolean;
+    dryRun?: boolean;
     rendered?: any;
     remote?: any;
     applied?: any;
@@ -65,6 +118,7 @@ export class ResultObject {
         this.orphan = source["orphan"];
         this.deleted = source["deleted"];
         this.hook = source["hook"];
+        this.dryRun = source["dryRun"];
         this.rendered = source["rendered"];
         this.remote = source["remote"];
         this.applied = source["applied"];
@@ -110,6 +164,26 @@ export class ConflictResolutionConfig {
         this.action = source["action"];
     }
 }
+export class DiffNormalizerConfig {
+    normalizer: string;
+    fieldPath: string[];
+    key?: string;
+    group?: string;
+    kind?: string;
+    name?: string;
+    namespace?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.normalizer = source["normalizer"];
+        this.fieldPath = source["fieldPath"];
+        this.key = source["key"];
+        this.group = source["group"];
+        this.kind = source["kind"];
+        this.name = source["name"];
+        this.namespace = source["namespace"];
+    }
+}
 export class IgnoreForDiffItemConfig {
     fieldPath?: string[];
     fieldPathRegex?: string[];
@@ -158,6 +232,66 @@ export class HelmChartConfig {
         this.skipPrePull = source["skipPrePull"];
     }
 }
+export class SecretGeneratorConfig {
+    name: string;
+    namespace?: string;
+    type?: string;
+    files?: string[];
+    literals?: string[];
+    envs?: string[];
+    labels?: {[key: string]: string};
+    annotations?: {[key: string]: string};
+    disableNameSuffixHash?: boolean;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.namespace = source["namespace"];
+        this.type = source["type"];
+        this.files = source["files"];
+        this.literals = source["literals"];
+        this.envs = source["envs"];
+        this.labels = source["labels"];
+        this.annotations = source["annotations"];
+        this.disableNameSuffixHash = source["disableNameSuffixHash"];
+    }
+}
+export class ConfigMapGeneratorConfig {
+    name: string;
+    namespace?: string;
+    files?: string[];
+    literals?: string[];
+    envs?: string[];
+    labels?: {[key: string]: string};
+    annotations?: {[key: string]: string};
+    disableNameSuffixHash?: boolean;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.name = source["name"];
+        this.namespace = source["namespace"];
+        this.files = source["files"];
+        this.literals = source["literals"];
+        this.envs = source["envs"];
+        this.labels = source["labels"];
+        this.annotations = source["annotations"];
+        this.disableNameSuffixHash = source["disableNameSuffixHash"];
+    }
+}
+export class WaitForObjectItemConfig {
+    group?: string;
+    kind?: string;
+    name: string;
+    namespace?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.group = source["group"];
+        this.kind = source["kind"];
+        this.name = source["name"];
+        this.namespace = source["namespace"];
+    }
+}
 export class WaitReadinessObjectItemConfig {
     group?: string;
     kind?: string;
@@ -246,13 +380,23 @@ export class DeploymentItemConfig {
     deleteObjects?: DeleteObjectItemConfig[];
     tags?: string[];
     barrier?: boolean;
+    waitReadinessBarrier?: boolean;
     message?: string;
+    preIncludeHooks?: string;
+    postIncludeHooks?: string;
     waitReadiness?: boolean;
     waitReadinessObjects?: WaitReadinessObjectItemConfig[];
+    waitFor?: WaitForObjectItemConfig[];
     args?: any;
     passVars?: boolean;
     vars?: VarsSource[];
+    defaultNamespace?: string;
+    configMapGenerator?: ConfigMapGeneratorConfig[];
+    secretGenerator?: SecretGeneratorConfig[];
+    artifacts?: string[];
+    overlays?: {[key: string]: string};
     skipDeleteIfTags?: boolean;
+    alwaysSkipDelete?: boolean;
     onlyRender?: boolean;
     alwaysDeploy?: boolean;
     when?: string;
@@ -269,13 +413,23 @@ export class DeploymentItemConfig {
         this.deleteObjects = this.convertValues(source["deleteObjects"], DeleteObjectItemConfig);
         this.tags = source["tags"];
         this.barrier = source["barrier"];
+        this.waitReadinessBarrier = source["waitReadinessBarrier"];
         this.message = source["message"];
+        this.preIncludeHooks = source["preIncludeHooks"];
+        this.postIncludeHooks = source["postIncludeHooks"];
         this.waitReadiness = source["waitReadiness"];
         this.waitReadinessObjects = this.convertValues(source["waitReadinessObjects"], WaitReadinessObjectItemConfig);
+        this.waitFor = this.convertValues(source["waitFor"], WaitForObjectItemConfig);
         this.args = source["args"];
         this.passVars = source["passVars"];
         this.vars = this.convertValues(source["vars"], VarsSource);
+        this.defaultNamespace = source["defaultNamespace"];
+        this.configMapGenerator = this.convertValues(source["configMapGenerator"], ConfigMapGeneratorConfig);
+        this.secretGenerator = this.convertValues(source["secretGenerator"], SecretGeneratorConfig);
+        this.artifacts = source["artifacts"];
+        this.overlays = source["overlays"];
         this.skipDeleteIfTags = source["skipDeleteIfTags"];
+        this.alwaysSkipDelete = source["alwaysSkipDelete"];
         this.onlyRender = source["onlyRender"];
         this.alwaysDeploy = source["alwaysDeploy"];
         this.when = source["when"];
@@ -302,6 +456,24 @@ export class DeploymentItemConfig {
 	    return a;
 	}
 }
+export class VarsSourceConjur {
+    applianceUrl: string;
+    account: string;
+    authnMethod?: string;
+    serviceId?: string;
+    secretPath?: string;
+    secrets?: any;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.applianceUrl = source["applianceUrl"];
+        this.account = source["account"];
+        this.authnMethod = source["authnMethod"];
+        this.serviceId = source["serviceId"];
+        this.secretPath = source["secretPath"];
+        this.secrets = source["secrets"];
+    }
+}
 export class VarSourceAzureKeyVault {
     vaultUri: string;
     secretName: string;
@@ -471,6 +643,7 @@ export class VarsSource {
     gcpSecretManager?: VarsSourceGcpSecretManager;
     vault?: VarsSourceVault;
     azureKeyVault?: VarSourceAzureKeyVault;
+    conjur?: VarsSourceConjur;
     targetPath?: string;
     when?: string;
     renderedSensitive?: boolean;
@@ -494,6 +667,7 @@ export class VarsSource {
         this.gcpSecretManager = this.convertValues(source["gcpSecretManager"], VarsSourceGcpSecretManager);
         this.vault = this.convertValues(source["vault"], VarsSourceVault);
         this.azureKeyVault = this.convertValues(source["azureKeyVault"], VarSourceAzureKeyVault);
+        this.conjur = this.convertValues(source["conjur"], VarsSourceConjur);
         this.targetPath = source["targetPath"];
         this.when = source["when"];
         this.renderedSensitive = source["renderedSensitive"];
@@ -527,6 +701,7 @@ export class DeploymentProjectConfig {
     overrideNamespace?: string;
     tags?: string[];
     ignoreForDiff?: IgnoreForDiffItemConfig[];
+    diffNormalizers?: DiffNormalizerConfig[];
     conflictResolution?: ConflictResolutionConfig[];
 
     constructor(source: any = {}) {
@@ -539,6 +714,7 @@ export class DeploymentProjectConfig {
         this.overrideNamespace = source["overrideNamespace"];
         this.tags = source["tags"];
         this.ignoreForDiff = this.convertValues(source["ignoreForDiff"], IgnoreForDiffItemConfig);
+        this.diffNormalizers = this.convertValues(source["diffNormalizers"], DiffNormalizerConfig);
         this.conflictResolution = this.convertValues(source["conflictResolution"], ConflictResolutionConfig);
     }
 
@@ -658,6 +834,34 @@ export class CommandInfo {
 	    return a;
 	}
 }
+export class MaintenanceWindow {
+    schedule: string;
+    duration: string;
+    timezone?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.schedule = source["schedule"];
+        this.duration = source["duration"];
+        this.timezone = source["timezone"];
+    }
+}
+export class DeployDefaults {
+    forceApply?: boolean;
+    replaceOnError?: boolean;
+    abortOnError?: boolean;
+    noWait?: boolean;
+    waitTimeout?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.forceApply = source["forceApply"];
+        this.replaceOnError = source["replaceOnError"];
+        this.abortOnError = source["abortOnError"];
+        this.noWait = source["noWait"];
+        this.waitTimeout = source["waitTimeout"];
+    }
+}
 export class ObjectRef {
     group?: string;
     version?: string;
@@ -718,6 +922,16 @@ export class FixedImage {
 	    return a;
 	}
 }
+export class TLSConfig {
+    caData?: string;
+    insecureSkipTLSVerify?: boolean;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.caData = source["caData"];
+        this.insecureSkipTLSVerify = source["insecureSkipTLSVerify"];
+    }
+}
 export class ServiceAccountRef {
     name: string;
     namespace: string;
@@ -758,20 +972,42 @@ export class AwsConfig {
 }
 export class Target {
     name: string;
+    extends?: string[];
+    abstract?: boolean;
     context?: string;
     args?: any;
+    argsFromEnv?: {[key: string]: string};
     aws?: AwsConfig;
+    tls?: TLSConfig;
     images?: FixedImage[];
     discriminator?: string;
+    defaultNamespace?: string;
+    overlay?: string;
+    maxDeletes?: number;
+    maxChanges?: number;
+    deployDefaults?: DeployDefaults;
+    maintenanceWindows?: MaintenanceWindow[];
+    featureFlags?: {[key: string]: boolean};
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
         this.name = source["name"];
+        this.extends = source["extends"];
+        this.abstract = source["abstract"];
         this.context = source["context"];
         this.args = source["args"];
+        this.argsFromEnv = source["argsFromEnv"];
         this.aws = this.convertValues(source["aws"], AwsConfig);
+        this.tls = this.convertValues(source["tls"], TLSConfig);
         this.images = this.convertValues(source["images"], FixedImage);
         this.discriminator = source["discriminator"];
+        this.defaultNamespace = source["defaultNamespace"];
+        this.overlay = source["overlay"];
+        this.maxDeletes = source["maxDeletes"];
+        this.maxChanges = source["maxChanges"];
+        this.deployDefaults = this.convertValues(source["deployDefaults"], DeployDefaults);
+        this.maintenanceWindows = this.convertValues(source["maintenanceWindows"], MaintenanceWindow);
+        this.featureFlags = source["featureFlags"];
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -815,6 +1051,7 @@ export class ProjectKey {
     }
 }
 export class CommandResult {
+    formatVersion?: number;
     id: string;
     reconcileId: string;
     projectKey: ProjectKey;
@@ -830,10 +1067,13 @@ export class CommandResult {
     objects?: ResultObject[];
     errors?: DeploymentError[];
     warnings?: DeploymentError[];
+    errorHints?: ErrorHint[];
     seenImages?: FixedImage[];
+    changeImpact?: ChangeImpact;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
+        this.formatVersion = source["formatVersion"];
         this.id = source["id"];
         this.reconcileId = source["reconcileId"];
         this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
@@ -849,7 +1089,9 @@ export class CommandResult {
         this.objects = this.convertValues(source["objects"], ResultObject);
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
+        this.errorHints = this.convertValues(source["errorHints"], ErrorHint);
         this.seenImages = this.convertValues(source["seenImages"], FixedImage);
+        this.changeImpact = this.convertValues(source["changeImpact"], ChangeImpact);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -871,6 +1113,7 @@ export class CommandResult {
 	}
 }
 export class CommandResultSummary {
+    formatVersion?: number;
     id: string;
     reconcileId: string;
     projectKey: ProjectKey;
@@ -892,9 +1135,11 @@ export class CommandResultSummary {
     errors: DeploymentError[];
     warnings: DeploymentError[];
     totalChanges: number;
+    changeImpact?: ChangeImpact;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
+        this.formatVersion = source["formatVersion"];
         this.id = source["id"];
         this.reconcileId = source["reconcileId"];
         this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
@@ -916,6 +1161,7 @@ export class CommandResultSummary {
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
         this.totalChanges = source["totalChanges"];
+        this.changeImpact = this.convertValues(source["changeImpact"], ChangeImpact);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -967,6 +1213,7 @@ export class ValidateResultEntry {
 	}
 }
 export class ValidateResult {
+    formatVersion?: number;
     id: string;
     reconcileId: string;
     projectKey: ProjectKey;
@@ -983,6 +1230,7 @@ export class ValidateResult {
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
+        this.formatVersion = source["formatVersion"];
         this.id = source["id"];
         this.reconcileId = source["reconcileId"];
         this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
@@ -1017,6 +1265,7 @@ export class ValidateResult {
 	}
 }
 export class ValidateResultSummary {
+    formatVersion?: number;
     id: string;
     reconcileId: string;
     projectKey: ProjectKey;
@@ -1032,6 +1281,7 @@ export class ValidateResultSummary {
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
+        this.formatVersion = source["formatVersion"];
         this.id = source["id"];
         this.reconcileId = source["reconcileId"];
         this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
@@ -1071,6 +1321,7 @@ export class DriftedObject {
     orphan?: boolean;
     deleted?: boolean;
     hook?: boolean;
+    dryRun?: boolean;
     lastResourceVersion: string;
 
     constructor(source: any = {}) {
@@ -1081,6 +1332,7 @@ export class DriftedObject {
         this.orphan = source["orphan"];
         this.deleted = source["deleted"];
         this.hook = source["hook"];
+        this.dryRun = source["dryRun"];
         this.lastResourceVersion = source["lastResourceVersion"];
     }
 